@@ -15,6 +15,10 @@ import (
 	"time"
 
 	"github.com/d-darac/inventory-api/env"
+	"github.com/d-darac/inventory-api/internal/files"
+	"github.com/d-darac/inventory-api/internal/inventories"
+	"github.com/d-darac/inventory-api/internal/reservations"
+	"github.com/d-darac/inventory-api/internal/storage"
 	"github.com/d-darac/inventory-api/middleware"
 	"github.com/d-darac/inventory-api/router"
 	"github.com/d-darac/inventory-assets/api"
@@ -49,14 +53,16 @@ func main() {
 	apiCfg.Db = database.New(db)
 
 	mux := http.NewServeMux()
-	router.LoadRoutes(mux, &apiCfg)
+	router.LoadRoutes(mux, &apiCfg, db, storage.NewLocalStorage(env.STORAGE_PATH))
 
 	v1 := http.NewServeMux()
 	v1.Handle("/v1/", http.StripPrefix("/v1", mux))
 
 	middleware := middleware.Middleware{
 		MaxReqSize: 10240,
-		Db:         apiCfg.Db,
+		// Leaves room for the multipart headers and form fields.
+		MaxUploadSize: files.MaxSize + 64<<10,
+		Db:            apiCfg.Db,
 		Auth: struct {
 			MasterKey string
 			Iv        string
@@ -98,6 +104,28 @@ func main() {
 		}
 	}()
 
+	go func() {
+		reservationsService := reservations.NewReservationsService(apiCfg.Db, db)
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := reservationsService.ReleaseExpired(); err != nil {
+				log.Printf("[main] Couldn't release expired reservations: %v.", err)
+			}
+		}
+	}()
+
+	go func() {
+		inventoriesService := inventories.NewInventoriesService(apiCfg.Db, db)
+		ticker := time.NewTicker(24 * time.Hour)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := inventoriesService.Snapshot(); err != nil {
+				log.Printf("[main] Couldn't snapshot inventories: %v.", err)
+			}
+		}
+	}()
+
 	// Wait for interrupt signal
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
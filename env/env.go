@@ -14,6 +14,7 @@ type Env struct {
 	MASTER_KEY    string
 	PLATFORM      string
 	PORT          string
+	STORAGE_PATH  string
 	TLS_CERT_PATH string
 	TLS_KEY_PATH  string
 }
@@ -53,6 +54,11 @@ func GetEnv() Env {
 		log.Fatalln("[env] env variable 'PORT' not set")
 		os.Exit(1)
 	}
+	storagePath, ok := os.LookupEnv("STORAGE_PATH")
+	if !ok {
+		log.Fatalln("[env] env variable 'STORAGE_PATH' not set")
+		os.Exit(1)
+	}
 	tlsCertPath, ok := os.LookupEnv("TLS_CERT_PATH")
 	if !ok {
 		log.Fatalln("[env] env variable 'TLS_CERT_PATH' not set")
@@ -71,6 +77,7 @@ func GetEnv() Env {
 		MASTER_KEY:    key,
 		PLATFORM:      platform,
 		PORT:          port,
+		STORAGE_PATH:  storagePath,
 		TLS_CERT_PATH: tlsCertPath,
 		TLS_KEY_PATH:  tlsKeyPath,
 	}
@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/d-darac/inventory-api/internal/components"
+	"github.com/d-darac/inventory-api/internal/items"
+	"github.com/d-darac/inventory-api/middleware"
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+type ComponentsHandler struct {
+	Components *components.ComponentsService
+	Items      *items.ItemsService
+	validator  *api.Validator
+}
+
+func NewComponentsHandler(db *database.Queries, conn *sql.DB) *ComponentsHandler {
+	return &ComponentsHandler{
+		Components: components.NewComponentsService(db),
+		Items:      items.NewItemsService(db, conn),
+		validator:  api.NewValidator(),
+	}
+}
+
+func (h *ComponentsHandler) Create(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	bundleId, err := api.GetIdFromPath(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	params := components.CreateComponentParams{}
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	component, err := h.Components.Create(components.Create{
+		AccountId:     accountId,
+		BundleId:      bundleId,
+		RequestParams: params,
+	})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if err := h.ExpandFields(params.Expand, component, accountId); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusCreated, component)
+}
+
+func (h *ComponentsHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	bundleId, componentId, err := getComponentIdsFromPath(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	err = h.Components.Delete(components.Delete{
+		AccountId:   accountId,
+		BundleId:    bundleId,
+		ComponentId: componentId,
+	})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusNoContent, nil)
+}
+
+func (h *ComponentsHandler) List(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	bundleId, err := api.GetIdFromPath(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	listRes := api.NewListResponse(r)
+	params := components.NewListComponentsParams()
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	cmps, hasMore, err := h.Components.List(components.List{
+		AccountId:     accountId,
+		BundleId:      bundleId,
+		RequestParams: params,
+	})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if len(cmps) != 0 {
+		listRes.Data = append(listRes.Data, cmps)
+		listRes.HasMore = hasMore
+	}
+
+	if err := h.ExpandFieldsList(params.Expand, cmps, accountId); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusOK, listRes)
+}
+
+func (h *ComponentsHandler) Retrieve(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	bundleId, componentId, err := getComponentIdsFromPath(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	params := components.RetrieveComponentParams{}
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	component, err := h.Components.Get(components.Get{
+		AccountId:     accountId,
+		BundleId:      bundleId,
+		ComponentId:   componentId,
+		RequestParams: params,
+		OmitBase:      false,
+	})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if err := h.ExpandFields(params.Expand, component, accountId); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusOK, component)
+}
+
+func (h *ComponentsHandler) Update(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	bundleId, componentId, err := getComponentIdsFromPath(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	params := components.UpdateComponentParams{}
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	component, err := h.Components.Update(components.Update{
+		AccountId:     accountId,
+		BundleId:      bundleId,
+		ComponentId:   componentId,
+		RequestParams: params,
+	})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if err := h.ExpandFields(params.Expand, component, accountId); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusOK, component)
+}
+
+// getComponentIdsFromPath returns the bundle's ID from {id} and the
+// component's from {component_id}.
+func getComponentIdsFromPath(r *http.Request) (bundleId, componentId uuid.UUID, err error) {
+	bundleId, err = api.GetIdFromPath(r)
+	if err != nil {
+		return
+	}
+	componentId, err = uuid.Parse(r.PathValue("component_id"))
+	if err != nil {
+		return bundleId, componentId, api.NotFoundMessage(componentId, "component")
+	}
+	return
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/d-darac/inventory-api/internal/events"
+	"github.com/d-darac/inventory-api/middleware"
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+type EventsHandler struct {
+	Events    *events.EventsService
+	validator *api.Validator
+}
+
+func NewEventsHandler(db *database.Queries) *EventsHandler {
+	return &EventsHandler{
+		Events:    events.NewEventsService(db),
+		validator: api.NewValidator(),
+	}
+}
+
+func (h *EventsHandler) List(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	listRes := api.NewListResponse(r)
+	params := events.NewListEventsParams()
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	evts, hasMore, err := h.Events.List(events.List{AccountId: accountId, RequestParams: params})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if len(evts) != 0 {
+		listRes.Data = append(listRes.Data, evts)
+		listRes.HasMore = hasMore
+	}
+
+	api.ResJSON(w, http.StatusOK, listRes)
+}
+
+func (h *EventsHandler) Retrieve(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	eventId, err := api.GetIdFromPath(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	params := events.RetrieveEventParams{}
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	event, err := h.Events.Get(events.Get{
+		AccountId:     accountId,
+		EventId:       eventId,
+		RequestParams: params,
+		OmitBase:      false,
+	})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusOK, event)
+}
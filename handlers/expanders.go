@@ -3,11 +3,28 @@ package handlers
 import (
 	"slices"
 
+	"github.com/d-darac/inventory-api/internal/components"
+	"github.com/d-darac/inventory-api/internal/files"
 	"github.com/d-darac/inventory-api/internal/groups"
 	"github.com/d-darac/inventory-api/internal/inventories"
 	itemidentifiers "github.com/d-darac/inventory-api/internal/item_identifiers"
 	"github.com/d-darac/inventory-api/internal/items"
+	"github.com/d-darac/inventory-api/internal/locations"
+	"github.com/d-darac/inventory-api/internal/lots"
+	"github.com/d-darac/inventory-api/internal/orders"
+	pricelists "github.com/d-darac/inventory-api/internal/price_lists"
+	purchaseorders "github.com/d-darac/inventory-api/internal/purchase_orders"
+	"github.com/d-darac/inventory-api/internal/reservations"
+	"github.com/d-darac/inventory-api/internal/returns"
+	serialnumbers "github.com/d-darac/inventory-api/internal/serial_numbers"
+	stockcounts "github.com/d-darac/inventory-api/internal/stock_counts"
+	stockmovements "github.com/d-darac/inventory-api/internal/stock_movements"
+	"github.com/d-darac/inventory-api/internal/suppliers"
+	"github.com/d-darac/inventory-api/internal/transfers"
+	"github.com/d-darac/inventory-api/internal/units"
+	"github.com/d-darac/inventory-api/internal/valuation"
 	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
 	"github.com/google/uuid"
 )
 
@@ -131,7 +148,139 @@ func (h *ItemIdentifiersHandler) expandItems(idtfs []*itemidentifiers.ItemIdenti
 	return nil
 }
 
+func (h *ComponentsHandler) ExpandFieldsList(fields []string, cmps []*components.Component, accountId uuid.UUID) error {
+	if fields != nil && slices.Contains(fields, "item") {
+		err := h.expandItems(cmps, accountId)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *ComponentsHandler) ExpandFields(fields []string, component *components.Component, accountId uuid.UUID) error {
+	if fields != nil && slices.Contains(fields, "item") {
+		getParams := items.Get{
+			AccountId:     accountId,
+			ItemId:        component.Item.ID.UUID,
+			RequestParams: items.RetrieveItemParams{},
+			OmitBase:      true,
+		}
+		if _, err := api.ExpandField(&component.Item, h.Items.Get, getParams); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *ComponentsHandler) expandItems(cmps []*components.Component, accountId uuid.UUID) error {
+	itemsIds := make([]uuid.UUID, 0, len(cmps))
+
+	withNonNillItem := make([]*components.Component, 0)
+	for _, component := range cmps {
+		if component.Item.ID.Valid {
+			itemsIds = append(itemsIds, component.Item.ID.UUID)
+			withNonNillItem = append(withNonNillItem, component)
+		}
+	}
+
+	itms, err := h.Items.ListByIds(items.ListByIds{
+		AccountId: accountId,
+		RequestParams: items.ListItemsByIdsParams{
+			Ids: itemsIds,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	idItemMap := make(map[uuid.UUID]*items.Item, 0)
+	for _, item := range itms {
+		idItemMap[*item.ID] = item
+	}
+
+	for _, component := range withNonNillItem {
+		if item, ok := idItemMap[component.Item.ID.UUID]; ok {
+			component.Item.Resource = item
+		}
+	}
+
+	return nil
+}
+
+func (h *FilesHandler) ExpandFieldsList(fields []string, fls []*files.File, accountId uuid.UUID) error {
+	if fields != nil && slices.Contains(fields, "item") {
+		err := h.expandItems(fls, accountId)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *FilesHandler) ExpandFields(fields []string, file *files.File, accountId uuid.UUID) error {
+	if fields != nil && slices.Contains(fields, "item") {
+		getParams := items.Get{
+			AccountId:     accountId,
+			ItemId:        file.Item.ID.UUID,
+			RequestParams: items.RetrieveItemParams{},
+			OmitBase:      true,
+		}
+		if _, err := api.ExpandField(&file.Item, h.Items.Get, getParams); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *FilesHandler) expandItems(fls []*files.File, accountId uuid.UUID) error {
+	itemsIds := make([]uuid.UUID, 0, len(fls))
+
+	withNonNillItem := make([]*files.File, 0)
+	for _, file := range fls {
+		if file.Item.ID.Valid {
+			itemsIds = append(itemsIds, file.Item.ID.UUID)
+			withNonNillItem = append(withNonNillItem, file)
+		}
+	}
+
+	itms, err := h.Items.ListByIds(items.ListByIds{
+		AccountId: accountId,
+		RequestParams: items.ListItemsByIdsParams{
+			Ids: itemsIds,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	idItemMap := make(map[uuid.UUID]*items.Item, 0)
+	for _, item := range itms {
+		idItemMap[*item.ID] = item
+	}
+
+	for _, file := range withNonNillItem {
+		if item, ok := idItemMap[file.Item.ID.UUID]; ok {
+			file.Item.Resource = item
+		}
+	}
+
+	return nil
+}
+
 func (h *ItemsHandler) ExpandFieldsList(fields []string, items []*items.Item, accountId uuid.UUID) error {
+	if fields != nil && slices.Contains(fields, "components") {
+		err := h.expandComponents(items, accountId)
+		if err != nil {
+			return err
+		}
+	}
+	if fields != nil && slices.Contains(fields, "files") {
+		err := h.expandFiles(items, accountId)
+		if err != nil {
+			return err
+		}
+	}
 	if fields != nil && slices.Contains(fields, "group") {
 		err := h.expandGroups(items, accountId)
 		if err != nil {
@@ -150,10 +299,64 @@ func (h *ItemsHandler) ExpandFieldsList(fields []string, items []*items.Item, ac
 			return err
 		}
 	}
+	if fields != nil && slices.Contains(fields, "serial_numbers") {
+		err := h.expandSerialNumbers(items, accountId)
+		if err != nil {
+			return err
+		}
+	}
+	if fields != nil && slices.Contains(fields, "stock") {
+		err := h.expandStock(items, accountId)
+		if err != nil {
+			return err
+		}
+	}
+	if fields != nil && slices.ContainsFunc(fields, isUnitField) {
+		err := h.expandUnits(fields, items, accountId)
+		if err != nil {
+			return err
+		}
+	}
+	if fields != nil && slices.Contains(fields, "parent") {
+		err := h.expandParents(items, accountId)
+		if err != nil {
+			return err
+		}
+	}
+	if fields != nil && slices.Contains(fields, "prices") {
+		err := h.expandPrices(items, accountId)
+		if err != nil {
+			return err
+		}
+	}
+	if fields != nil && slices.Contains(fields, "suppliers") {
+		err := h.expandSuppliers(items, accountId)
+		if err != nil {
+			return err
+		}
+	}
+	if fields != nil && slices.Contains(fields, "variants") {
+		err := h.expandVariants(items, accountId)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
 func (h *ItemsHandler) ExpandFields(fields []string, item *items.Item, accountId uuid.UUID) error {
+	if fields != nil && slices.Contains(fields, "components") {
+		err := h.expandComponents([]*items.Item{item}, accountId)
+		if err != nil {
+			return err
+		}
+	}
+	if fields != nil && slices.Contains(fields, "files") {
+		err := h.expandFiles([]*items.Item{item}, accountId)
+		if err != nil {
+			return err
+		}
+	}
 	if fields != nil && slices.Contains(fields, "group") {
 		getParams := groups.Get{
 			AccountId:     accountId,
@@ -187,6 +390,88 @@ func (h *ItemsHandler) ExpandFields(fields []string, item *items.Item, accountId
 			return err
 		}
 	}
+	if fields != nil && slices.Contains(fields, "serial_numbers") {
+		err := h.expandSerialNumbers([]*items.Item{item}, accountId)
+		if err != nil {
+			return err
+		}
+	}
+	if fields != nil && slices.Contains(fields, "stock") {
+		err := h.expandStock([]*items.Item{item}, accountId)
+		if err != nil {
+			return err
+		}
+	}
+	if fields != nil && slices.ContainsFunc(fields, isUnitField) {
+		err := h.expandUnits(fields, []*items.Item{item}, accountId)
+		if err != nil {
+			return err
+		}
+	}
+	if fields != nil && slices.Contains(fields, "parent") {
+		getParams := items.Get{
+			AccountId:     accountId,
+			ItemId:        item.Parent.ID.UUID,
+			RequestParams: items.RetrieveItemParams{},
+			OmitBase:      true,
+		}
+		if _, err := api.ExpandField(&item.Parent, h.Items.Get, getParams); err != nil {
+			return err
+		}
+	}
+	if fields != nil && slices.Contains(fields, "prices") {
+		err := h.expandPrices([]*items.Item{item}, accountId)
+		if err != nil {
+			return err
+		}
+	}
+	if fields != nil && slices.Contains(fields, "suppliers") {
+		err := h.expandSuppliers([]*items.Item{item}, accountId)
+		if err != nil {
+			return err
+		}
+	}
+	if fields != nil && slices.Contains(fields, "variants") {
+		err := h.expandVariants([]*items.Item{item}, accountId)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expandComponents only looks up the components of bundles, other items
+// have none.
+func (h *ItemsHandler) expandComponents(itms []*items.Item, accountId uuid.UUID) error {
+	bundlesIds := make([]uuid.UUID, 0, len(itms))
+
+	bundles := make([]*items.Item, 0)
+	for _, item := range itms {
+		if item.Type == database.ItemTypeBUNDLE {
+			bundlesIds = append(bundlesIds, *item.ID)
+			bundles = append(bundles, item)
+		}
+	}
+
+	cmps, err := h.Components.ListByBundles(components.ListByBundles{
+		AccountId: accountId,
+		RequestParams: components.ListComponentsByBundlesParams{
+			Ids: bundlesIds,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	bundleIdComponentsMap := make(map[uuid.UUID][]*components.Component, 0)
+	for _, component := range cmps {
+		bundleIdComponentsMap[component.Bundle.ID.UUID] = append(bundleIdComponentsMap[component.Bundle.ID.UUID], component)
+	}
+
+	for _, item := range bundles {
+		item.Components.Resource = bundleIdComponentsMap[*item.ID]
+	}
+
 	return nil
 }
 
@@ -260,35 +545,1445 @@ func (h *ItemsHandler) expandInventories(itms []*items.Item, accountId uuid.UUID
 	return nil
 }
 
-func (h *ItemsHandler) expandIdentifiers(itms []*items.Item, accountId uuid.UUID) error {
-	identifiersIds := make([]uuid.UUID, 0, len(itms))
+func (h *ItemsHandler) expandStock(itms []*items.Item, accountId uuid.UUID) error {
+	itemsIds := make([]uuid.UUID, 0, len(itms))
+	for _, item := range itms {
+		itemsIds = append(itemsIds, *item.ID)
+	}
+
+	invs, err := h.Inventories.ListByItems(inventories.ListByItems{
+		AccountId: accountId,
+		RequestParams: inventories.ListInventoriesByItemsParams{
+			Ids: itemsIds,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	itemIdInventoriesMap := make(map[uuid.UUID][]*inventories.Inventory, 0)
+	for _, inventory := range invs {
+		itemIdInventoriesMap[inventory.Item.ID.UUID] = append(itemIdInventoriesMap[inventory.Item.ID.UUID], inventory)
+	}
 
-	withNonNillIdentifiers := make([]*items.Item, 0)
 	for _, item := range itms {
-		if item.Identifiers.ID.Valid {
-			identifiersIds = append(identifiersIds, item.Identifiers.ID.UUID)
-			withNonNillIdentifiers = append(withNonNillIdentifiers, item)
+		item.Stock.Resource = inventories.NewStock(itemIdInventoriesMap[*item.ID])
+	}
+
+	return nil
+}
+
+// expandUnits expands the requested unit fields of the items with a single
+// lookup, as they usually share the same few units.
+func (h *ItemsHandler) expandUnits(fields []string, itms []*items.Item, accountId uuid.UUID) error {
+	unitFields := func(item *items.Item) map[string]*api.Expandable {
+		return map[string]*api.Expandable{
+			"purchase_unit": &item.PurchaseUnit,
+			"sales_unit":    &item.SalesUnit,
+			"stocking_unit": &item.StockingUnit,
 		}
 	}
 
-	invs, err := h.ItemIdentifiers.ListByIds(itemidentifiers.ListByIds{
+	unitsIds := make([]uuid.UUID, 0, len(itms))
+
+	withNonNillUnit := make([]*api.Expandable, 0)
+	for _, item := range itms {
+		for field, unit := range unitFields(item) {
+			if slices.Contains(fields, field) && unit.ID.Valid {
+				unitsIds = append(unitsIds, unit.ID.UUID)
+				withNonNillUnit = append(withNonNillUnit, unit)
+			}
+		}
+	}
+
+	unts, err := h.Units.ListByIds(units.ListByIds{
 		AccountId: accountId,
-		RequestParams: itemidentifiers.ListItemIdentifiersByIdsParams{
-			Ids: identifiersIds,
+		RequestParams: units.ListUnitsByIdsParams{
+			Ids: unitsIds,
 		},
 	})
 	if err != nil {
 		return err
 	}
 
-	idIdentifiersMap := make(map[uuid.UUID]*itemidentifiers.ItemIdentifiers, 0)
-	for _, identifiers := range invs {
-		idIdentifiersMap[*identifiers.ID] = identifiers
+	idUnitMap := make(map[uuid.UUID]*units.Unit, 0)
+	for _, unit := range unts {
+		idUnitMap[*unit.ID] = unit
 	}
 
-	for _, item := range withNonNillIdentifiers {
-		if itemIdentifiers, ok := idIdentifiersMap[item.Identifiers.ID.UUID]; ok {
-			item.Identifiers.Resource = itemIdentifiers
+	for _, expandable := range withNonNillUnit {
+		if unit, ok := idUnitMap[expandable.ID.UUID]; ok {
+			expandable.Resource = unit
+		}
+	}
+
+	return nil
+}
+
+func isUnitField(field string) bool {
+	return field == "purchase_unit" || field == "sales_unit" || field == "stocking_unit"
+}
+
+func (h *ItemsHandler) expandParents(itms []*items.Item, accountId uuid.UUID) error {
+	parentsIds := make([]uuid.UUID, 0, len(itms))
+
+	withNonNillParent := make([]*items.Item, 0)
+	for _, item := range itms {
+		if item.Parent.ID.Valid {
+			parentsIds = append(parentsIds, item.Parent.ID.UUID)
+			withNonNillParent = append(withNonNillParent, item)
+		}
+	}
+
+	parents, err := h.Items.ListByIds(items.ListByIds{
+		AccountId: accountId,
+		RequestParams: items.ListItemsByIdsParams{
+			Ids: parentsIds,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	idParentMap := make(map[uuid.UUID]*items.Item, 0)
+	for _, parent := range parents {
+		idParentMap[*parent.ID] = parent
+	}
+
+	for _, item := range withNonNillParent {
+		if parent, ok := idParentMap[item.Parent.ID.UUID]; ok {
+			item.Parent.Resource = parent
+		}
+	}
+
+	return nil
+}
+
+func (h *ItemsHandler) expandVariants(itms []*items.Item, accountId uuid.UUID) error {
+	itemsIds := make([]uuid.UUID, 0, len(itms))
+	for _, item := range itms {
+		itemsIds = append(itemsIds, *item.ID)
+	}
+
+	variants, err := h.Items.ListByParents(items.ListByParents{
+		AccountId: accountId,
+		RequestParams: items.ListItemsByParentsParams{
+			Ids: itemsIds,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	parentIdVariantsMap := make(map[uuid.UUID][]*items.Item, 0)
+	for _, variant := range variants {
+		parentIdVariantsMap[variant.Parent.ID.UUID] = append(parentIdVariantsMap[variant.Parent.ID.UUID], variant)
+	}
+
+	for _, item := range itms {
+		item.Variants.Resource = parentIdVariantsMap[*item.ID]
+	}
+
+	return nil
+}
+
+func (h *ItemsHandler) expandSerialNumbers(itms []*items.Item, accountId uuid.UUID) error {
+	itemsIds := make([]uuid.UUID, 0, len(itms))
+	for _, item := range itms {
+		itemsIds = append(itemsIds, *item.ID)
+	}
+
+	serialNumbers, err := h.SerialNumbers.ListByItems(serialnumbers.ListByItems{
+		AccountId: accountId,
+		RequestParams: serialnumbers.ListSerialNumbersByItemsParams{
+			Ids: itemsIds,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	itemIdSerialNumbersMap := make(map[uuid.UUID][]*serialnumbers.SerialNumber, 0)
+	for _, serialNumber := range serialNumbers {
+		itemIdSerialNumbersMap[serialNumber.Item.ID.UUID] = append(itemIdSerialNumbersMap[serialNumber.Item.ID.UUID], serialNumber)
+	}
+
+	for _, item := range itms {
+		item.SerialNumbers.Resource = itemIdSerialNumbersMap[*item.ID]
+	}
+
+	return nil
+}
+
+func (h *ItemsHandler) expandIdentifiers(itms []*items.Item, accountId uuid.UUID) error {
+	identifiersIds := make([]uuid.UUID, 0, len(itms))
+
+	withNonNillIdentifiers := make([]*items.Item, 0)
+	for _, item := range itms {
+		if item.Identifiers.ID.Valid {
+			identifiersIds = append(identifiersIds, item.Identifiers.ID.UUID)
+			withNonNillIdentifiers = append(withNonNillIdentifiers, item)
+		}
+	}
+
+	invs, err := h.ItemIdentifiers.ListByIds(itemidentifiers.ListByIds{
+		AccountId: accountId,
+		RequestParams: itemidentifiers.ListItemIdentifiersByIdsParams{
+			Ids: identifiersIds,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	idIdentifiersMap := make(map[uuid.UUID]*itemidentifiers.ItemIdentifiers, 0)
+	for _, identifiers := range invs {
+		idIdentifiersMap[*identifiers.ID] = identifiers
+	}
+
+	for _, item := range withNonNillIdentifiers {
+		if itemIdentifiers, ok := idIdentifiersMap[item.Identifiers.ID.UUID]; ok {
+			item.Identifiers.Resource = itemIdentifiers
+		}
+	}
+
+	return nil
+}
+
+func (h *StockMovementsHandler) ExpandFieldsList(fields []string, stockMovements []*stockmovements.StockMovement, accountId uuid.UUID) error {
+	if fields != nil && slices.Contains(fields, "inventory") {
+		err := h.expandInventories(stockMovements, accountId)
+		if err != nil {
+			return err
+		}
+	}
+	if fields != nil && slices.Contains(fields, "lot") {
+		err := h.expandLots(stockMovements, accountId)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *StockMovementsHandler) ExpandFields(fields []string, stockMovement *stockmovements.StockMovement, accountId uuid.UUID) error {
+	if fields != nil && slices.Contains(fields, "inventory") {
+		getParams := inventories.Get{
+			AccountId:     accountId,
+			InventoryId:   stockMovement.Inventory.ID.UUID,
+			RequestParams: inventories.RetrieveInventoryParams{},
+			OmitBase:      true,
+		}
+		if _, err := api.ExpandField(&stockMovement.Inventory, h.Inventories.Get, getParams); err != nil {
+			return err
+		}
+	}
+	if fields != nil && slices.Contains(fields, "lot") {
+		getParams := lots.Get{
+			AccountId:     accountId,
+			LotId:         stockMovement.Lot.ID.UUID,
+			RequestParams: lots.RetrieveLotParams{},
+			OmitBase:      true,
+		}
+		if _, err := api.ExpandField(&stockMovement.Lot, h.Lots.Get, getParams); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *StockMovementsHandler) expandInventories(stockMovements []*stockmovements.StockMovement, accountId uuid.UUID) error {
+	inventoriesIds := make([]uuid.UUID, 0, len(stockMovements))
+
+	withNonNillInventory := make([]*stockmovements.StockMovement, 0)
+	for _, stockMovement := range stockMovements {
+		if stockMovement.Inventory.ID.Valid {
+			inventoriesIds = append(inventoriesIds, stockMovement.Inventory.ID.UUID)
+			withNonNillInventory = append(withNonNillInventory, stockMovement)
+		}
+	}
+
+	invs, err := h.Inventories.ListByIds(inventories.ListByIds{
+		AccountId: accountId,
+		RequestParams: inventories.ListInventoriesByIdsParams{
+			Ids: inventoriesIds,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	idInventoryMap := make(map[uuid.UUID]*inventories.Inventory, 0)
+	for _, inventory := range invs {
+		idInventoryMap[*inventory.ID] = inventory
+	}
+
+	for _, stockMovement := range withNonNillInventory {
+		if inventory, ok := idInventoryMap[stockMovement.Inventory.ID.UUID]; ok {
+			stockMovement.Inventory.Resource = inventory
+		}
+	}
+
+	return nil
+}
+
+func (h *StockMovementsHandler) expandLots(stockMovements []*stockmovements.StockMovement, accountId uuid.UUID) error {
+	lotsIds := make([]uuid.UUID, 0, len(stockMovements))
+
+	withNonNillLot := make([]*stockmovements.StockMovement, 0)
+	for _, stockMovement := range stockMovements {
+		if stockMovement.Lot.ID.Valid {
+			lotsIds = append(lotsIds, stockMovement.Lot.ID.UUID)
+			withNonNillLot = append(withNonNillLot, stockMovement)
+		}
+	}
+
+	lts, err := h.Lots.ListByIds(lots.ListByIds{
+		AccountId: accountId,
+		RequestParams: lots.ListLotsByIdsParams{
+			Ids: lotsIds,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	idLotMap := make(map[uuid.UUID]*lots.Lot, 0)
+	for _, lot := range lts {
+		idLotMap[*lot.ID] = lot
+	}
+
+	for _, stockMovement := range withNonNillLot {
+		if lot, ok := idLotMap[stockMovement.Lot.ID.UUID]; ok {
+			stockMovement.Lot.Resource = lot
+		}
+	}
+
+	return nil
+}
+
+func (h *ReservationsHandler) ExpandFieldsList(fields []string, rsvs []*reservations.Reservation, accountId uuid.UUID) error {
+	if fields != nil && slices.Contains(fields, "inventory") {
+		err := h.expandInventories(rsvs, accountId)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *ReservationsHandler) ExpandFields(fields []string, reservation *reservations.Reservation, accountId uuid.UUID) error {
+	if fields != nil && slices.Contains(fields, "inventory") {
+		getParams := inventories.Get{
+			AccountId:     accountId,
+			InventoryId:   reservation.Inventory.ID.UUID,
+			RequestParams: inventories.RetrieveInventoryParams{},
+			OmitBase:      true,
+		}
+		if _, err := api.ExpandField(&reservation.Inventory, h.Inventories.Get, getParams); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *ReservationsHandler) expandInventories(rsvs []*reservations.Reservation, accountId uuid.UUID) error {
+	inventoriesIds := make([]uuid.UUID, 0, len(rsvs))
+
+	withNonNillInventory := make([]*reservations.Reservation, 0)
+	for _, reservation := range rsvs {
+		if reservation.Inventory.ID.Valid {
+			inventoriesIds = append(inventoriesIds, reservation.Inventory.ID.UUID)
+			withNonNillInventory = append(withNonNillInventory, reservation)
+		}
+	}
+
+	invs, err := h.Inventories.ListByIds(inventories.ListByIds{
+		AccountId: accountId,
+		RequestParams: inventories.ListInventoriesByIdsParams{
+			Ids: inventoriesIds,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	idInventoryMap := make(map[uuid.UUID]*inventories.Inventory, 0)
+	for _, inventory := range invs {
+		idInventoryMap[*inventory.ID] = inventory
+	}
+
+	for _, reservation := range withNonNillInventory {
+		if inventory, ok := idInventoryMap[reservation.Inventory.ID.UUID]; ok {
+			reservation.Inventory.Resource = inventory
+		}
+	}
+
+	return nil
+}
+
+func (h *InventoriesHandler) ExpandFieldsList(fields []string, invs []*inventories.Inventory, accountId uuid.UUID) error {
+	if fields != nil && slices.Contains(fields, "item") {
+		err := h.expandItems(invs, accountId)
+		if err != nil {
+			return err
+		}
+	}
+	if fields != nil && slices.Contains(fields, "location") {
+		err := h.expandLocations(invs, accountId)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *InventoriesHandler) ExpandFields(fields []string, inventory *inventories.Inventory, accountId uuid.UUID) error {
+	if fields != nil && slices.Contains(fields, "item") {
+		getParams := items.Get{
+			AccountId:     accountId,
+			ItemId:        inventory.Item.ID.UUID,
+			RequestParams: items.RetrieveItemParams{},
+			OmitBase:      true,
+		}
+		if _, err := api.ExpandField(&inventory.Item, h.Items.Get, getParams); err != nil {
+			return err
+		}
+	}
+	if fields != nil && slices.Contains(fields, "location") {
+		getParams := locations.Get{
+			AccountId:     accountId,
+			LocationId:    inventory.Location.ID.UUID,
+			RequestParams: locations.RetrieveLocationParams{},
+			OmitBase:      true,
+		}
+		if _, err := api.ExpandField(&inventory.Location, h.Locations.Get, getParams); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *InventoriesHandler) expandItems(invs []*inventories.Inventory, accountId uuid.UUID) error {
+	itemsIds := make([]uuid.UUID, 0, len(invs))
+
+	withNonNillItem := make([]*inventories.Inventory, 0)
+	for _, inventory := range invs {
+		if inventory.Item.ID.Valid {
+			itemsIds = append(itemsIds, inventory.Item.ID.UUID)
+			withNonNillItem = append(withNonNillItem, inventory)
+		}
+	}
+
+	itms, err := h.Items.ListByIds(items.ListByIds{
+		AccountId: accountId,
+		RequestParams: items.ListItemsByIdsParams{
+			Ids: itemsIds,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	idItemMap := make(map[uuid.UUID]*items.Item, 0)
+	for _, item := range itms {
+		idItemMap[*item.ID] = item
+	}
+
+	for _, inventory := range withNonNillItem {
+		if item, ok := idItemMap[inventory.Item.ID.UUID]; ok {
+			inventory.Item.Resource = item
+		}
+	}
+
+	return nil
+}
+
+func (h *InventoriesHandler) expandLocations(invs []*inventories.Inventory, accountId uuid.UUID) error {
+	locationsIds := make([]uuid.UUID, 0, len(invs))
+
+	withNonNillLocation := make([]*inventories.Inventory, 0)
+	for _, inventory := range invs {
+		if inventory.Location.ID.Valid {
+			locationsIds = append(locationsIds, inventory.Location.ID.UUID)
+			withNonNillLocation = append(withNonNillLocation, inventory)
+		}
+	}
+
+	locs, err := h.Locations.ListByIds(locations.ListByIds{
+		AccountId: accountId,
+		RequestParams: locations.ListLocationsByIdsParams{
+			Ids: locationsIds,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	idLocationMap := make(map[uuid.UUID]*locations.Location, 0)
+	for _, location := range locs {
+		idLocationMap[*location.ID] = location
+	}
+
+	for _, inventory := range withNonNillLocation {
+		if location, ok := idLocationMap[inventory.Location.ID.UUID]; ok {
+			inventory.Location.Resource = location
+		}
+	}
+
+	return nil
+}
+
+func (h *TransfersHandler) ExpandFieldsList(fields []string, trfs []*transfers.Transfer, accountId uuid.UUID) error {
+	if fields != nil && (slices.Contains(fields, "destination_inventory") || slices.Contains(fields, "source_inventory")) {
+		err := h.expandInventories(fields, trfs, accountId)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *TransfersHandler) ExpandFields(fields []string, transfer *transfers.Transfer, accountId uuid.UUID) error {
+	if fields != nil && slices.Contains(fields, "destination_inventory") {
+		getParams := inventories.Get{
+			AccountId:     accountId,
+			InventoryId:   transfer.DestinationInventory.ID.UUID,
+			RequestParams: inventories.RetrieveInventoryParams{},
+			OmitBase:      true,
+		}
+		if _, err := api.ExpandField(&transfer.DestinationInventory, h.Inventories.Get, getParams); err != nil {
+			return err
+		}
+	}
+	if fields != nil && slices.Contains(fields, "source_inventory") {
+		getParams := inventories.Get{
+			AccountId:     accountId,
+			InventoryId:   transfer.SourceInventory.ID.UUID,
+			RequestParams: inventories.RetrieveInventoryParams{},
+			OmitBase:      true,
+		}
+		if _, err := api.ExpandField(&transfer.SourceInventory, h.Inventories.Get, getParams); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *TransfersHandler) expandInventories(fields []string, trfs []*transfers.Transfer, accountId uuid.UUID) error {
+	inventoriesIds := make([]uuid.UUID, 0, len(trfs)*2)
+	for _, transfer := range trfs {
+		inventoriesIds = append(inventoriesIds, transfer.DestinationInventory.ID.UUID, transfer.SourceInventory.ID.UUID)
+	}
+
+	invs, err := h.Inventories.ListByIds(inventories.ListByIds{
+		AccountId: accountId,
+		RequestParams: inventories.ListInventoriesByIdsParams{
+			Ids: inventoriesIds,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	idInventoryMap := make(map[uuid.UUID]*inventories.Inventory, 0)
+	for _, inventory := range invs {
+		idInventoryMap[*inventory.ID] = inventory
+	}
+
+	for _, transfer := range trfs {
+		if inventory, ok := idInventoryMap[transfer.DestinationInventory.ID.UUID]; ok && slices.Contains(fields, "destination_inventory") {
+			transfer.DestinationInventory.Resource = inventory
+		}
+		if inventory, ok := idInventoryMap[transfer.SourceInventory.ID.UUID]; ok && slices.Contains(fields, "source_inventory") {
+			transfer.SourceInventory.Resource = inventory
+		}
+	}
+
+	return nil
+}
+
+func (h *StockCountsHandler) ExpandFieldsList(fields []string, stockCounts []*stockcounts.StockCount, accountId uuid.UUID) error {
+	if fields != nil && slices.Contains(fields, "group") {
+		err := h.expandGroups(stockCounts, accountId)
+		if err != nil {
+			return err
+		}
+	}
+	if fields != nil && slices.Contains(fields, "location") {
+		err := h.expandLocations(stockCounts, accountId)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *StockCountsHandler) ExpandFields(fields []string, stockCount *stockcounts.StockCount, accountId uuid.UUID) error {
+	if fields != nil && slices.Contains(fields, "group") {
+		getParams := groups.Get{
+			AccountId:     accountId,
+			GroupId:       stockCount.Group.ID.UUID,
+			RequestParams: groups.RetrieveGroupParams{},
+			OmitBase:      true,
+		}
+		if _, err := api.ExpandField(&stockCount.Group, h.Groups.Get, getParams); err != nil {
+			return err
+		}
+	}
+	if fields != nil && slices.Contains(fields, "location") {
+		getParams := locations.Get{
+			AccountId:     accountId,
+			LocationId:    stockCount.Location.ID.UUID,
+			RequestParams: locations.RetrieveLocationParams{},
+			OmitBase:      true,
+		}
+		if _, err := api.ExpandField(&stockCount.Location, h.Locations.Get, getParams); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *StockCountsHandler) expandGroups(stockCounts []*stockcounts.StockCount, accountId uuid.UUID) error {
+	groupsIds := make([]uuid.UUID, 0, len(stockCounts))
+
+	withNonNillGroup := make([]*stockcounts.StockCount, 0)
+	for _, stockCount := range stockCounts {
+		if stockCount.Group.ID.Valid {
+			groupsIds = append(groupsIds, stockCount.Group.ID.UUID)
+			withNonNillGroup = append(withNonNillGroup, stockCount)
+		}
+	}
+
+	grps, err := h.Groups.ListByIds(groups.ListByIds{
+		AccountId: accountId,
+		RequestParams: groups.ListGroupsByIdsParams{
+			Ids: groupsIds,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	idGroupMap := make(map[uuid.UUID]*groups.Group, 0)
+	for _, group := range grps {
+		idGroupMap[*group.ID] = group
+	}
+
+	for _, stockCount := range withNonNillGroup {
+		if group, ok := idGroupMap[stockCount.Group.ID.UUID]; ok {
+			stockCount.Group.Resource = group
+		}
+	}
+
+	return nil
+}
+
+func (h *StockCountsHandler) expandLocations(stockCounts []*stockcounts.StockCount, accountId uuid.UUID) error {
+	locationsIds := make([]uuid.UUID, 0, len(stockCounts))
+
+	withNonNillLocation := make([]*stockcounts.StockCount, 0)
+	for _, stockCount := range stockCounts {
+		if stockCount.Location.ID.Valid {
+			locationsIds = append(locationsIds, stockCount.Location.ID.UUID)
+			withNonNillLocation = append(withNonNillLocation, stockCount)
+		}
+	}
+
+	locs, err := h.Locations.ListByIds(locations.ListByIds{
+		AccountId: accountId,
+		RequestParams: locations.ListLocationsByIdsParams{
+			Ids: locationsIds,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	idLocationMap := make(map[uuid.UUID]*locations.Location, 0)
+	for _, location := range locs {
+		idLocationMap[*location.ID] = location
+	}
+
+	for _, stockCount := range withNonNillLocation {
+		if location, ok := idLocationMap[stockCount.Location.ID.UUID]; ok {
+			stockCount.Location.Resource = location
+		}
+	}
+
+	return nil
+}
+
+func (h *LotsHandler) ExpandFieldsList(fields []string, lts []*lots.Lot, accountId uuid.UUID) error {
+	if fields != nil && slices.Contains(fields, "inventory") {
+		err := h.expandInventories(lts, accountId)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *LotsHandler) ExpandFields(fields []string, lot *lots.Lot, accountId uuid.UUID) error {
+	if fields != nil && slices.Contains(fields, "inventory") {
+		getParams := inventories.Get{
+			AccountId:     accountId,
+			InventoryId:   lot.Inventory.ID.UUID,
+			RequestParams: inventories.RetrieveInventoryParams{},
+			OmitBase:      true,
+		}
+		if _, err := api.ExpandField(&lot.Inventory, h.Inventories.Get, getParams); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *LotsHandler) expandInventories(lts []*lots.Lot, accountId uuid.UUID) error {
+	inventoriesIds := make([]uuid.UUID, 0, len(lts))
+
+	withNonNillInventory := make([]*lots.Lot, 0)
+	for _, lot := range lts {
+		if lot.Inventory.ID.Valid {
+			inventoriesIds = append(inventoriesIds, lot.Inventory.ID.UUID)
+			withNonNillInventory = append(withNonNillInventory, lot)
+		}
+	}
+
+	invs, err := h.Inventories.ListByIds(inventories.ListByIds{
+		AccountId: accountId,
+		RequestParams: inventories.ListInventoriesByIdsParams{
+			Ids: inventoriesIds,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	idInventoryMap := make(map[uuid.UUID]*inventories.Inventory, 0)
+	for _, inventory := range invs {
+		idInventoryMap[*inventory.ID] = inventory
+	}
+
+	for _, lot := range withNonNillInventory {
+		if inventory, ok := idInventoryMap[lot.Inventory.ID.UUID]; ok {
+			lot.Inventory.Resource = inventory
+		}
+	}
+
+	return nil
+}
+
+func (h *SerialNumbersHandler) ExpandFieldsList(fields []string, serialNumbers []*serialnumbers.SerialNumber, accountId uuid.UUID) error {
+	if fields != nil && slices.Contains(fields, "inventory") {
+		err := h.expandInventories(serialNumbers, accountId)
+		if err != nil {
+			return err
+		}
+	}
+	if fields != nil && slices.Contains(fields, "item") {
+		err := h.expandItems(serialNumbers, accountId)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *SerialNumbersHandler) ExpandFields(fields []string, serialNumber *serialnumbers.SerialNumber, accountId uuid.UUID) error {
+	if fields != nil && slices.Contains(fields, "inventory") {
+		getParams := inventories.Get{
+			AccountId:     accountId,
+			InventoryId:   serialNumber.Inventory.ID.UUID,
+			RequestParams: inventories.RetrieveInventoryParams{},
+			OmitBase:      true,
+		}
+		if _, err := api.ExpandField(&serialNumber.Inventory, h.Inventories.Get, getParams); err != nil {
+			return err
+		}
+	}
+	if fields != nil && slices.Contains(fields, "item") {
+		getParams := items.Get{
+			AccountId:     accountId,
+			ItemId:        serialNumber.Item.ID.UUID,
+			RequestParams: items.RetrieveItemParams{},
+			OmitBase:      true,
+		}
+		if _, err := api.ExpandField(&serialNumber.Item, h.Items.Get, getParams); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *SerialNumbersHandler) expandInventories(serialNumbers []*serialnumbers.SerialNumber, accountId uuid.UUID) error {
+	inventoriesIds := make([]uuid.UUID, 0, len(serialNumbers))
+
+	withNonNillInventory := make([]*serialnumbers.SerialNumber, 0)
+	for _, serialNumber := range serialNumbers {
+		if serialNumber.Inventory.ID.Valid {
+			inventoriesIds = append(inventoriesIds, serialNumber.Inventory.ID.UUID)
+			withNonNillInventory = append(withNonNillInventory, serialNumber)
+		}
+	}
+
+	invs, err := h.Inventories.ListByIds(inventories.ListByIds{
+		AccountId: accountId,
+		RequestParams: inventories.ListInventoriesByIdsParams{
+			Ids: inventoriesIds,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	idInventoryMap := make(map[uuid.UUID]*inventories.Inventory, 0)
+	for _, inventory := range invs {
+		idInventoryMap[*inventory.ID] = inventory
+	}
+
+	for _, serialNumber := range withNonNillInventory {
+		if inventory, ok := idInventoryMap[serialNumber.Inventory.ID.UUID]; ok {
+			serialNumber.Inventory.Resource = inventory
+		}
+	}
+
+	return nil
+}
+
+func (h *SerialNumbersHandler) expandItems(serialNumbers []*serialnumbers.SerialNumber, accountId uuid.UUID) error {
+	itemsIds := make([]uuid.UUID, 0, len(serialNumbers))
+
+	withNonNillItem := make([]*serialnumbers.SerialNumber, 0)
+	for _, serialNumber := range serialNumbers {
+		if serialNumber.Item.ID.Valid {
+			itemsIds = append(itemsIds, serialNumber.Item.ID.UUID)
+			withNonNillItem = append(withNonNillItem, serialNumber)
+		}
+	}
+
+	itms, err := h.Items.ListByIds(items.ListByIds{
+		AccountId: accountId,
+		RequestParams: items.ListItemsByIdsParams{
+			Ids: itemsIds,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	idItemMap := make(map[uuid.UUID]*items.Item, 0)
+	for _, item := range itms {
+		idItemMap[*item.ID] = item
+	}
+
+	for _, serialNumber := range withNonNillItem {
+		if item, ok := idItemMap[serialNumber.Item.ID.UUID]; ok {
+			serialNumber.Item.Resource = item
+		}
+	}
+
+	return nil
+}
+
+// ExpandValuationFields expands the groups and items of every line of a
+// valuation report.
+func (h *ReportsHandler) ExpandValuationFields(fields []string, report *valuation.Valuation, accountId uuid.UUID) error {
+	if fields != nil && slices.Contains(fields, "group") {
+		groupsIds := make([]uuid.UUID, 0, len(report.Groups))
+		for _, line := range report.Groups {
+			groupsIds = append(groupsIds, line.Group.ID.UUID)
+		}
+
+		grps, err := h.Groups.ListByIds(groups.ListByIds{
+			AccountId: accountId,
+			RequestParams: groups.ListGroupsByIdsParams{
+				Ids: groupsIds,
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		idGroupMap := make(map[uuid.UUID]*groups.Group, 0)
+		for _, group := range grps {
+			idGroupMap[*group.ID] = group
+		}
+
+		for _, line := range report.Groups {
+			if group, ok := idGroupMap[line.Group.ID.UUID]; ok {
+				line.Group.Resource = group
+			}
+		}
+		for _, line := range report.Items {
+			if group, ok := idGroupMap[line.Group.ID.UUID]; ok && line.Group.ID.Valid {
+				line.Group.Resource = group
+			}
+		}
+	}
+	if fields != nil && slices.Contains(fields, "item") {
+		itemsIds := make([]uuid.UUID, 0, len(report.Items))
+		for _, line := range report.Items {
+			itemsIds = append(itemsIds, line.Item.ID.UUID)
+		}
+
+		itms, err := h.Items.ListByIds(items.ListByIds{
+			AccountId: accountId,
+			RequestParams: items.ListItemsByIdsParams{
+				Ids: itemsIds,
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		idItemMap := make(map[uuid.UUID]*items.Item, 0)
+		for _, item := range itms {
+			idItemMap[*item.ID] = item
+		}
+
+		for _, line := range report.Items {
+			if item, ok := idItemMap[line.Item.ID.UUID]; ok {
+				line.Item.Resource = item
+			}
+		}
+	}
+	return nil
+}
+
+func (h *PriceListsHandler) ExpandPriceFieldsList(fields []string, prices []*pricelists.Price, accountId uuid.UUID) error {
+	if fields != nil && slices.Contains(fields, "item") {
+		err := h.expandPriceItems(prices, accountId)
+		if err != nil {
+			return err
+		}
+	}
+	if fields != nil && slices.Contains(fields, "price_list") {
+		for _, price := range prices {
+			if err := h.expandPriceList(&price.PriceList, accountId); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (h *PriceListsHandler) ExpandPriceFields(fields []string, price *pricelists.Price, accountId uuid.UUID) error {
+	if fields != nil && slices.Contains(fields, "item") {
+		if err := h.expandItem(&price.Item, accountId); err != nil {
+			return err
+		}
+	}
+	if fields != nil && slices.Contains(fields, "price_list") {
+		if err := h.expandPriceList(&price.PriceList, accountId); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *PriceListsHandler) ExpandQuoteFields(fields []string, quote *pricelists.Quote, accountId uuid.UUID) error {
+	if fields != nil && slices.Contains(fields, "item") {
+		if err := h.expandItem(&quote.Item, accountId); err != nil {
+			return err
+		}
+	}
+	if fields != nil && slices.Contains(fields, "price_list") {
+		if err := h.expandPriceList(&quote.PriceList, accountId); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *PriceListsHandler) expandItem(item *api.Expandable, accountId uuid.UUID) error {
+	getParams := items.Get{
+		AccountId:     accountId,
+		ItemId:        item.ID.UUID,
+		RequestParams: items.RetrieveItemParams{},
+		OmitBase:      true,
+	}
+	_, err := api.ExpandField(item, h.Items.Get, getParams)
+	return err
+}
+
+func (h *PriceListsHandler) expandPriceList(priceList *api.Expandable, accountId uuid.UUID) error {
+	getParams := pricelists.Get{
+		AccountId:     accountId,
+		PriceListId:   priceList.ID.UUID,
+		RequestParams: pricelists.RetrievePriceListParams{},
+		OmitBase:      true,
+	}
+	_, err := api.ExpandField(priceList, h.PriceLists.Get, getParams)
+	return err
+}
+
+func (h *PriceListsHandler) expandPriceItems(prices []*pricelists.Price, accountId uuid.UUID) error {
+	itemsIds := make([]uuid.UUID, 0, len(prices))
+
+	withNonNillItem := make([]*pricelists.Price, 0)
+	for _, price := range prices {
+		if price.Item.ID.Valid {
+			itemsIds = append(itemsIds, price.Item.ID.UUID)
+			withNonNillItem = append(withNonNillItem, price)
+		}
+	}
+
+	itms, err := h.Items.ListByIds(items.ListByIds{
+		AccountId: accountId,
+		RequestParams: items.ListItemsByIdsParams{
+			Ids: itemsIds,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	idItemMap := make(map[uuid.UUID]*items.Item, 0)
+	for _, item := range itms {
+		idItemMap[*item.ID] = item
+	}
+
+	for _, price := range withNonNillItem {
+		if item, ok := idItemMap[price.Item.ID.UUID]; ok {
+			price.Item.Resource = item
+		}
+	}
+
+	return nil
+}
+
+func (h *ItemsHandler) expandPrices(itms []*items.Item, accountId uuid.UUID) error {
+	itemsIds := make([]uuid.UUID, 0, len(itms))
+	for _, item := range itms {
+		itemsIds = append(itemsIds, *item.ID)
+	}
+
+	prices, err := h.PriceLists.ListPricesByItems(pricelists.ListPricesByItems{
+		AccountId: accountId,
+		RequestParams: pricelists.ListPricesByItemsParams{
+			Ids: itemsIds,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	itemIdPricesMap := make(map[uuid.UUID][]*pricelists.Price, 0)
+	for _, price := range prices {
+		itemIdPricesMap[price.Item.ID.UUID] = append(itemIdPricesMap[price.Item.ID.UUID], price)
+	}
+
+	for _, item := range itms {
+		item.Prices.Resource = itemIdPricesMap[*item.ID]
+	}
+
+	return nil
+}
+
+func (h *ItemsHandler) expandFiles(itms []*items.Item, accountId uuid.UUID) error {
+	itemsIds := make([]uuid.UUID, 0, len(itms))
+	for _, item := range itms {
+		itemsIds = append(itemsIds, *item.ID)
+	}
+
+	fls, err := h.Files.ListByItems(files.ListByItems{
+		AccountId: accountId,
+		RequestParams: files.ListFilesByItemsParams{
+			Ids: itemsIds,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	itemIdFilesMap := make(map[uuid.UUID][]*files.File, 0)
+	for _, file := range fls {
+		itemIdFilesMap[file.Item.ID.UUID] = append(itemIdFilesMap[file.Item.ID.UUID], file)
+	}
+
+	for _, item := range itms {
+		item.Files.Resource = itemIdFilesMap[*item.ID]
+	}
+
+	return nil
+}
+
+func (h *ItemsHandler) expandSuppliers(itms []*items.Item, accountId uuid.UUID) error {
+	itemsIds := make([]uuid.UUID, 0, len(itms))
+	for _, item := range itms {
+		itemsIds = append(itemsIds, *item.ID)
+	}
+
+	itemSuppliers, err := h.Suppliers.ListItemSuppliersByItems(suppliers.ListItemSuppliersByItems{
+		AccountId: accountId,
+		RequestParams: suppliers.ListItemSuppliersByItemsParams{
+			Ids: itemsIds,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	itemIdSuppliersMap := make(map[uuid.UUID][]*suppliers.ItemSupplier, 0)
+	for _, itemSupplier := range itemSuppliers {
+		itemIdSuppliersMap[itemSupplier.Item.ID.UUID] = append(itemIdSuppliersMap[itemSupplier.Item.ID.UUID], itemSupplier)
+	}
+
+	for _, item := range itms {
+		item.Suppliers.Resource = itemIdSuppliersMap[*item.ID]
+	}
+
+	return nil
+}
+
+func (h *SuppliersHandler) ExpandItemSupplierFieldsList(fields []string, itemSuppliers []*suppliers.ItemSupplier, accountId uuid.UUID) error {
+	if fields != nil && slices.Contains(fields, "item") {
+		for _, itemSupplier := range itemSuppliers {
+			if err := h.expandItem(&itemSupplier.Item, accountId); err != nil {
+				return err
+			}
+		}
+	}
+	if fields != nil && slices.Contains(fields, "supplier") {
+		err := h.expandSuppliers(itemSuppliers, accountId)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *SuppliersHandler) ExpandItemSupplierFields(fields []string, itemSupplier *suppliers.ItemSupplier, accountId uuid.UUID) error {
+	if fields != nil && slices.Contains(fields, "item") {
+		if err := h.expandItem(&itemSupplier.Item, accountId); err != nil {
+			return err
+		}
+	}
+	if fields != nil && slices.Contains(fields, "supplier") {
+		getParams := suppliers.Get{
+			AccountId:     accountId,
+			SupplierId:    itemSupplier.Supplier.ID.UUID,
+			RequestParams: suppliers.RetrieveSupplierParams{},
+			OmitBase:      true,
+		}
+		if _, err := api.ExpandField(&itemSupplier.Supplier, h.Suppliers.Get, getParams); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *SuppliersHandler) expandItem(item *api.Expandable, accountId uuid.UUID) error {
+	getParams := items.Get{
+		AccountId:     accountId,
+		ItemId:        item.ID.UUID,
+		RequestParams: items.RetrieveItemParams{},
+		OmitBase:      true,
+	}
+	_, err := api.ExpandField(item, h.Items.Get, getParams)
+	return err
+}
+
+func (h *SuppliersHandler) expandSuppliers(itemSuppliers []*suppliers.ItemSupplier, accountId uuid.UUID) error {
+	suppliersIds := make([]uuid.UUID, 0, len(itemSuppliers))
+
+	withNonNillSupplier := make([]*suppliers.ItemSupplier, 0)
+	for _, itemSupplier := range itemSuppliers {
+		if itemSupplier.Supplier.ID.Valid {
+			suppliersIds = append(suppliersIds, itemSupplier.Supplier.ID.UUID)
+			withNonNillSupplier = append(withNonNillSupplier, itemSupplier)
+		}
+	}
+
+	spls, err := h.Suppliers.ListByIds(suppliers.ListByIds{
+		AccountId: accountId,
+		RequestParams: suppliers.ListSuppliersByIdsParams{
+			Ids: suppliersIds,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	idSupplierMap := make(map[uuid.UUID]*suppliers.Supplier, 0)
+	for _, supplier := range spls {
+		idSupplierMap[*supplier.ID] = supplier
+	}
+
+	for _, itemSupplier := range withNonNillSupplier {
+		if supplier, ok := idSupplierMap[itemSupplier.Supplier.ID.UUID]; ok {
+			itemSupplier.Supplier.Resource = supplier
+		}
+	}
+
+	return nil
+}
+
+func (h *PurchaseOrdersHandler) ExpandFieldsList(fields []string, purchaseOrders []*purchaseorders.PurchaseOrder, accountId uuid.UUID) error {
+	if fields != nil && slices.Contains(fields, "supplier") {
+		err := h.expandSuppliers(purchaseOrders, accountId)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *PurchaseOrdersHandler) ExpandFields(fields []string, purchaseOrder *purchaseorders.PurchaseOrder, accountId uuid.UUID) error {
+	if fields != nil && slices.Contains(fields, "supplier") {
+		getParams := suppliers.Get{
+			AccountId:     accountId,
+			SupplierId:    purchaseOrder.Supplier.ID.UUID,
+			RequestParams: suppliers.RetrieveSupplierParams{},
+			OmitBase:      true,
+		}
+		if _, err := api.ExpandField(&purchaseOrder.Supplier, h.Suppliers.Get, getParams); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *PurchaseOrdersHandler) expandSuppliers(purchaseOrders []*purchaseorders.PurchaseOrder, accountId uuid.UUID) error {
+	suppliersIds := make([]uuid.UUID, 0, len(purchaseOrders))
+
+	withNonNillSupplier := make([]*purchaseorders.PurchaseOrder, 0)
+	for _, purchaseOrder := range purchaseOrders {
+		if purchaseOrder.Supplier.ID.Valid {
+			suppliersIds = append(suppliersIds, purchaseOrder.Supplier.ID.UUID)
+			withNonNillSupplier = append(withNonNillSupplier, purchaseOrder)
+		}
+	}
+
+	spls, err := h.Suppliers.ListByIds(suppliers.ListByIds{
+		AccountId: accountId,
+		RequestParams: suppliers.ListSuppliersByIdsParams{
+			Ids: suppliersIds,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	idSupplierMap := make(map[uuid.UUID]*suppliers.Supplier, 0)
+	for _, supplier := range spls {
+		idSupplierMap[*supplier.ID] = supplier
+	}
+
+	for _, purchaseOrder := range withNonNillSupplier {
+		if supplier, ok := idSupplierMap[purchaseOrder.Supplier.ID.UUID]; ok {
+			purchaseOrder.Supplier.Resource = supplier
+		}
+	}
+
+	return nil
+}
+
+func (h *OrdersHandler) ExpandFields(fields []string, order *orders.Order, accountId uuid.UUID) error {
+	if fields != nil && slices.Contains(fields, "lines.inventory") {
+		err := h.expandLineInventories(order.Lines, accountId)
+		if err != nil {
+			return err
+		}
+	}
+	if fields != nil && slices.Contains(fields, "lines.item") {
+		err := h.expandLineItems(order.Lines, accountId)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *OrdersHandler) expandLineInventories(lines []*orders.OrderLine, accountId uuid.UUID) error {
+	inventoriesIds := make([]uuid.UUID, 0, len(lines))
+
+	withNonNillInventory := make([]*orders.OrderLine, 0)
+	for _, line := range lines {
+		if line.Inventory.ID.Valid {
+			inventoriesIds = append(inventoriesIds, line.Inventory.ID.UUID)
+			withNonNillInventory = append(withNonNillInventory, line)
+		}
+	}
+
+	invs, err := h.Inventories.ListByIds(inventories.ListByIds{
+		AccountId: accountId,
+		RequestParams: inventories.ListInventoriesByIdsParams{
+			Ids: inventoriesIds,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	idInventoryMap := make(map[uuid.UUID]*inventories.Inventory, 0)
+	for _, inventory := range invs {
+		idInventoryMap[*inventory.ID] = inventory
+	}
+
+	for _, line := range withNonNillInventory {
+		if inventory, ok := idInventoryMap[line.Inventory.ID.UUID]; ok {
+			line.Inventory.Resource = inventory
+		}
+	}
+
+	return nil
+}
+
+func (h *OrdersHandler) expandLineItems(lines []*orders.OrderLine, accountId uuid.UUID) error {
+	itemsIds := make([]uuid.UUID, 0, len(lines))
+
+	withNonNillItem := make([]*orders.OrderLine, 0)
+	for _, line := range lines {
+		if line.Item.ID.Valid {
+			itemsIds = append(itemsIds, line.Item.ID.UUID)
+			withNonNillItem = append(withNonNillItem, line)
+		}
+	}
+
+	itms, err := h.Items.ListByIds(items.ListByIds{
+		AccountId: accountId,
+		RequestParams: items.ListItemsByIdsParams{
+			Ids: itemsIds,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	idItemMap := make(map[uuid.UUID]*items.Item, 0)
+	for _, item := range itms {
+		idItemMap[*item.ID] = item
+	}
+
+	for _, line := range withNonNillItem {
+		if item, ok := idItemMap[line.Item.ID.UUID]; ok {
+			line.Item.Resource = item
+		}
+	}
+
+	return nil
+}
+
+func (h *ReturnsHandler) ExpandFieldsList(fields []string, rets []*returns.Return, accountId uuid.UUID) error {
+	if fields != nil && slices.Contains(fields, "inventory") {
+		err := h.expandInventories(rets, accountId)
+		if err != nil {
+			return err
+		}
+	}
+	if fields != nil && slices.Contains(fields, "item") {
+		err := h.expandItems(rets, accountId)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *ReturnsHandler) ExpandFields(fields []string, ret *returns.Return, accountId uuid.UUID) error {
+	if fields != nil && slices.Contains(fields, "inventory") && ret.Inventory.ID.Valid {
+		getParams := inventories.Get{
+			AccountId:     accountId,
+			InventoryId:   ret.Inventory.ID.UUID,
+			RequestParams: inventories.RetrieveInventoryParams{},
+			OmitBase:      true,
+		}
+		if _, err := api.ExpandField(&ret.Inventory, h.Inventories.Get, getParams); err != nil {
+			return err
+		}
+	}
+	if fields != nil && slices.Contains(fields, "item") && ret.Item.ID.Valid {
+		getParams := items.Get{
+			AccountId:     accountId,
+			ItemId:        ret.Item.ID.UUID,
+			RequestParams: items.RetrieveItemParams{},
+			OmitBase:      true,
+		}
+		if _, err := api.ExpandField(&ret.Item, h.Items.Get, getParams); err != nil {
+			return err
+		}
+	}
+	if fields != nil && slices.Contains(fields, "order") && ret.Order.ID.Valid {
+		getParams := orders.Get{
+			AccountId:     accountId,
+			OrderId:       ret.Order.ID.UUID,
+			RequestParams: orders.RetrieveOrderParams{},
+			OmitBase:      true,
+		}
+		if _, err := api.ExpandField(&ret.Order, h.Orders.Get, getParams); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *ReturnsHandler) expandInventories(rets []*returns.Return, accountId uuid.UUID) error {
+	inventoriesIds := make([]uuid.UUID, 0, len(rets))
+
+	withNonNillInventory := make([]*returns.Return, 0)
+	for _, ret := range rets {
+		if ret.Inventory.ID.Valid {
+			inventoriesIds = append(inventoriesIds, ret.Inventory.ID.UUID)
+			withNonNillInventory = append(withNonNillInventory, ret)
+		}
+	}
+
+	invs, err := h.Inventories.ListByIds(inventories.ListByIds{
+		AccountId: accountId,
+		RequestParams: inventories.ListInventoriesByIdsParams{
+			Ids: inventoriesIds,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	idInventoryMap := make(map[uuid.UUID]*inventories.Inventory, 0)
+	for _, inventory := range invs {
+		idInventoryMap[*inventory.ID] = inventory
+	}
+
+	for _, ret := range withNonNillInventory {
+		if inventory, ok := idInventoryMap[ret.Inventory.ID.UUID]; ok {
+			ret.Inventory.Resource = inventory
+		}
+	}
+
+	return nil
+}
+
+func (h *ReturnsHandler) expandItems(rets []*returns.Return, accountId uuid.UUID) error {
+	itemsIds := make([]uuid.UUID, 0, len(rets))
+
+	withNonNillItem := make([]*returns.Return, 0)
+	for _, ret := range rets {
+		if ret.Item.ID.Valid {
+			itemsIds = append(itemsIds, ret.Item.ID.UUID)
+			withNonNillItem = append(withNonNillItem, ret)
+		}
+	}
+
+	itms, err := h.Items.ListByIds(items.ListByIds{
+		AccountId: accountId,
+		RequestParams: items.ListItemsByIdsParams{
+			Ids: itemsIds,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	idItemMap := make(map[uuid.UUID]*items.Item, 0)
+	for _, item := range itms {
+		idItemMap[*item.ID] = item
+	}
+
+	for _, ret := range withNonNillItem {
+		if item, ok := idItemMap[ret.Item.ID.UUID]; ok {
+			ret.Item.Resource = item
 		}
 	}
 
@@ -0,0 +1,278 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/d-darac/inventory-api/internal/files"
+	"github.com/d-darac/inventory-api/internal/items"
+	"github.com/d-darac/inventory-api/internal/storage"
+	"github.com/d-darac/inventory-api/middleware"
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+// maxFieldSize caps the form fields of an upload, which are all short.
+const maxFieldSize = 1024
+
+type FilesHandler struct {
+	Files     *files.FilesService
+	Items     *items.ItemsService
+	validator *api.Validator
+}
+
+func NewFilesHandler(db *database.Queries, conn *sql.DB, store storage.Storage) *FilesHandler {
+	return &FilesHandler{
+		Files:     files.NewFilesService(db, store),
+		Items:     items.NewItemsService(db, conn),
+		validator: api.NewValidator(),
+	}
+}
+
+// Create takes a multipart/form-data upload. The "filename", "item" and
+// "expand" fields have to come before the "file" part, which is streamed
+// to storage as it's read; anything after it is ignored.
+func (h *FilesHandler) Create(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	params := files.CreateFileParams{}
+
+	mr, err := r.MultipartReader()
+	if err != nil {
+		api.ResError(w, &api.AppError{
+			Message: "Files have to be uploaded as multipart/form-data.",
+			Status:  http.StatusBadRequest,
+			Type:    api.InvalidRequestError,
+		})
+		return
+	}
+
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			api.ResError(w, uploadError(err))
+			return
+		}
+
+		if part.FormName() != "file" {
+			value, err := io.ReadAll(io.LimitReader(part, maxFieldSize))
+			if err != nil {
+				api.ResError(w, uploadError(err))
+				return
+			}
+			switch part.FormName() {
+			case "filename":
+				params.Filename = string(value)
+			case "item":
+				item := string(value)
+				params.Item = &item
+			case "expand":
+				params.Expand = append(params.Expand, string(value))
+			}
+			continue
+		}
+
+		if params.Filename == "" {
+			params.Filename = part.FileName()
+		}
+
+		if errs := h.validator.ValidateRequestParams(params); errs != nil {
+			api.ResErrorList(w, errs)
+			return
+		}
+
+		file, err := h.Files.Create(files.Create{AccountId: accountId, RequestParams: params, Body: part})
+		if err != nil {
+			api.ResError(w, err)
+			return
+		}
+
+		if err := h.ExpandFields(params.Expand, file, accountId); err != nil {
+			api.ResError(w, err)
+			return
+		}
+
+		api.ResJSON(w, http.StatusCreated, file)
+		return
+	}
+
+	api.ResError(w, &api.AppError{
+		Message: "The upload has no 'file' part.",
+		Status:  http.StatusBadRequest,
+		Type:    api.InvalidRequestError,
+	})
+}
+
+// Content streams the contents of a file with the content type it was
+// sniffed as on upload.
+func (h *FilesHandler) Content(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	fileId, err := api.GetIdFromPath(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	file, contents, err := h.Files.Open(files.Get{AccountId: accountId, FileId: fileId})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+	defer contents.Close()
+
+	w.Header().Set("Content-Type", file.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(file.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": file.Filename}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := io.Copy(w, contents); err != nil {
+		log.Printf("[FilesHandler] Failed to send contents of file '%v': %v", fileId, err)
+	}
+}
+
+func (h *FilesHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	fileId, err := api.GetIdFromPath(r)
+
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if err = h.Files.Delete(files.Delete{AccountId: accountId, FileId: fileId}); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusNoContent, nil)
+}
+
+func (h *FilesHandler) List(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	listRes := api.NewListResponse(r)
+	params := files.NewListFilesParams()
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	files, hasMore, err := h.Files.List(files.List{AccountId: accountId, RequestParams: params})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if len(files) != 0 {
+		listRes.Data = append(listRes.Data, files)
+		listRes.HasMore = hasMore
+	}
+
+	if err := h.ExpandFieldsList(params.Expand, files, accountId); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusOK, listRes)
+}
+
+func (h *FilesHandler) Retrieve(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	fileId, err := api.GetIdFromPath(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	params := files.RetrieveFileParams{}
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	file, err := h.Files.Get(files.Get{
+		AccountId:     accountId,
+		FileId:        fileId,
+		RequestParams: params,
+		OmitBase:      false,
+	})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if err := h.ExpandFields(params.Expand, file, accountId); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusOK, file)
+}
+
+func (h *FilesHandler) Update(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	fileId, err := api.GetIdFromPath(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	params := files.UpdateFileParams{}
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	file, err := h.Files.Update(files.Update{AccountId: accountId, FileId: fileId, RequestParams: params})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if err := h.ExpandFields(params.Expand, file, accountId); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusOK, file)
+}
+
+// uploadError turns a failure to read an upload into the error sent back,
+// which is a 413 once the body goes over the upload limit.
+func uploadError(err error) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return api.RequestTooLargeMessage()
+	}
+	return &api.AppError{
+		Message: "The upload couldn't be read as multipart/form-data.",
+		Status:  http.StatusBadRequest,
+		Type:    api.InvalidRequestError,
+	}
+}
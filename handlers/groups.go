@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"database/sql"
 	"net/http"
 
 	"github.com/d-darac/inventory-api/internal/groups"
@@ -15,13 +16,35 @@ type GroupsHandler struct {
 	validator *api.Validator
 }
 
-func NewGroupsHandler(db *database.Queries) *GroupsHandler {
+func NewGroupsHandler(db *database.Queries, conn *sql.DB) *GroupsHandler {
 	return &GroupsHandler{
-		Groups:    *groups.NewGroupsService(db),
+		Groups:    *groups.NewGroupsService(db, conn),
 		validator: api.NewValidator(),
 	}
 }
 
+func (h *GroupsHandler) Ancestors(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	listRes := api.NewListResponse(r)
+	groupId, err := api.GetIdFromPath(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	ancestors, err := h.Groups.Ancestors(groups.GetAncestors{AccountId: accountId, GroupId: groupId})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if len(ancestors) != 0 {
+		listRes.Data = append(listRes.Data, ancestors)
+	}
+
+	api.ResJSON(w, http.StatusOK, listRes)
+}
+
 func (h *GroupsHandler) Create(w http.ResponseWriter, r *http.Request) {
 	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
 	params := groups.CreateGroupParams{}
@@ -47,6 +70,7 @@ func (h *GroupsHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	setETag(w, group.Version)
 	api.ResJSON(w, http.StatusCreated, group)
 }
 
@@ -59,7 +83,25 @@ func (h *GroupsHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err = h.Groups.Delete(groups.Delete{AccountId: accountId, GroupId: groupId}); err != nil {
+	ifMatch, err := parseIfMatch(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	params := groups.NewDeleteGroupParams()
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	if err = h.Groups.Delete(groups.Delete{AccountId: accountId, GroupId: groupId, IfMatch: ifMatch, RequestParams: params}); err != nil {
 		api.ResError(w, err)
 		return
 	}
@@ -137,9 +179,39 @@ func (h *GroupsHandler) Retrieve(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	setETag(w, group.Version)
 	api.ResJSON(w, http.StatusOK, group)
 }
 
+func (h *GroupsHandler) Tree(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	groupId, err := api.GetIdFromPath(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	params := groups.NewRetrieveGroupTreeParams()
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	tree, err := h.Groups.Tree(groups.GetTree{AccountId: accountId, GroupId: groupId, RequestParams: params})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusOK, tree)
+}
+
 func (h *GroupsHandler) Update(w http.ResponseWriter, r *http.Request) {
 	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
 	groupId, err := api.GetIdFromPath(r)
@@ -148,6 +220,12 @@ func (h *GroupsHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	ifMatch, err := parseIfMatch(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
 	params := groups.UpdateGroupParams{}
 
 	if err := api.JsonDecode(r, &params, w); err != nil {
@@ -160,7 +238,7 @@ func (h *GroupsHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	group, err := h.Groups.Update(groups.Update{AccountId: accountId, GroupId: groupId, RequestParams: params})
+	group, err := h.Groups.Update(groups.Update{AccountId: accountId, GroupId: groupId, IfMatch: ifMatch, RequestParams: params})
 	if err != nil {
 		api.ResError(w, err)
 		return
@@ -171,5 +249,6 @@ func (h *GroupsHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	setETag(w, group.Version)
 	api.ResJSON(w, http.StatusOK, group)
 }
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/d-darac/inventory-api/middleware"
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/joho/godotenv"
+	_ "github.com/lib/pq"
+)
+
+func TestUnitParseIfMatch(t *testing.T) {
+	cases := []struct {
+		name     string
+		header   []string
+		versions []int32
+		failed   bool
+	}{
+		{name: "no header"},
+		{name: "any", header: []string{"*"}},
+		{name: "any in a list", header: []string{`"1", *`}},
+		{name: "single", header: []string{`"3"`}, versions: []int32{3}},
+		{name: "list", header: []string{`"3", "4"`}, versions: []int32{3, 4}},
+		{name: "repeated header", header: []string{`"3"`, `"4"`}, versions: []int32{3, 4}},
+		{name: "weak tag skipped", header: []string{`W/"2", "3"`}, versions: []int32{3}},
+		{name: "only weak tags", header: []string{`W/"2"`}, failed: true},
+		{name: "unquoted", header: []string{"3"}, failed: true},
+		{name: "not a version", header: []string{`"abc"`}, failed: true},
+		{name: "out of range", header: []string{`"4294967296"`}, failed: true},
+		{name: "empty", header: []string{""}, failed: true},
+	}
+
+	for _, c := range cases {
+		r := httptest.NewRequest(http.MethodPatch, "/groups/1", nil)
+		for _, value := range c.header {
+			r.Header.Add("If-Match", value)
+		}
+
+		versions, err := parseIfMatch(r)
+		if c.failed {
+			appErr, ok := err.(*api.AppError)
+			if !ok || appErr.Status != http.StatusPreconditionFailed {
+				t.Fatalf("%s: expected a %d error, got %v", c.name, http.StatusPreconditionFailed, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.name, err)
+		}
+		if !slices.Equal(versions, c.versions) {
+			t.Fatalf("%s: expected versions %v, got %v", c.name, c.versions, versions)
+		}
+	}
+}
+
+func TestIntegrationUpdateStaleIfMatch(t *testing.T) {
+	godotenv.Load("../.env")
+	dbUrl := os.Getenv("DB_URL")
+
+	db, err := sql.Open("postgres", dbUrl)
+	if err != nil {
+		t.Fatalf("couldn't open database: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.Ping(); err != nil {
+		t.Fatalf("couldn't connect to database: %v", err)
+	}
+
+	q := database.New(db)
+
+	acc, err := q.CreateAccount(context.Background(), database.CreateAccountParams{
+		Country: database.CountryIE,
+		Nickname: sql.NullString{
+			String: "Test Account",
+			Valid:  true,
+		},
+	})
+	if err != nil {
+		t.Fatalf("couldn't create test account: %v", err)
+	}
+	defer db.Exec("DELETE FROM accounts WHERE id = $1;", acc.ID)
+
+	grp, err := q.CreateGroup(context.Background(), database.CreateGroupParams{
+		Name:      "test-group",
+		AccountID: acc.ID,
+	})
+	if err != nil {
+		t.Fatalf("couldn't create test group: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("PATCH /groups/{id}", NewGroupsHandler(q, db).Update)
+
+	patch := func(ifMatch string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/groups/%v", grp.ID), strings.NewReader(`{"name": "renamed"}`))
+		r.Header.Set("Content-Type", "application/json")
+		r.Header.Set("If-Match", ifMatch)
+		r = r.WithContext(context.WithValue(r.Context(), middleware.AuthAccountID, acc.ID))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		return w
+	}
+
+	stale := patch(fmt.Sprintf("\"%d\"", grp.Version+1))
+	if stale.Code != http.StatusPreconditionFailed {
+		t.Fatalf("expected status %d for a stale If-Match, got %d", http.StatusPreconditionFailed, stale.Code)
+	}
+
+	row, err := q.GetGroup(context.Background(), database.GetGroupParams{ID: grp.ID, AccountID: acc.ID})
+	if err != nil {
+		t.Fatalf("error retrieving group: %v", err)
+	}
+	if row.Name != grp.Name {
+		t.Fatalf("expected name %v after a stale update, got %v", grp.Name, row.Name)
+	}
+
+	current := patch(fmt.Sprintf("\"%d\"", grp.Version))
+	if current.Code != http.StatusOK {
+		t.Fatalf("expected status %d for the current If-Match, got %d", http.StatusOK, current.Code)
+	}
+	if etag := current.Header().Get("ETag"); etag != fmt.Sprintf("\"%d\"", grp.Version+1) {
+		t.Fatalf("expected ETag \"%d\", got %s", grp.Version+1, etag)
+	}
+}
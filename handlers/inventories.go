@@ -1,12 +1,14 @@
 package handlers
 
 import (
+	"database/sql"
 	"net/http"
 
 	"github.com/d-darac/inventory-api/internal/groups"
 	"github.com/d-darac/inventory-api/internal/inventories"
 	itemidentifiers "github.com/d-darac/inventory-api/internal/item_identifiers"
 	"github.com/d-darac/inventory-api/internal/items"
+	"github.com/d-darac/inventory-api/internal/locations"
 	"github.com/d-darac/inventory-api/middleware"
 	"github.com/d-darac/inventory-assets/api"
 	"github.com/d-darac/inventory-assets/database"
@@ -18,15 +20,17 @@ type InventoriesHandler struct {
 	Inventories     inventories.InventoriesService
 	Items           items.ItemsService
 	ItemIdentifiers itemidentifiers.ItemIdentifiersService
+	Locations       locations.LocationsService
 	validator       *api.Validator
 }
 
-func NewInventoriesHandler(db *database.Queries) *InventoriesHandler {
+func NewInventoriesHandler(db *database.Queries, conn *sql.DB) *InventoriesHandler {
 	return &InventoriesHandler{
-		Groups:          *groups.NewGroupsService(db),
-		Inventories:     *inventories.NewInventoriesService(db),
-		Items:           *items.NewItemsService(db),
+		Groups:          *groups.NewGroupsService(db, conn),
+		Inventories:     *inventories.NewInventoriesService(db, conn),
+		Items:           *items.NewItemsService(db, conn),
 		ItemIdentifiers: *itemidentifiers.NewItemIdentifiersService(db),
+		Locations:       *locations.NewLocationsService(db),
 		validator:       api.NewValidator(),
 	}
 }
@@ -45,12 +49,34 @@ func (h *InventoriesHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if params.Item != nil {
+		_, err := h.Items.Get(items.Get{AccountId: accountId, ItemId: uuid.MustParse(*params.Item)})
+		if err != nil {
+			api.ResError(w, err)
+			return
+		}
+	}
+
+	if params.Location != nil {
+		_, err := h.Locations.Get(locations.Get{AccountId: accountId, LocationId: uuid.MustParse(*params.Location)})
+		if err != nil {
+			api.ResError(w, err)
+			return
+		}
+	}
+
 	inventory, err := h.Inventories.Create(inventories.Create{AccountId: accountId, RequestParams: params})
 	if err != nil {
 		api.ResError(w, err)
 		return
 	}
 
+	if err := h.ExpandFields(params.Expand, inventory, accountId); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	setETag(w, inventory.Version)
 	api.ResJSON(w, http.StatusCreated, inventory)
 }
 
@@ -63,7 +89,13 @@ func (h *InventoriesHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err = h.Inventories.Delete(inventories.Delete{AccountId: accountId, InventoryId: inventoryId}); err != nil {
+	ifMatch, err := parseIfMatch(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if err = h.Inventories.Delete(inventories.Delete{AccountId: accountId, InventoryId: inventoryId, IfMatch: ifMatch}); err != nil {
 		api.ResError(w, err)
 		return
 	}
@@ -71,6 +103,45 @@ func (h *InventoriesHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	api.ResJSON(w, http.StatusNoContent, nil)
 }
 
+func (h *InventoriesHandler) History(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	inventoryId, err := api.GetIdFromPath(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	listRes := api.NewListResponse(r)
+	params := inventories.NewListInventoryHistoryParams()
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	entries, hasMore, err := h.Inventories.History(inventories.History{
+		AccountId:     accountId,
+		InventoryId:   inventoryId,
+		RequestParams: params,
+	})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if len(entries) != 0 {
+		listRes.Data = append(listRes.Data, entries)
+		listRes.HasMore = hasMore
+	}
+
+	api.ResJSON(w, http.StatusOK, listRes)
+}
+
 func (h *InventoriesHandler) List(w http.ResponseWriter, r *http.Request) {
 	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
 	listRes := api.NewListResponse(r)
@@ -97,6 +168,11 @@ func (h *InventoriesHandler) List(w http.ResponseWriter, r *http.Request) {
 		listRes.HasMore = hasMore
 	}
 
+	if err := h.ExpandFieldsList(params.Expand, inventories, accountId); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
 	api.ResJSON(w, http.StatusOK, listRes)
 }
 
@@ -131,6 +207,12 @@ func (h *InventoriesHandler) Retrieve(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := h.ExpandFields(params.Expand, inventory, accountId); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	setETag(w, inventory.Version)
 	api.ResJSON(w, http.StatusOK, inventory)
 }
 
@@ -142,6 +224,12 @@ func (h *InventoriesHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	ifMatch, err := parseIfMatch(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
 	params := inventories.UpdateInventoryParams{}
 
 	if err := api.JsonDecode(r, &params, w); err != nil {
@@ -154,11 +242,17 @@ func (h *InventoriesHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	inventory, err := h.Inventories.Update(inventories.Update{AccountId: accountId, InventoryId: inventoryId, RequestParams: params})
+	inventory, err := h.Inventories.Update(inventories.Update{AccountId: accountId, InventoryId: inventoryId, IfMatch: ifMatch, RequestParams: params})
 	if err != nil {
 		api.ResError(w, err)
 		return
 	}
 
+	if err := h.ExpandFields(params.Expand, inventory, accountId); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	setETag(w, inventory.Version)
 	api.ResJSON(w, http.StatusOK, inventory)
 }
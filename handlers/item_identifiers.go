@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"database/sql"
 	"net/http"
 
 	itemidentifiers "github.com/d-darac/inventory-api/internal/item_identifiers"
@@ -17,10 +18,10 @@ type ItemIdentifiersHandler struct {
 	validator       *api.Validator
 }
 
-func NewItemIdentifiersHandler(db *database.Queries) *ItemIdentifiersHandler {
+func NewItemIdentifiersHandler(db *database.Queries, conn *sql.DB) *ItemIdentifiersHandler {
 	return &ItemIdentifiersHandler{
 		ItemIdentifiers: *itemidentifiers.NewItemIdentifiersService(db),
-		Items:           *items.NewItemsService(db),
+		Items:           *items.NewItemsService(db, conn),
 		validator:       api.NewValidator(),
 	}
 }
@@ -50,6 +51,7 @@ func (h *ItemIdentifiersHandler) Create(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	setETag(w, itemIdentifiers.Version)
 	api.ResJSON(w, http.StatusCreated, itemIdentifiers)
 }
 
@@ -62,7 +64,13 @@ func (h *ItemIdentifiersHandler) Delete(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if err = h.ItemIdentifiers.Delete(itemidentifiers.Delete{AccountId: accountId, ItemIdentifiersId: itemIdentifiersId}); err != nil {
+	ifMatch, err := parseIfMatch(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if err = h.ItemIdentifiers.Delete(itemidentifiers.Delete{AccountId: accountId, ItemIdentifiersId: itemIdentifiersId, IfMatch: ifMatch}); err != nil {
 		api.ResError(w, err)
 		return
 	}
@@ -140,6 +148,7 @@ func (h *ItemIdentifiersHandler) Retrieve(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	setETag(w, itemIdentifiers.Version)
 	api.ResJSON(w, http.StatusOK, itemIdentifiers)
 }
 
@@ -151,6 +160,12 @@ func (h *ItemIdentifiersHandler) Update(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	ifMatch, err := parseIfMatch(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
 	params := itemidentifiers.UpdateItemIdentifiersParams{}
 
 	if err := api.JsonDecode(r, &params, w); err != nil {
@@ -166,6 +181,7 @@ func (h *ItemIdentifiersHandler) Update(w http.ResponseWriter, r *http.Request)
 	itemIdentifiers, err := h.ItemIdentifiers.Update(itemidentifiers.Update{
 		AccountId:         accountId,
 		ItemIdentifiersId: itemIdentifiersId,
+		IfMatch:           ifMatch,
 		RequestParams:     params,
 	})
 	if err != nil {
@@ -178,5 +194,6 @@ func (h *ItemIdentifiersHandler) Update(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	setETag(w, itemIdentifiers.Version)
 	api.ResJSON(w, http.StatusOK, itemIdentifiers)
 }
@@ -1,12 +1,20 @@
 package handlers
 
 import (
+	"database/sql"
 	"net/http"
 
+	"github.com/d-darac/inventory-api/internal/components"
+	"github.com/d-darac/inventory-api/internal/files"
 	"github.com/d-darac/inventory-api/internal/groups"
 	"github.com/d-darac/inventory-api/internal/inventories"
 	itemidentifiers "github.com/d-darac/inventory-api/internal/item_identifiers"
 	"github.com/d-darac/inventory-api/internal/items"
+	pricelists "github.com/d-darac/inventory-api/internal/price_lists"
+	serialnumbers "github.com/d-darac/inventory-api/internal/serial_numbers"
+	"github.com/d-darac/inventory-api/internal/storage"
+	"github.com/d-darac/inventory-api/internal/suppliers"
+	"github.com/d-darac/inventory-api/internal/units"
 	"github.com/d-darac/inventory-api/middleware"
 	"github.com/d-darac/inventory-assets/api"
 	"github.com/d-darac/inventory-assets/database"
@@ -14,19 +22,31 @@ import (
 )
 
 type ItemsHandler struct {
+	Components      *components.ComponentsService
+	Files           *files.FilesService
 	Groups          *groups.GroupsService
 	Inventories     *inventories.InventoriesService
 	ItemIdentifiers *itemidentifiers.ItemIdentifiersService
 	Items           *items.ItemsService
+	PriceLists      *pricelists.PriceListsService
+	SerialNumbers   *serialnumbers.SerialNumbersService
+	Suppliers       *suppliers.SuppliersService
+	Units           *units.UnitsService
 	validator       *api.Validator
 }
 
-func NewItemsHandler(db *database.Queries) *ItemsHandler {
+func NewItemsHandler(db *database.Queries, conn *sql.DB, store storage.Storage) *ItemsHandler {
 	return &ItemsHandler{
-		Groups:          groups.NewGroupsService(db),
-		Inventories:     inventories.NewInventoriesService(db),
+		Components:      components.NewComponentsService(db),
+		Files:           files.NewFilesService(db, store),
+		Groups:          groups.NewGroupsService(db, conn),
+		Inventories:     inventories.NewInventoriesService(db, conn),
 		ItemIdentifiers: itemidentifiers.NewItemIdentifiersService(db),
-		Items:           items.NewItemsService(db),
+		Items:           items.NewItemsService(db, conn),
+		PriceLists:      pricelists.NewPriceListsService(db),
+		SerialNumbers:   serialnumbers.NewSerialNumbersService(db, conn),
+		Suppliers:       suppliers.NewSuppliersService(db),
+		Units:           units.NewUnitsService(db),
 		validator:       api.NewValidator(),
 	}
 }
@@ -61,6 +81,16 @@ func (h *ItemsHandler) Create(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	for _, unit := range []*string{params.PurchaseUnit, params.SalesUnit, params.StockingUnit} {
+		if unit != nil {
+			_, err := h.Units.Get(units.Get{AccountId: accountId, UnitId: uuid.MustParse(*unit)})
+			if err != nil {
+				api.ResError(w, err)
+				return
+			}
+		}
+	}
+
 	if params.GroupData != nil {
 		group, err := h.Groups.Create(groups.Create{
 			AccountId: accountId,
@@ -79,11 +109,15 @@ func (h *ItemsHandler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if params.InventoryData != nil {
+		if err := items.CheckOpeningStock(params, params.InventoryData.InStock); err != nil {
+			api.ResError(w, err)
+			return
+		}
+
 		inventory, err := h.Inventories.Create(inventories.Create{
 			AccountId: accountId,
 			RequestParams: inventories.CreateInventoryParams{
-				InStock:   params.InventoryData.InStock,
-				Orderable: params.InventoryData.Orderable,
+				InStock: params.InventoryData.InStock,
 			},
 		})
 		if err != nil {
@@ -130,6 +164,7 @@ func (h *ItemsHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	setETag(w, item.Version)
 	api.ResJSON(w, http.StatusCreated, item)
 }
 
@@ -142,7 +177,13 @@ func (h *ItemsHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err = h.Items.Delete(items.Delete{AccountId: accountId, ItemId: itemId}); err != nil {
+	ifMatch, err := parseIfMatch(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if err = h.Items.Delete(items.Delete{AccountId: accountId, ItemId: itemId, IfMatch: ifMatch}); err != nil {
 		api.ResError(w, err)
 		return
 	}
@@ -220,6 +261,7 @@ func (h *ItemsHandler) Retrieve(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	setETag(w, item.Version)
 	api.ResJSON(w, http.StatusOK, item)
 }
 
@@ -231,6 +273,12 @@ func (h *ItemsHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	ifMatch, err := parseIfMatch(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
 	params := items.UpdateItemParams{}
 
 	if err := api.JsonDecode(r, &params, w); err != nil {
@@ -259,9 +307,21 @@ func (h *ItemsHandler) Update(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	for _, unit := range []*string{params.PurchaseUnit, params.SalesUnit, params.StockingUnit} {
+		if unit != nil {
+			_, err := h.Units.Get(units.Get{AccountId: accountId, UnitId: uuid.MustParse(*unit)})
+			if err != nil {
+				api.ResError(w, err)
+				return
+			}
+		}
+	}
+
 	item, err := h.Items.Update(items.Update{
 		AccountId:     accountId,
+		ApiKeyId:      r.Context().Value(middleware.AuthApiKeyID).(uuid.UUID),
 		ItemId:        itemId,
+		IfMatch:       ifMatch,
 		RequestParams: params,
 	})
 	if err != nil {
@@ -274,5 +334,175 @@ func (h *ItemsHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	setETag(w, item.Version)
 	api.ResJSON(w, http.StatusOK, item)
 }
+
+func (h *ItemsHandler) GenerateVariants(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	itemId, err := api.GetIdFromPath(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	listRes := api.NewListResponse(r)
+	params := items.GenerateVariantsParams{}
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	variants, err := h.Items.GenerateVariants(items.GenerateVariants{
+		AccountId:     accountId,
+		ItemId:        itemId,
+		RequestParams: params,
+	})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if len(variants) != 0 {
+		listRes.Data = append(listRes.Data, variants)
+	}
+
+	if err := h.ExpandFieldsList(params.Expand, variants, accountId); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusCreated, listRes)
+}
+
+func (h *ItemsHandler) Variants(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	itemId, err := api.GetIdFromPath(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	listRes := api.NewListResponse(r)
+	params := items.NewListVariantsParams()
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	variants, hasMore, err := h.Items.ListVariants(items.ListVariants{
+		AccountId:     accountId,
+		ItemId:        itemId,
+		RequestParams: params,
+	})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if len(variants) != 0 {
+		listRes.Data = append(listRes.Data, variants)
+		listRes.HasMore = hasMore
+	}
+
+	if err := h.ExpandFieldsList(params.Expand, variants, accountId); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusOK, listRes)
+}
+
+func (h *ItemsHandler) PriceHistory(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	itemId, err := api.GetIdFromPath(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	listRes := api.NewListResponse(r)
+	params := items.NewListPriceHistoryParams()
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	changes, hasMore, err := h.Items.PriceHistory(items.PriceHistory{
+		AccountId:     accountId,
+		ItemId:        itemId,
+		RequestParams: params,
+	})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if len(changes) != 0 {
+		listRes.Data = append(listRes.Data, changes)
+		listRes.HasMore = hasMore
+	}
+
+	api.ResJSON(w, http.StatusOK, listRes)
+}
+
+func (h *ItemsHandler) AddTags(w http.ResponseWriter, r *http.Request) {
+	h.updateTags(w, r, h.Items.AddTags)
+}
+
+func (h *ItemsHandler) RemoveTags(w http.ResponseWriter, r *http.Request) {
+	h.updateTags(w, r, h.Items.RemoveTags)
+}
+
+// updateTags handles both bulk tag endpoints, which only differ in what
+// they do to the tags.
+func (h *ItemsHandler) updateTags(w http.ResponseWriter, r *http.Request, update func(items.UpdateTags) ([]*items.Item, error)) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	listRes := api.NewListResponse(r)
+	params := items.UpdateItemTagsParams{}
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	tagged, err := update(items.UpdateTags{AccountId: accountId, RequestParams: params})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if len(tagged) != 0 {
+		listRes.Data = append(listRes.Data, tagged)
+	}
+
+	if err := h.ExpandFieldsList(params.Expand, tagged, accountId); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusOK, listRes)
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/d-darac/inventory-api/internal/locations"
+	"github.com/d-darac/inventory-api/middleware"
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+type LocationsHandler struct {
+	Locations locations.LocationsService
+	validator *api.Validator
+}
+
+func NewLocationsHandler(db *database.Queries) *LocationsHandler {
+	return &LocationsHandler{
+		Locations: *locations.NewLocationsService(db),
+		validator: api.NewValidator(),
+	}
+}
+
+func (h *LocationsHandler) Create(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	params := locations.CreateLocationParams{}
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	location, err := h.Locations.Create(locations.Create{AccountId: accountId, RequestParams: params})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusCreated, location)
+}
+
+func (h *LocationsHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	locationId, err := api.GetIdFromPath(r)
+
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if err = h.Locations.Delete(locations.Delete{AccountId: accountId, LocationId: locationId}); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusNoContent, nil)
+}
+
+func (h *LocationsHandler) List(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	listRes := api.NewListResponse(r)
+	params := locations.NewListLocationsParams()
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	locations, hasMore, err := h.Locations.List(locations.List{AccountId: accountId, RequestParams: params})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if len(locations) != 0 {
+		listRes.Data = append(listRes.Data, locations)
+		listRes.HasMore = hasMore
+	}
+
+	api.ResJSON(w, http.StatusOK, listRes)
+}
+
+func (h *LocationsHandler) Retrieve(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	locationId, err := api.GetIdFromPath(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	params := locations.RetrieveLocationParams{}
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	location, err := h.Locations.Get(locations.Get{
+		AccountId:     accountId,
+		LocationId:    locationId,
+		RequestParams: params,
+		OmitBase:      false,
+	})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusOK, location)
+}
+
+func (h *LocationsHandler) Update(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	locationId, err := api.GetIdFromPath(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	params := locations.UpdateLocationParams{}
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	location, err := h.Locations.Update(locations.Update{AccountId: accountId, LocationId: locationId, RequestParams: params})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusOK, location)
+}
@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/d-darac/inventory-api/internal/inventories"
+	"github.com/d-darac/inventory-api/internal/lots"
+	"github.com/d-darac/inventory-api/middleware"
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+type LotsHandler struct {
+	Inventories *inventories.InventoriesService
+	Lots        *lots.LotsService
+	validator   *api.Validator
+}
+
+func NewLotsHandler(db *database.Queries, conn *sql.DB) *LotsHandler {
+	return &LotsHandler{
+		Inventories: inventories.NewInventoriesService(db, conn),
+		Lots:        lots.NewLotsService(db, conn),
+		validator:   api.NewValidator(),
+	}
+}
+
+func (h *LotsHandler) Create(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	params := lots.CreateLotParams{}
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	lot, err := h.Lots.Create(lots.Create{AccountId: accountId, RequestParams: params})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if err := h.ExpandFields(params.Expand, lot, accountId); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusCreated, lot)
+}
+
+func (h *LotsHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	lotId, err := api.GetIdFromPath(r)
+
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if err = h.Lots.Delete(lots.Delete{AccountId: accountId, LotId: lotId}); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusNoContent, nil)
+}
+
+func (h *LotsHandler) List(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	listRes := api.NewListResponse(r)
+	params := lots.NewListLotsParams()
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	lts, hasMore, err := h.Lots.List(lots.List{AccountId: accountId, RequestParams: params})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if len(lts) != 0 {
+		listRes.Data = append(listRes.Data, lts)
+		listRes.HasMore = hasMore
+	}
+
+	if err := h.ExpandFieldsList(params.Expand, lts, accountId); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusOK, listRes)
+}
+
+func (h *LotsHandler) Retrieve(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	lotId, err := api.GetIdFromPath(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	params := lots.RetrieveLotParams{}
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	lot, err := h.Lots.Get(lots.Get{
+		AccountId:     accountId,
+		LotId:         lotId,
+		RequestParams: params,
+		OmitBase:      false,
+	})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if err := h.ExpandFields(params.Expand, lot, accountId); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusOK, lot)
+}
+
+func (h *LotsHandler) Update(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	lotId, err := api.GetIdFromPath(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	params := lots.UpdateLotParams{}
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	lot, err := h.Lots.Update(lots.Update{AccountId: accountId, LotId: lotId, RequestParams: params})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if err := h.ExpandFields(params.Expand, lot, accountId); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusOK, lot)
+}
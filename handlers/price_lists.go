@@ -0,0 +1,393 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/d-darac/inventory-api/internal/items"
+	pricelists "github.com/d-darac/inventory-api/internal/price_lists"
+	"github.com/d-darac/inventory-api/middleware"
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+type PriceListsHandler struct {
+	Items      *items.ItemsService
+	PriceLists *pricelists.PriceListsService
+	validator  *api.Validator
+}
+
+func NewPriceListsHandler(db *database.Queries, conn *sql.DB) *PriceListsHandler {
+	return &PriceListsHandler{
+		Items:      items.NewItemsService(db, conn),
+		PriceLists: pricelists.NewPriceListsService(db),
+		validator:  api.NewValidator(),
+	}
+}
+
+func (h *PriceListsHandler) Create(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	params := pricelists.CreatePriceListParams{}
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	priceList, err := h.PriceLists.Create(pricelists.Create{AccountId: accountId, RequestParams: params})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusCreated, priceList)
+}
+
+func (h *PriceListsHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	priceListId, err := api.GetIdFromPath(r)
+
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if err = h.PriceLists.Delete(pricelists.Delete{AccountId: accountId, PriceListId: priceListId}); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusNoContent, nil)
+}
+
+func (h *PriceListsHandler) List(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	listRes := api.NewListResponse(r)
+	params := pricelists.NewListPriceListsParams()
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	priceLists, hasMore, err := h.PriceLists.List(pricelists.List{AccountId: accountId, RequestParams: params})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if len(priceLists) != 0 {
+		listRes.Data = append(listRes.Data, priceLists)
+		listRes.HasMore = hasMore
+	}
+
+	api.ResJSON(w, http.StatusOK, listRes)
+}
+
+func (h *PriceListsHandler) Retrieve(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	priceListId, err := api.GetIdFromPath(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	params := pricelists.RetrievePriceListParams{}
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	priceList, err := h.PriceLists.Get(pricelists.Get{
+		AccountId:     accountId,
+		PriceListId:   priceListId,
+		RequestParams: params,
+		OmitBase:      false,
+	})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusOK, priceList)
+}
+
+func (h *PriceListsHandler) Update(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	priceListId, err := api.GetIdFromPath(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	params := pricelists.UpdatePriceListParams{}
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	priceList, err := h.PriceLists.Update(pricelists.Update{AccountId: accountId, PriceListId: priceListId, RequestParams: params})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusOK, priceList)
+}
+
+func (h *PriceListsHandler) CreatePrice(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	priceListId, err := api.GetIdFromPath(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	params := pricelists.CreatePriceParams{}
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	price, err := h.PriceLists.CreatePrice(pricelists.CreatePrice{
+		AccountId:     accountId,
+		PriceListId:   priceListId,
+		RequestParams: params,
+	})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if err := h.ExpandPriceFields(params.Expand, price, accountId); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusCreated, price)
+}
+
+func (h *PriceListsHandler) DeletePrice(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	priceListId, priceId, err := getPriceIdsFromPath(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	err = h.PriceLists.DeletePrice(pricelists.DeletePrice{
+		AccountId:   accountId,
+		PriceListId: priceListId,
+		PriceId:     priceId,
+	})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusNoContent, nil)
+}
+
+func (h *PriceListsHandler) ListPrices(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	priceListId, err := api.GetIdFromPath(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	listRes := api.NewListResponse(r)
+	params := pricelists.NewListPricesParams()
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	prices, hasMore, err := h.PriceLists.ListPrices(pricelists.ListPrices{
+		AccountId:     accountId,
+		PriceListId:   priceListId,
+		RequestParams: params,
+	})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if len(prices) != 0 {
+		listRes.Data = append(listRes.Data, prices)
+		listRes.HasMore = hasMore
+	}
+
+	if err := h.ExpandPriceFieldsList(params.Expand, prices, accountId); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusOK, listRes)
+}
+
+func (h *PriceListsHandler) RetrievePrice(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	priceListId, priceId, err := getPriceIdsFromPath(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	params := pricelists.RetrievePriceParams{}
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	price, err := h.PriceLists.GetPrice(pricelists.GetPrice{
+		AccountId:     accountId,
+		PriceListId:   priceListId,
+		PriceId:       priceId,
+		RequestParams: params,
+		OmitBase:      false,
+	})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if err := h.ExpandPriceFields(params.Expand, price, accountId); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusOK, price)
+}
+
+func (h *PriceListsHandler) UpdatePrice(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	priceListId, priceId, err := getPriceIdsFromPath(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	params := pricelists.UpdatePriceParams{}
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	price, err := h.PriceLists.UpdatePrice(pricelists.UpdatePrice{
+		AccountId:     accountId,
+		PriceListId:   priceListId,
+		PriceId:       priceId,
+		RequestParams: params,
+	})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if err := h.ExpandPriceFields(params.Expand, price, accountId); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusOK, price)
+}
+
+func (h *PriceListsHandler) Quote(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	priceListId, err := api.GetIdFromPath(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	params := pricelists.QuoteParams{}
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	quote, err := h.PriceLists.Quote(pricelists.GetQuote{
+		AccountId:     accountId,
+		PriceListId:   priceListId,
+		RequestParams: params,
+	})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if err := h.ExpandQuoteFields(params.Expand, quote, accountId); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusOK, quote)
+}
+
+// getPriceIdsFromPath returns the price list's ID from {id} and the price's
+// from {price_id}.
+func getPriceIdsFromPath(r *http.Request) (priceListId, priceId uuid.UUID, err error) {
+	priceListId, err = api.GetIdFromPath(r)
+	if err != nil {
+		return
+	}
+	priceId, err = uuid.Parse(r.PathValue("price_id"))
+	if err != nil {
+		return priceListId, priceId, api.NotFoundMessage(priceId, "price")
+	}
+	return
+}
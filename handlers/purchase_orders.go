@@ -0,0 +1,264 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+
+	purchaseorders "github.com/d-darac/inventory-api/internal/purchase_orders"
+	"github.com/d-darac/inventory-api/internal/suppliers"
+	"github.com/d-darac/inventory-api/middleware"
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+type PurchaseOrdersHandler struct {
+	PurchaseOrders *purchaseorders.PurchaseOrdersService
+	Suppliers      *suppliers.SuppliersService
+	validator      *api.Validator
+}
+
+func NewPurchaseOrdersHandler(db *database.Queries, conn *sql.DB) *PurchaseOrdersHandler {
+	return &PurchaseOrdersHandler{
+		PurchaseOrders: purchaseorders.NewPurchaseOrdersService(db, conn),
+		Suppliers:      suppliers.NewSuppliersService(db),
+		validator:      api.NewValidator(),
+	}
+}
+
+func (h *PurchaseOrdersHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	purchaseOrderId, err := api.GetIdFromPath(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	params := purchaseorders.CancelPurchaseOrderParams{}
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	purchaseOrder, err := h.PurchaseOrders.Cancel(purchaseorders.Cancel{AccountId: accountId, PurchaseOrderId: purchaseOrderId, RequestParams: params})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if err := h.ExpandFields(params.Expand, purchaseOrder, accountId); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusOK, purchaseOrder)
+}
+
+func (h *PurchaseOrdersHandler) Create(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	params := purchaseorders.CreatePurchaseOrderParams{}
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	purchaseOrder, err := h.PurchaseOrders.Create(purchaseorders.Create{AccountId: accountId, RequestParams: params})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if err := h.ExpandFields(params.Expand, purchaseOrder, accountId); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusCreated, purchaseOrder)
+}
+
+func (h *PurchaseOrdersHandler) List(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	listRes := api.NewListResponse(r)
+	params := purchaseorders.NewListPurchaseOrdersParams()
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	purchaseOrders, hasMore, err := h.PurchaseOrders.List(purchaseorders.List{AccountId: accountId, RequestParams: params})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if len(purchaseOrders) != 0 {
+		listRes.Data = append(listRes.Data, purchaseOrders)
+		listRes.HasMore = hasMore
+	}
+
+	if err := h.ExpandFieldsList(params.Expand, purchaseOrders, accountId); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusOK, listRes)
+}
+
+func (h *PurchaseOrdersHandler) Receive(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	purchaseOrderId, err := api.GetIdFromPath(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	params := purchaseorders.ReceivePurchaseOrderParams{}
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	purchaseOrder, err := h.PurchaseOrders.Receive(purchaseorders.Receive{AccountId: accountId, PurchaseOrderId: purchaseOrderId, RequestParams: params})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if err := h.ExpandFields(params.Expand, purchaseOrder, accountId); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusOK, purchaseOrder)
+}
+
+func (h *PurchaseOrdersHandler) Retrieve(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	purchaseOrderId, err := api.GetIdFromPath(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	params := purchaseorders.RetrievePurchaseOrderParams{}
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	purchaseOrder, err := h.PurchaseOrders.Get(purchaseorders.Get{
+		AccountId:       accountId,
+		PurchaseOrderId: purchaseOrderId,
+		RequestParams:   params,
+		OmitBase:        false,
+	})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if err := h.ExpandFields(params.Expand, purchaseOrder, accountId); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusOK, purchaseOrder)
+}
+
+func (h *PurchaseOrdersHandler) Send(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	purchaseOrderId, err := api.GetIdFromPath(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	params := purchaseorders.SendPurchaseOrderParams{}
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	purchaseOrder, err := h.PurchaseOrders.Send(purchaseorders.Send{AccountId: accountId, PurchaseOrderId: purchaseOrderId, RequestParams: params})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if err := h.ExpandFields(params.Expand, purchaseOrder, accountId); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusOK, purchaseOrder)
+}
+
+func (h *PurchaseOrdersHandler) Update(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	purchaseOrderId, err := api.GetIdFromPath(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	params := purchaseorders.UpdatePurchaseOrderParams{}
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	purchaseOrder, err := h.PurchaseOrders.Update(purchaseorders.Update{AccountId: accountId, PurchaseOrderId: purchaseOrderId, RequestParams: params})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if err := h.ExpandFields(params.Expand, purchaseOrder, accountId); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusOK, purchaseOrder)
+}
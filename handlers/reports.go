@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/d-darac/inventory-api/internal/groups"
+	"github.com/d-darac/inventory-api/internal/items"
+	"github.com/d-darac/inventory-api/internal/returns"
+	"github.com/d-darac/inventory-api/internal/valuation"
+	"github.com/d-darac/inventory-api/middleware"
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+type ReportsHandler struct {
+	Groups     *groups.GroupsService
+	Items      *items.ItemsService
+	Returns    *returns.ReturnsService
+	Valuations *valuation.ValuationService
+	validator  *api.Validator
+}
+
+func NewReportsHandler(db *database.Queries, conn *sql.DB) *ReportsHandler {
+	return &ReportsHandler{
+		Groups:     groups.NewGroupsService(db, conn),
+		Items:      items.NewItemsService(db, conn),
+		Returns:    returns.NewReturnsService(db, conn),
+		Valuations: valuation.NewValuationService(db),
+		validator:  api.NewValidator(),
+	}
+}
+
+func (h *ReportsHandler) ReturnReasons(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	params := returns.RetrieveReturnsReportParams{}
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	report, err := h.Returns.Summarize(returns.Summarize{AccountId: accountId, RequestParams: params})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusOK, report)
+}
+
+func (h *ReportsHandler) Valuation(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	params := valuation.RetrieveValuationParams{}
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	report, err := h.Valuations.Get(valuation.Get{AccountId: accountId, RequestParams: params})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if err := h.ExpandValuationFields(params.Expand, report, accountId); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusOK, report)
+}
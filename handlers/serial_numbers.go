@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/d-darac/inventory-api/internal/inventories"
+	"github.com/d-darac/inventory-api/internal/items"
+	serialnumbers "github.com/d-darac/inventory-api/internal/serial_numbers"
+	"github.com/d-darac/inventory-api/middleware"
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+type SerialNumbersHandler struct {
+	Inventories   *inventories.InventoriesService
+	Items         *items.ItemsService
+	SerialNumbers *serialnumbers.SerialNumbersService
+	validator     *api.Validator
+}
+
+func NewSerialNumbersHandler(db *database.Queries, conn *sql.DB) *SerialNumbersHandler {
+	return &SerialNumbersHandler{
+		Inventories:   inventories.NewInventoriesService(db, conn),
+		Items:         items.NewItemsService(db, conn),
+		SerialNumbers: serialnumbers.NewSerialNumbersService(db, conn),
+		validator:     api.NewValidator(),
+	}
+}
+
+func (h *SerialNumbersHandler) Create(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	params := serialnumbers.CreateSerialNumberParams{}
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	serialNumber, err := h.SerialNumbers.Create(serialnumbers.Create{AccountId: accountId, RequestParams: params})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if err := h.ExpandFields(params.Expand, serialNumber, accountId); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusCreated, serialNumber)
+}
+
+func (h *SerialNumbersHandler) List(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	listRes := api.NewListResponse(r)
+	params := serialnumbers.NewListSerialNumbersParams()
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	serialNumbers, hasMore, err := h.SerialNumbers.List(serialnumbers.List{AccountId: accountId, RequestParams: params})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if len(serialNumbers) != 0 {
+		listRes.Data = append(listRes.Data, serialNumbers)
+		listRes.HasMore = hasMore
+	}
+
+	if err := h.ExpandFieldsList(params.Expand, serialNumbers, accountId); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusOK, listRes)
+}
+
+func (h *SerialNumbersHandler) Retrieve(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	serialNumberId, err := api.GetIdFromPath(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	params := serialnumbers.RetrieveSerialNumberParams{}
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	serialNumber, err := h.SerialNumbers.Get(serialnumbers.Get{
+		AccountId:      accountId,
+		SerialNumberId: serialNumberId,
+		RequestParams:  params,
+		OmitBase:       false,
+	})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if err := h.ExpandFields(params.Expand, serialNumber, accountId); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusOK, serialNumber)
+}
+
+func (h *SerialNumbersHandler) Update(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	serialNumberId, err := api.GetIdFromPath(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	params := serialnumbers.UpdateSerialNumberParams{}
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	serialNumber, err := h.SerialNumbers.Update(serialnumbers.Update{AccountId: accountId, SerialNumberId: serialNumberId, RequestParams: params})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if err := h.ExpandFields(params.Expand, serialNumber, accountId); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusOK, serialNumber)
+}
@@ -0,0 +1,247 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/d-darac/inventory-api/internal/groups"
+	"github.com/d-darac/inventory-api/internal/locations"
+	stockcounts "github.com/d-darac/inventory-api/internal/stock_counts"
+	"github.com/d-darac/inventory-api/middleware"
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+type StockCountsHandler struct {
+	Groups      *groups.GroupsService
+	Locations   *locations.LocationsService
+	StockCounts *stockcounts.StockCountsService
+	validator   *api.Validator
+}
+
+func NewStockCountsHandler(db *database.Queries, conn *sql.DB) *StockCountsHandler {
+	return &StockCountsHandler{
+		Groups:      groups.NewGroupsService(db, conn),
+		Locations:   locations.NewLocationsService(db),
+		StockCounts: stockcounts.NewStockCountsService(db, conn),
+		validator:   api.NewValidator(),
+	}
+}
+
+func (h *StockCountsHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	stockCountId, err := api.GetIdFromPath(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	params := stockcounts.CancelStockCountParams{}
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	stockCount, err := h.StockCounts.Cancel(stockcounts.Cancel{AccountId: accountId, StockCountId: stockCountId, RequestParams: params})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if err := h.ExpandFields(params.Expand, stockCount, accountId); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusOK, stockCount)
+}
+
+func (h *StockCountsHandler) Create(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	params := stockcounts.CreateStockCountParams{}
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	if params.Group != nil {
+		_, err := h.Groups.Get(groups.Get{AccountId: accountId, GroupId: uuid.MustParse(*params.Group)})
+		if err != nil {
+			api.ResError(w, err)
+			return
+		}
+	}
+
+	_, err := h.Locations.Get(locations.Get{AccountId: accountId, LocationId: uuid.MustParse(params.Location)})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	stockCount, err := h.StockCounts.Create(stockcounts.Create{AccountId: accountId, RequestParams: params})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if err := h.ExpandFields(params.Expand, stockCount, accountId); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusCreated, stockCount)
+}
+
+func (h *StockCountsHandler) Finalize(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	stockCountId, err := api.GetIdFromPath(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	params := stockcounts.FinalizeStockCountParams{}
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	stockCount, err := h.StockCounts.Finalize(stockcounts.Finalize{AccountId: accountId, StockCountId: stockCountId, RequestParams: params})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if err := h.ExpandFields(params.Expand, stockCount, accountId); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusOK, stockCount)
+}
+
+func (h *StockCountsHandler) List(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	listRes := api.NewListResponse(r)
+	params := stockcounts.NewListStockCountsParams()
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	stockCounts, hasMore, err := h.StockCounts.List(stockcounts.List{AccountId: accountId, RequestParams: params})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if len(stockCounts) != 0 {
+		listRes.Data = append(listRes.Data, stockCounts)
+		listRes.HasMore = hasMore
+	}
+
+	if err := h.ExpandFieldsList(params.Expand, stockCounts, accountId); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusOK, listRes)
+}
+
+func (h *StockCountsHandler) Retrieve(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	stockCountId, err := api.GetIdFromPath(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	params := stockcounts.RetrieveStockCountParams{}
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	stockCount, err := h.StockCounts.Get(stockcounts.Get{
+		AccountId:     accountId,
+		StockCountId:  stockCountId,
+		RequestParams: params,
+		OmitBase:      false,
+	})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if err := h.ExpandFields(params.Expand, stockCount, accountId); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusOK, stockCount)
+}
+
+func (h *StockCountsHandler) Submit(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	stockCountId, err := api.GetIdFromPath(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	params := stockcounts.SubmitStockCountParams{}
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	stockCount, err := h.StockCounts.Submit(stockcounts.Submit{AccountId: accountId, StockCountId: stockCountId, RequestParams: params})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if err := h.ExpandFields(params.Expand, stockCount, accountId); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusOK, stockCount)
+}
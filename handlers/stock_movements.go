@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/d-darac/inventory-api/internal/inventories"
+	"github.com/d-darac/inventory-api/internal/lots"
+	stockmovements "github.com/d-darac/inventory-api/internal/stock_movements"
+	"github.com/d-darac/inventory-api/middleware"
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+type StockMovementsHandler struct {
+	Inventories    *inventories.InventoriesService
+	Lots           *lots.LotsService
+	StockMovements *stockmovements.StockMovementsService
+	validator      *api.Validator
+}
+
+func NewStockMovementsHandler(db *database.Queries, conn *sql.DB) *StockMovementsHandler {
+	return &StockMovementsHandler{
+		Inventories:    inventories.NewInventoriesService(db, conn),
+		Lots:           lots.NewLotsService(db, conn),
+		StockMovements: stockmovements.NewStockMovementsService(db, conn),
+		validator:      api.NewValidator(),
+	}
+}
+
+func (h *StockMovementsHandler) Create(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	params := stockmovements.CreateStockMovementParams{}
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	stockMovement, err := h.StockMovements.Create(stockmovements.Create{AccountId: accountId, RequestParams: params})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if err := h.ExpandFields(params.Expand, stockMovement, accountId); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusCreated, stockMovement)
+}
+
+func (h *StockMovementsHandler) List(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	listRes := api.NewListResponse(r)
+	params := stockmovements.NewListStockMovementsParams()
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	stockMovements, hasMore, err := h.StockMovements.List(stockmovements.List{AccountId: accountId, RequestParams: params})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if len(stockMovements) != 0 {
+		listRes.Data = append(listRes.Data, stockMovements)
+		listRes.HasMore = hasMore
+	}
+
+	if err := h.ExpandFieldsList(params.Expand, stockMovements, accountId); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusOK, listRes)
+}
+
+func (h *StockMovementsHandler) Retrieve(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	stockMovementId, err := api.GetIdFromPath(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	params := stockmovements.RetrieveStockMovementParams{}
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	stockMovement, err := h.StockMovements.Get(stockmovements.Get{
+		AccountId:       accountId,
+		StockMovementId: stockMovementId,
+		RequestParams:   params,
+		OmitBase:        false,
+	})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if err := h.ExpandFields(params.Expand, stockMovement, accountId); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusOK, stockMovement)
+}
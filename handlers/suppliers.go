@@ -0,0 +1,355 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/d-darac/inventory-api/internal/items"
+	"github.com/d-darac/inventory-api/internal/suppliers"
+	"github.com/d-darac/inventory-api/middleware"
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+type SuppliersHandler struct {
+	Items     *items.ItemsService
+	Suppliers *suppliers.SuppliersService
+	validator *api.Validator
+}
+
+func NewSuppliersHandler(db *database.Queries, conn *sql.DB) *SuppliersHandler {
+	return &SuppliersHandler{
+		Items:     items.NewItemsService(db, conn),
+		Suppliers: suppliers.NewSuppliersService(db),
+		validator: api.NewValidator(),
+	}
+}
+
+func (h *SuppliersHandler) Create(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	params := suppliers.CreateSupplierParams{}
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	supplier, err := h.Suppliers.Create(suppliers.Create{AccountId: accountId, RequestParams: params})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusCreated, supplier)
+}
+
+func (h *SuppliersHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	supplierId, err := api.GetIdFromPath(r)
+
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if err = h.Suppliers.Delete(suppliers.Delete{AccountId: accountId, SupplierId: supplierId}); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusNoContent, nil)
+}
+
+func (h *SuppliersHandler) List(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	listRes := api.NewListResponse(r)
+	params := suppliers.NewListSuppliersParams()
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	suppliers, hasMore, err := h.Suppliers.List(suppliers.List{AccountId: accountId, RequestParams: params})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if len(suppliers) != 0 {
+		listRes.Data = append(listRes.Data, suppliers)
+		listRes.HasMore = hasMore
+	}
+
+	api.ResJSON(w, http.StatusOK, listRes)
+}
+
+func (h *SuppliersHandler) Retrieve(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	supplierId, err := api.GetIdFromPath(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	params := suppliers.RetrieveSupplierParams{}
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	supplier, err := h.Suppliers.Get(suppliers.Get{
+		AccountId:     accountId,
+		SupplierId:    supplierId,
+		RequestParams: params,
+		OmitBase:      false,
+	})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusOK, supplier)
+}
+
+func (h *SuppliersHandler) Update(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	supplierId, err := api.GetIdFromPath(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	params := suppliers.UpdateSupplierParams{}
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	supplier, err := h.Suppliers.Update(suppliers.Update{AccountId: accountId, SupplierId: supplierId, RequestParams: params})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusOK, supplier)
+}
+
+func (h *SuppliersHandler) CreateItemSupplier(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	itemId, err := api.GetIdFromPath(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	params := suppliers.CreateItemSupplierParams{}
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	itemSupplier, err := h.Suppliers.CreateItemSupplier(suppliers.CreateItemSupplier{
+		AccountId:     accountId,
+		ItemId:        itemId,
+		RequestParams: params,
+	})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if err := h.ExpandItemSupplierFields(params.Expand, itemSupplier, accountId); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusCreated, itemSupplier)
+}
+
+func (h *SuppliersHandler) DeleteItemSupplier(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	itemId, supplierId, err := getItemSupplierIdsFromPath(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	err = h.Suppliers.DeleteItemSupplier(suppliers.DeleteItemSupplier{
+		AccountId:  accountId,
+		ItemId:     itemId,
+		SupplierId: supplierId,
+	})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusNoContent, nil)
+}
+
+func (h *SuppliersHandler) ListItemSuppliers(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	itemId, err := api.GetIdFromPath(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	listRes := api.NewListResponse(r)
+	params := suppliers.NewListItemSuppliersParams()
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	itemSuppliers, hasMore, err := h.Suppliers.ListItemSuppliers(suppliers.ListItemSuppliers{
+		AccountId:     accountId,
+		ItemId:        itemId,
+		RequestParams: params,
+	})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if len(itemSuppliers) != 0 {
+		listRes.Data = append(listRes.Data, itemSuppliers)
+		listRes.HasMore = hasMore
+	}
+
+	if err := h.ExpandItemSupplierFieldsList(params.Expand, itemSuppliers, accountId); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusOK, listRes)
+}
+
+func (h *SuppliersHandler) RetrieveItemSupplier(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	itemId, supplierId, err := getItemSupplierIdsFromPath(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	params := suppliers.RetrieveItemSupplierParams{}
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	itemSupplier, err := h.Suppliers.GetItemSupplier(suppliers.GetItemSupplier{
+		AccountId:     accountId,
+		ItemId:        itemId,
+		SupplierId:    supplierId,
+		RequestParams: params,
+		OmitBase:      false,
+	})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if err := h.ExpandItemSupplierFields(params.Expand, itemSupplier, accountId); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusOK, itemSupplier)
+}
+
+func (h *SuppliersHandler) UpdateItemSupplier(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	itemId, supplierId, err := getItemSupplierIdsFromPath(r)
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	params := suppliers.UpdateItemSupplierParams{}
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	itemSupplier, err := h.Suppliers.UpdateItemSupplier(suppliers.UpdateItemSupplier{
+		AccountId:     accountId,
+		ItemId:        itemId,
+		SupplierId:    supplierId,
+		RequestParams: params,
+	})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if err := h.ExpandItemSupplierFields(params.Expand, itemSupplier, accountId); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	api.ResJSON(w, http.StatusOK, itemSupplier)
+}
+
+// getItemSupplierIdsFromPath returns the item's ID from {id} and the
+// supplier's from {supplier_id}.
+func getItemSupplierIdsFromPath(r *http.Request) (itemId, supplierId uuid.UUID, err error) {
+	itemId, err = api.GetIdFromPath(r)
+	if err != nil {
+		return
+	}
+	supplierId, err = uuid.Parse(r.PathValue("supplier_id"))
+	if err != nil {
+		return itemId, supplierId, api.NotFoundMessage(supplierId, "item supplier")
+	}
+	return
+}
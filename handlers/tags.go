@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/d-darac/inventory-api/internal/tags"
+	"github.com/d-darac/inventory-api/middleware"
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+type TagsHandler struct {
+	Tags      *tags.TagsService
+	validator *api.Validator
+}
+
+func NewTagsHandler(db *database.Queries) *TagsHandler {
+	return &TagsHandler{
+		Tags:      tags.NewTagsService(db),
+		validator: api.NewValidator(),
+	}
+}
+
+func (h *TagsHandler) List(w http.ResponseWriter, r *http.Request) {
+	accountId := r.Context().Value(middleware.AuthAccountID).(uuid.UUID)
+	listRes := api.NewListResponse(r)
+	params := tags.NewListTagsParams()
+
+	if err := api.JsonDecode(r, &params, w); err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if errs := h.validator.ValidateRequestParams(params); errs != nil {
+		api.ResErrorList(w, errs)
+		return
+	}
+
+	tags, hasMore, err := h.Tags.List(tags.List{AccountId: accountId, RequestParams: params})
+	if err != nil {
+		api.ResError(w, err)
+		return
+	}
+
+	if len(tags) != 0 {
+		listRes.Data = append(listRes.Data, tags)
+		listRes.HasMore = hasMore
+	}
+
+	api.ResJSON(w, http.StatusOK, listRes)
+}
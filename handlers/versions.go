@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/d-darac/inventory-assets/api"
+)
+
+// setETag exposes the version of a resource as its entity tag, so it can be
+// sent back in If-Match on the next update or delete.
+func setETag(w http.ResponseWriter, version int32) {
+	w.Header().Set("ETag", fmt.Sprintf("\"%d\"", version))
+}
+
+// parseIfMatch returns the versions listed in the If-Match header. No header,
+// or "*", matches any version and returns nil. Weak tags never match, since
+// If-Match uses strong comparison, and a header left with no usable tags
+// fails the precondition outright.
+func parseIfMatch(r *http.Request) ([]int32, error) {
+	values := r.Header.Values("If-Match")
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	var versions []int32
+	for _, tag := range strings.Split(strings.Join(values, ","), ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return nil, nil
+		}
+		if len(tag) < 2 || !strings.HasPrefix(tag, "\"") || !strings.HasSuffix(tag, "\"") {
+			continue
+		}
+		version, err := strconv.ParseInt(tag[1:len(tag)-1], 10, 32)
+		if err != nil {
+			continue
+		}
+		versions = append(versions, int32(version))
+	}
+
+	if len(versions) == 0 {
+		return nil, &api.AppError{
+			Message: "If-Match doesn't list any version of this resource.",
+			Status:  http.StatusPreconditionFailed,
+			Type:    api.InvalidRequestError,
+		}
+	}
+
+	return versions, nil
+}
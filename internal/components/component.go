@@ -0,0 +1,19 @@
+package components
+
+import (
+	"time"
+
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/google/uuid"
+)
+
+// Component is an item that goes into a bundle, with the quantity of it
+// that makes up one bundle.
+type Component struct {
+	ID        *uuid.UUID     `json:"id,omitempty"`
+	CreatedAt *time.Time     `json:"created_at,omitempty"`
+	UpdatedAt *time.Time     `json:"updated_at,omitempty"`
+	Bundle    api.Expandable `json:"bundle"`
+	Item      api.Expandable `json:"item"`
+	Quantity  int32          `json:"quantity"`
+}
@@ -0,0 +1,43 @@
+package components
+
+import (
+	"time"
+
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+func MapCreateComponentParams(create Create) database.CreateComponentParams {
+	t := time.Now()
+	ccp := database.CreateComponentParams{
+		ID:        uuid.New(),
+		CreatedAt: t,
+		UpdatedAt: t,
+		AccountID: create.AccountId,
+		BundleID:  create.BundleId,
+		ItemID:    uuid.MustParse(create.RequestParams.Item),
+		Quantity:  create.RequestParams.Quantity,
+	}
+	return ccp
+}
+
+func MapListComponentsParams(list List) database.ListComponentsParams {
+	lcp := database.ListComponentsParams{
+		AccountID: list.AccountId,
+		BundleID:  list.BundleId,
+	}
+	database.MapPaginationParams(*list.RequestParams.PaginationParams, &lcp)
+	return lcp
+}
+
+func MapUpdateComponentParams(update Update) database.UpdateComponentParams {
+	ucp := database.UpdateComponentParams{
+		UpdatedAt: time.Now(),
+		AccountID: update.AccountId,
+		BundleID:  update.BundleId,
+		ID:        update.ComponentId,
+		Quantity:  api.NullInt32(update.RequestParams.Quantity),
+	}
+	return ucp
+}
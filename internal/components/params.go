@@ -0,0 +1,39 @@
+package components
+
+import (
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+type CreateComponentParams struct {
+	Item     string   `json:"item" validate:"required,uuid"`
+	Quantity int32    `json:"quantity" validate:"required,gt=0"`
+	Expand   []string `json:"expand" validate:"omitnil,dive,oneof=item"`
+}
+
+type ListComponentsByBundlesParams struct {
+	Ids []uuid.UUID
+}
+
+type ListComponentsParams struct {
+	*database.PaginationParams
+	Expand []string `json:"expand" validate:"omitnil,dive,oneof=item"`
+}
+
+type RetrieveComponentParams struct {
+	Expand []string `json:"expand" validate:"omitnil,dive,oneof=item"`
+}
+
+type UpdateComponentParams struct {
+	Quantity *int32   `json:"quantity" validate:"omitnil,gt=0"`
+	Expand   []string `json:"expand" validate:"omitnil,dive,oneof=item"`
+}
+
+func NewListComponentsParams() ListComponentsParams {
+	limit := int32(10)
+	return ListComponentsParams{
+		PaginationParams: &database.PaginationParams{
+			Limit: &limit,
+		},
+	}
+}
@@ -0,0 +1,303 @@
+package components
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+type ComponentsService struct {
+	Db *database.Queries
+}
+
+type Create struct {
+	AccountId     uuid.UUID
+	BundleId      uuid.UUID
+	RequestParams CreateComponentParams
+}
+
+type Delete struct {
+	AccountId   uuid.UUID
+	BundleId    uuid.UUID
+	ComponentId uuid.UUID
+}
+
+type Get struct {
+	AccountId     uuid.UUID
+	BundleId      uuid.UUID
+	ComponentId   uuid.UUID
+	RequestParams RetrieveComponentParams
+	OmitBase      bool
+}
+
+type ListByBundles struct {
+	AccountId     uuid.UUID
+	RequestParams ListComponentsByBundlesParams
+}
+
+type List struct {
+	AccountId     uuid.UUID
+	BundleId      uuid.UUID
+	RequestParams ListComponentsParams
+}
+
+type Update struct {
+	AccountId     uuid.UUID
+	BundleId      uuid.UUID
+	ComponentId   uuid.UUID
+	RequestParams UpdateComponentParams
+}
+
+func NewComponentsService(db *database.Queries) *ComponentsService {
+	return &ComponentsService{
+		Db: db,
+	}
+}
+
+// Create adds an item to a bundle. Components are plain stocked items:
+// bundles don't nest, and serialized items can't be taken out of stock by
+// quantity, so neither can be a component.
+func (s *ComponentsService) Create(create Create) (*Component, error) {
+	if err := s.checkBundle(create.AccountId, create.BundleId); err != nil {
+		return nil, err
+	}
+
+	itemId := uuid.MustParse(create.RequestParams.Item)
+
+	item, err := s.Db.GetItem(context.Background(), database.GetItemParams{
+		ID:        itemId,
+		AccountID: create.AccountId,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, api.NotFoundMessage(itemId, "item")
+		}
+		return nil, err
+	}
+	if item.Type == database.ItemTypeBUNDLE || item.Serialized {
+		return nil, &api.AppError{
+			Message: fmt.Sprintf("Item '%v' is a bundle or serialized and can't be a component.", itemId),
+			Status:  http.StatusBadRequest,
+			Type:    api.InvalidRequestError,
+		}
+	}
+
+	dbParams := MapCreateComponentParams(create)
+	row, err := s.Db.CreateComponent(context.Background(), dbParams)
+	if err != nil {
+		return nil, err
+	}
+
+	component := &Component{
+		ID:        &row.ID,
+		CreatedAt: &row.CreatedAt,
+		UpdatedAt: &row.UpdatedAt,
+		Bundle:    api.Expandable{ID: row.Bundle},
+		Item:      api.Expandable{ID: row.Item},
+		Quantity:  row.Quantity,
+	}
+
+	return component, nil
+}
+
+func (s *ComponentsService) Delete(delete Delete) error {
+	_, err := s.Get(Get{
+		AccountId:     delete.AccountId,
+		BundleId:      delete.BundleId,
+		ComponentId:   delete.ComponentId,
+		RequestParams: RetrieveComponentParams{},
+		OmitBase:      true,
+	})
+	if err != nil {
+		return err
+	}
+	return s.Db.DeleteComponent(context.Background(), database.DeleteComponentParams{
+		ID:        delete.ComponentId,
+		AccountID: delete.AccountId,
+		BundleID:  delete.BundleId,
+	})
+}
+
+func (s *ComponentsService) Get(get Get) (*Component, error) {
+	row, err := s.Db.GetComponent(context.Background(), database.GetComponentParams{
+		ID:        get.ComponentId,
+		AccountID: get.AccountId,
+		BundleID:  get.BundleId,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, api.NotFoundMessage(get.ComponentId, "component")
+		}
+		return nil, err
+	}
+
+	component := &Component{
+		Bundle:   api.Expandable{ID: row.Bundle},
+		Item:     api.Expandable{ID: row.Item},
+		Quantity: row.Quantity,
+	}
+
+	if !get.OmitBase {
+		component.ID = &row.ID
+		component.CreatedAt = &row.CreatedAt
+		component.UpdatedAt = &row.UpdatedAt
+	}
+
+	return component, nil
+}
+
+func (s *ComponentsService) ListByBundles(list ListByBundles) (components []*Component, err error) {
+	params := database.ListComponentsByBundlesParams{
+		AccountID: list.AccountId,
+		Ids:       list.RequestParams.Ids,
+	}
+
+	rows, err := s.Db.ListComponentsByBundles(context.Background(), params)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return components, nil
+		}
+		return
+	}
+
+	for _, row := range rows {
+		components = append(components, &Component{
+			ID:        &row.ID,
+			CreatedAt: &row.CreatedAt,
+			UpdatedAt: &row.UpdatedAt,
+			Bundle:    api.Expandable{ID: row.Bundle},
+			Item:      api.Expandable{ID: row.Item},
+			Quantity:  row.Quantity,
+		})
+	}
+
+	return
+}
+
+func (s *ComponentsService) List(list List) (components []*Component, hasMore bool, err error) {
+	if err := s.checkBundle(list.AccountId, list.BundleId); err != nil {
+		return components, hasMore, err
+	}
+
+	if list.RequestParams.StartingAfter != nil {
+		component, err := s.Get(Get{
+			AccountId:     list.AccountId,
+			BundleId:      list.BundleId,
+			ComponentId:   *list.RequestParams.StartingAfter,
+			RequestParams: RetrieveComponentParams{},
+			OmitBase:      false,
+		})
+		if err != nil {
+			return components, hasMore, err
+		}
+		list.RequestParams.StartingAfterDate = component.CreatedAt
+	}
+
+	if list.RequestParams.EndingBefore != nil {
+		component, err := s.Get(Get{
+			AccountId:     list.AccountId,
+			BundleId:      list.BundleId,
+			ComponentId:   *list.RequestParams.EndingBefore,
+			RequestParams: RetrieveComponentParams{},
+			OmitBase:      false,
+		})
+		if err != nil {
+			return components, hasMore, err
+		}
+		list.RequestParams.EndingBeforeDate = component.CreatedAt
+	}
+
+	dbParams := MapListComponentsParams(list)
+
+	rows, err := s.Db.ListComponents(context.Background(), dbParams)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return components, hasMore, nil
+		}
+		return
+	}
+
+	if dbParams.Limit.Valid {
+		hasMore = len(rows) > int(dbParams.Limit.Int32)
+	} else {
+		hasMore = len(rows) > 10
+	}
+
+	if hasMore {
+		if dbParams.EndingBefore.Valid {
+			rows = rows[1:]
+		} else {
+			rows = rows[:len(rows)-1]
+		}
+	}
+
+	for _, row := range rows {
+		components = append(components, &Component{
+			ID:        &row.ID,
+			CreatedAt: &row.CreatedAt,
+			UpdatedAt: &row.UpdatedAt,
+			Bundle:    api.Expandable{ID: row.Bundle},
+			Item:      api.Expandable{ID: row.Item},
+			Quantity:  row.Quantity,
+		})
+	}
+
+	return components, hasMore, err
+}
+
+func (s *ComponentsService) Update(update Update) (*Component, error) {
+	_, err := s.Get(Get{
+		AccountId:     update.AccountId,
+		BundleId:      update.BundleId,
+		ComponentId:   update.ComponentId,
+		RequestParams: RetrieveComponentParams{},
+		OmitBase:      true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dbParams := MapUpdateComponentParams(update)
+
+	row, err := s.Db.UpdateComponent(context.Background(), dbParams)
+	if err != nil {
+		return nil, err
+	}
+
+	component := &Component{
+		ID:        &row.ID,
+		CreatedAt: &row.CreatedAt,
+		UpdatedAt: &row.UpdatedAt,
+		Bundle:    api.Expandable{ID: row.Bundle},
+		Item:      api.Expandable{ID: row.Item},
+		Quantity:  row.Quantity,
+	}
+
+	return component, nil
+}
+
+func (s *ComponentsService) checkBundle(accountId, bundleId uuid.UUID) error {
+	bundle, err := s.Db.GetItem(context.Background(), database.GetItemParams{
+		ID:        bundleId,
+		AccountID: accountId,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return api.NotFoundMessage(bundleId, "item")
+		}
+		return err
+	}
+	if bundle.Type != database.ItemTypeBUNDLE {
+		return &api.AppError{
+			Message: fmt.Sprintf("Item '%v' isn't a bundle, only bundles have components.", bundleId),
+			Status:  http.StatusBadRequest,
+			Type:    api.InvalidRequestError,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package events
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+type Event struct {
+	ID        *uuid.UUID         `json:"id,omitempty"`
+	CreatedAt *time.Time         `json:"created_at,omitempty"`
+	Data      json.RawMessage    `json:"data"`
+	Type      database.EventType `json:"type"`
+}
@@ -0,0 +1,29 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/d-darac/inventory-assets/ints"
+)
+
+func TestUnitBelowReorderPoint(t *testing.T) {
+	cases := []struct {
+		inStock      int32
+		reserved     int32
+		reorderPoint ints.NullInt32
+		below        bool
+	}{
+		{10, 0, ints.NullInt32{Int32: 5, Valid: true}, false},
+		{5, 0, ints.NullInt32{Int32: 5, Valid: true}, true},
+		{10, 6, ints.NullInt32{Int32: 5, Valid: true}, true},
+		{10, 4, ints.NullInt32{Int32: 5, Valid: true}, false},
+		{0, 0, ints.NullInt32{}, false},
+	}
+
+	for _, c := range cases {
+		below := BelowReorderPoint(c.inStock, c.reserved, c.reorderPoint)
+		if below != c.below {
+			t.Fatalf("expected %d in stock with %d reserved against %v to be below %v, got %v", c.inStock, c.reserved, c.reorderPoint, c.below, below)
+		}
+	}
+}
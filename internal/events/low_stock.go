@@ -0,0 +1,47 @@
+package events
+
+import (
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/d-darac/inventory-assets/ints"
+	"github.com/google/uuid"
+)
+
+// LowStock is the data of an inventory.low_stock event. Available is the
+// inventory's in_stock less what's reserved, which is what's compared with
+// the reorder point.
+type LowStock struct {
+	Inventory       uuid.UUID      `json:"inventory"`
+	Available       int32          `json:"available"`
+	InStock         int32          `json:"in_stock"`
+	Item            uuid.NullUUID  `json:"item"`
+	Location        uuid.NullUUID  `json:"location"`
+	ReorderPoint    ints.NullInt32 `json:"reorder_point"`
+	ReorderQuantity ints.NullInt32 `json:"reorder_quantity"`
+	Reserved        int32          `json:"reserved"`
+}
+
+// BelowReorderPoint reports whether the stock available, in_stock less what's
+// reserved, is at or below the reorder point.
+func BelowReorderPoint(inStock, reserved int32, reorderPoint ints.NullInt32) bool {
+	return reorderPoint.Valid && inStock-reserved <= reorderPoint.Int32
+}
+
+// RecordLowStock records an inventory.low_stock event when a write takes the
+// inventory to or below its reorder point. Only the write that crosses the
+// threshold raises one; later writes while the inventory stays there don't,
+// so wasBelow tells whether it already was before the write.
+func RecordLowStock(q *database.Queries, accountId uuid.UUID, wasBelow bool, data LowStock) error {
+	if wasBelow || !BelowReorderPoint(data.InStock, data.Reserved, data.ReorderPoint) {
+		return nil
+	}
+
+	data.Available = data.InStock - data.Reserved
+	_, err := Record(q, Create{
+		AccountId: accountId,
+		RequestParams: CreateEventParams{
+			Data: data,
+			Type: database.EventTypeInventoryLowStock,
+		},
+	})
+	return err
+}
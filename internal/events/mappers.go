@@ -0,0 +1,39 @@
+package events
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+func MapCreateEventParams(create Create) (database.CreateEventParams, error) {
+	data, err := json.Marshal(create.RequestParams.Data)
+	if err != nil {
+		return database.CreateEventParams{}, err
+	}
+	cep := database.CreateEventParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now(),
+		AccountID: create.AccountId,
+		Data:      data,
+		Type:      create.RequestParams.Type,
+	}
+	return cep, nil
+}
+
+func MapListEventsParams(list List) database.ListEventsParams {
+	lep := database.ListEventsParams{
+		AccountID: list.AccountId,
+	}
+	if list.RequestParams.Type != nil {
+		lep.Type = database.NullEventType{
+			EventType: *list.RequestParams.Type,
+			Valid:     true,
+		}
+	}
+	database.MapTimeRange(list.RequestParams.CreatedAt, &lep.CreatedAtGt, &lep.CreatedAtGte, &lep.CreatedAtLt, &lep.CreatedAtLte)
+	database.MapPaginationParams(*list.RequestParams.PaginationParams, &lep)
+	return lep
+}
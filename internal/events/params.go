@@ -0,0 +1,28 @@
+package events
+
+import (
+	"github.com/d-darac/inventory-assets/database"
+)
+
+type CreateEventParams struct {
+	Data any
+	Type database.EventType
+}
+
+type ListEventsParams struct {
+	*database.PaginationParams
+	CreatedAt *database.TimeRange `json:"created_at" validate:"omitnil"`
+	Type      *database.EventType `json:"type" validate:"omitnil,oneof=inventory.low_stock"`
+}
+
+type RetrieveEventParams struct {
+}
+
+func NewListEventsParams() ListEventsParams {
+	limit := int32(10)
+	return ListEventsParams{
+		PaginationParams: &database.PaginationParams{
+			Limit: &limit,
+		},
+	}
+}
@@ -0,0 +1,148 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+type EventsService struct {
+	Db *database.Queries
+}
+
+type Create struct {
+	AccountId     uuid.UUID
+	RequestParams CreateEventParams
+}
+
+type Get struct {
+	AccountId     uuid.UUID
+	EventId       uuid.UUID
+	RequestParams RetrieveEventParams
+	OmitBase      bool
+}
+
+type List struct {
+	AccountId     uuid.UUID
+	RequestParams ListEventsParams
+}
+
+func NewEventsService(db *database.Queries) *EventsService {
+	return &EventsService{
+		Db: db,
+	}
+}
+
+func (s *EventsService) Get(get Get) (*Event, error) {
+	row, err := s.Db.GetEvent(context.Background(), database.GetEventParams{
+		ID:        get.EventId,
+		AccountID: get.AccountId,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, api.NotFoundMessage(get.EventId, "event")
+		}
+		return nil, err
+	}
+
+	event := &Event{
+		Data: row.Data,
+		Type: row.Type,
+	}
+
+	if !get.OmitBase {
+		event.ID = &row.ID
+		event.CreatedAt = &row.CreatedAt
+	}
+
+	return event, nil
+}
+
+func (s *EventsService) List(list List) (events []*Event, hasMore bool, err error) {
+	if list.RequestParams.StartingAfter != nil {
+		event, err := s.Get(Get{
+			AccountId:     list.AccountId,
+			EventId:       *list.RequestParams.StartingAfter,
+			RequestParams: RetrieveEventParams{},
+			OmitBase:      false,
+		})
+		if err != nil {
+			return events, hasMore, err
+		}
+		list.RequestParams.StartingAfterDate = event.CreatedAt
+	}
+
+	if list.RequestParams.EndingBefore != nil {
+		event, err := s.Get(Get{
+			AccountId:     list.AccountId,
+			EventId:       *list.RequestParams.EndingBefore,
+			RequestParams: RetrieveEventParams{},
+			OmitBase:      false,
+		})
+		if err != nil {
+			return events, hasMore, err
+		}
+		list.RequestParams.EndingBeforeDate = event.CreatedAt
+	}
+
+	dbParams := MapListEventsParams(list)
+
+	rows, err := s.Db.ListEvents(context.Background(), dbParams)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return events, hasMore, nil
+		}
+		return
+	}
+
+	if dbParams.Limit.Valid {
+		hasMore = len(rows) > int(dbParams.Limit.Int32)
+	} else {
+		hasMore = len(rows) > 10
+	}
+
+	if hasMore {
+		if dbParams.EndingBefore.Valid {
+			rows = rows[1:]
+		} else {
+			rows = rows[:len(rows)-1]
+		}
+	}
+
+	for _, row := range rows {
+		events = append(events, &Event{
+			ID:        &row.ID,
+			CreatedAt: &row.CreatedAt,
+			Data:      row.Data,
+			Type:      row.Type,
+		})
+	}
+
+	return events, hasMore, err
+}
+
+// Record stores an event using q, so that it's written in the same
+// transaction as the change it describes.
+func Record(q *database.Queries, create Create) (*Event, error) {
+	dbParams, err := MapCreateEventParams(create)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := q.CreateEvent(context.Background(), dbParams)
+	if err != nil {
+		return nil, err
+	}
+
+	event := &Event{
+		ID:        &row.ID,
+		CreatedAt: &row.CreatedAt,
+		Data:      row.Data,
+		Type:      row.Type,
+	}
+
+	return event, nil
+}
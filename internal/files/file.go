@@ -0,0 +1,20 @@
+package files
+
+import (
+	"time"
+
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/google/uuid"
+)
+
+// File is an uploaded image or document, optionally attached to an item.
+// Its contents are downloaded separately from its content endpoint.
+type File struct {
+	ID          *uuid.UUID     `json:"id,omitempty"`
+	CreatedAt   *time.Time     `json:"created_at,omitempty"`
+	UpdatedAt   *time.Time     `json:"updated_at,omitempty"`
+	ContentType string         `json:"content_type"`
+	Filename    string         `json:"filename"`
+	Item        api.Expandable `json:"item"`
+	Size        int64          `json:"size"`
+}
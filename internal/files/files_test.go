@@ -0,0 +1,43 @@
+package files
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestUnitSniffContentType(t *testing.T) {
+	tests := []struct {
+		name        string
+		contents    string
+		contentType string
+		wantErr     bool
+	}{
+		{name: "png", contents: "\x89PNG\r\n\x1a\n" + strings.Repeat("\x00", 600), contentType: "image/png"},
+		{name: "pdf", contents: "%PDF-1.7\n", contentType: "application/pdf"},
+		{name: "text", contents: "Weight: 2 kg", contentType: "text/plain"},
+		{name: "binary", contents: "\x00\x01\x02\x03", wantErr: true},
+		{name: "html", contents: "<html><body></body></html>", wantErr: true},
+		{name: "empty", contents: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		contentType, r, err := sniffContentType(strings.NewReader(tt.contents))
+		if tt.wantErr {
+			if err == nil {
+				t.Fatalf("%s: expected an error", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if contentType != tt.contentType {
+			t.Fatalf("%s: expected %s, got %s", tt.name, tt.contentType, contentType)
+		}
+		contents, _ := io.ReadAll(r)
+		if string(contents) != tt.contents {
+			t.Fatalf("%s: the sniffed bytes weren't given back", tt.name)
+		}
+	}
+}
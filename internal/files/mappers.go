@@ -0,0 +1,56 @@
+package files
+
+import (
+	"time"
+
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+func MapCreateFileParams(create Create) database.CreateFileParams {
+	t := time.Now()
+	cfp := database.CreateFileParams{
+		ID:        uuid.New(),
+		CreatedAt: t,
+		UpdatedAt: t,
+		AccountID: create.AccountId,
+		Filename:  create.RequestParams.Filename,
+		ItemID:    api.NullUUID(nil),
+	}
+	if create.RequestParams.Item != nil {
+		itemId := uuid.MustParse(*create.RequestParams.Item)
+		cfp.ItemID = api.NullUUID(&itemId)
+	}
+	return cfp
+}
+
+func MapListFilesParams(list List) database.ListFilesParams {
+	lfp := database.ListFilesParams{
+		AccountID:   list.AccountId,
+		ContentType: api.NullString(list.RequestParams.ContentType),
+		ItemID:      api.NullUUID(nil),
+	}
+	if list.RequestParams.Item != nil {
+		itemId := uuid.MustParse(*list.RequestParams.Item)
+		lfp.ItemID = api.NullUUID(&itemId)
+	}
+	database.MapTimeRange(list.RequestParams.CreatedAt, &lfp.CreatedAtGt, &lfp.CreatedAtGte, &lfp.CreatedAtLt, &lfp.CreatedAtLte)
+	database.MapPaginationParams(*list.RequestParams.PaginationParams, &lfp)
+	return lfp
+}
+
+func MapUpdateFileParams(update Update) database.UpdateFileParams {
+	ufp := database.UpdateFileParams{
+		UpdatedAt: time.Now(),
+		AccountID: update.AccountId,
+		ID:        update.FileId,
+		Filename:  api.NullString(update.RequestParams.Filename),
+		ItemID:    api.NullUUID(nil),
+	}
+	if update.RequestParams.Item != nil {
+		itemId := uuid.MustParse(*update.RequestParams.Item)
+		ufp.ItemID = api.NullUUID(&itemId)
+	}
+	return ufp
+}
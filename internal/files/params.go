@@ -0,0 +1,45 @@
+package files
+
+import (
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+// CreateFileParams holds the form fields of an upload, which come before
+// its "file" part.
+type CreateFileParams struct {
+	Filename string   `json:"filename" validate:"required,max=255"`
+	Item     *string  `json:"item" validate:"omitnil,uuid"`
+	Expand   []string `json:"expand" validate:"omitnil,dive,oneof=item"`
+}
+
+type ListFilesByItemsParams struct {
+	Ids []uuid.UUID
+}
+
+type ListFilesParams struct {
+	*database.PaginationParams
+	ContentType *string             `json:"content_type" validate:"omitnil"`
+	CreatedAt   *database.TimeRange `json:"created_at" validate:"omitnil"`
+	Item        *string             `json:"item" validate:"omitnil,uuid"`
+	Expand      []string            `json:"expand" validate:"omitnil,dive,oneof=item"`
+}
+
+type RetrieveFileParams struct {
+	Expand []string `json:"expand" validate:"omitnil,dive,oneof=item"`
+}
+
+type UpdateFileParams struct {
+	Filename *string  `json:"filename" validate:"omitnil,min=1,max=255"`
+	Item     *string  `json:"item" validate:"omitnil,uuid"`
+	Expand   []string `json:"expand" validate:"omitnil,dive,oneof=item"`
+}
+
+func NewListFilesParams() ListFilesParams {
+	limit := int32(10)
+	return ListFilesParams{
+		PaginationParams: &database.PaginationParams{
+			Limit: &limit,
+		},
+	}
+}
@@ -0,0 +1,413 @@
+package files
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+
+	"github.com/d-darac/inventory-api/internal/storage"
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+// MaxSize is the largest file that can be uploaded, in bytes.
+const MaxSize = 10 << 20
+
+// contentTypes are the content types files can have: images for product
+// photos and documents for spec sheets. They're sniffed from the contents,
+// whatever the client claims.
+var contentTypes = map[string]bool{
+	"application/pdf": true,
+	"image/gif":       true,
+	"image/jpeg":      true,
+	"image/png":       true,
+	"image/webp":      true,
+	"text/plain":      true,
+}
+
+type FilesService struct {
+	Db      *database.Queries
+	Storage storage.Storage
+}
+
+type Create struct {
+	AccountId     uuid.UUID
+	RequestParams CreateFileParams
+	Body          io.Reader
+}
+
+type Delete struct {
+	AccountId uuid.UUID
+	FileId    uuid.UUID
+}
+
+type Get struct {
+	AccountId     uuid.UUID
+	FileId        uuid.UUID
+	RequestParams RetrieveFileParams
+	OmitBase      bool
+}
+
+type ListByItems struct {
+	AccountId     uuid.UUID
+	RequestParams ListFilesByItemsParams
+}
+
+type List struct {
+	AccountId     uuid.UUID
+	RequestParams ListFilesParams
+}
+
+type Update struct {
+	AccountId     uuid.UUID
+	FileId        uuid.UUID
+	RequestParams UpdateFileParams
+}
+
+func NewFilesService(db *database.Queries, store storage.Storage) *FilesService {
+	return &FilesService{
+		Db:      db,
+		Storage: store,
+	}
+}
+
+// Create streams the upload in Body to storage and records it. The contents
+// are stored before the row is created, and removed again if that fails.
+func (s *FilesService) Create(create Create) (*File, error) {
+	dbParams := MapCreateFileParams(create)
+
+	if dbParams.ItemID.Valid {
+		if err := s.checkItem(create.AccountId, dbParams.ItemID.UUID); err != nil {
+			return nil, err
+		}
+	}
+
+	contentType, body, err := sniffContentType(create.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	key := storageKey(create.AccountId, dbParams.ID)
+	size, err := s.Storage.Put(key, io.LimitReader(body, MaxSize+1))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return nil, api.RequestTooLargeMessage()
+		}
+		return nil, err
+	}
+	if size > MaxSize {
+		s.deleteContents(key)
+		return nil, tooLargeMessage()
+	}
+
+	dbParams.ContentType = contentType
+	dbParams.Size = size
+	dbParams.StorageKey = key
+
+	row, err := s.Db.CreateFile(context.Background(), dbParams)
+	if err != nil {
+		s.deleteContents(key)
+		return nil, err
+	}
+
+	file := &File{
+		ID:          &row.ID,
+		CreatedAt:   &row.CreatedAt,
+		UpdatedAt:   &row.UpdatedAt,
+		ContentType: row.ContentType,
+		Filename:    row.Filename,
+		Item:        api.Expandable{ID: row.Item},
+		Size:        row.Size,
+	}
+
+	return file, nil
+}
+
+func (s *FilesService) Delete(delete Delete) error {
+	row, err := s.Db.GetFile(context.Background(), database.GetFileParams{
+		ID:        delete.FileId,
+		AccountID: delete.AccountId,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return api.NotFoundMessage(delete.FileId, "file")
+		}
+		return err
+	}
+
+	err = s.Db.DeleteFile(context.Background(), database.DeleteFileParams{
+		ID:        delete.FileId,
+		AccountID: delete.AccountId,
+	})
+	if err != nil {
+		return err
+	}
+
+	s.deleteContents(row.StorageKey)
+	return nil
+}
+
+func (s *FilesService) Get(get Get) (*File, error) {
+	row, err := s.Db.GetFile(context.Background(), database.GetFileParams{
+		ID:        get.FileId,
+		AccountID: get.AccountId,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, api.NotFoundMessage(get.FileId, "file")
+		}
+		return nil, err
+	}
+
+	file := &File{
+		ContentType: row.ContentType,
+		Filename:    row.Filename,
+		Item:        api.Expandable{ID: row.Item},
+		Size:        row.Size,
+	}
+
+	if !get.OmitBase {
+		file.ID = &row.ID
+		file.CreatedAt = &row.CreatedAt
+		file.UpdatedAt = &row.UpdatedAt
+	}
+
+	return file, nil
+}
+
+func (s *FilesService) ListByItems(list ListByItems) (files []*File, err error) {
+	params := database.ListFilesByItemsParams{
+		AccountID: list.AccountId,
+		Ids:       list.RequestParams.Ids,
+	}
+
+	rows, err := s.Db.ListFilesByItems(context.Background(), params)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return files, nil
+		}
+		return
+	}
+
+	for _, row := range rows {
+		files = append(files, &File{
+			ID:          &row.ID,
+			CreatedAt:   &row.CreatedAt,
+			UpdatedAt:   &row.UpdatedAt,
+			ContentType: row.ContentType,
+			Filename:    row.Filename,
+			Item:        api.Expandable{ID: row.Item},
+			Size:        row.Size,
+		})
+	}
+
+	return
+}
+
+func (s *FilesService) List(list List) (files []*File, hasMore bool, err error) {
+	if list.RequestParams.StartingAfter != nil {
+		file, err := s.Get(Get{
+			AccountId:     list.AccountId,
+			FileId:        *list.RequestParams.StartingAfter,
+			RequestParams: RetrieveFileParams{},
+			OmitBase:      false,
+		})
+		if err != nil {
+			return files, hasMore, err
+		}
+		list.RequestParams.StartingAfterDate = file.CreatedAt
+	}
+
+	if list.RequestParams.EndingBefore != nil {
+		file, err := s.Get(Get{
+			AccountId:     list.AccountId,
+			FileId:        *list.RequestParams.EndingBefore,
+			RequestParams: RetrieveFileParams{},
+			OmitBase:      false,
+		})
+		if err != nil {
+			return files, hasMore, err
+		}
+		list.RequestParams.EndingBeforeDate = file.CreatedAt
+	}
+
+	dbParams := MapListFilesParams(list)
+
+	rows, err := s.Db.ListFiles(context.Background(), dbParams)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return files, hasMore, nil
+		}
+		return
+	}
+
+	if dbParams.Limit.Valid {
+		hasMore = len(rows) > int(dbParams.Limit.Int32)
+	} else {
+		hasMore = len(rows) > 10
+	}
+
+	if hasMore {
+		if dbParams.EndingBefore.Valid {
+			rows = rows[1:]
+		} else {
+			rows = rows[:len(rows)-1]
+		}
+	}
+
+	for _, row := range rows {
+		files = append(files, &File{
+			ID:          &row.ID,
+			CreatedAt:   &row.CreatedAt,
+			UpdatedAt:   &row.UpdatedAt,
+			ContentType: row.ContentType,
+			Filename:    row.Filename,
+			Item:        api.Expandable{ID: row.Item},
+			Size:        row.Size,
+		})
+	}
+
+	return files, hasMore, err
+}
+
+// Open returns a file along with its contents, which the caller closes.
+func (s *FilesService) Open(get Get) (*File, io.ReadCloser, error) {
+	row, err := s.Db.GetFile(context.Background(), database.GetFileParams{
+		ID:        get.FileId,
+		AccountID: get.AccountId,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil, api.NotFoundMessage(get.FileId, "file")
+		}
+		return nil, nil, err
+	}
+
+	contents, err := s.Storage.Open(row.StorageKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	file := &File{
+		ID:          &row.ID,
+		CreatedAt:   &row.CreatedAt,
+		UpdatedAt:   &row.UpdatedAt,
+		ContentType: row.ContentType,
+		Filename:    row.Filename,
+		Item:        api.Expandable{ID: row.Item},
+		Size:        row.Size,
+	}
+
+	return file, contents, nil
+}
+
+func (s *FilesService) Update(update Update) (*File, error) {
+	_, err := s.Get(Get{
+		AccountId:     update.AccountId,
+		FileId:        update.FileId,
+		RequestParams: RetrieveFileParams{},
+		OmitBase:      true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dbParams := MapUpdateFileParams(update)
+
+	if dbParams.ItemID.Valid {
+		if err := s.checkItem(update.AccountId, dbParams.ItemID.UUID); err != nil {
+			return nil, err
+		}
+	}
+
+	row, err := s.Db.UpdateFile(context.Background(), dbParams)
+	if err != nil {
+		return nil, err
+	}
+
+	file := &File{
+		ID:          &row.ID,
+		CreatedAt:   &row.CreatedAt,
+		UpdatedAt:   &row.UpdatedAt,
+		ContentType: row.ContentType,
+		Filename:    row.Filename,
+		Item:        api.Expandable{ID: row.Item},
+		Size:        row.Size,
+	}
+
+	return file, nil
+}
+
+func (s *FilesService) checkItem(accountId, itemId uuid.UUID) error {
+	_, err := s.Db.GetItem(context.Background(), database.GetItemParams{
+		ID:        itemId,
+		AccountID: accountId,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return api.NotFoundMessage(itemId, "item")
+		}
+		return err
+	}
+	return nil
+}
+
+// deleteContents removes stored contents that no longer have a row. It only
+// logs failures, since the row they belonged to is already gone.
+func (s *FilesService) deleteContents(key string) {
+	if err := s.Storage.Delete(key); err != nil {
+		log.Printf("[files] Couldn't delete contents '%s': %v.", key, err)
+	}
+}
+
+// sniffContentType detects the content type of r from its first 512 bytes
+// and returns a reader that still yields all of r.
+func sniffContentType(r io.Reader) (string, io.Reader, error) {
+	head := make([]byte, 512)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return "", nil, api.RequestTooLargeMessage()
+		}
+		return "", nil, err
+	}
+	if n == 0 {
+		return "", nil, &api.AppError{
+			Message: "The uploaded file is empty.",
+			Status:  http.StatusBadRequest,
+			Type:    api.InvalidRequestError,
+		}
+	}
+
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(head[:n]))
+	if !contentTypes[contentType] {
+		return "", nil, &api.AppError{
+			Message: fmt.Sprintf("Files of type '%s' can't be uploaded.", contentType),
+			Status:  http.StatusUnsupportedMediaType,
+			Type:    api.InvalidRequestError,
+		}
+	}
+
+	return contentType, io.MultiReader(bytes.NewReader(head[:n]), r), nil
+}
+
+func storageKey(accountId, fileId uuid.UUID) string {
+	return accountId.String() + "/" + fileId.String()
+}
+
+func tooLargeMessage() error {
+	return &api.AppError{
+		Message: fmt.Sprintf("Files can be at most %d bytes.", MaxSize),
+		Status:  http.StatusRequestEntityTooLarge,
+		Type:    api.InvalidRequestError,
+	}
+}
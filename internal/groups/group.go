@@ -8,11 +8,31 @@ import (
 	"github.com/google/uuid"
 )
 
+// Group files items into a hierarchy. Path holds the names of the groups from
+// the root of the hierarchy down to and including the group.
 type Group struct {
-	ID          *uuid.UUID     `json:"id,omitempty"`
-	CreatedAt   *time.Time     `json:"created_at,omitempty"`
-	UpdatedAt   *time.Time     `json:"updated_at,omitempty"`
-	Description str.NullString `json:"description"`
-	Name        string         `json:"name"`
-	ParentGroup api.Expandable `json:"parent_group"`
+	ID          *uuid.UUID        `json:"id,omitempty"`
+	CreatedAt   *time.Time        `json:"created_at,omitempty"`
+	UpdatedAt   *time.Time        `json:"updated_at,omitempty"`
+	Description str.NullString    `json:"description"`
+	Name        string            `json:"name"`
+	ParentGroup api.Expandable    `json:"parent_group"`
+	Path        []string          `json:"path"`
+	Metadata    map[string]string `json:"metadata"`
+	Version     int32             `json:"version"`
 }
+
+// Tree is a group with its descendants nested under it.
+type Tree struct {
+	*Group
+	Children []*Tree `json:"children"`
+}
+
+// OnDelete is what deleting a group does to its child groups and items.
+type OnDelete string
+
+const (
+	OnDeleteCascade  OnDelete = "cascade"
+	OnDeleteReparent OnDelete = "reparent"
+	OnDeleteRestrict OnDelete = "restrict"
+)
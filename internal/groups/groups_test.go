@@ -5,9 +5,11 @@ import (
 	"database/sql"
 	"fmt"
 	"os"
+	"slices"
 	"testing"
 	"time"
 
+	"github.com/d-darac/inventory-assets/api"
 	"github.com/d-darac/inventory-assets/database"
 	"github.com/google/uuid"
 	"github.com/joho/godotenv"
@@ -27,6 +29,26 @@ func TestUnitNewListGroupsParams(t *testing.T) {
 	}
 }
 
+func TestUnitRetrieveGroupTreeParamsDepth(t *testing.T) {
+	five := int32(5)
+
+	tests := []struct {
+		name   string
+		params RetrieveGroupTreeParams
+		want   int32
+	}{
+		{name: "default", params: NewRetrieveGroupTreeParams(), want: 3},
+		{name: "null", params: RetrieveGroupTreeParams{}, want: 3},
+		{name: "requested", params: RetrieveGroupTreeParams{Depth: &five}, want: 5},
+	}
+
+	for _, tt := range tests {
+		if got := tt.params.depth(); got != tt.want {
+			t.Fatalf("%s: expected depth %d, got %d", tt.name, tt.want, got)
+		}
+	}
+}
+
 func TestUnitMapCreateGroupParams(t *testing.T) {
 	acc := uuid.New()
 	name := "test-group"
@@ -59,8 +81,10 @@ func TestUnitMapListGroupsParams(t *testing.T) {
 	lp := NewListGroupsParams()
 	name := "test-group"
 	desc := "description"
+	descendantOf := uuid.New()
 	lp.Name = &name
 	lp.Description = &desc
+	lp.DescendantOf = func() *string { s := descendantOf.String(); return &s }()
 
 	dbp := MapListGroupsParams(List{AccountId: acc, RequestParams: lp})
 	if dbp.AccountID != acc {
@@ -72,6 +96,82 @@ func TestUnitMapListGroupsParams(t *testing.T) {
 	if (!dbp.Description.Valid) || dbp.Description.String != desc {
 		t.Fatalf("expected description %s, got %s", desc, dbp.Description.String)
 	}
+	if (!dbp.DescendantOf.Valid) || dbp.DescendantOf.UUID != descendantOf {
+		t.Fatalf("expected descendant of %v, got %v", descendantOf, dbp.DescendantOf.UUID)
+	}
+}
+
+func TestUnitBuildTree(t *testing.T) {
+	group := func(parent *uuid.UUID, name string) *Group {
+		id := uuid.New()
+		return &Group{ID: &id, Name: name, ParentGroup: api.Expandable{ID: api.NullUUID(parent)}}
+	}
+	root := group(nil, "root")
+	shirts := group(root.ID, "shirts")
+	shoes := group(root.ID, "shoes")
+	polos := group(shirts.ID, "polos")
+	stray := group(func() *uuid.UUID { id := uuid.New(); return &id }(), "stray")
+
+	// polos is listed before its parent to make sure order doesn't matter
+	tree := buildTree(root, []*Group{polos, shirts, shoes, stray})
+
+	if len(tree.Children) != 2 {
+		t.Fatalf("expected 2 children, got %d", len(tree.Children))
+	}
+	if tree.Children[0].Name != "shirts" || tree.Children[1].Name != "shoes" {
+		t.Fatalf("expected children shirts and shoes, got %s and %s", tree.Children[0].Name, tree.Children[1].Name)
+	}
+	if len(tree.Children[0].Children) != 1 || tree.Children[0].Children[0].Name != "polos" {
+		t.Fatalf("expected polos under shirts, got %v", tree.Children[0].Children)
+	}
+	if tree.Children[1].Children == nil || len(tree.Children[1].Children) != 0 {
+		t.Fatalf("expected shoes to have an empty list of children, got %v", tree.Children[1].Children)
+	}
+}
+
+func TestUnitCreatesCycle(t *testing.T) {
+	group := uuid.New()
+	parent := uuid.New()
+
+	tests := []struct {
+		name      string
+		parent    uuid.UUID
+		ancestors []uuid.UUID
+		want      bool
+	}{
+		{name: "itself", parent: group, want: true},
+		{name: "root parent", parent: parent, want: false},
+		{name: "unrelated parent", parent: parent, ancestors: []uuid.UUID{uuid.New(), uuid.New()}, want: false},
+		{name: "descendant", parent: parent, ancestors: []uuid.UUID{uuid.New(), group}, want: true},
+	}
+
+	for _, tt := range tests {
+		if got := createsCycle(group, tt.parent, tt.ancestors); got != tt.want {
+			t.Fatalf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestUnitItemsInUse(t *testing.T) {
+	tests := []struct {
+		name       string
+		dependents database.CountGroupTreeItemDependentsRow
+		want       []string
+	}{
+		{name: "unused", want: []string{}},
+		{name: "inventories", dependents: database.CountGroupTreeItemDependentsRow{Inventories: 2}, want: []string{"2 inventories"}},
+		{
+			name:       "several",
+			dependents: database.CountGroupTreeItemDependentsRow{Reservations: 1, OrderLines: 3, Variants: 1},
+			want:       []string{"1 open reservations", "3 lines on open orders", "1 variants in other groups"},
+		},
+	}
+
+	for _, tt := range tests {
+		if got := itemsInUse(tt.dependents); !slices.Equal(got, tt.want) {
+			t.Fatalf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
 }
 
 func TestUnitMapUpdateGroupParams(t *testing.T) {
@@ -82,7 +182,7 @@ func TestUnitMapUpdateGroupParams(t *testing.T) {
 		Name: &name,
 	}
 
-	dbp := MapUpdateGroupParams(Update{AccountId: acc, GroupId: id, RequestParams: up})
+	dbp := MapUpdateGroupParams(Update{AccountId: acc, GroupId: id, IfMatch: []int32{3}, RequestParams: up})
 	if dbp.ID != id {
 		t.Fatalf("expected id %v, got %v", id, dbp.ID)
 	}
@@ -92,6 +192,9 @@ func TestUnitMapUpdateGroupParams(t *testing.T) {
 	if (!dbp.Name.Valid) || dbp.Name.String != name {
 		t.Fatalf("expected name %s, got %s", name, dbp.Name.String)
 	}
+	if len(dbp.IfMatch) != 1 || dbp.IfMatch[0] != 3 {
+		t.Fatalf("expected if match [3], got %v", dbp.IfMatch)
+	}
 }
 
 func TestIntegrationCreate(t *testing.T) {
@@ -126,7 +229,7 @@ func TestIntegrationCreate(t *testing.T) {
 	}
 	defer db.Exec("DELETE FROM accounts WHERE id = $1;", acc.ID)
 
-	s := NewGroupsService(q)
+	s := NewGroupsService(q, db)
 	parentName := "test-group-parent"
 	parentDesc := "parent-description"
 	childName := "test-group-child"
@@ -220,7 +323,7 @@ func TestIntegrationDelete(t *testing.T) {
 	}
 	defer db.Exec("DELETE FROM accounts WHERE id = $1;", acc.ID)
 
-	s := NewGroupsService(q)
+	s := NewGroupsService(q, db)
 	name := "test-group"
 	desc := "description"
 
@@ -279,7 +382,7 @@ func TestIntegrationList(t *testing.T) {
 	}
 	defer db.Exec("DELETE FROM accounts WHERE id = $1;", acc.ID)
 
-	s := NewGroupsService(q)
+	s := NewGroupsService(q, db)
 	name := "test-group"
 	desc := "description"
 	rows := make([]database.CreateGroupRow, 0, 10)
@@ -357,7 +460,7 @@ func TestIntegrationRetrieve(t *testing.T) {
 	}
 	defer db.Exec("DELETE FROM accounts WHERE id = $1;", acc.ID)
 
-	s := NewGroupsService(q)
+	s := NewGroupsService(q, db)
 	name := "test-group"
 	desc := "description"
 
@@ -430,7 +533,7 @@ func TestIntegrationUpdate(t *testing.T) {
 		t.Fatalf("couldn't create test account: %v", err)
 	}
 
-	s := NewGroupsService(q)
+	s := NewGroupsService(q, db)
 	name := "test-group"
 	desc := "description"
 	updatedName := "test-group-updated"
@@ -477,3 +580,75 @@ func TestIntegrationUpdate(t *testing.T) {
 		t.Fatalf("expected description %s, got %s", updatedDesc, row.Description.String)
 	}
 }
+
+func TestIntegrationUpdateConcurrentMoves(t *testing.T) {
+	godotenv.Load("../../.env")
+	dbUrl := os.Getenv("DB_URL")
+
+	db, err := sql.Open("postgres", dbUrl)
+	if err != nil {
+		t.Fatalf("couldn't open database: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.Ping(); err != nil {
+		t.Fatalf("couldn't connect to database: %v", err)
+	}
+
+	q := database.New(db)
+
+	acc, err := q.CreateAccount(context.Background(), database.CreateAccountParams{
+		Country: database.CountryIE,
+		Nickname: sql.NullString{
+			String: "Test Account",
+			Valid:  true,
+		},
+	})
+	if err != nil {
+		t.Fatalf("couldn't create test account: %v", err)
+	}
+	defer db.Exec("DELETE FROM accounts WHERE id = $1;", acc.ID)
+
+	s := NewGroupsService(q, db)
+
+	a, err := q.CreateGroup(context.Background(), database.CreateGroupParams{Name: "a", AccountID: acc.ID})
+	if err != nil {
+		t.Fatalf("couldn't create test group: %v", err)
+	}
+	b, err := q.CreateGroup(context.Background(), database.CreateGroupParams{Name: "b", AccountID: acc.ID})
+	if err != nil {
+		t.Fatalf("couldn't create test group: %v", err)
+	}
+
+	// a under b and b under a are each fine on their own, but not together
+	move := func(groupId, parentId uuid.UUID, errs chan<- error) {
+		parent := parentId.String()
+		_, err := s.Update(Update{AccountId: acc.ID, GroupId: groupId, RequestParams: UpdateGroupParams{ParentGroup: &parent}})
+		errs <- err
+	}
+	errs := make(chan error, 2)
+	go move(a.ID, b.ID, errs)
+	go move(b.ID, a.ID, errs)
+
+	failed := 0
+	for range 2 {
+		if err := <-errs; err != nil {
+			failed++
+		}
+	}
+	if failed != 1 {
+		t.Fatalf("expected exactly one of the moves to fail, %d did", failed)
+	}
+
+	for _, id := range []uuid.UUID{a.ID, b.ID} {
+		ancestors, err := q.ListGroupAncestors(context.Background(), database.ListGroupAncestorsParams{AccountID: acc.ID, ID: id})
+		if err != nil && err != sql.ErrNoRows {
+			t.Fatalf("error listing ancestors: %v", err)
+		}
+		for _, ancestor := range ancestors {
+			if ancestor.ID == id {
+				t.Fatalf("group %v is its own ancestor", id)
+			}
+		}
+	}
+}
@@ -3,6 +3,7 @@ package groups
 import (
 	"time"
 
+	"github.com/d-darac/inventory-api/internal/metadata"
 	"github.com/d-darac/inventory-assets/api"
 	"github.com/d-darac/inventory-assets/database"
 	"github.com/google/uuid"
@@ -18,6 +19,7 @@ func MapCreateGroupParams(create Create) database.CreateGroupParams {
 		Description: api.NullString(create.RequestParams.Description),
 		Name:        create.RequestParams.Name,
 		ParentID:    api.NullUUID(nil),
+		Metadata:    metadata.Encode(create.RequestParams.Metadata),
 	}
 	if create.RequestParams.ParentGroup != nil {
 		parentGroupId := uuid.MustParse(*create.RequestParams.ParentGroup)
@@ -28,10 +30,16 @@ func MapCreateGroupParams(create Create) database.CreateGroupParams {
 
 func MapListGroupsParams(list List) database.ListGroupsParams {
 	lgp := database.ListGroupsParams{
-		AccountID:   list.AccountId,
-		Description: api.NullString(list.RequestParams.Description),
-		Name:        api.NullString(list.RequestParams.Name),
-		ParentID:    api.NullUUID(nil),
+		AccountID:    list.AccountId,
+		Description:  api.NullString(list.RequestParams.Description),
+		Name:         api.NullString(list.RequestParams.Name),
+		ParentID:     api.NullUUID(nil),
+		DescendantOf: api.NullUUID(nil),
+		Metadata:     metadata.Encode(list.RequestParams.Metadata),
+	}
+	if list.RequestParams.DescendantOf != nil {
+		descendantOf := uuid.MustParse(*list.RequestParams.DescendantOf)
+		lgp.DescendantOf = api.NullUUID(&descendantOf)
 	}
 	if list.RequestParams.ParentGroup != nil {
 		parentGroupId := uuid.MustParse(*list.RequestParams.ParentGroup)
@@ -47,10 +55,12 @@ func MapUpdateGroupParams(update Update) database.UpdateGroupParams {
 	ugp := database.UpdateGroupParams{
 		UpdatedAt:   time.Now(),
 		AccountID:   update.AccountId,
+		IfMatch:     update.IfMatch,
 		Description: api.NullString(update.RequestParams.Description),
 		ID:          update.GroupId,
 		Name:        api.NullString(update.RequestParams.Name),
 		ParentID:    api.NullUUID(nil),
+		Metadata:    metadata.Encode(update.RequestParams.Metadata),
 	}
 	if update.RequestParams.ParentGroup != nil {
 		parentGroupId := uuid.MustParse(*update.RequestParams.ParentGroup)
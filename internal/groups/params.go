@@ -6,10 +6,15 @@ import (
 )
 
 type CreateGroupParams struct {
-	Description *string  `json:"description" validate:"omitnil"`
-	Name        string   `json:"name" validate:"required"`
-	ParentGroup *string  `json:"parent_group" validate:"omitnil,uuid"`
-	Expand      []string `json:"expand" validate:"omitnil,dive,oneof=parent_group"`
+	Description *string           `json:"description" validate:"omitnil"`
+	Name        string            `json:"name" validate:"required"`
+	ParentGroup *string           `json:"parent_group" validate:"omitnil,uuid"`
+	Metadata    map[string]string `json:"metadata" validate:"omitnil,max=50,dive,keys,min=1,max=40,endkeys,max=500"`
+	Expand      []string          `json:"expand" validate:"omitnil,dive,oneof=parent_group"`
+}
+
+type DeleteGroupParams struct {
+	OnDelete OnDelete `json:"on_delete" validate:"required,oneof=cascade reparent restrict"`
 }
 
 type ListGroupsByIdsParams struct {
@@ -18,23 +23,55 @@ type ListGroupsByIdsParams struct {
 
 type ListGroupsParams struct {
 	*database.PaginationParams
-	CreatedAt   *database.TimeRange `json:"created_at" validate:"omitnil"`
-	ParentGroup *string             `json:"parent_group" validate:"omitnil,uuid"`
-	Description *string             `json:"description" validate:"omitnil"`
-	Name        *string             `json:"name" validate:"omitnil"`
-	UpdatedAt   *database.TimeRange `json:"updated_at" validate:"omitnil"`
-	Expand      []string            `json:"expand" validate:"omitnil,dive,oneof=parent_group"`
+	CreatedAt    *database.TimeRange `json:"created_at" validate:"omitnil"`
+	DescendantOf *string             `json:"descendant_of" validate:"omitnil,uuid"`
+	ParentGroup  *string             `json:"parent_group" validate:"omitnil,uuid"`
+	Description  *string             `json:"description" validate:"omitnil"`
+	Name         *string             `json:"name" validate:"omitnil"`
+	UpdatedAt    *database.TimeRange `json:"updated_at" validate:"omitnil"`
+	Metadata     map[string]string   `json:"metadata" validate:"omitnil,max=50,dive,keys,min=1,max=40,endkeys,max=500"`
+	Expand       []string            `json:"expand" validate:"omitnil,dive,oneof=parent_group"`
 }
 
 type RetrieveGroupParams struct {
 	Expand []string `json:"expand" validate:"omitnil,dive,oneof=parent_group"`
 }
 
+// RetrieveGroupTreeParams limits how many levels of descendants are nested
+// under the group, 1 being its children only.
+type RetrieveGroupTreeParams struct {
+	Depth *int32 `json:"depth" validate:"omitnil,gte=1,lte=10"`
+}
+
+const defaultTreeDepth = int32(3)
+
+// depth is the requested depth, or the default when it was sent as null.
+func (p RetrieveGroupTreeParams) depth() int32 {
+	if p.Depth == nil {
+		return defaultTreeDepth
+	}
+	return *p.Depth
+}
+
 type UpdateGroupParams struct {
-	Description *string  `json:"description" validate:"omitnil"`
-	Name        *string  `json:"name" validate:"omitnil"`
-	ParentGroup *string  `json:"parent_group" validate:"omitnil,uuid"`
-	Expand      []string `json:"expand" validate:"omitnil,dive,oneof=parent_group"`
+	Description *string           `json:"description" validate:"omitnil"`
+	Name        *string           `json:"name" validate:"omitnil"`
+	ParentGroup *string           `json:"parent_group" validate:"omitnil,uuid"`
+	Metadata    map[string]string `json:"metadata" validate:"omitnil,max=50,dive,keys,min=1,max=40,endkeys,max=500"`
+	Expand      []string          `json:"expand" validate:"omitnil,dive,oneof=parent_group"`
+}
+
+func NewDeleteGroupParams() DeleteGroupParams {
+	return DeleteGroupParams{
+		OnDelete: OnDeleteRestrict,
+	}
+}
+
+func NewRetrieveGroupTreeParams() RetrieveGroupTreeParams {
+	depth := defaultTreeDepth
+	return RetrieveGroupTreeParams{
+		Depth: &depth,
+	}
 }
 
 func NewListGroupsParams() ListGroupsParams {
@@ -3,7 +3,13 @@ package groups
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/d-darac/inventory-api/internal/metadata"
 	"github.com/d-darac/inventory-assets/api"
 	"github.com/d-darac/inventory-assets/database"
 	"github.com/d-darac/inventory-assets/str"
@@ -11,7 +17,8 @@ import (
 )
 
 type GroupsService struct {
-	Db *database.Queries
+	Conn *sql.DB
+	Db   *database.Queries
 }
 
 type Create struct {
@@ -20,6 +27,13 @@ type Create struct {
 }
 
 type Delete struct {
+	AccountId     uuid.UUID
+	GroupId       uuid.UUID
+	IfMatch       []int32
+	RequestParams DeleteGroupParams
+}
+
+type GetAncestors struct {
 	AccountId uuid.UUID
 	GroupId   uuid.UUID
 }
@@ -41,19 +55,34 @@ type List struct {
 	RequestParams ListGroupsParams
 }
 
+type GetTree struct {
+	AccountId     uuid.UUID
+	GroupId       uuid.UUID
+	RequestParams RetrieveGroupTreeParams
+}
+
 type Update struct {
 	AccountId     uuid.UUID
 	GroupId       uuid.UUID
+	IfMatch       []int32
 	RequestParams UpdateGroupParams
 }
 
-func NewGroupsService(db *database.Queries) *GroupsService {
+func NewGroupsService(db *database.Queries, conn *sql.DB) *GroupsService {
 	return &GroupsService{
-		Db: db,
+		Conn: conn,
+		Db:   db,
 	}
 }
 
 func (s *GroupsService) Create(create Create) (*Group, error) {
+	if create.RequestParams.ParentGroup != nil {
+		parentId := uuid.MustParse(*create.RequestParams.ParentGroup)
+		if err := checkParent(s.Db, create.AccountId, nil, parentId); err != nil {
+			return nil, err
+		}
+	}
+
 	dbParams := MapCreateGroupParams(create)
 	row, err := s.Db.CreateGroup(context.Background(), dbParams)
 	if err != nil {
@@ -67,25 +96,158 @@ func (s *GroupsService) Create(create Create) (*Group, error) {
 		Description: str.NullString(row.Description),
 		Name:        row.Name,
 		ParentGroup: api.Expandable{ID: row.ParentGroup},
+		Path:        row.Path,
+		Metadata:    metadata.Decode(row.Metadata),
+		Version:     row.Version,
 	}
 
 	return group, nil
 }
 
+// Delete removes the group. OnDelete decides what happens to its child groups
+// and items: restrict refuses to delete a group that has any, reparent moves
+// them up to the group's own parent and cascade deletes the groups below it
+// along with the items in all of them. Cascade is refused while any of those
+// items is still in use in a way deleting the item on its own would refuse,
+// or that would leave stock and orders pointing at nothing.
 func (s *GroupsService) Delete(delete Delete) error {
-	_, err := s.Get(Get{
-		AccountId:     delete.AccountId,
-		GroupId:       delete.GroupId,
-		RequestParams: RetrieveGroupParams{},
-		OmitBase:      true,
+	tx, err := s.Conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := s.Db.WithTx(tx)
+
+	current, err := qtx.GetGroupForUpdate(context.Background(), database.GetGroupForUpdateParams{
+		ID:        delete.GroupId,
+		AccountID: delete.AccountId,
 	})
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return api.NotFoundMessage(delete.GroupId, "group")
+		}
 		return err
 	}
-	return s.Db.DeleteGroup(context.Background(), database.DeleteGroupParams{
+
+	switch delete.RequestParams.OnDelete {
+	case OnDeleteCascade:
+		dependents, err := qtx.CountGroupTreeItemDependents(context.Background(), database.CountGroupTreeItemDependentsParams{
+			AccountID: delete.AccountId,
+			ID:        delete.GroupId,
+		})
+		if err != nil {
+			return err
+		}
+		if inUse := itemsInUse(dependents); len(inUse) != 0 {
+			return &api.AppError{
+				Message: fmt.Sprintf("Group '%v' can't be deleted with its items, which still have %s.", delete.GroupId, strings.Join(inUse, ", ")),
+				Status:  http.StatusBadRequest,
+				Type:    api.InvalidRequestError,
+			}
+		}
+		if _, err := qtx.DeleteGroupTreeItems(context.Background(), database.DeleteGroupTreeItemsParams{
+			AccountID: delete.AccountId,
+			ID:        delete.GroupId,
+		}); err != nil {
+			return err
+		}
+		if _, err := qtx.DeleteGroupDescendants(context.Background(), database.DeleteGroupDescendantsParams{
+			AccountID: delete.AccountId,
+			ID:        delete.GroupId,
+		}); err != nil {
+			return err
+		}
+	case OnDeleteReparent:
+		if err := qtx.LockGroupHierarchy(context.Background(), delete.AccountId); err != nil {
+			return err
+		}
+		t := time.Now()
+		if _, err := qtx.ReparentGroupChildren(context.Background(), database.ReparentGroupChildrenParams{
+			UpdatedAt: t,
+			AccountID: delete.AccountId,
+			ID:        delete.GroupId,
+			ParentID:  current.ParentGroup,
+		}); err != nil {
+			return err
+		}
+		if _, err := qtx.ReparentGroupItems(context.Background(), database.ReparentGroupItemsParams{
+			UpdatedAt: t,
+			AccountID: delete.AccountId,
+			ID:        delete.GroupId,
+			GroupID:   current.ParentGroup,
+		}); err != nil {
+			return err
+		}
+	default:
+		dependents, err := qtx.CountGroupDependents(context.Background(), database.CountGroupDependentsParams{
+			AccountID: delete.AccountId,
+			ID:        delete.GroupId,
+		})
+		if err != nil {
+			return err
+		}
+		if dependents.Groups != 0 || dependents.Items != 0 {
+			return &api.AppError{
+				Message: fmt.Sprintf("Group '%v' still has %d child groups and %d items, use on_delete reparent to move them up or cascade to delete them too.", delete.GroupId, dependents.Groups, dependents.Items),
+				Status:  http.StatusBadRequest,
+				Type:    api.InvalidRequestError,
+			}
+		}
+	}
+
+	rows, err := qtx.DeleteGroup(context.Background(), database.DeleteGroupParams{
 		ID:        delete.GroupId,
 		AccountID: delete.AccountId,
+		IfMatch:   delete.IfMatch,
+	})
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return versionMismatchMessage(delete.GroupId)
+	}
+
+	return tx.Commit()
+}
+
+// Ancestors lists the groups above the group, starting from the root of the
+// hierarchy and ending with its parent.
+func (s *GroupsService) Ancestors(get GetAncestors) (groups []*Group, err error) {
+	if _, err := s.Get(Get{
+		AccountId:     get.AccountId,
+		GroupId:       get.GroupId,
+		RequestParams: RetrieveGroupParams{},
+		OmitBase:      true,
+	}); err != nil {
+		return groups, err
+	}
+
+	rows, err := s.Db.ListGroupAncestors(context.Background(), database.ListGroupAncestorsParams{
+		AccountID: get.AccountId,
+		ID:        get.GroupId,
 	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return groups, nil
+		}
+		return
+	}
+
+	for _, row := range rows {
+		groups = append(groups, &Group{
+			ID:          &row.ID,
+			CreatedAt:   &row.CreatedAt,
+			UpdatedAt:   &row.UpdatedAt,
+			Description: str.NullString(row.Description),
+			Name:        row.Name,
+			ParentGroup: api.Expandable{ID: row.ParentGroup},
+			Path:        row.Path,
+			Metadata:    metadata.Decode(row.Metadata),
+			Version:     row.Version,
+		})
+	}
+
+	return
 }
 
 func (s *GroupsService) Get(get Get) (*Group, error) {
@@ -104,6 +266,9 @@ func (s *GroupsService) Get(get Get) (*Group, error) {
 		Description: str.NullString(row.Description),
 		Name:        row.Name,
 		ParentGroup: api.Expandable{ID: row.ParentGroup},
+		Path:        row.Path,
+		Metadata:    metadata.Decode(row.Metadata),
+		Version:     row.Version,
 	}
 
 	if !get.OmitBase {
@@ -137,6 +302,9 @@ func (s *GroupsService) ListByIds(list ListByIds) (groups []*Group, err error) {
 			Description: str.NullString(row.Description),
 			Name:        row.Name,
 			ParentGroup: api.Expandable{ID: row.ParentGroup},
+			Path:        row.Path,
+			Metadata:    metadata.Decode(row.Metadata),
+			Version:     row.Version,
 		})
 	}
 
@@ -202,27 +370,110 @@ func (s *GroupsService) List(list List) (groups []*Group, hasMore bool, err erro
 			Description: str.NullString(row.Description),
 			Name:        row.Name,
 			ParentGroup: api.Expandable{ID: row.ParentGroup},
+			Path:        row.Path,
+			Metadata:    metadata.Decode(row.Metadata),
+			Version:     row.Version,
 		})
 	}
 
 	return groups, hasMore, err
 }
 
-func (s *GroupsService) Update(update Update) (*Group, error) {
-	_, err := s.Get(Get{
-		AccountId:     update.AccountId,
-		GroupId:       update.GroupId,
+// Tree returns the group with its descendants nested under it, down to the
+// requested depth.
+func (s *GroupsService) Tree(get GetTree) (*Tree, error) {
+	group, err := s.Get(Get{
+		AccountId:     get.AccountId,
+		GroupId:       get.GroupId,
 		RequestParams: RetrieveGroupParams{},
-		OmitBase:      true,
+		OmitBase:      false,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.Db.ListGroupDescendants(context.Background(), database.ListGroupDescendantsParams{
+		AccountID: get.AccountId,
+		ID:        get.GroupId,
+		Depth:     get.RequestParams.depth(),
 	})
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	descendants := make([]*Group, 0, len(rows))
+	for _, row := range rows {
+		descendants = append(descendants, &Group{
+			ID:          &row.ID,
+			CreatedAt:   &row.CreatedAt,
+			UpdatedAt:   &row.UpdatedAt,
+			Description: str.NullString(row.Description),
+			Name:        row.Name,
+			ParentGroup: api.Expandable{ID: row.ParentGroup},
+			Path:        row.Path,
+			Metadata:    metadata.Decode(row.Metadata),
+			Version:     row.Version,
+		})
+	}
+
+	return buildTree(group, descendants), nil
+}
+
+func (s *GroupsService) Update(update Update) (*Group, error) {
+	tx, err := s.Conn.BeginTx(context.Background(), nil)
 	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
+	qtx := s.Db.WithTx(tx)
+
+	// Two moves checked at the same time could each pass on their own and
+	// still make a cycle together, e.g. a under b and b under a, so moves
+	// within an account wait on each other until the first one commits.
+	if update.RequestParams.ParentGroup != nil {
+		if err := qtx.LockGroupHierarchy(context.Background(), update.AccountId); err != nil {
+			return nil, err
+		}
+	}
+
+	current, err := qtx.GetGroupForUpdate(context.Background(), database.GetGroupForUpdateParams{
+		ID:        update.GroupId,
+		AccountID: update.AccountId,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, api.NotFoundMessage(update.GroupId, "group")
+		}
+		return nil, err
+	}
+
+	if update.RequestParams.ParentGroup != nil {
+		parentId := uuid.MustParse(*update.RequestParams.ParentGroup)
+		if err := checkParent(qtx, update.AccountId, &update.GroupId, parentId); err != nil {
+			return nil, err
+		}
+	}
+
+	if update.RequestParams.Metadata != nil {
+		update.RequestParams.Metadata, err = metadata.Merge(metadata.Decode(current.Metadata), update.RequestParams.Metadata)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	dbParams := MapUpdateGroupParams(update)
 
-	row, err := s.Db.UpdateGroup(context.Background(), dbParams)
+	row, err := qtx.UpdateGroup(context.Background(), dbParams)
 	if err != nil {
+		// The group is locked, so no row means its version has moved
+		// past the one in If-Match.
+		if err == sql.ErrNoRows {
+			return nil, versionMismatchMessage(update.GroupId)
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
 		return nil, err
 	}
 
@@ -233,7 +484,117 @@ func (s *GroupsService) Update(update Update) (*Group, error) {
 		Description: str.NullString(row.Description),
 		Name:        row.Name,
 		ParentGroup: api.Expandable{ID: row.ParentGroup},
+		Path:        row.Path,
+		Metadata:    metadata.Decode(row.Metadata),
+		Version:     row.Version,
 	}
 
 	return group, nil
 }
+
+// buildTree nests the descendants under their parents, keeping the order they
+// were listed in. Descendants whose parent isn't in the tree are left out.
+func buildTree(root *Group, descendants []*Group) *Tree {
+	tree := &Tree{Group: root, Children: []*Tree{}}
+
+	nodes := map[uuid.UUID]*Tree{*root.ID: tree}
+	for _, group := range descendants {
+		nodes[*group.ID] = &Tree{Group: group, Children: []*Tree{}}
+	}
+
+	for _, group := range descendants {
+		if parent, ok := nodes[group.ParentGroup.ID.UUID]; ok && group.ParentGroup.ID.Valid {
+			parent.Children = append(parent.Children, nodes[*group.ID])
+		}
+	}
+
+	return tree
+}
+
+// checkParent makes sure the parent exists in the account and, when a group is
+// being moved, that the parent isn't the group itself or one of its
+// descendants, which would turn the hierarchy into a cycle. Moves should hold
+// the account's hierarchy lock, so the ancestors can't change underneath it.
+func checkParent(q *database.Queries, accountId uuid.UUID, groupId *uuid.UUID, parentId uuid.UUID) error {
+	_, err := q.GetGroup(context.Background(), database.GetGroupParams{
+		ID:        parentId,
+		AccountID: accountId,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return &api.AppError{
+				Message: fmt.Sprintf("Parent group '%v' doesn't exist.", parentId),
+				Status:  http.StatusBadRequest,
+				Type:    api.InvalidRequestError,
+			}
+		}
+		return err
+	}
+
+	if groupId == nil {
+		return nil
+	}
+
+	rows, err := q.ListGroupAncestors(context.Background(), database.ListGroupAncestorsParams{
+		AccountID: accountId,
+		ID:        parentId,
+	})
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	ancestors := make([]uuid.UUID, 0, len(rows))
+	for _, row := range rows {
+		ancestors = append(ancestors, row.ID)
+	}
+
+	if createsCycle(*groupId, parentId, ancestors) {
+		return &api.AppError{
+			Message: fmt.Sprintf("Group '%v' can't be moved under group '%v', which is the group itself or one of its descendants.", *groupId, parentId),
+			Status:  http.StatusBadRequest,
+			Type:    api.InvalidRequestError,
+		}
+	}
+
+	return nil
+}
+
+// itemsInUse lists what still holds on to the items of a group's tree, each
+// with its count. Variants and bundle components only count when they're
+// outside the tree, as the ones inside are deleted along with it.
+func itemsInUse(dependents database.CountGroupTreeItemDependentsRow) []string {
+	counts := []struct {
+		count int64
+		what  string
+	}{
+		{dependents.Inventories, "inventories"},
+		{dependents.SerialNumbers, "serial numbers"},
+		{dependents.Reservations, "open reservations"},
+		{dependents.OrderLines, "lines on open orders"},
+		{dependents.PurchaseOrderLines, "lines on open purchase orders"},
+		{dependents.Variants, "variants in other groups"},
+		{dependents.BundleComponents, "places as components of bundles in other groups"},
+	}
+
+	inUse := make([]string, 0, len(counts))
+	for _, c := range counts {
+		if c.count != 0 {
+			inUse = append(inUse, fmt.Sprintf("%d %s", c.count, c.what))
+		}
+	}
+	return inUse
+}
+
+// createsCycle reports whether moving the group under the parent, given the
+// parent's ancestors, would make the group its own ancestor.
+func createsCycle(groupId, parentId uuid.UUID, ancestors []uuid.UUID) bool {
+	return groupId == parentId || slices.Contains(ancestors, groupId)
+}
+
+func versionMismatchMessage(groupId uuid.UUID) error {
+	return &api.AppError{
+		Message: fmt.Sprintf("Group '%v' has changed since the version in If-Match.", groupId),
+		Status:  http.StatusPreconditionFailed,
+		Type:    api.InvalidRequestError,
+	}
+}
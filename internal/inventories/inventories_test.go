@@ -0,0 +1,85 @@
+package inventories
+
+import (
+	"testing"
+
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/d-darac/inventory-assets/ints"
+	"github.com/google/uuid"
+)
+
+func TestUnitBundleInStock(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	component := func(id uuid.UUID, quantity int32) database.ListBundleComponentInventoriesRow {
+		return database.ListBundleComponentInventoriesRow{
+			Item:      uuid.New(),
+			Inventory: uuid.NullUUID{UUID: id, Valid: true},
+			Quantity:  quantity,
+		}
+	}
+
+	cases := []struct {
+		name       string
+		components []database.ListBundleComponentInventoriesRow
+		inStock    map[uuid.UUID]int32
+		bundles    int32
+	}{
+		{"no components", nil, nil, 0},
+		{"single component", []database.ListBundleComponentInventoriesRow{component(a, 2)}, map[uuid.UUID]int32{a: 7}, 3},
+		{"fewest covered", []database.ListBundleComponentInventoriesRow{component(a, 1), component(b, 3)}, map[uuid.UUID]int32{a: 10, b: 6}, 2},
+		{"no stock as of", []database.ListBundleComponentInventoriesRow{component(a, 1), component(b, 1)}, map[uuid.UUID]int32{a: 4}, 0},
+		{"negative stock", []database.ListBundleComponentInventoriesRow{component(a, 1)}, map[uuid.UUID]int32{a: -3}, 0},
+		{"component without inventory", []database.ListBundleComponentInventoriesRow{component(a, 1), {Item: uuid.New(), Quantity: 1}}, map[uuid.UUID]int32{a: 4}, 0},
+	}
+
+	for _, c := range cases {
+		bundles := bundleInStock(c.components, c.inStock)
+		if bundles != c.bundles {
+			t.Fatalf("%s: expected %d bundles, got %d", c.name, c.bundles, bundles)
+		}
+	}
+}
+
+func TestUnitMatchesStock(t *testing.T) {
+	yes, no := true, false
+	five := int32(5)
+
+	cases := []struct {
+		name      string
+		inventory *Inventory
+		params    ListInventoriesParams
+		matches   bool
+	}{
+		{"no filters", &Inventory{InStock: 3}, ListInventoriesParams{}, true},
+		{"in_stock equal", &Inventory{InStock: 5}, ListInventoriesParams{InStock: &five}, true},
+		{"in_stock differs", &Inventory{InStock: 4}, ListInventoriesParams{InStock: &five}, false},
+		{"below reorder point", &Inventory{InStock: 5, ReorderPoint: ints.NullInt32{Int32: 5, Valid: true}}, ListInventoriesParams{BelowReorderPoint: &yes}, true},
+		{"above reorder point", &Inventory{InStock: 6, ReorderPoint: ints.NullInt32{Int32: 5, Valid: true}}, ListInventoriesParams{BelowReorderPoint: &yes}, false},
+		{"not below reorder point", &Inventory{InStock: 6, ReorderPoint: ints.NullInt32{Int32: 5, Valid: true}}, ListInventoriesParams{BelowReorderPoint: &no}, true},
+		{"reserved ignored", &Inventory{InStock: 6, Reserved: ints.NullInt32{Int32: 4, Valid: true}, ReorderPoint: ints.NullInt32{Int32: 5, Valid: true}}, ListInventoriesParams{BelowReorderPoint: &yes}, false},
+		{"no reorder point", &Inventory{InStock: 0}, ListInventoriesParams{BelowReorderPoint: &yes}, false},
+	}
+
+	for _, c := range cases {
+		matches := matchesStock(c.inventory, c.params)
+		if matches != c.matches {
+			t.Fatalf("%s: expected match %v, got %v", c.name, c.matches, matches)
+		}
+	}
+}
+
+func TestUnitMapUpdateInventoryParams(t *testing.T) {
+	reorderPoint := int32(5)
+	update := Update{AccountId: uuid.New(), InventoryId: uuid.New(), RequestParams: UpdateInventoryParams{ReorderPoint: &reorderPoint}}
+
+	dbp := MapUpdateInventoryParams(update)
+	if dbp.ID != update.InventoryId {
+		t.Fatalf("expected inventory id %v, got %v", update.InventoryId, dbp.ID)
+	}
+	if dbp.LocationID.Valid {
+		t.Fatalf("expected the location to be left as it is, got %v", dbp.LocationID.UUID)
+	}
+	if (!dbp.ReorderPoint.Valid) || dbp.ReorderPoint.Int32 != reorderPoint {
+		t.Fatalf("expected reorder point %d, got %v", reorderPoint, dbp.ReorderPoint)
+	}
+}
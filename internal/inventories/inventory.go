@@ -3,15 +3,60 @@ package inventories
 import (
 	"time"
 
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
 	"github.com/d-darac/inventory-assets/ints"
+	"github.com/d-darac/inventory-assets/str"
 	"github.com/google/uuid"
 )
 
+// Inventory is the stock of an item at a location. Orderable is the quantity
+// still on order through sent purchase orders and Reserved the quantity held
+// by reservations, both are kept up to date by those and can't be set
+// directly.
 type Inventory struct {
-	ID        *uuid.UUID     `json:"id,omitempty"`
-	CreatedAt *time.Time     `json:"created_at,omitempty"`
-	UpdatedAt *time.Time     `json:"updated_at,omitempty"`
-	InStock   int32          `json:"in_stock"`
-	Orderable ints.NullInt32 `json:"orderable"`
-	Reserved  ints.NullInt32 `json:"reserved"`
+	ID              *uuid.UUID        `json:"id,omitempty"`
+	CreatedAt       *time.Time        `json:"created_at,omitempty"`
+	UpdatedAt       *time.Time        `json:"updated_at,omitempty"`
+	InStock         int32             `json:"in_stock"`
+	Item            api.Expandable    `json:"item"`
+	Location        api.Expandable    `json:"location"`
+	Orderable       ints.NullInt32    `json:"orderable"`
+	ReorderPoint    ints.NullInt32    `json:"reorder_point"`
+	ReorderQuantity ints.NullInt32    `json:"reorder_quantity"`
+	Reserved        ints.NullInt32    `json:"reserved"`
+	SafetyStock     ints.NullInt32    `json:"safety_stock"`
+	Metadata        map[string]string `json:"metadata"`
+	Version         int32             `json:"version"`
+}
+
+// HistoryEntry is a stock movement of an inventory together with the
+// in_stock it left the inventory with.
+type HistoryEntry struct {
+	ID        uuid.UUID                  `json:"id"`
+	CreatedAt time.Time                  `json:"created_at"`
+	InStock   int32                      `json:"in_stock"`
+	Quantity  int32                      `json:"quantity"`
+	Reason    str.NullString             `json:"reason"`
+	Reference str.NullString             `json:"reference"`
+	Type      database.StockMovementType `json:"type"`
+}
+
+// Stock is the stock of a single item summed over all of its inventories.
+type Stock struct {
+	InStock     int32        `json:"in_stock"`
+	Reserved    int32        `json:"reserved"`
+	Inventories []*Inventory `json:"inventories"`
+}
+
+func NewStock(inventories []*Inventory) *Stock {
+	stock := &Stock{
+		Inventories: make([]*Inventory, 0, len(inventories)),
+	}
+	for _, inventory := range inventories {
+		stock.InStock += inventory.InStock
+		stock.Reserved += inventory.Reserved.Int32
+		stock.Inventories = append(stock.Inventories, inventory)
+	}
+	return stock
 }
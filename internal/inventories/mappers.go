@@ -1,8 +1,10 @@
 package inventories
 
 import (
+	"database/sql"
 	"time"
 
+	"github.com/d-darac/inventory-api/internal/metadata"
 	"github.com/d-darac/inventory-assets/api"
 	"github.com/d-darac/inventory-assets/database"
 	"github.com/google/uuid"
@@ -11,32 +13,74 @@ import (
 func MapCreateInventoryParams(create Create) database.CreateInventoryParams {
 	t := time.Now()
 	cip := database.CreateInventoryParams{
-		ID:        uuid.New(),
-		CreatedAt: t,
-		UpdatedAt: t,
-		AccountID: create.AccountId,
-		InStock:   create.RequestParams.InStock,
-		Orderable: api.NullInt32(create.RequestParams.Orderable),
+		ID:              uuid.New(),
+		CreatedAt:       t,
+		UpdatedAt:       t,
+		AccountID:       create.AccountId,
+		InStock:         create.RequestParams.InStock,
+		ItemID:          api.NullUUID(nil),
+		LocationID:      api.NullUUID(nil),
+		ReorderPoint:    api.NullInt32(create.RequestParams.ReorderPoint),
+		ReorderQuantity: api.NullInt32(create.RequestParams.ReorderQuantity),
+		SafetyStock:     api.NullInt32(create.RequestParams.SafetyStock),
+		Metadata:        metadata.Encode(create.RequestParams.Metadata),
+	}
+	if create.RequestParams.Item != nil {
+		itemId := uuid.MustParse(*create.RequestParams.Item)
+		cip.ItemID = api.NullUUID(&itemId)
+	}
+	if create.RequestParams.Location != nil {
+		locationId := uuid.MustParse(*create.RequestParams.Location)
+		cip.LocationID = api.NullUUID(&locationId)
 	}
 	return cip
 }
 
 func MapListInventoriesParams(list List) database.ListInventoriesParams {
 	lip := database.ListInventoriesParams{
-		AccountID: list.AccountId,
+		AccountID:         list.AccountId,
+		BelowReorderPoint: api.NullBool(list.RequestParams.BelowReorderPoint),
+		ItemID:            api.NullUUID(nil),
+		LocationID:        api.NullUUID(nil),
+		Metadata:          metadata.Encode(list.RequestParams.Metadata),
+	}
+	if list.RequestParams.Item != nil {
+		itemId := uuid.MustParse(*list.RequestParams.Item)
+		lip.ItemID = api.NullUUID(&itemId)
+	}
+	if list.RequestParams.Location != nil {
+		locationId := uuid.MustParse(*list.RequestParams.Location)
+		lip.LocationID = api.NullUUID(&locationId)
 	}
 	database.MapTimeRange(list.RequestParams.CreatedAt, &lip.CreatedAtGt, &lip.CreatedAtGte, &lip.CreatedAtLt, &lip.CreatedAtLte)
 	database.MapTimeRange(list.RequestParams.UpdatedAt, &lip.UpdatedAtGt, &lip.UpdatedAtGte, &lip.UpdatedAtLt, &lip.UpdatedAtLte)
+	if asOf := list.RequestParams.AsOf; asOf != nil && (!lip.CreatedAtLte.Valid || asOf.Before(lip.CreatedAtLte.Time)) {
+		lip.CreatedAtLte = sql.NullTime{Time: *asOf, Valid: true}
+	}
 	database.MapPaginationParams(*list.RequestParams.PaginationParams, &lip)
 	return lip
 }
 
+func MapListInventoryHistoryParams(history History) database.ListInventoryHistoryParams {
+	lihp := database.ListInventoryHistoryParams{
+		AccountID:   history.AccountId,
+		InventoryID: history.InventoryId,
+	}
+	database.MapTimeRange(history.RequestParams.CreatedAt, &lihp.CreatedAtGt, &lihp.CreatedAtGte, &lihp.CreatedAtLt, &lihp.CreatedAtLte)
+	database.MapPaginationParams(*history.RequestParams.PaginationParams, &lihp)
+	return lihp
+}
+
 func MapUpdateInventoryParams(update Update) database.UpdateInventoryParams {
-	return database.UpdateInventoryParams{
-		UpdatedAt: time.Now(),
-		AccountID: update.AccountId,
-		ID:        update.InventoryId,
-		InStock:   api.NullInt32(update.RequestParams.InStock),
-		Orderable: api.NullInt32(update.RequestParams.Orderable),
+	uip := database.UpdateInventoryParams{
+		UpdatedAt:       time.Now(),
+		AccountID:       update.AccountId,
+		ID:              update.InventoryId,
+		LocationID:      api.NullUUID(nil),
+		ReorderPoint:    api.NullInt32(update.RequestParams.ReorderPoint),
+		ReorderQuantity: api.NullInt32(update.RequestParams.ReorderQuantity),
+		SafetyStock:     api.NullInt32(update.RequestParams.SafetyStock),
+		Metadata:        metadata.Encode(update.RequestParams.Metadata),
 	}
+	return uip
 }
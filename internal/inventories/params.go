@@ -1,34 +1,74 @@
 package inventories
 
 import (
+	"time"
+
 	"github.com/d-darac/inventory-assets/database"
 	"github.com/google/uuid"
 )
 
 type CreateInventoryParams struct {
-	InStock   int32  `json:"in_stock" validate:"required"`
-	Orderable *int32 `json:"orderable" validate:"omitnil"`
+	InStock         int32             `json:"in_stock" validate:"required"`
+	Item            *string           `json:"item" validate:"omitnil,uuid"`
+	Location        *string           `json:"location" validate:"omitnil,uuid"`
+	ReorderPoint    *int32            `json:"reorder_point" validate:"omitnil,gte=0"`
+	ReorderQuantity *int32            `json:"reorder_quantity" validate:"omitnil,gt=0"`
+	SafetyStock     *int32            `json:"safety_stock" validate:"omitnil,gte=0"`
+	Metadata        map[string]string `json:"metadata" validate:"omitnil,max=50,dive,keys,min=1,max=40,endkeys,max=500"`
+	Expand          []string          `json:"expand" validate:"omitnil,dive,oneof=item location"`
 }
 
 type ListInventoriesByIdsParams struct {
 	Ids []uuid.UUID
 }
 
-type ListInventoriesParams struct {
+type ListInventoriesByItemsParams struct {
+	Ids []uuid.UUID
+}
+
+type ListInventoryHistoryParams struct {
 	*database.PaginationParams
 	CreatedAt *database.TimeRange `json:"created_at" validate:"omitnil"`
-	UpdatedAt *database.TimeRange `json:"updated_at" validate:"omitnil"`
-	InStock   *int32              `json:"in_stock" validate:"omitnil"`
-	Orderable *int32              `json:"orderable" validate:"omitnil"`
-	Reserved  *int32              `json:"reserved" validate:"omitnil"`
+}
+
+type ListInventoriesParams struct {
+	*database.PaginationParams
+	AsOf              *time.Time          `json:"as_of" validate:"omitnil"`
+	CreatedAt         *database.TimeRange `json:"created_at" validate:"omitnil"`
+	UpdatedAt         *database.TimeRange `json:"updated_at" validate:"omitnil"`
+	BelowReorderPoint *bool               `json:"below_reorder_point" validate:"omitnil"`
+	InStock           *int32              `json:"in_stock" validate:"omitnil"`
+	Item              *string             `json:"item" validate:"omitnil,uuid"`
+	Location          *string             `json:"location" validate:"omitnil,uuid"`
+	Orderable         *int32              `json:"orderable" validate:"omitnil"`
+	Reserved          *int32              `json:"reserved" validate:"omitnil"`
+	Metadata          map[string]string   `json:"metadata" validate:"omitnil,max=50,dive,keys,min=1,max=40,endkeys,max=500"`
+	Expand            []string            `json:"expand" validate:"omitnil,dive,oneof=item location"`
 }
 
 type RetrieveInventoryParams struct {
+	Expand []string `json:"expand" validate:"omitnil,dive,oneof=item location"`
 }
 
+// UpdateInventoryParams leaves out location, since moving an inventory would
+// take its stock, lots and reservations along without a trace. Stock moves
+// between locations through transfers.
 type UpdateInventoryParams struct {
-	InStock   *int32 `json:"in_stock" validate:"omitnil"`
-	Orderable *int32 `json:"orderable" validate:"omitnil"`
+	InStock         *int32            `json:"in_stock" validate:"omitnil"`
+	ReorderPoint    *int32            `json:"reorder_point" validate:"omitnil,gte=0"`
+	ReorderQuantity *int32            `json:"reorder_quantity" validate:"omitnil,gt=0"`
+	SafetyStock     *int32            `json:"safety_stock" validate:"omitnil,gte=0"`
+	Metadata        map[string]string `json:"metadata" validate:"omitnil,max=50,dive,keys,min=1,max=40,endkeys,max=500"`
+	Expand          []string          `json:"expand" validate:"omitnil,dive,oneof=item location"`
+}
+
+func NewListInventoryHistoryParams() ListInventoryHistoryParams {
+	limit := int32(10)
+	return ListInventoryHistoryParams{
+		PaginationParams: &database.PaginationParams{
+			Limit: &limit,
+		},
+	}
 }
 
 func NewListInventoriesParams() ListInventoriesParams {
@@ -3,15 +3,24 @@ package inventories
 import (
 	"context"
 	"database/sql"
-
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/d-darac/inventory-api/internal/events"
+	"github.com/d-darac/inventory-api/internal/metadata"
+	stockmovements "github.com/d-darac/inventory-api/internal/stock_movements"
 	"github.com/d-darac/inventory-assets/api"
 	"github.com/d-darac/inventory-assets/database"
 	"github.com/d-darac/inventory-assets/ints"
+	"github.com/d-darac/inventory-assets/str"
 	"github.com/google/uuid"
 )
 
 type InventoriesService struct {
-	Db *database.Queries
+	Conn *sql.DB
+	Db   *database.Queries
 }
 
 type Create struct {
@@ -22,6 +31,7 @@ type Create struct {
 type Delete struct {
 	AccountId   uuid.UUID
 	InventoryId uuid.UUID
+	IfMatch     []int32
 }
 
 type Get struct {
@@ -36,6 +46,17 @@ type ListByIds struct {
 	RequestParams ListInventoriesByIdsParams
 }
 
+type ListByItems struct {
+	AccountId     uuid.UUID
+	RequestParams ListInventoriesByItemsParams
+}
+
+type History struct {
+	AccountId     uuid.UUID
+	InventoryId   uuid.UUID
+	RequestParams ListInventoryHistoryParams
+}
+
 type List struct {
 	AccountId     uuid.UUID
 	RequestParams ListInventoriesParams
@@ -44,28 +65,97 @@ type List struct {
 type Update struct {
 	AccountId     uuid.UUID
 	InventoryId   uuid.UUID
+	IfMatch       []int32
 	RequestParams UpdateInventoryParams
 }
 
-func NewInventoriesService(db *database.Queries) *InventoriesService {
+func NewInventoriesService(db *database.Queries, conn *sql.DB) *InventoriesService {
 	return &InventoriesService{
-		Db: db,
+		Conn: conn,
+		Db:   db,
 	}
 }
 
 func (s *InventoriesService) Create(create Create) (*Inventory, error) {
+	tx, err := s.Conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.Db.WithTx(tx)
+
 	dbParams := MapCreateInventoryParams(create)
-	row, err := s.Db.CreateInventory(context.Background(), dbParams)
+	row, err := qtx.CreateInventory(context.Background(), dbParams)
 	if err != nil {
 		return nil, err
 	}
 
+	// in_stock is already set on the new row, so the opening balance is
+	// written straight to the ledger instead of being posted.
+	if row.InStock != 0 {
+		serialized, err := qtx.IsInventorySerialized(context.Background(), database.IsInventorySerializedParams{
+			ID:        row.ID,
+			AccountID: create.AccountId,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if serialized {
+			return nil, &api.AppError{
+				Message: "An inventory of a serialized item can't be created with stock, its stock comes in through serial numbers.",
+				Status:  http.StatusBadRequest,
+				Type:    api.InvalidRequestError,
+			}
+		}
+
+		bundle, err := qtx.IsInventoryBundle(context.Background(), database.IsInventoryBundleParams{
+			ID:        row.ID,
+			AccountID: create.AccountId,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if bundle {
+			return nil, &api.AppError{
+				Message: "An inventory of a bundle can't be created with stock, a bundle has no stock of its own.",
+				Status:  http.StatusBadRequest,
+				Type:    api.InvalidRequestError,
+			}
+		}
+
+		reason := "opening balance"
+		_, err = qtx.CreateStockMovement(context.Background(), stockmovements.MapCreateStockMovementParams(stockmovements.Create{
+			AccountId: create.AccountId,
+			RequestParams: stockmovements.CreateStockMovementParams{
+				Inventory: row.ID.String(),
+				Quantity:  row.InStock,
+				Reason:    &reason,
+				Type:      database.StockMovementTypeAdjustment,
+			},
+		}))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
 	inventory := &Inventory{
-		ID:        &row.ID,
-		CreatedAt: &row.CreatedAt,
-		UpdatedAt: &row.UpdatedAt,
-		InStock:   row.InStock,
-		Orderable: ints.NullInt32(row.Orderable),
+		ID:              &row.ID,
+		CreatedAt:       &row.CreatedAt,
+		UpdatedAt:       &row.UpdatedAt,
+		InStock:         row.InStock,
+		Item:            api.Expandable{ID: row.Item},
+		Location:        api.Expandable{ID: row.Location},
+		Orderable:       ints.NullInt32(row.Orderable),
+		ReorderPoint:    ints.NullInt32(row.ReorderPoint),
+		ReorderQuantity: ints.NullInt32(row.ReorderQuantity),
+		Reserved:        ints.NullInt32(row.Reserved),
+		SafetyStock:     ints.NullInt32(row.SafetyStock),
+		Metadata:        metadata.Decode(row.Metadata),
+		Version:         row.Version,
 	}
 
 	return inventory, nil
@@ -81,10 +171,19 @@ func (s *InventoriesService) Delete(delete Delete) error {
 	if err != nil {
 		return err
 	}
-	return s.Db.DeleteInventory(context.Background(), database.DeleteInventoryParams{
+
+	rows, err := s.Db.DeleteInventory(context.Background(), database.DeleteInventoryParams{
 		ID:        delete.InventoryId,
 		AccountID: delete.AccountId,
+		IfMatch:   delete.IfMatch,
 	})
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return versionMismatchMessage(delete.InventoryId)
+	}
+	return nil
 }
 
 func (s *InventoriesService) Get(get Get) (*Inventory, error) {
@@ -100,8 +199,20 @@ func (s *InventoriesService) Get(get Get) (*Inventory, error) {
 	}
 
 	inventory := &Inventory{
-		InStock:   row.InStock,
-		Orderable: ints.NullInt32(row.Orderable),
+		InStock:         row.InStock,
+		Item:            api.Expandable{ID: row.Item},
+		Location:        api.Expandable{ID: row.Location},
+		Orderable:       ints.NullInt32(row.Orderable),
+		ReorderPoint:    ints.NullInt32(row.ReorderPoint),
+		ReorderQuantity: ints.NullInt32(row.ReorderQuantity),
+		Reserved:        ints.NullInt32(row.Reserved),
+		SafetyStock:     ints.NullInt32(row.SafetyStock),
+		Metadata:        metadata.Decode(row.Metadata),
+		Version:         row.Version,
+	}
+
+	if err := s.withBundleStock(get.AccountId, []uuid.UUID{row.ID}, []*Inventory{inventory}); err != nil {
+		return nil, err
 	}
 
 	if !get.OmitBase {
@@ -113,6 +224,74 @@ func (s *InventoriesService) Get(get Get) (*Inventory, error) {
 	return inventory, nil
 }
 
+// History lists the stock movements of an inventory, newest first, each
+// with the in_stock the inventory had right after it.
+func (s *InventoriesService) History(history History) (entries []*HistoryEntry, hasMore bool, err error) {
+	_, err = s.Get(Get{
+		AccountId:     history.AccountId,
+		InventoryId:   history.InventoryId,
+		RequestParams: RetrieveInventoryParams{},
+		OmitBase:      true,
+	})
+	if err != nil {
+		return entries, hasMore, err
+	}
+
+	if history.RequestParams.StartingAfter != nil {
+		createdAt, err := s.movementCreatedAt(history.AccountId, *history.RequestParams.StartingAfter)
+		if err != nil {
+			return entries, hasMore, err
+		}
+		history.RequestParams.StartingAfterDate = createdAt
+	}
+
+	if history.RequestParams.EndingBefore != nil {
+		createdAt, err := s.movementCreatedAt(history.AccountId, *history.RequestParams.EndingBefore)
+		if err != nil {
+			return entries, hasMore, err
+		}
+		history.RequestParams.EndingBeforeDate = createdAt
+	}
+
+	dbParams := MapListInventoryHistoryParams(history)
+
+	rows, err := s.Db.ListInventoryHistory(context.Background(), dbParams)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return entries, hasMore, nil
+		}
+		return
+	}
+
+	if dbParams.Limit.Valid {
+		hasMore = len(rows) > int(dbParams.Limit.Int32)
+	} else {
+		hasMore = len(rows) > 10
+	}
+
+	if hasMore {
+		if dbParams.EndingBefore.Valid {
+			rows = rows[1:]
+		} else {
+			rows = rows[:len(rows)-1]
+		}
+	}
+
+	for _, row := range rows {
+		entries = append(entries, &HistoryEntry{
+			ID:        row.ID,
+			CreatedAt: row.CreatedAt,
+			InStock:   row.InStock,
+			Quantity:  row.Quantity,
+			Reason:    str.NullString(row.Reason),
+			Reference: str.NullString(row.Reference),
+			Type:      row.Type,
+		})
+	}
+
+	return entries, hasMore, err
+}
+
 func (s *InventoriesService) ListByIds(list ListByIds) (inventories []*Inventory, err error) {
 	params := database.ListInventoriesByIdsParams{
 		AccountID: list.AccountId,
@@ -129,15 +308,61 @@ func (s *InventoriesService) ListByIds(list ListByIds) (inventories []*Inventory
 
 	for _, row := range rows {
 		inventories = append(inventories, &Inventory{
-			ID:        &row.ID,
-			CreatedAt: &row.CreatedAt,
-			UpdatedAt: &row.UpdatedAt,
-			InStock:   row.InStock,
-			Orderable: ints.NullInt32(row.Orderable),
-			Reserved:  ints.NullInt32(row.Reserved),
+			ID:              &row.ID,
+			CreatedAt:       &row.CreatedAt,
+			UpdatedAt:       &row.UpdatedAt,
+			InStock:         row.InStock,
+			Item:            api.Expandable{ID: row.Item},
+			Location:        api.Expandable{ID: row.Location},
+			Orderable:       ints.NullInt32(row.Orderable),
+			ReorderPoint:    ints.NullInt32(row.ReorderPoint),
+			ReorderQuantity: ints.NullInt32(row.ReorderQuantity),
+			Reserved:        ints.NullInt32(row.Reserved),
+			SafetyStock:     ints.NullInt32(row.SafetyStock),
+			Metadata:        metadata.Decode(row.Metadata),
+			Version:         row.Version,
+		})
+	}
+
+	err = s.withBundleStock(list.AccountId, inventoryIds(inventories), inventories)
+	return
+}
+
+// ListByItems returns the inventories that hold stock of any of the given
+// items, across all locations.
+func (s *InventoriesService) ListByItems(list ListByItems) (inventories []*Inventory, err error) {
+	params := database.ListInventoriesByItemsParams{
+		AccountID: list.AccountId,
+		Ids:       list.RequestParams.Ids,
+	}
+
+	rows, err := s.Db.ListInventoriesByItems(context.Background(), params)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return inventories, nil
+		}
+		return
+	}
+
+	for _, row := range rows {
+		inventories = append(inventories, &Inventory{
+			ID:              &row.ID,
+			CreatedAt:       &row.CreatedAt,
+			UpdatedAt:       &row.UpdatedAt,
+			InStock:         row.InStock,
+			Item:            api.Expandable{ID: row.Item},
+			Location:        api.Expandable{ID: row.Location},
+			Orderable:       ints.NullInt32(row.Orderable),
+			ReorderPoint:    ints.NullInt32(row.ReorderPoint),
+			ReorderQuantity: ints.NullInt32(row.ReorderQuantity),
+			Reserved:        ints.NullInt32(row.Reserved),
+			SafetyStock:     ints.NullInt32(row.SafetyStock),
+			Metadata:        metadata.Decode(row.Metadata),
+			Version:         row.Version,
 		})
 	}
 
+	err = s.withBundleStock(list.AccountId, inventoryIds(inventories), inventories)
 	return
 }
 
@@ -170,6 +395,10 @@ func (s *InventoriesService) List(list List) (inventories []*Inventory, hasMore
 
 	dbParams := MapListInventoriesParams(list)
 
+	if list.RequestParams.AsOf != nil {
+		return s.listAsOf(list, dbParams)
+	}
+
 	rows, err := s.Db.ListInventories(context.Background(), dbParams)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -194,15 +423,26 @@ func (s *InventoriesService) List(list List) (inventories []*Inventory, hasMore
 
 	for _, row := range rows {
 		inventories = append(inventories, &Inventory{
-			ID:        &row.ID,
-			CreatedAt: &row.CreatedAt,
-			UpdatedAt: &row.UpdatedAt,
-			InStock:   row.InStock,
-			Orderable: ints.NullInt32(row.Orderable),
-			Reserved:  ints.NullInt32(row.Reserved),
+			ID:              &row.ID,
+			CreatedAt:       &row.CreatedAt,
+			UpdatedAt:       &row.UpdatedAt,
+			InStock:         row.InStock,
+			Item:            api.Expandable{ID: row.Item},
+			Location:        api.Expandable{ID: row.Location},
+			Orderable:       ints.NullInt32(row.Orderable),
+			ReorderPoint:    ints.NullInt32(row.ReorderPoint),
+			ReorderQuantity: ints.NullInt32(row.ReorderQuantity),
+			Reserved:        ints.NullInt32(row.Reserved),
+			SafetyStock:     ints.NullInt32(row.SafetyStock),
+			Metadata:        metadata.Decode(row.Metadata),
+			Version:         row.Version,
 		})
 	}
 
+	if err := s.withBundleStock(list.AccountId, inventoryIds(inventories), inventories); err != nil {
+		return inventories, hasMore, err
+	}
+
 	return inventories, hasMore, err
 }
 
@@ -217,20 +457,358 @@ func (s *InventoriesService) Update(update Update) (*Inventory, error) {
 		return nil, err
 	}
 
+	tx, err := s.Conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.Db.WithTx(tx)
+
+	current, err := qtx.GetInventoryForUpdate(context.Background(), database.GetInventoryForUpdateParams{
+		ID:        update.InventoryId,
+		AccountID: update.AccountId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The inventory is locked, so its version can be checked here rather
+	// than in UpdateInventory, which runs after the adjustment below has
+	// already moved it on.
+	if len(update.IfMatch) != 0 && !slices.Contains(update.IfMatch, current.Version) {
+		return nil, versionMismatchMessage(update.InventoryId)
+	}
+
+	// An absolute in_stock is turned into an adjustment for the difference,
+	// so the change is kept in the stock movement ledger.
+	if update.RequestParams.InStock != nil {
+		if delta := *update.RequestParams.InStock - current.InStock; delta != 0 {
+			reason := "in_stock updated"
+			_, err = stockmovements.Post(qtx, stockmovements.Create{
+				AccountId: update.AccountId,
+				RequestParams: stockmovements.CreateStockMovementParams{
+					Inventory: update.InventoryId.String(),
+					Quantity:  delta,
+					Reason:    &reason,
+					Type:      database.StockMovementTypeAdjustment,
+				},
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if update.RequestParams.Metadata != nil {
+		update.RequestParams.Metadata, err = metadata.Merge(metadata.Decode(current.Metadata), update.RequestParams.Metadata)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	dbParams := MapUpdateInventoryParams(update)
 
-	row, err := s.Db.UpdateInventory(context.Background(), dbParams)
+	row, err := qtx.UpdateInventory(context.Background(), dbParams)
 	if err != nil {
 		return nil, err
 	}
 
 	inventory := &Inventory{
-		ID:        &row.ID,
-		CreatedAt: &row.CreatedAt,
-		UpdatedAt: &row.UpdatedAt,
-		InStock:   row.InStock,
-		Orderable: ints.NullInt32(row.Orderable),
+		ID:              &row.ID,
+		CreatedAt:       &row.CreatedAt,
+		UpdatedAt:       &row.UpdatedAt,
+		InStock:         row.InStock,
+		Item:            api.Expandable{ID: row.Item},
+		Location:        api.Expandable{ID: row.Location},
+		Orderable:       ints.NullInt32(row.Orderable),
+		ReorderPoint:    ints.NullInt32(row.ReorderPoint),
+		ReorderQuantity: ints.NullInt32(row.ReorderQuantity),
+		Reserved:        ints.NullInt32(row.Reserved),
+		SafetyStock:     ints.NullInt32(row.SafetyStock),
+		Metadata:        metadata.Decode(row.Metadata),
+		Version:         row.Version,
+	}
+
+	// A change of in_stock raised its own event when it was posted above, so
+	// this only catches the reorder point being moved up past the stock.
+	wasBelow := events.BelowReorderPoint(row.InStock, row.Reserved.Int32, ints.NullInt32(current.ReorderPoint))
+	err = events.RecordLowStock(qtx, update.AccountId, wasBelow, events.LowStock{
+		Inventory:       row.ID,
+		InStock:         row.InStock,
+		Item:            row.Item,
+		Location:        row.Location,
+		ReorderPoint:    ints.NullInt32(row.ReorderPoint),
+		ReorderQuantity: ints.NullInt32(row.ReorderQuantity),
+		Reserved:        row.Reserved.Int32,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
 	}
 
 	return inventory, nil
 }
+
+// Snapshot records the in_stock of every inventory, so rebuilding a past
+// in_stock only has to replay the movements since the latest snapshot
+// instead of the whole ledger.
+func (s *InventoriesService) Snapshot() error {
+	return s.Db.CreateInventorySnapshots(context.Background(), time.Now())
+}
+
+func (s *InventoriesService) movementCreatedAt(accountId, stockMovementId uuid.UUID) (*time.Time, error) {
+	row, err := s.Db.GetStockMovement(context.Background(), database.GetStockMovementParams{
+		ID:        stockMovementId,
+		AccountID: accountId,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, api.NotFoundMessage(stockMovementId, "stock movement")
+		}
+		return nil, err
+	}
+	return &row.CreatedAt, nil
+}
+
+// listAsOf lists the inventories with their in_stock as of a past time. The
+// database only filters on the current stock, so the stock filters are
+// applied here once the stock has been rebuilt, fetching further pages until
+// there's a full one of inventories that match.
+func (s *InventoriesService) listAsOf(list List, dbParams database.ListInventoriesParams) (inventories []*Inventory, hasMore bool, err error) {
+	asOf := *list.RequestParams.AsOf
+	dbParams.BelowReorderPoint = sql.NullBool{}
+
+	limit := 10
+	if dbParams.Limit.Valid {
+		limit = int(dbParams.Limit.Int32)
+	}
+	backward := dbParams.EndingBefore.Valid
+
+	for {
+		rows, err := s.Db.ListInventories(context.Background(), dbParams)
+		if err != nil && err != sql.ErrNoRows {
+			return inventories, hasMore, err
+		}
+
+		page := make([]*Inventory, 0, len(rows))
+		for _, row := range rows {
+			page = append(page, &Inventory{
+				ID:              &row.ID,
+				CreatedAt:       &row.CreatedAt,
+				UpdatedAt:       &row.UpdatedAt,
+				InStock:         row.InStock,
+				Item:            api.Expandable{ID: row.Item},
+				Location:        api.Expandable{ID: row.Location},
+				Orderable:       ints.NullInt32(row.Orderable),
+				ReorderPoint:    ints.NullInt32(row.ReorderPoint),
+				ReorderQuantity: ints.NullInt32(row.ReorderQuantity),
+				Reserved:        ints.NullInt32(row.Reserved),
+				SafetyStock:     ints.NullInt32(row.SafetyStock),
+				Metadata:        metadata.Decode(row.Metadata),
+				Version:         row.Version,
+			})
+		}
+
+		if err := s.rebuildInStock(list.AccountId, asOf, page); err != nil {
+			return inventories, hasMore, err
+		}
+		if err := s.withBundleStockAsOf(list.AccountId, asOf, page); err != nil {
+			return inventories, hasMore, err
+		}
+
+		matched := make([]*Inventory, 0, len(page))
+		for _, inventory := range page {
+			if matchesStock(inventory, list.RequestParams) {
+				matched = append(matched, inventory)
+			}
+		}
+
+		// Pages run from newest to oldest, ending_before fetches the ones
+		// newer than the cursor so those go in front.
+		if backward {
+			inventories = append(matched, inventories...)
+		} else {
+			inventories = append(inventories, matched...)
+		}
+
+		if len(inventories) > limit || len(rows) <= limit {
+			break
+		}
+
+		if backward {
+			dbParams.EndingBefore = sql.NullTime{Time: rows[0].CreatedAt, Valid: true}
+		} else {
+			dbParams.StartingAfter = sql.NullTime{Time: rows[len(rows)-1].CreatedAt, Valid: true}
+		}
+	}
+
+	hasMore = len(inventories) > limit
+	if hasMore {
+		if backward {
+			inventories = inventories[len(inventories)-limit:]
+		} else {
+			inventories = inventories[:limit]
+		}
+	}
+
+	return inventories, hasMore, nil
+}
+
+// rebuildInStock replaces the in_stock of the inventories with the one they
+// had at asOf: the latest snapshot taken by then plus the movements after it.
+func (s *InventoriesService) rebuildInStock(accountId uuid.UUID, asOf time.Time, invs []*Inventory) error {
+	rows, err := s.Db.ListInventoriesInStockAsOf(context.Background(), database.ListInventoriesInStockAsOfParams{
+		AccountID: accountId,
+		AsOf:      asOf,
+		Ids:       inventoryIds(invs),
+	})
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	idInStockMap := make(map[uuid.UUID]int32, len(rows))
+	for _, row := range rows {
+		idInStockMap[row.InventoryID] = row.InStock
+	}
+
+	for _, inventory := range invs {
+		inventory.InStock = idInStockMap[*inventory.ID]
+	}
+
+	return nil
+}
+
+// withBundleStock replaces the in_stock of bundle inventories, which hold no
+// stock of their own, with the number of bundles that could be made up at
+// their location: the fewest any component's unreserved stock covers, plus
+// the bundles already reserved, whose components are held for them. ids
+// are the IDs of invs, as those may have been omitted from the inventories.
+func (s *InventoriesService) withBundleStock(accountId uuid.UUID, ids []uuid.UUID, invs []*Inventory) error {
+	rows, err := s.Db.ListBundleInventoriesInStock(context.Background(), database.ListBundleInventoriesInStockParams{
+		AccountID: accountId,
+		Ids:       ids,
+	})
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	idInStockMap := make(map[uuid.UUID]int32, len(rows))
+	for _, row := range rows {
+		idInStockMap[row.InventoryID] = row.InStock
+	}
+
+	for i, inventory := range invs {
+		if inStock, ok := idInStockMap[ids[i]]; ok {
+			inventory.InStock = inStock
+		}
+	}
+
+	return nil
+}
+
+// withBundleStockAsOf is withBundleStock for stock rebuilt as of a past time:
+// the number of bundles the stock of the components at that time made up.
+// Past reservations aren't kept, so all of that stock counts.
+func (s *InventoriesService) withBundleStockAsOf(accountId uuid.UUID, asOf time.Time, invs []*Inventory) error {
+	for _, inventory := range invs {
+		bundle, err := s.Db.IsInventoryBundle(context.Background(), database.IsInventoryBundleParams{
+			ID:        *inventory.ID,
+			AccountID: accountId,
+		})
+		if err != nil {
+			return err
+		}
+		if !bundle {
+			continue
+		}
+
+		components, err := s.Db.ListBundleComponentInventories(context.Background(), database.ListBundleComponentInventoriesParams{
+			AccountID:   accountId,
+			InventoryID: *inventory.ID,
+		})
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+
+		ids := make([]uuid.UUID, 0, len(components))
+		for _, component := range components {
+			if component.Inventory.Valid {
+				ids = append(ids, component.Inventory.UUID)
+			}
+		}
+
+		rows, err := s.Db.ListInventoriesInStockAsOf(context.Background(), database.ListInventoriesInStockAsOfParams{
+			AccountID: accountId,
+			AsOf:      asOf,
+			Ids:       ids,
+		})
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+
+		idInStockMap := make(map[uuid.UUID]int32, len(rows))
+		for _, row := range rows {
+			idInStockMap[row.InventoryID] = row.InStock
+		}
+
+		inventory.InStock = bundleInStock(components, idInStockMap)
+	}
+
+	return nil
+}
+
+// bundleInStock is the number of bundles the components' stock makes up, the
+// fewest any one of them covers. A component without an inventory covers none.
+func bundleInStock(components []database.ListBundleComponentInventoriesRow, idInStockMap map[uuid.UUID]int32) int32 {
+	if len(components) == 0 {
+		return 0
+	}
+
+	bundles := int32(-1)
+	for _, component := range components {
+		if !component.Inventory.Valid || component.Quantity <= 0 {
+			return 0
+		}
+		covered := max(idInStockMap[component.Inventory.UUID], 0) / component.Quantity
+		if bundles < 0 || covered < bundles {
+			bundles = covered
+		}
+	}
+
+	return bundles
+}
+
+// matchesStock applies the list filters on stock to an inventory whose stock
+// was rebuilt as of a past time. Past reservations aren't kept, so the
+// reorder point is compared with the whole of in_stock.
+func matchesStock(inventory *Inventory, params ListInventoriesParams) bool {
+	if params.InStock != nil && inventory.InStock != *params.InStock {
+		return false
+	}
+	if params.BelowReorderPoint != nil {
+		below := events.BelowReorderPoint(inventory.InStock, 0, inventory.ReorderPoint)
+		if below != *params.BelowReorderPoint {
+			return false
+		}
+	}
+	return true
+}
+
+func inventoryIds(invs []*Inventory) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(invs))
+	for _, inventory := range invs {
+		ids = append(ids, *inventory.ID)
+	}
+	return ids
+}
+
+func versionMismatchMessage(inventoryId uuid.UUID) error {
+	return &api.AppError{
+		Message: fmt.Sprintf("Inventory '%v' has changed since the version in If-Match.", inventoryId),
+		Status:  http.StatusPreconditionFailed,
+		Type:    api.InvalidRequestError,
+	}
+}
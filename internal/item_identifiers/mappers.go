@@ -3,6 +3,7 @@ package itemidentifiers
 import (
 	"time"
 
+	"github.com/d-darac/inventory-api/internal/metadata"
 	"github.com/d-darac/inventory-assets/api"
 	"github.com/d-darac/inventory-assets/database"
 	"github.com/google/uuid"
@@ -25,6 +26,7 @@ func MapCreateItemIdentifiersParams(create Create) database.CreateItemIdentifier
 		Upc:       api.NullString(create.RequestParams.Upc),
 		Qr:        api.NullString(create.RequestParams.Qr),
 		Sku:       api.NullString(create.RequestParams.Sku),
+		Metadata:  metadata.Encode(create.RequestParams.Metadata),
 	}
 	return ciip
 }
@@ -32,6 +34,7 @@ func MapCreateItemIdentifiersParams(create Create) database.CreateItemIdentifier
 func MapListItemIdentifiersParams(list List) database.ListItemIdentifiersParams {
 	liip := database.ListItemIdentifiersParams{
 		AccountID: list.AccountId,
+		Metadata:  metadata.Encode(list.RequestParams.Metadata),
 	}
 	database.MapTimeRange(list.RequestParams.CreatedAt, &liip.CreatedAtGt, &liip.CreatedAtGte, &liip.CreatedAtLt, &liip.CreatedAtLte)
 	database.MapTimeRange(list.RequestParams.UpdatedAt, &liip.UpdatedAtGt, &liip.UpdatedAtGte, &liip.UpdatedAtLt, &liip.UpdatedAtLte)
@@ -44,6 +47,7 @@ func MapUpdateItemIdentifiersParams(update Update) database.UpdateItemIdentifier
 	ciip := database.UpdateItemIdentifierParams{
 		UpdatedAt: t,
 		AccountID: update.AccountId,
+		IfMatch:   update.IfMatch,
 		Ean:       api.NullString(update.RequestParams.Ean),
 		Gtin:      api.NullString(update.RequestParams.Gtin),
 		Isbn:      api.NullString(update.RequestParams.Isbn),
@@ -54,6 +58,7 @@ func MapUpdateItemIdentifiersParams(update Update) database.UpdateItemIdentifier
 		Upc:       api.NullString(update.RequestParams.Upc),
 		Qr:        api.NullString(update.RequestParams.Qr),
 		Sku:       api.NullString(update.RequestParams.Sku),
+		Metadata:  metadata.Encode(update.RequestParams.Metadata),
 	}
 	return ciip
 }
@@ -6,17 +6,18 @@ import (
 )
 
 type CreateItemIdentifiersParams struct {
-	Ean    *string  `json:"ean" validate:"omitnil"`
-	Gtin   *string  `json:"gtin" validate:"omitnil"`
-	Isbn   *string  `json:"isbn" validate:"omitnil"`
-	Jan    *string  `json:"jan" validate:"omitnil"`
-	Mpn    *string  `json:"mpn" validate:"omitnil"`
-	Nsn    *string  `json:"nsn" validate:"omitnil"`
-	Upc    *string  `json:"upc" validate:"omitnil"`
-	Qr     *string  `json:"qr" validate:"omitnil"`
-	Sku    *string  `json:"sku" validate:"omitnil"`
-	Item   string   `json:"item" validate:"required,uuid"`
-	Expand []string `json:"expand" validate:"omitnil,dive,oneof=item"`
+	Ean      *string           `json:"ean" validate:"omitnil"`
+	Gtin     *string           `json:"gtin" validate:"omitnil"`
+	Isbn     *string           `json:"isbn" validate:"omitnil"`
+	Jan      *string           `json:"jan" validate:"omitnil"`
+	Mpn      *string           `json:"mpn" validate:"omitnil"`
+	Nsn      *string           `json:"nsn" validate:"omitnil"`
+	Upc      *string           `json:"upc" validate:"omitnil"`
+	Qr       *string           `json:"qr" validate:"omitnil"`
+	Sku      *string           `json:"sku" validate:"omitnil"`
+	Item     string            `json:"item" validate:"required,uuid"`
+	Metadata map[string]string `json:"metadata" validate:"omitnil,max=50,dive,keys,min=1,max=40,endkeys,max=500"`
+	Expand   []string          `json:"expand" validate:"omitnil,dive,oneof=item"`
 }
 
 type ListItemIdentifiersByIdsParams struct {
@@ -27,6 +28,7 @@ type ListItemIdentifiersParams struct {
 	*database.PaginationParams
 	CreatedAt *database.TimeRange `json:"created_at" validate:"omitnil"`
 	UpdatedAt *database.TimeRange `json:"updated_at" validate:"omitnil"`
+	Metadata  map[string]string   `json:"metadata" validate:"omitnil,max=50,dive,keys,min=1,max=40,endkeys,max=500"`
 	Expand    []string            `json:"expand" validate:"omitnil,dive,oneof=item"`
 }
 
@@ -35,16 +37,17 @@ type RetrieveItemIdentifiersParams struct {
 }
 
 type UpdateItemIdentifiersParams struct {
-	Ean    *string  `json:"ean" validate:"omitnil"`
-	Gtin   *string  `json:"gtin" validate:"omitnil"`
-	Isbn   *string  `json:"isbn" validate:"omitnil"`
-	Jan    *string  `json:"jan" validate:"omitnil"`
-	Mpn    *string  `json:"mpn" validate:"omitnil"`
-	Nsn    *string  `json:"nsn" validate:"omitnil"`
-	Upc    *string  `json:"upc" validate:"omitnil"`
-	Qr     *string  `json:"qr" validate:"omitnil"`
-	Sku    *string  `json:"sku" validate:"omitnil"`
-	Expand []string `json:"expand" validate:"omitnil,dive,oneof=item"`
+	Ean      *string           `json:"ean" validate:"omitnil"`
+	Gtin     *string           `json:"gtin" validate:"omitnil"`
+	Isbn     *string           `json:"isbn" validate:"omitnil"`
+	Jan      *string           `json:"jan" validate:"omitnil"`
+	Mpn      *string           `json:"mpn" validate:"omitnil"`
+	Nsn      *string           `json:"nsn" validate:"omitnil"`
+	Upc      *string           `json:"upc" validate:"omitnil"`
+	Qr       *string           `json:"qr" validate:"omitnil"`
+	Sku      *string           `json:"sku" validate:"omitnil"`
+	Metadata map[string]string `json:"metadata" validate:"omitnil,max=50,dive,keys,min=1,max=40,endkeys,max=500"`
+	Expand   []string          `json:"expand" validate:"omitnil,dive,oneof=item"`
 }
 
 func NewListItemIdentifiersParams() ListItemIdentifiersParams {
@@ -3,7 +3,10 @@ package itemidentifiers
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"net/http"
 
+	"github.com/d-darac/inventory-api/internal/metadata"
 	"github.com/d-darac/inventory-assets/api"
 	"github.com/d-darac/inventory-assets/database"
 	"github.com/d-darac/inventory-assets/str"
@@ -22,6 +25,7 @@ type Create struct {
 type Delete struct {
 	AccountId         uuid.UUID
 	ItemIdentifiersId uuid.UUID
+	IfMatch           []int32
 }
 
 type Get struct {
@@ -44,6 +48,7 @@ type List struct {
 type Update struct {
 	AccountId         uuid.UUID
 	ItemIdentifiersId uuid.UUID
+	IfMatch           []int32
 	RequestParams     UpdateItemIdentifiersParams
 }
 
@@ -73,6 +78,8 @@ func (s *ItemIdentifiersService) Create(create Create) (*ItemIdentifiers, error)
 		Upc:       str.NullString(row.Upc),
 		Qr:        str.NullString(row.Qr),
 		Sku:       str.NullString(row.Sku),
+		Metadata:  metadata.Decode(row.Metadata),
+		Version:   row.Version,
 	}
 
 	return itemIdentifiers, nil
@@ -88,10 +95,19 @@ func (s *ItemIdentifiersService) Delete(delete Delete) error {
 	if err != nil {
 		return err
 	}
-	return s.Db.DeleteItemIdentifier(context.Background(), database.DeleteItemIdentifierParams{
+
+	rows, err := s.Db.DeleteItemIdentifier(context.Background(), database.DeleteItemIdentifierParams{
 		ID:        delete.ItemIdentifiersId,
 		AccountID: delete.AccountId,
+		IfMatch:   delete.IfMatch,
 	})
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return versionMismatchMessage(delete.ItemIdentifiersId)
+	}
+	return nil
 }
 
 func (s *ItemIdentifiersService) Get(get Get) (*ItemIdentifiers, error) {
@@ -107,15 +123,17 @@ func (s *ItemIdentifiersService) Get(get Get) (*ItemIdentifiers, error) {
 	}
 
 	itemIdentifiers := &ItemIdentifiers{
-		Ean:  str.NullString(row.Ean),
-		Gtin: str.NullString(row.Gtin),
-		Isbn: str.NullString(row.Isbn),
-		Jan:  str.NullString(row.Jan),
-		Mpn:  str.NullString(row.Mpn),
-		Nsn:  str.NullString(row.Nsn),
-		Upc:  str.NullString(row.Upc),
-		Qr:   str.NullString(row.Qr),
-		Sku:  str.NullString(row.Sku),
+		Ean:      str.NullString(row.Ean),
+		Gtin:     str.NullString(row.Gtin),
+		Isbn:     str.NullString(row.Isbn),
+		Jan:      str.NullString(row.Jan),
+		Mpn:      str.NullString(row.Mpn),
+		Nsn:      str.NullString(row.Nsn),
+		Upc:      str.NullString(row.Upc),
+		Qr:       str.NullString(row.Qr),
+		Sku:      str.NullString(row.Sku),
+		Metadata: metadata.Decode(row.Metadata),
+		Version:  row.Version,
 	}
 
 	if !get.OmitBase {
@@ -154,6 +172,8 @@ func (s *ItemIdentifiersService) ListByIds(list ListByIds) (itemIdentifiers []*I
 			Upc:       str.NullString(row.Upc),
 			Qr:        str.NullString(row.Qr),
 			Sku:       str.NullString(row.Sku),
+			Metadata:  metadata.Decode(row.Metadata),
+			Version:   row.Version,
 		})
 	}
 
@@ -225,6 +245,8 @@ func (s *ItemIdentifiersService) List(list List) (itemIdentifiers []*ItemIdentif
 			Upc:       str.NullString(row.Upc),
 			Qr:        str.NullString(row.Qr),
 			Sku:       str.NullString(row.Sku),
+			Metadata:  metadata.Decode(row.Metadata),
+			Version:   row.Version,
 		})
 	}
 
@@ -232,7 +254,7 @@ func (s *ItemIdentifiersService) List(list List) (itemIdentifiers []*ItemIdentif
 }
 
 func (s *ItemIdentifiersService) Update(update Update) (*ItemIdentifiers, error) {
-	_, err := s.Get(Get{
+	current, err := s.Get(Get{
 		AccountId:         update.AccountId,
 		ItemIdentifiersId: update.ItemIdentifiersId,
 		RequestParams:     RetrieveItemIdentifiersParams{},
@@ -242,10 +264,22 @@ func (s *ItemIdentifiersService) Update(update Update) (*ItemIdentifiers, error)
 		return nil, err
 	}
 
+	if update.RequestParams.Metadata != nil {
+		update.RequestParams.Metadata, err = metadata.Merge(current.Metadata, update.RequestParams.Metadata)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	dbParams := MapUpdateItemIdentifiersParams(update)
 
 	row, err := s.Db.UpdateItemIdentifier(context.Background(), dbParams)
 	if err != nil {
+		// Get found the item identifiers, so no row means their version
+		// has moved past the one in If-Match.
+		if err == sql.ErrNoRows {
+			return nil, versionMismatchMessage(update.ItemIdentifiersId)
+		}
 		return nil, err
 	}
 
@@ -262,7 +296,17 @@ func (s *ItemIdentifiersService) Update(update Update) (*ItemIdentifiers, error)
 		Upc:       str.NullString(row.Upc),
 		Qr:        str.NullString(row.Qr),
 		Sku:       str.NullString(row.Sku),
+		Metadata:  metadata.Decode(row.Metadata),
+		Version:   row.Version,
 	}
 
 	return itemIdentifiers, nil
 }
+
+func versionMismatchMessage(itemIdentifiersId uuid.UUID) error {
+	return &api.AppError{
+		Message: fmt.Sprintf("Item identifiers '%v' has changed since the version in If-Match.", itemIdentifiersId),
+		Status:  http.StatusPreconditionFailed,
+		Type:    api.InvalidRequestError,
+	}
+}
@@ -12,17 +12,55 @@ import (
 )
 
 type Item struct {
-	ID            *uuid.UUID            `json:"id,omitempty"`
-	CreatedAt     *time.Time            `json:"created_at,omitempty"`
-	UpdatedAt     *time.Time            `json:"updated_at,omitempty"`
-	Active        bool                  `json:"active"`
-	Description   str.NullString        `json:"description"`
-	Group         api.Expandable        `json:"group"`
-	Identifiers   api.Expandable        `json:"identifiers"`
-	Inventory     api.Expandable        `json:"inventory"`
-	Name          string                `json:"name"`
-	PriceAmount   ints.NullInt32        `json:"price_amount"`
-	PriceCurrency currency.NullCurrency `json:"price_currency"`
-	Variant       bool                  `json:"variant"`
-	Type          database.ItemType     `json:"type"`
+	ID                 *uuid.UUID            `json:"id,omitempty"`
+	CreatedAt          *time.Time            `json:"created_at,omitempty"`
+	UpdatedAt          *time.Time            `json:"updated_at,omitempty"`
+	Active             bool                  `json:"active"`
+	Components         api.Expandable        `json:"components"`
+	Description        str.NullString        `json:"description"`
+	Files              api.Expandable        `json:"files"`
+	Group              api.Expandable        `json:"group"`
+	Identifiers        api.Expandable        `json:"identifiers"`
+	Inventory          api.Expandable        `json:"inventory"`
+	Name               string                `json:"name"`
+	OptionValues       map[string]string     `json:"option_values"`
+	Options            []ItemOption          `json:"options"`
+	Parent             api.Expandable        `json:"parent"`
+	PriceAmount        ints.NullInt32        `json:"price_amount"`
+	PriceCurrency      currency.NullCurrency `json:"price_currency"`
+	Prices             api.Expandable        `json:"prices"`
+	PurchaseUnit       api.Expandable        `json:"purchase_unit"`
+	PurchaseUnitFactor ints.NullInt32        `json:"purchase_unit_factor"`
+	SalesUnit          api.Expandable        `json:"sales_unit"`
+	SalesUnitFactor    ints.NullInt32        `json:"sales_unit_factor"`
+	SerialNumbers      api.Expandable        `json:"serial_numbers"`
+	Serialized         bool                  `json:"serialized"`
+	Stock              api.Expandable        `json:"stock"`
+	StockingUnit       api.Expandable        `json:"stocking_unit"`
+	Suppliers          api.Expandable        `json:"suppliers"`
+	Tags               []string              `json:"tags"`
+	Variant            bool                  `json:"variant"`
+	Variants           api.Expandable        `json:"variants"`
+	Type               database.ItemType     `json:"type"`
+	Metadata           map[string]string     `json:"metadata"`
+	Version            int32                 `json:"version"`
+}
+
+// ItemOption is an attribute, such as size or colour, that the variants of
+// an item differ by, along with the values it can take.
+type ItemOption struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
+// PriceChange is a change to the price_amount or price_currency of an item,
+// made by ApiKey at CreatedAt. ApiKey is null once the key is deleted.
+type PriceChange struct {
+	ID                    uuid.UUID             `json:"id"`
+	CreatedAt             time.Time             `json:"created_at"`
+	ApiKey                uuid.NullUUID         `json:"api_key"`
+	PreviousPriceAmount   ints.NullInt32        `json:"previous_price_amount"`
+	PreviousPriceCurrency currency.NullCurrency `json:"previous_price_currency"`
+	PriceAmount           ints.NullInt32        `json:"price_amount"`
+	PriceCurrency         currency.NullCurrency `json:"price_currency"`
 }
@@ -0,0 +1,171 @@
+package items
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/d-darac/inventory-api/internal/testutil"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+func TestUnitVariantMatrix(t *testing.T) {
+	options := []ItemOption{
+		{Name: "size", Values: []string{"S", "M", "L"}},
+		{Name: "colour", Values: []string{"red", "blue"}},
+	}
+
+	matrix := variantMatrix(options)
+	if len(matrix) != 6 {
+		t.Fatalf("expected 6 combinations, got %d", len(matrix))
+	}
+
+	seen := make(map[string]bool, len(matrix))
+	for _, values := range matrix {
+		if err := checkOptionValues(options, values); err != nil {
+			t.Fatalf("combination %v: %v", values, err)
+		}
+		key := optionValuesKey(options, values)
+		if seen[key] {
+			t.Fatalf("combination %v generated twice", values)
+		}
+		seen[key] = true
+	}
+
+	if matrix[0]["size"] != "S" || matrix[0]["colour"] != "red" || matrix[1]["colour"] != "blue" {
+		t.Fatalf("expected the last option to vary fastest, got %v", matrix[:2])
+	}
+}
+
+func TestUnitCheckOptionValues(t *testing.T) {
+	options := []ItemOption{
+		{Name: "size", Values: []string{"S", "M"}},
+		{Name: "colour", Values: []string{"red"}},
+	}
+
+	tests := []struct {
+		name    string
+		values  map[string]string
+		wantErr bool
+	}{
+		{name: "all options", values: map[string]string{"size": "M", "colour": "red"}},
+		{name: "missing option", values: map[string]string{"size": "M"}, wantErr: true},
+		{name: "unknown value", values: map[string]string{"size": "XL", "colour": "red"}, wantErr: true},
+		{name: "extra option", values: map[string]string{"size": "S", "colour": "red", "fit": "slim"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		err := checkOptionValues(options, tt.values)
+		if tt.wantErr && err == nil {
+			t.Fatalf("%s: expected an error", tt.name)
+		}
+		if !tt.wantErr && err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+	}
+}
+
+func TestUnitMergeTags(t *testing.T) {
+	tests := []struct {
+		name    string
+		current []string
+		added   []string
+		want    []string
+	}{
+		{name: "no tags yet", current: nil, added: []string{"fragile"}, want: []string{"fragile"}},
+		{name: "new tag", current: []string{"fragile"}, added: []string{"clearance"}, want: []string{"fragile", "clearance"}},
+		{name: "existing tag", current: []string{"fragile", "clearance"}, added: []string{"clearance"}, want: []string{"fragile", "clearance"}},
+	}
+
+	for _, tt := range tests {
+		got := mergeTags(tt.current, tt.added)
+		if !slices.Equal(got, tt.want) {
+			t.Fatalf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestUnitCheckOpeningStock(t *testing.T) {
+	serialized := true
+	notSerialized := false
+
+	cases := []struct {
+		name    string
+		params  CreateItemParams
+		inStock int32
+		ok      bool
+	}{
+		{"product with stock", CreateItemParams{Serialized: &notSerialized}, 5, true},
+		{"serialized without stock", CreateItemParams{Serialized: &serialized}, 0, true},
+		{"serialized with stock", CreateItemParams{Serialized: &serialized}, 5, false},
+		{"bundle without stock", CreateItemParams{Type: database.ItemTypeBUNDLE}, 0, true},
+		{"bundle with stock", CreateItemParams{Type: database.ItemTypeBUNDLE}, 5, false},
+	}
+
+	for _, c := range cases {
+		err := CheckOpeningStock(c.params, c.inStock)
+		if c.ok && err != nil {
+			t.Fatalf("%s: expected no error, got %v", c.name, err)
+		}
+		if !c.ok && err == nil {
+			t.Fatalf("%s: expected an error", c.name)
+		}
+	}
+}
+
+func TestIntegrationGenerateVariantsConcurrently(t *testing.T) {
+	db, q, accountId := testutil.OpenAccount(t)
+	defer db.Close()
+	defer db.Exec("DELETE FROM accounts WHERE id = $1;", accountId)
+
+	s := NewItemsService(q, db)
+
+	options, _ := json.Marshal([]ItemOption{
+		{Name: "size", Values: []string{"S", "M", "L"}},
+		{Name: "color", Values: []string{"red", "blue"}},
+	})
+	parent, err := q.CreateItem(context.Background(), database.CreateItemParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		AccountID: accountId,
+		Name:      "Test Item",
+		Options:   options,
+		Type:      database.ItemTypePRODUCT,
+	})
+	if err != nil {
+		t.Fatalf("couldn't create test item: %v", err)
+	}
+
+	// The second call waits for the first and then finds nothing left to
+	// generate.
+	generate := func(counts chan<- int) {
+		variants, err := s.GenerateVariants(GenerateVariants{AccountId: accountId, ItemId: parent.ID})
+		if err != nil {
+			t.Errorf("couldn't generate variants: %v", err)
+		}
+		counts <- len(variants)
+	}
+	counts := make(chan int, 2)
+	go generate(counts)
+	go generate(counts)
+
+	generated := 0
+	for range 2 {
+		generated += <-counts
+	}
+	if generated != 6 {
+		t.Fatalf("expected 6 variants generated in total, got %d", generated)
+	}
+
+	variants, err := s.ListByParents(ListByParents{AccountId: accountId, RequestParams: ListItemsByParentsParams{Ids: []uuid.UUID{parent.ID}}})
+	if err != nil {
+		t.Fatalf("error listing variants: %v", err)
+	}
+	if len(variants) != 6 {
+		t.Fatalf("expected 6 variants, got %d", len(variants))
+	}
+}
@@ -1,8 +1,11 @@
 package items
 
 import (
+	"database/sql"
+	"encoding/json"
 	"time"
 
+	"github.com/d-darac/inventory-api/internal/metadata"
 	"github.com/d-darac/inventory-assets/api"
 	"github.com/d-darac/inventory-assets/database"
 	"github.com/google/uuid"
@@ -11,17 +14,23 @@ import (
 func MapCreateItemParams(create Create) database.CreateItemParams {
 	t := time.Now()
 	cip := database.CreateItemParams{
-		ID:            uuid.New(),
-		CreatedAt:     t,
-		UpdatedAt:     t,
-		AccountID:     create.AccountId,
-		Description:   api.NullString(create.RequestParams.Description),
-		GroupID:       api.NullUUID(nil),
-		InventoryID:   api.NullUUID(nil),
-		Name:          create.RequestParams.Name,
-		PriceAmount:   api.NullInt32(create.RequestParams.PriceAmount),
-		PriceCurrency: api.NullCurrency(create.RequestParams.PriceCurrency),
-		Type:          create.RequestParams.Type,
+		ID:                 uuid.New(),
+		CreatedAt:          t,
+		UpdatedAt:          t,
+		AccountID:          create.AccountId,
+		Description:        api.NullString(create.RequestParams.Description),
+		GroupID:            api.NullUUID(nil),
+		InventoryID:        api.NullUUID(nil),
+		Name:               create.RequestParams.Name,
+		PriceAmount:        api.NullInt32(create.RequestParams.PriceAmount),
+		PriceCurrency:      api.NullCurrency(create.RequestParams.PriceCurrency),
+		PurchaseUnitFactor: api.NullInt32(create.RequestParams.PurchaseUnitFactor),
+		SalesUnitFactor:    api.NullInt32(create.RequestParams.SalesUnitFactor),
+		Serialized:         create.RequestParams.Serialized != nil && *create.RequestParams.Serialized,
+		Type:               create.RequestParams.Type,
+		Tags:               create.RequestParams.Tags,
+		Variant:            create.RequestParams.Parent != nil,
+		Metadata:           metadata.Encode(create.RequestParams.Metadata),
 	}
 	if create.RequestParams.Group != nil {
 		groupId := uuid.MustParse(*create.RequestParams.Group)
@@ -31,6 +40,21 @@ func MapCreateItemParams(create Create) database.CreateItemParams {
 		inventoryId := uuid.MustParse(*create.RequestParams.Inventory)
 		cip.InventoryID = api.NullUUID(&inventoryId)
 	}
+	if create.RequestParams.Parent != nil {
+		parentId := uuid.MustParse(*create.RequestParams.Parent)
+		cip.ParentID = api.NullUUID(&parentId)
+		cip.OptionValues, _ = json.Marshal(create.RequestParams.OptionValues)
+	}
+	if len(create.RequestParams.Options) != 0 {
+		options := make([]ItemOption, 0, len(create.RequestParams.Options))
+		for _, option := range create.RequestParams.Options {
+			options = append(options, ItemOption(option))
+		}
+		cip.Options, _ = json.Marshal(options)
+	}
+	cip.PurchaseUnitID = nullUnit(create.RequestParams.PurchaseUnit)
+	cip.SalesUnitID = nullUnit(create.RequestParams.SalesUnit)
+	cip.StockingUnitID = nullUnit(create.RequestParams.StockingUnit)
 	return cip
 }
 
@@ -38,14 +62,27 @@ func MapListItemsParams(list List) database.ListItemsParams {
 	lip := database.ListItemsParams{
 		AccountID:     list.AccountId,
 		Active:        api.NullBool(list.RequestParams.Active),
+		DescendantOf:  api.NullUUID(nil),
 		Description:   api.NullString(list.RequestParams.Description),
 		GroupID:       api.NullUUID(nil),
 		InventoryID:   api.NullUUID(nil),
+		LocationID:    api.NullUUID(nil),
 		Name:          api.NullString(list.RequestParams.Name),
+		ParentID:      api.NullUUID(nil),
+		SupplierID:    api.NullUUID(nil),
 		PriceAmount:   api.NullInt32(list.RequestParams.PriceAmount),
 		PriceCurrency: api.NullCurrency(list.RequestParams.PriceCurrency),
+		Tags:          list.RequestParams.Tags,
+		TagsAll:       list.RequestParams.TagsAll,
+		TagsAny:       list.RequestParams.TagsAny,
 		Type:          api.NullItemType(list.RequestParams.Type),
+		Serialized:    api.NullBool(list.RequestParams.Serialized),
 		Variant:       api.NullBool(list.RequestParams.Variant),
+		Metadata:      metadata.Encode(list.RequestParams.Metadata),
+	}
+	if list.RequestParams.DescendantOf != nil {
+		descendantOf := uuid.MustParse(*list.RequestParams.DescendantOf)
+		lip.DescendantOf = api.NullUUID(&descendantOf)
 	}
 	if list.RequestParams.Group != nil {
 		groupId := uuid.MustParse(*list.RequestParams.Group)
@@ -55,25 +92,55 @@ func MapListItemsParams(list List) database.ListItemsParams {
 		inventoryId := uuid.MustParse(*list.RequestParams.Inventory)
 		lip.InventoryID = api.NullUUID(&inventoryId)
 	}
+	if list.RequestParams.Parent != nil {
+		parentId := uuid.MustParse(*list.RequestParams.Parent)
+		lip.ParentID = api.NullUUID(&parentId)
+	}
+	if list.RequestParams.Supplier != nil {
+		supplierId := uuid.MustParse(*list.RequestParams.Supplier)
+		lip.SupplierID = api.NullUUID(&supplierId)
+	}
+	if list.RequestParams.Location != nil {
+		locationId := uuid.MustParse(*list.RequestParams.Location)
+		lip.LocationID = api.NullUUID(&locationId)
+	}
+	if list.RequestParams.LotsExpiringBefore != nil {
+		lip.LotsExpiringBefore = sql.NullTime{Time: *list.RequestParams.LotsExpiringBefore, Valid: true}
+	}
 	database.MapTimeRange(list.RequestParams.CreatedAt, &lip.CreatedAtGt, &lip.CreatedAtGte, &lip.CreatedAtLt, &lip.CreatedAtLte)
 	database.MapTimeRange(list.RequestParams.UpdatedAt, &lip.UpdatedAtGt, &lip.UpdatedAtGte, &lip.UpdatedAtLt, &lip.UpdatedAtLte)
 	database.MapPaginationParams(*list.RequestParams.PaginationParams, &lip)
 	return lip
 }
 
+func MapListItemPriceHistoryParams(history PriceHistory) database.ListItemPriceHistoryParams {
+	liphp := database.ListItemPriceHistoryParams{
+		AccountID: history.AccountId,
+		ItemID:    history.ItemId,
+	}
+	database.MapTimeRange(history.RequestParams.CreatedAt, &liphp.CreatedAtGt, &liphp.CreatedAtGte, &liphp.CreatedAtLt, &liphp.CreatedAtLte)
+	database.MapPaginationParams(*history.RequestParams.PaginationParams, &liphp)
+	return liphp
+}
+
 func MapUpdateItemParams(update Update) database.UpdateItemParams {
 	t := time.Now()
 	uip := database.UpdateItemParams{
-		UpdatedAt:     t,
-		ID:            update.ItemId,
-		AccountID:     update.AccountId,
-		Active:        api.NullBool(update.RequestParams.Active),
-		Description:   api.NullString(update.RequestParams.Description),
-		GroupID:       api.NullUUID(nil),
-		InventoryID:   api.NullUUID(nil),
-		Name:          api.NullString(update.RequestParams.Name),
-		PriceAmount:   api.NullInt32(update.RequestParams.PriceAmount),
-		PriceCurrency: api.NullCurrency(update.RequestParams.PriceCurrency),
+		UpdatedAt:          t,
+		ID:                 update.ItemId,
+		AccountID:          update.AccountId,
+		IfMatch:            update.IfMatch,
+		Active:             api.NullBool(update.RequestParams.Active),
+		Description:        api.NullString(update.RequestParams.Description),
+		GroupID:            api.NullUUID(nil),
+		InventoryID:        api.NullUUID(nil),
+		Name:               api.NullString(update.RequestParams.Name),
+		PriceAmount:        api.NullInt32(update.RequestParams.PriceAmount),
+		PriceCurrency:      api.NullCurrency(update.RequestParams.PriceCurrency),
+		PurchaseUnitFactor: api.NullInt32(update.RequestParams.PurchaseUnitFactor),
+		SalesUnitFactor:    api.NullInt32(update.RequestParams.SalesUnitFactor),
+		Tags:               update.RequestParams.Tags,
+		Metadata:           metadata.Encode(update.RequestParams.Metadata),
 	}
 	if update.RequestParams.Group != nil {
 		groupId := uuid.MustParse(*update.RequestParams.Group)
@@ -83,5 +150,16 @@ func MapUpdateItemParams(update Update) database.UpdateItemParams {
 		inventoryId := uuid.MustParse(*update.RequestParams.Inventory)
 		uip.InventoryID = api.NullUUID(&inventoryId)
 	}
+	uip.PurchaseUnitID = nullUnit(update.RequestParams.PurchaseUnit)
+	uip.SalesUnitID = nullUnit(update.RequestParams.SalesUnit)
+	uip.StockingUnitID = nullUnit(update.RequestParams.StockingUnit)
 	return uip
 }
+
+func nullUnit(unit *string) uuid.NullUUID {
+	if unit == nil {
+		return api.NullUUID(nil)
+	}
+	unitId := uuid.MustParse(*unit)
+	return api.NullUUID(&unitId)
+}
@@ -1,6 +1,8 @@
 package items
 
 import (
+	"time"
+
 	"github.com/d-darac/inventory-assets/database"
 	"github.com/google/uuid"
 )
@@ -12,8 +14,7 @@ type GroupData struct {
 }
 
 type InventoryData struct {
-	InStock   int32  `json:"in_stock" validate:"required"`
-	Orderable *int32 `json:"orderable" validate:"omitnil"`
+	InStock int32 `json:"in_stock" validate:"required"`
 }
 
 type IdentifiersData struct {
@@ -28,53 +29,115 @@ type IdentifiersData struct {
 	Sku  *string `json:"sku" validate:"omitnil"`
 }
 
+type OptionData struct {
+	Name   string   `json:"name" validate:"required"`
+	Values []string `json:"values" validate:"required,min=1,unique,dive,required"`
+}
+
 type CreateItemParams struct {
-	Description     *string            `json:"description" validate:"omitnil"`
-	Group           *string            `json:"group" validate:"omitnil,uuid,excluded_with=GroupData"`
-	GroupData       *GroupData         `json:"group_data" validate:"omitnil"`
-	IdentifiersData *IdentifiersData   `json:"identifiers_data" validate:"omitnil"`
-	Inventory       *string            `json:"inventory" validate:"omitnil,uuid,excluded_with=InventoryData"`
-	InventoryData   *InventoryData     `json:"inventory_data" valdiate:"omitnil"`
-	Name            string             `json:"name" validate:"required"`
-	PriceAmount     *int32             `json:"price_amount" validate:"omitnil"`
-	PriceCurrency   *database.Currency `json:"price_currency" validate:"omitnil,currency"`
-	Type            database.ItemType  `json:"type" validate:"required,itemtype"`
-	Expand          []string           `json:"expand" validate:"omitnil,dive,oneof=group identifiers inventory"`
+	Description        *string            `json:"description" validate:"omitnil"`
+	Group              *string            `json:"group" validate:"omitnil,uuid,excluded_with=GroupData"`
+	GroupData          *GroupData         `json:"group_data" validate:"omitnil"`
+	IdentifiersData    *IdentifiersData   `json:"identifiers_data" validate:"omitnil"`
+	Inventory          *string            `json:"inventory" validate:"omitnil,uuid,excluded_with=InventoryData"`
+	InventoryData      *InventoryData     `json:"inventory_data" valdiate:"omitnil"`
+	Name               string             `json:"name" validate:"required"`
+	OptionValues       map[string]string  `json:"option_values" validate:"omitnil,required_with=Parent"`
+	Options            []OptionData       `json:"options" validate:"omitnil,excluded_with=Parent,unique=Name,dive"`
+	Parent             *string            `json:"parent" validate:"omitnil,uuid,required_with=OptionValues"`
+	PriceAmount        *int32             `json:"price_amount" validate:"omitnil"`
+	PriceCurrency      *database.Currency `json:"price_currency" validate:"omitnil,currency"`
+	PurchaseUnit       *string            `json:"purchase_unit" validate:"omitnil,uuid,required_with=PurchaseUnitFactor"`
+	PurchaseUnitFactor *int32             `json:"purchase_unit_factor" validate:"omitnil,gt=0,required_with=PurchaseUnit"`
+	SalesUnit          *string            `json:"sales_unit" validate:"omitnil,uuid,required_with=SalesUnitFactor"`
+	SalesUnitFactor    *int32             `json:"sales_unit_factor" validate:"omitnil,gt=0,required_with=SalesUnit"`
+	Serialized         *bool              `json:"serialized" validate:"omitnil"`
+	StockingUnit       *string            `json:"stocking_unit" validate:"omitnil,uuid"`
+	Tags               []string           `json:"tags" validate:"omitnil,max=50,unique,dive,min=1,max=40"`
+	Type               database.ItemType  `json:"type" validate:"required,itemtype"`
+	Metadata           map[string]string  `json:"metadata" validate:"omitnil,max=50,dive,keys,min=1,max=40,endkeys,max=500"`
+	Expand             []string           `json:"expand" validate:"omitnil,dive,oneof=components files group identifiers inventory parent prices purchase_unit sales_unit serial_numbers stock stocking_unit suppliers variants"`
+}
+
+type GenerateVariantsParams struct {
+	Expand []string `json:"expand" validate:"omitnil,dive,oneof=components files group identifiers inventory parent prices purchase_unit sales_unit serial_numbers stock stocking_unit suppliers variants"`
 }
 
 type ListItemsByIdsParams struct {
 	Ids []uuid.UUID
 }
 
+type ListItemsByParentsParams struct {
+	Ids []uuid.UUID
+}
+
+type ListPriceHistoryParams struct {
+	*database.PaginationParams
+	CreatedAt *database.TimeRange `json:"created_at" validate:"omitnil"`
+}
+
+// ListItemsParams filters items. Of the tag filters, Tags matches items with
+// exactly these tags, TagsAll items with all of them and TagsAny items with
+// at least one.
 type ListItemsParams struct {
 	*database.PaginationParams
-	Active        *bool               `json:"active" validate:"omitnil"`
-	CreatedAt     *database.TimeRange `json:"created_at" validate:"omitnil"`
-	Description   *string             `json:"description" validate:"omitnil"`
-	Group         *string             `json:"group" validate:"omitnil"`
-	Inventory     *string             `json:"inventory" validate:"omitnil,uuid"`
-	Name          *string             `json:"name" validate:"omitnil"`
-	PriceAmount   *int32              `json:"price_amount" validate:"omitnil"`
-	PriceCurrency *database.Currency  `json:"price_currency" validate:"omitnil,currency"`
-	Type          *database.ItemType  `json:"type" validate:"omitnil,itemtype"`
-	UpdatedAt     *database.TimeRange `json:"updated_at" validate:"omitnil"`
-	Variant       *bool               `json:"variant" validate:"omitnil"`
-	Expand        []string            `json:"expand" validate:"omitnil,dive,oneof=group identifiers inventory"`
+	Active             *bool               `json:"active" validate:"omitnil"`
+	CreatedAt          *database.TimeRange `json:"created_at" validate:"omitnil"`
+	DescendantOf       *string             `json:"descendant_of" validate:"omitnil,uuid"`
+	Description        *string             `json:"description" validate:"omitnil"`
+	Group              *string             `json:"group" validate:"omitnil"`
+	Inventory          *string             `json:"inventory" validate:"omitnil,uuid"`
+	Location           *string             `json:"location" validate:"omitnil,uuid"`
+	LotsExpiringBefore *time.Time          `json:"lots_expiring_before" validate:"omitnil"`
+	Name               *string             `json:"name" validate:"omitnil"`
+	Parent             *string             `json:"parent" validate:"omitnil,uuid"`
+	PriceAmount        *int32              `json:"price_amount" validate:"omitnil"`
+	PriceCurrency      *database.Currency  `json:"price_currency" validate:"omitnil,currency"`
+	Supplier           *string             `json:"supplier" validate:"omitnil,uuid"`
+	Tags               []string            `json:"tags" validate:"omitnil,max=50,unique,dive,min=1,max=40"`
+	TagsAll            []string            `json:"tags_all" validate:"omitnil,max=50,unique,dive,min=1,max=40"`
+	TagsAny            []string            `json:"tags_any" validate:"omitnil,max=50,unique,dive,min=1,max=40"`
+	Type               *database.ItemType  `json:"type" validate:"omitnil,itemtype"`
+	UpdatedAt          *database.TimeRange `json:"updated_at" validate:"omitnil"`
+	Serialized         *bool               `json:"serialized" validate:"omitnil"`
+	Variant            *bool               `json:"variant" validate:"omitnil"`
+	Metadata           map[string]string   `json:"metadata" validate:"omitnil,max=50,dive,keys,min=1,max=40,endkeys,max=500"`
+	Expand             []string            `json:"expand" validate:"omitnil,dive,oneof=components files group identifiers inventory parent prices purchase_unit sales_unit serial_numbers stock stocking_unit suppliers variants"`
+}
+
+type ListVariantsParams struct {
+	*database.PaginationParams
+	Expand []string `json:"expand" validate:"omitnil,dive,oneof=components files group identifiers inventory parent prices purchase_unit sales_unit serial_numbers stock stocking_unit suppliers variants"`
 }
 
 type RetrieveItemParams struct {
-	Expand []string `json:"expand" validate:"omitnil,dive,oneof=group identifiers inventory"`
+	Expand []string `json:"expand" validate:"omitnil,dive,oneof=components files group identifiers inventory parent prices purchase_unit sales_unit serial_numbers stock stocking_unit suppliers variants"`
+}
+
+// UpdateItemTagsParams adds tags to or removes them from several items at
+// once.
+type UpdateItemTagsParams struct {
+	Items  []string `json:"items" validate:"required,min=1,max=100,unique,dive,uuid"`
+	Tags   []string `json:"tags" validate:"required,min=1,max=50,unique,dive,min=1,max=40"`
+	Expand []string `json:"expand" validate:"omitnil,dive,oneof=components files group identifiers inventory parent prices purchase_unit sales_unit serial_numbers stock stocking_unit suppliers variants"`
 }
 
 type UpdateItemParams struct {
-	Active        *bool              `json:"active" validate:"omitnil"`
-	Description   *string            `json:"description" validate:"omitnil"`
-	Group         *string            `json:"group" validate:"omitnil"`
-	Inventory     *string            `json:"inventory" validate:"omitnil,uuid"`
-	Name          *string            `json:"name" validate:"omitnil"`
-	PriceAmount   *int32             `json:"price_amount" validate:"omitnil"`
-	PriceCurrency *database.Currency `json:"price_currency" validate:"omitnil,currency"`
-	Expand        []string           `json:"expand" validate:"omitnil,dive,oneof=group identifiers inventory"`
+	Active             *bool              `json:"active" validate:"omitnil"`
+	Description        *string            `json:"description" validate:"omitnil"`
+	Group              *string            `json:"group" validate:"omitnil"`
+	Inventory          *string            `json:"inventory" validate:"omitnil,uuid"`
+	Name               *string            `json:"name" validate:"omitnil"`
+	PriceAmount        *int32             `json:"price_amount" validate:"omitnil"`
+	PriceCurrency      *database.Currency `json:"price_currency" validate:"omitnil,currency"`
+	PurchaseUnit       *string            `json:"purchase_unit" validate:"omitnil,uuid,required_with=PurchaseUnitFactor"`
+	PurchaseUnitFactor *int32             `json:"purchase_unit_factor" validate:"omitnil,gt=0,required_with=PurchaseUnit"`
+	SalesUnit          *string            `json:"sales_unit" validate:"omitnil,uuid,required_with=SalesUnitFactor"`
+	SalesUnitFactor    *int32             `json:"sales_unit_factor" validate:"omitnil,gt=0,required_with=SalesUnit"`
+	StockingUnit       *string            `json:"stocking_unit" validate:"omitnil,uuid"`
+	Tags               []string           `json:"tags" validate:"omitnil,max=50,unique,dive,min=1,max=40"`
+	Metadata           map[string]string  `json:"metadata" validate:"omitnil,max=50,dive,keys,min=1,max=40,endkeys,max=500"`
+	Expand             []string           `json:"expand" validate:"omitnil,dive,oneof=components files group identifiers inventory parent prices purchase_unit sales_unit serial_numbers stock stocking_unit suppliers variants"`
 }
 
 func NewListItemsParams() ListItemsParams {
@@ -85,3 +148,21 @@ func NewListItemsParams() ListItemsParams {
 		},
 	}
 }
+
+func NewListPriceHistoryParams() ListPriceHistoryParams {
+	limit := int32(10)
+	return ListPriceHistoryParams{
+		PaginationParams: &database.PaginationParams{
+			Limit: &limit,
+		},
+	}
+}
+
+func NewListVariantsParams() ListVariantsParams {
+	limit := int32(10)
+	return ListVariantsParams{
+		PaginationParams: &database.PaginationParams{
+			Limit: &limit,
+		},
+	}
+}
@@ -0,0 +1,124 @@
+package items
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/currency"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/d-darac/inventory-assets/ints"
+	"github.com/google/uuid"
+)
+
+type PriceHistory struct {
+	AccountId     uuid.UUID
+	ItemId        uuid.UUID
+	RequestParams ListPriceHistoryParams
+}
+
+// PriceHistory lists the price changes of an item, newest first. The price
+// on a given date is that of the latest change created by then.
+func (s *ItemsService) PriceHistory(history PriceHistory) (changes []*PriceChange, hasMore bool, err error) {
+	_, err = s.Get(Get{
+		AccountId:     history.AccountId,
+		ItemId:        history.ItemId,
+		RequestParams: RetrieveItemParams{},
+		OmitBase:      true,
+	})
+	if err != nil {
+		return changes, hasMore, err
+	}
+
+	if history.RequestParams.StartingAfter != nil {
+		createdAt, err := s.priceChangeCreatedAt(history.AccountId, *history.RequestParams.StartingAfter)
+		if err != nil {
+			return changes, hasMore, err
+		}
+		history.RequestParams.StartingAfterDate = createdAt
+	}
+
+	if history.RequestParams.EndingBefore != nil {
+		createdAt, err := s.priceChangeCreatedAt(history.AccountId, *history.RequestParams.EndingBefore)
+		if err != nil {
+			return changes, hasMore, err
+		}
+		history.RequestParams.EndingBeforeDate = createdAt
+	}
+
+	dbParams := MapListItemPriceHistoryParams(history)
+
+	rows, err := s.Db.ListItemPriceHistory(context.Background(), dbParams)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return changes, hasMore, nil
+		}
+		return
+	}
+
+	if dbParams.Limit.Valid {
+		hasMore = len(rows) > int(dbParams.Limit.Int32)
+	} else {
+		hasMore = len(rows) > 10
+	}
+
+	if hasMore {
+		if dbParams.EndingBefore.Valid {
+			rows = rows[1:]
+		} else {
+			rows = rows[:len(rows)-1]
+		}
+	}
+
+	for _, row := range rows {
+		changes = append(changes, &PriceChange{
+			ID:                    row.ID,
+			CreatedAt:             row.CreatedAt,
+			ApiKey:                row.ApiKey,
+			PreviousPriceAmount:   ints.NullInt32(row.PreviousPriceAmount),
+			PreviousPriceCurrency: currency.NullCurrency(row.PreviousPriceCurrency),
+			PriceAmount:           ints.NullInt32(row.PriceAmount),
+			PriceCurrency:         currency.NullCurrency(row.PriceCurrency),
+		})
+	}
+
+	return changes, hasMore, err
+}
+
+// recordPriceChange adds a price change for an update that left the item
+// with a different price_amount or price_currency than current had.
+func recordPriceChange(q *database.Queries, update Update, current *Item, row database.UpdateItemRow) error {
+	previousAmount := sql.NullInt32(current.PriceAmount)
+	previousCurrency := database.NullCurrency(current.PriceCurrency)
+	if previousAmount == row.PriceAmount && previousCurrency == row.PriceCurrency {
+		return nil
+	}
+
+	_, err := q.CreateItemPriceChange(context.Background(), database.CreateItemPriceChangeParams{
+		ID:                    uuid.New(),
+		CreatedAt:             time.Now(),
+		AccountID:             update.AccountId,
+		ApiKeyID:              api.NullUUID(&update.ApiKeyId),
+		ItemID:                update.ItemId,
+		PreviousPriceAmount:   previousAmount,
+		PreviousPriceCurrency: previousCurrency,
+		PriceAmount:           row.PriceAmount,
+		PriceCurrency:         row.PriceCurrency,
+	})
+	return err
+}
+
+func (s *ItemsService) priceChangeCreatedAt(accountId, priceChangeId uuid.UUID) (*time.Time, error) {
+	row, err := s.Db.GetItemPriceChange(context.Background(), database.GetItemPriceChangeParams{
+		ID:        priceChangeId,
+		AccountID: accountId,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, api.NotFoundMessage(priceChangeId, "price change")
+		}
+		return nil, err
+	}
+	return &row.CreatedAt, nil
+}
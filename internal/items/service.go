@@ -3,7 +3,10 @@ package items
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"net/http"
 
+	"github.com/d-darac/inventory-api/internal/metadata"
 	"github.com/d-darac/inventory-assets/api"
 	"github.com/d-darac/inventory-assets/currency"
 	"github.com/d-darac/inventory-assets/database"
@@ -13,7 +16,8 @@ import (
 )
 
 type ItemsService struct {
-	Db *database.Queries
+	Conn *sql.DB
+	Db   *database.Queries
 }
 
 type Create struct {
@@ -24,6 +28,7 @@ type Create struct {
 type Delete struct {
 	AccountId uuid.UUID
 	ItemId    uuid.UUID
+	IfMatch   []int32
 }
 
 type Get struct {
@@ -45,17 +50,54 @@ type List struct {
 
 type Update struct {
 	AccountId     uuid.UUID
+	ApiKeyId      uuid.UUID
 	ItemId        uuid.UUID
+	IfMatch       []int32
 	RequestParams UpdateItemParams
 }
 
-func NewItemsService(db *database.Queries) *ItemsService {
+func NewItemsService(db *database.Queries, conn *sql.DB) *ItemsService {
 	return &ItemsService{
-		Db: db,
+		Conn: conn,
+		Db:   db,
 	}
 }
 
 func (s *ItemsService) Create(create Create) (*Item, error) {
+	params := create.RequestParams
+	if params.StockingUnit == nil && (params.PurchaseUnit != nil || params.SalesUnit != nil) {
+		return nil, noStockingUnitMessage()
+	}
+	if params.Type == database.ItemTypeBUNDLE && params.Serialized != nil && *params.Serialized {
+		return nil, &api.AppError{
+			Message: "A bundle can't be serialized, its stock is that of its components.",
+			Status:  http.StatusBadRequest,
+			Type:    api.InvalidRequestError,
+		}
+	}
+
+	if params.Inventory != nil {
+		inventory, err := s.Db.GetInventory(context.Background(), database.GetInventoryParams{
+			ID:        uuid.MustParse(*params.Inventory),
+			AccountID: create.AccountId,
+		})
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return nil, api.NotFoundMessage(uuid.MustParse(*params.Inventory), "inventory")
+			}
+			return nil, err
+		}
+		if err := CheckOpeningStock(params, inventory.InStock); err != nil {
+			return nil, err
+		}
+	}
+
+	if params.Parent != nil {
+		if err := s.checkVariant(create.AccountId, uuid.MustParse(*params.Parent), params.OptionValues); err != nil {
+			return nil, err
+		}
+	}
+
 	dbParams := MapCreateItemParams(create)
 	row, err := s.Db.CreateItem(context.Background(), dbParams)
 	if err != nil {
@@ -63,19 +105,31 @@ func (s *ItemsService) Create(create Create) (*Item, error) {
 	}
 
 	item := &Item{
-		ID:            &row.ID,
-		CreatedAt:     &row.CreatedAt,
-		UpdatedAt:     &row.UpdatedAt,
-		Active:        row.Active,
-		Description:   str.NullString(row.Description),
-		Group:         api.Expandable{ID: row.Group},
-		Identifiers:   api.Expandable{},
-		Inventory:     api.Expandable{ID: row.Inventory},
-		Name:          row.Name,
-		PriceAmount:   ints.NullInt32(row.PriceAmount),
-		PriceCurrency: currency.NullCurrency(row.PriceCurrency),
-		Variant:       row.Variant,
-		Type:          row.Type,
+		ID:                 &row.ID,
+		CreatedAt:          &row.CreatedAt,
+		UpdatedAt:          &row.UpdatedAt,
+		Active:             row.Active,
+		Description:        str.NullString(row.Description),
+		Group:              api.Expandable{ID: row.Group},
+		Identifiers:        api.Expandable{},
+		Inventory:          api.Expandable{ID: row.Inventory},
+		Name:               row.Name,
+		OptionValues:       decodeOptionValues(row.OptionValues),
+		Options:            decodeOptions(row.Options),
+		Parent:             api.Expandable{ID: row.Parent},
+		PriceAmount:        ints.NullInt32(row.PriceAmount),
+		PriceCurrency:      currency.NullCurrency(row.PriceCurrency),
+		PurchaseUnit:       api.Expandable{ID: row.PurchaseUnit},
+		PurchaseUnitFactor: ints.NullInt32(row.PurchaseUnitFactor),
+		SalesUnit:          api.Expandable{ID: row.SalesUnit},
+		SalesUnitFactor:    ints.NullInt32(row.SalesUnitFactor),
+		Serialized:         row.Serialized,
+		StockingUnit:       api.Expandable{ID: row.StockingUnit},
+		Tags:               row.Tags,
+		Variant:            row.Variant,
+		Type:               row.Type,
+		Metadata:           metadata.Decode(row.Metadata),
+		Version:            row.Version,
 	}
 
 	return item, nil
@@ -91,10 +145,36 @@ func (s *ItemsService) Delete(delete Delete) error {
 	if err != nil {
 		return err
 	}
-	return s.Db.DeleteItem(context.Background(), database.DeleteItemParams{
+
+	variants, err := s.ListByParents(ListByParents{
+		AccountId: delete.AccountId,
+		RequestParams: ListItemsByParentsParams{
+			Ids: []uuid.UUID{delete.ItemId},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if len(variants) != 0 {
+		return &api.AppError{
+			Message: fmt.Sprintf("Item '%v' still has variants, which have to be deleted first.", delete.ItemId),
+			Status:  http.StatusBadRequest,
+			Type:    api.InvalidRequestError,
+		}
+	}
+
+	rows, err := s.Db.DeleteItem(context.Background(), database.DeleteItemParams{
 		ID:        delete.ItemId,
 		AccountID: delete.AccountId,
+		IfMatch:   delete.IfMatch,
 	})
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return versionMismatchMessage(delete.ItemId)
+	}
+	return nil
 }
 
 func (s *ItemsService) Get(get Get) (*Item, error) {
@@ -110,16 +190,28 @@ func (s *ItemsService) Get(get Get) (*Item, error) {
 	}
 
 	item := &Item{
-		Active:        row.Active,
-		Description:   str.NullString(row.Description),
-		Group:         api.Expandable{ID: row.Group},
-		Identifiers:   api.Expandable{ID: row.Identifiers},
-		Inventory:     api.Expandable{ID: row.Inventory},
-		Name:          row.Name,
-		PriceAmount:   ints.NullInt32(row.PriceAmount),
-		PriceCurrency: currency.NullCurrency(row.PriceCurrency),
-		Variant:       row.Variant,
-		Type:          row.Type,
+		Active:             row.Active,
+		Description:        str.NullString(row.Description),
+		Group:              api.Expandable{ID: row.Group},
+		Identifiers:        api.Expandable{ID: row.Identifiers},
+		Inventory:          api.Expandable{ID: row.Inventory},
+		Name:               row.Name,
+		OptionValues:       decodeOptionValues(row.OptionValues),
+		Options:            decodeOptions(row.Options),
+		Parent:             api.Expandable{ID: row.Parent},
+		PriceAmount:        ints.NullInt32(row.PriceAmount),
+		PriceCurrency:      currency.NullCurrency(row.PriceCurrency),
+		PurchaseUnit:       api.Expandable{ID: row.PurchaseUnit},
+		PurchaseUnitFactor: ints.NullInt32(row.PurchaseUnitFactor),
+		SalesUnit:          api.Expandable{ID: row.SalesUnit},
+		SalesUnitFactor:    ints.NullInt32(row.SalesUnitFactor),
+		Serialized:         row.Serialized,
+		StockingUnit:       api.Expandable{ID: row.StockingUnit},
+		Tags:               row.Tags,
+		Variant:            row.Variant,
+		Type:               row.Type,
+		Metadata:           metadata.Decode(row.Metadata),
+		Version:            row.Version,
 	}
 
 	if !get.OmitBase {
@@ -146,19 +238,31 @@ func (s *ItemsService) ListByIds(list ListByIds) (items []*Item, err error) {
 
 	for _, row := range rows {
 		items = append(items, &Item{
-			ID:            &row.ID,
-			CreatedAt:     &row.CreatedAt,
-			UpdatedAt:     &row.UpdatedAt,
-			Active:        row.Active,
-			Description:   str.NullString(row.Description),
-			Group:         api.Expandable{ID: row.Group},
-			Identifiers:   api.Expandable{ID: row.Identifiers},
-			Inventory:     api.Expandable{ID: row.Inventory},
-			Name:          row.Name,
-			PriceAmount:   ints.NullInt32(row.PriceAmount),
-			PriceCurrency: currency.NullCurrency(row.PriceCurrency),
-			Variant:       row.Variant,
-			Type:          row.Type,
+			ID:                 &row.ID,
+			CreatedAt:          &row.CreatedAt,
+			UpdatedAt:          &row.UpdatedAt,
+			Active:             row.Active,
+			Description:        str.NullString(row.Description),
+			Group:              api.Expandable{ID: row.Group},
+			Identifiers:        api.Expandable{ID: row.Identifiers},
+			Inventory:          api.Expandable{ID: row.Inventory},
+			Name:               row.Name,
+			OptionValues:       decodeOptionValues(row.OptionValues),
+			Options:            decodeOptions(row.Options),
+			Parent:             api.Expandable{ID: row.Parent},
+			PriceAmount:        ints.NullInt32(row.PriceAmount),
+			PriceCurrency:      currency.NullCurrency(row.PriceCurrency),
+			PurchaseUnit:       api.Expandable{ID: row.PurchaseUnit},
+			PurchaseUnitFactor: ints.NullInt32(row.PurchaseUnitFactor),
+			SalesUnit:          api.Expandable{ID: row.SalesUnit},
+			SalesUnitFactor:    ints.NullInt32(row.SalesUnitFactor),
+			Serialized:         row.Serialized,
+			StockingUnit:       api.Expandable{ID: row.StockingUnit},
+			Tags:               row.Tags,
+			Variant:            row.Variant,
+			Type:               row.Type,
+			Metadata:           metadata.Decode(row.Metadata),
+			Version:            row.Version,
 		})
 	}
 
@@ -218,19 +322,31 @@ func (s *ItemsService) List(list List) (items []*Item, hasMore bool, err error)
 
 	for _, row := range rows {
 		items = append(items, &Item{
-			ID:            &row.ID,
-			CreatedAt:     &row.CreatedAt,
-			UpdatedAt:     &row.UpdatedAt,
-			Active:        row.Active,
-			Description:   str.NullString(row.Description),
-			Group:         api.Expandable{ID: row.Group},
-			Identifiers:   api.Expandable{ID: row.Identifiers},
-			Inventory:     api.Expandable{ID: row.Inventory},
-			Name:          row.Name,
-			PriceAmount:   ints.NullInt32(row.PriceAmount),
-			PriceCurrency: currency.NullCurrency(row.PriceCurrency),
-			Variant:       row.Variant,
-			Type:          row.Type,
+			ID:                 &row.ID,
+			CreatedAt:          &row.CreatedAt,
+			UpdatedAt:          &row.UpdatedAt,
+			Active:             row.Active,
+			Description:        str.NullString(row.Description),
+			Group:              api.Expandable{ID: row.Group},
+			Identifiers:        api.Expandable{ID: row.Identifiers},
+			Inventory:          api.Expandable{ID: row.Inventory},
+			Name:               row.Name,
+			OptionValues:       decodeOptionValues(row.OptionValues),
+			Options:            decodeOptions(row.Options),
+			Parent:             api.Expandable{ID: row.Parent},
+			PriceAmount:        ints.NullInt32(row.PriceAmount),
+			PriceCurrency:      currency.NullCurrency(row.PriceCurrency),
+			PurchaseUnit:       api.Expandable{ID: row.PurchaseUnit},
+			PurchaseUnitFactor: ints.NullInt32(row.PurchaseUnitFactor),
+			SalesUnit:          api.Expandable{ID: row.SalesUnit},
+			SalesUnitFactor:    ints.NullInt32(row.SalesUnitFactor),
+			Serialized:         row.Serialized,
+			StockingUnit:       api.Expandable{ID: row.StockingUnit},
+			Tags:               row.Tags,
+			Variant:            row.Variant,
+			Type:               row.Type,
+			Metadata:           metadata.Decode(row.Metadata),
+			Version:            row.Version,
 		})
 	}
 
@@ -238,38 +354,159 @@ func (s *ItemsService) List(list List) (items []*Item, hasMore bool, err error)
 }
 
 func (s *ItemsService) Update(update Update) (*Item, error) {
-	_, err := s.Get(Get{
-		AccountId:     update.AccountId,
-		ItemId:        update.ItemId,
-		RequestParams: RetrieveItemParams{},
-		OmitBase:      true,
+	tx, err := s.Conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.Db.WithTx(tx)
+
+	// Locking the item keeps its price from changing between reading it here
+	// and recording it as the previous price of the change.
+	locked, err := qtx.GetItemForUpdate(context.Background(), database.GetItemForUpdateParams{
+		ID:        update.ItemId,
+		AccountID: update.AccountId,
 	})
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, api.NotFoundMessage(update.ItemId, "item")
+		}
 		return nil, err
 	}
 
+	current := &Item{
+		Active:             locked.Active,
+		Description:        str.NullString(locked.Description),
+		Group:              api.Expandable{ID: locked.Group},
+		Identifiers:        api.Expandable{ID: locked.Identifiers},
+		Inventory:          api.Expandable{ID: locked.Inventory},
+		Name:               locked.Name,
+		OptionValues:       decodeOptionValues(locked.OptionValues),
+		Options:            decodeOptions(locked.Options),
+		Parent:             api.Expandable{ID: locked.Parent},
+		PriceAmount:        ints.NullInt32(locked.PriceAmount),
+		PriceCurrency:      currency.NullCurrency(locked.PriceCurrency),
+		PurchaseUnit:       api.Expandable{ID: locked.PurchaseUnit},
+		PurchaseUnitFactor: ints.NullInt32(locked.PurchaseUnitFactor),
+		SalesUnit:          api.Expandable{ID: locked.SalesUnit},
+		SalesUnitFactor:    ints.NullInt32(locked.SalesUnitFactor),
+		Serialized:         locked.Serialized,
+		StockingUnit:       api.Expandable{ID: locked.StockingUnit},
+		Tags:               locked.Tags,
+		Variant:            locked.Variant,
+		Type:               locked.Type,
+		Metadata:           metadata.Decode(locked.Metadata),
+		Version:            locked.Version,
+	}
+
+	// Quantities are kept in the stocking unit, so switching it would
+	// silently change the meaning of the stock already recorded.
+	params := update.RequestParams
+	if params.StockingUnit != nil && current.StockingUnit.ID.Valid && current.StockingUnit.ID.UUID.String() != *params.StockingUnit {
+		return nil, &api.AppError{
+			Message: fmt.Sprintf("Item '%v' already has a stocking unit, which can't be changed.", update.ItemId),
+			Status:  http.StatusBadRequest,
+			Type:    api.InvalidRequestError,
+		}
+	}
+	if params.StockingUnit == nil && !current.StockingUnit.ID.Valid && (params.PurchaseUnit != nil || params.SalesUnit != nil) {
+		return nil, noStockingUnitMessage()
+	}
+
+	if update.RequestParams.Metadata != nil {
+		update.RequestParams.Metadata, err = metadata.Merge(current.Metadata, update.RequestParams.Metadata)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	dbParams := MapUpdateItemParams(update)
 
-	row, err := s.Db.UpdateItem(context.Background(), dbParams)
+	row, err := qtx.UpdateItem(context.Background(), dbParams)
 	if err != nil {
+		// The item was found and locked above, so no row means its version
+		// has moved past the one in If-Match.
+		if err == sql.ErrNoRows {
+			return nil, versionMismatchMessage(update.ItemId)
+		}
+		return nil, err
+	}
+
+	if err := recordPriceChange(qtx, update, current, row); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
 		return nil, err
 	}
 
 	item := &Item{
-		ID:            &row.ID,
-		CreatedAt:     &row.CreatedAt,
-		UpdatedAt:     &row.UpdatedAt,
-		Active:        row.Active,
-		Description:   str.NullString(row.Description),
-		Group:         api.Expandable{ID: row.Group},
-		Identifiers:   api.Expandable{ID: row.Identifiers},
-		Inventory:     api.Expandable{ID: row.Inventory},
-		Name:          row.Name,
-		PriceAmount:   ints.NullInt32(row.PriceAmount),
-		PriceCurrency: currency.NullCurrency(row.PriceCurrency),
-		Variant:       row.Variant,
-		Type:          row.Type,
+		ID:                 &row.ID,
+		CreatedAt:          &row.CreatedAt,
+		UpdatedAt:          &row.UpdatedAt,
+		Active:             row.Active,
+		Description:        str.NullString(row.Description),
+		Group:              api.Expandable{ID: row.Group},
+		Identifiers:        api.Expandable{ID: row.Identifiers},
+		Inventory:          api.Expandable{ID: row.Inventory},
+		Name:               row.Name,
+		OptionValues:       decodeOptionValues(row.OptionValues),
+		Options:            decodeOptions(row.Options),
+		Parent:             api.Expandable{ID: row.Parent},
+		PriceAmount:        ints.NullInt32(row.PriceAmount),
+		PriceCurrency:      currency.NullCurrency(row.PriceCurrency),
+		PurchaseUnit:       api.Expandable{ID: row.PurchaseUnit},
+		PurchaseUnitFactor: ints.NullInt32(row.PurchaseUnitFactor),
+		SalesUnit:          api.Expandable{ID: row.SalesUnit},
+		SalesUnitFactor:    ints.NullInt32(row.SalesUnitFactor),
+		Serialized:         row.Serialized,
+		StockingUnit:       api.Expandable{ID: row.StockingUnit},
+		Tags:               row.Tags,
+		Variant:            row.Variant,
+		Type:               row.Type,
+		Metadata:           metadata.Decode(row.Metadata),
+		Version:            row.Version,
 	}
 
 	return item, nil
 }
+
+// CheckOpeningStock makes sure a new item doesn't start out with stock it
+// can't hold. The stock of a serialized item is its in_stock serial numbers,
+// so it has to come in through those, and a bundle has no stock of its own.
+func CheckOpeningStock(params CreateItemParams, inStock int32) error {
+	if inStock == 0 {
+		return nil
+	}
+	if params.Type == database.ItemTypeBUNDLE {
+		return &api.AppError{
+			Message: "A bundle can't start out with stock, its stock is that of its components.",
+			Status:  http.StatusBadRequest,
+			Type:    api.InvalidRequestError,
+		}
+	}
+	if params.Serialized != nil && *params.Serialized {
+		return &api.AppError{
+			Message: "A serialized item can't start out with stock, its stock comes in through serial numbers.",
+			Status:  http.StatusBadRequest,
+			Type:    api.InvalidRequestError,
+		}
+	}
+	return nil
+}
+
+func noStockingUnitMessage() error {
+	return &api.AppError{
+		Message: "A purchase or sales unit can only be set on an item with a stocking unit.",
+		Status:  http.StatusBadRequest,
+		Type:    api.InvalidRequestError,
+	}
+}
+
+func versionMismatchMessage(itemId uuid.UUID) error {
+	return &api.AppError{
+		Message: fmt.Sprintf("Item '%v' has changed since the version in If-Match.", itemId),
+		Status:  http.StatusPreconditionFailed,
+		Type:    api.InvalidRequestError,
+	}
+}
@@ -0,0 +1,188 @@
+package items
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/d-darac/inventory-api/internal/metadata"
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/currency"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/d-darac/inventory-assets/ints"
+	"github.com/d-darac/inventory-assets/str"
+	"github.com/google/uuid"
+)
+
+// MaxTags is the most tags an item can have, matching the validation of
+// the tags param.
+const MaxTags = 50
+
+type UpdateTags struct {
+	AccountId     uuid.UUID
+	RequestParams UpdateItemTagsParams
+}
+
+// AddTags adds tags to every item in the request. Tags an item already has
+// are left as they are, and no item may end up with more than MaxTags.
+func (s *ItemsService) AddTags(update UpdateTags) ([]*Item, error) {
+	itms, err := s.tagged(update)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range itms {
+		if len(mergeTags(item.Tags, update.RequestParams.Tags)) > MaxTags {
+			return nil, &api.AppError{
+				Message: fmt.Sprintf("Item '%v' can't have more than %d tags.", *item.ID, MaxTags),
+				Status:  http.StatusBadRequest,
+				Type:    api.InvalidRequestError,
+			}
+		}
+	}
+
+	rows, err := s.Db.AddItemTags(context.Background(), database.AddItemTagsParams{
+		UpdatedAt: time.Now(),
+		AccountID: update.AccountId,
+		Ids:       itemIds(itms),
+		Tags:      update.RequestParams.Tags,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	itms = make([]*Item, 0, len(rows))
+	for _, row := range rows {
+		itms = append(itms, &Item{
+			ID:                 &row.ID,
+			CreatedAt:          &row.CreatedAt,
+			UpdatedAt:          &row.UpdatedAt,
+			Active:             row.Active,
+			Description:        str.NullString(row.Description),
+			Group:              api.Expandable{ID: row.Group},
+			Identifiers:        api.Expandable{ID: row.Identifiers},
+			Inventory:          api.Expandable{ID: row.Inventory},
+			Name:               row.Name,
+			OptionValues:       decodeOptionValues(row.OptionValues),
+			Options:            decodeOptions(row.Options),
+			Parent:             api.Expandable{ID: row.Parent},
+			PriceAmount:        ints.NullInt32(row.PriceAmount),
+			PriceCurrency:      currency.NullCurrency(row.PriceCurrency),
+			PurchaseUnit:       api.Expandable{ID: row.PurchaseUnit},
+			PurchaseUnitFactor: ints.NullInt32(row.PurchaseUnitFactor),
+			SalesUnit:          api.Expandable{ID: row.SalesUnit},
+			SalesUnitFactor:    ints.NullInt32(row.SalesUnitFactor),
+			Serialized:         row.Serialized,
+			StockingUnit:       api.Expandable{ID: row.StockingUnit},
+			Tags:               row.Tags,
+			Variant:            row.Variant,
+			Type:               row.Type,
+			Metadata:           metadata.Decode(row.Metadata),
+			Version:            row.Version,
+		})
+	}
+
+	return itms, nil
+}
+
+// RemoveTags removes tags from every item in the request. Tags an item
+// doesn't have are ignored.
+func (s *ItemsService) RemoveTags(update UpdateTags) ([]*Item, error) {
+	itms, err := s.tagged(update)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.Db.RemoveItemTags(context.Background(), database.RemoveItemTagsParams{
+		UpdatedAt: time.Now(),
+		AccountID: update.AccountId,
+		Ids:       itemIds(itms),
+		Tags:      update.RequestParams.Tags,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	itms = make([]*Item, 0, len(rows))
+	for _, row := range rows {
+		itms = append(itms, &Item{
+			ID:                 &row.ID,
+			CreatedAt:          &row.CreatedAt,
+			UpdatedAt:          &row.UpdatedAt,
+			Active:             row.Active,
+			Description:        str.NullString(row.Description),
+			Group:              api.Expandable{ID: row.Group},
+			Identifiers:        api.Expandable{ID: row.Identifiers},
+			Inventory:          api.Expandable{ID: row.Inventory},
+			Name:               row.Name,
+			OptionValues:       decodeOptionValues(row.OptionValues),
+			Options:            decodeOptions(row.Options),
+			Parent:             api.Expandable{ID: row.Parent},
+			PriceAmount:        ints.NullInt32(row.PriceAmount),
+			PriceCurrency:      currency.NullCurrency(row.PriceCurrency),
+			PurchaseUnit:       api.Expandable{ID: row.PurchaseUnit},
+			PurchaseUnitFactor: ints.NullInt32(row.PurchaseUnitFactor),
+			SalesUnit:          api.Expandable{ID: row.SalesUnit},
+			SalesUnitFactor:    ints.NullInt32(row.SalesUnitFactor),
+			Serialized:         row.Serialized,
+			StockingUnit:       api.Expandable{ID: row.StockingUnit},
+			Tags:               row.Tags,
+			Variant:            row.Variant,
+			Type:               row.Type,
+			Metadata:           metadata.Decode(row.Metadata),
+			Version:            row.Version,
+		})
+	}
+
+	return itms, nil
+}
+
+// tagged returns the items of a tags update, failing if any of them
+// doesn't exist.
+func (s *ItemsService) tagged(update UpdateTags) ([]*Item, error) {
+	ids := make([]uuid.UUID, 0, len(update.RequestParams.Items))
+	for _, id := range update.RequestParams.Items {
+		ids = append(ids, uuid.MustParse(id))
+	}
+
+	itms, err := s.ListByIds(ListByIds{
+		AccountId: update.AccountId,
+		RequestParams: ListItemsByIdsParams{
+			Ids: ids,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	found := itemIds(itms)
+	for _, id := range ids {
+		if !slices.Contains(found, id) {
+			return nil, api.NotFoundMessage(id, "item")
+		}
+	}
+
+	return itms, nil
+}
+
+// mergeTags returns current with the tags of added it doesn't have yet
+// appended, in order.
+func mergeTags(current, added []string) []string {
+	merged := slices.Clone(current)
+	for _, tag := range added {
+		if !slices.Contains(merged, tag) {
+			merged = append(merged, tag)
+		}
+	}
+	return merged
+}
+
+func itemIds(itms []*Item) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(itms))
+	for _, item := range itms {
+		ids = append(ids, *item.ID)
+	}
+	return ids
+}
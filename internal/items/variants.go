@@ -0,0 +1,365 @@
+package items
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/d-darac/inventory-api/internal/metadata"
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/currency"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/d-darac/inventory-assets/ints"
+	"github.com/d-darac/inventory-assets/str"
+	"github.com/google/uuid"
+)
+
+// maxVariants caps the variant matrix of a single item, so a few long
+// option lists can't create an unbounded number of items in one request.
+const maxVariants = 100
+
+type GenerateVariants struct {
+	AccountId     uuid.UUID
+	ItemId        uuid.UUID
+	RequestParams GenerateVariantsParams
+}
+
+type ListByParents struct {
+	AccountId     uuid.UUID
+	RequestParams ListItemsByParentsParams
+}
+
+type ListVariants struct {
+	AccountId     uuid.UUID
+	ItemId        uuid.UUID
+	RequestParams ListVariantsParams
+}
+
+// GenerateVariants creates a variant for every combination of the item's
+// option values that doesn't have one yet. Variants start out as copies of
+// the item, named after it and their option values. Existing combinations
+// are skipped, and the parent stays locked until all of the variants are
+// created, so concurrent calls can't both create the same ones.
+func (s *ItemsService) GenerateVariants(generate GenerateVariants) ([]*Item, error) {
+	tx, err := s.Conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.Db.WithTx(tx)
+
+	parent, err := qtx.GetItemForUpdate(context.Background(), database.GetItemForUpdateParams{
+		ID:        generate.ItemId,
+		AccountID: generate.AccountId,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, api.NotFoundMessage(generate.ItemId, "item")
+		}
+		return nil, err
+	}
+
+	options := decodeOptions(parent.Options)
+	if parent.Parent.Valid || len(options) == 0 {
+		return nil, noOptionsMessage(generate.ItemId)
+	}
+
+	matrix := variantMatrix(options)
+	if len(matrix) > maxVariants {
+		return nil, &api.AppError{
+			Message: fmt.Sprintf("The options of item '%v' make %d variants, more than the %d that can be generated.", generate.ItemId, len(matrix), maxVariants),
+			Status:  http.StatusBadRequest,
+			Type:    api.InvalidRequestError,
+		}
+	}
+
+	existing, err := qtx.ListItemsByParents(context.Background(), database.ListItemsByParentsParams{
+		AccountID: generate.AccountId,
+		Ids:       []uuid.UUID{generate.ItemId},
+	})
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	taken := make(map[string]bool, len(existing))
+	for _, variant := range existing {
+		taken[optionValuesKey(options, decodeOptionValues(variant.OptionValues))] = true
+	}
+
+	variants := make([]*Item, 0)
+	for _, values := range matrix {
+		if taken[optionValuesKey(options, values)] {
+			continue
+		}
+
+		names := make([]string, 0, len(options))
+		for _, option := range options {
+			names = append(names, values[option.Name])
+		}
+		optionValues, _ := json.Marshal(values)
+
+		t := time.Now()
+		row, err := qtx.CreateItem(context.Background(), database.CreateItemParams{
+			ID:                 uuid.New(),
+			CreatedAt:          t,
+			UpdatedAt:          t,
+			AccountID:          generate.AccountId,
+			Description:        parent.Description,
+			GroupID:            parent.Group,
+			InventoryID:        api.NullUUID(nil),
+			Metadata:           parent.Metadata,
+			Name:               fmt.Sprintf("%s / %s", parent.Name, strings.Join(names, " / ")),
+			OptionValues:       optionValues,
+			ParentID:           api.NullUUID(&parent.ID),
+			PriceAmount:        parent.PriceAmount,
+			PriceCurrency:      parent.PriceCurrency,
+			PurchaseUnitID:     parent.PurchaseUnit,
+			PurchaseUnitFactor: parent.PurchaseUnitFactor,
+			SalesUnitID:        parent.SalesUnit,
+			SalesUnitFactor:    parent.SalesUnitFactor,
+			Serialized:         parent.Serialized,
+			StockingUnitID:     parent.StockingUnit,
+			Tags:               parent.Tags,
+			Type:               parent.Type,
+			Variant:            true,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		variants = append(variants, &Item{
+			ID:                 &row.ID,
+			CreatedAt:          &row.CreatedAt,
+			UpdatedAt:          &row.UpdatedAt,
+			Active:             row.Active,
+			Description:        str.NullString(row.Description),
+			Group:              api.Expandable{ID: row.Group},
+			Identifiers:        api.Expandable{},
+			Inventory:          api.Expandable{ID: row.Inventory},
+			Name:               row.Name,
+			OptionValues:       decodeOptionValues(row.OptionValues),
+			Options:            decodeOptions(row.Options),
+			Parent:             api.Expandable{ID: row.Parent},
+			PriceAmount:        ints.NullInt32(row.PriceAmount),
+			PriceCurrency:      currency.NullCurrency(row.PriceCurrency),
+			PurchaseUnit:       api.Expandable{ID: row.PurchaseUnit},
+			PurchaseUnitFactor: ints.NullInt32(row.PurchaseUnitFactor),
+			SalesUnit:          api.Expandable{ID: row.SalesUnit},
+			SalesUnitFactor:    ints.NullInt32(row.SalesUnitFactor),
+			Serialized:         row.Serialized,
+			StockingUnit:       api.Expandable{ID: row.StockingUnit},
+			Tags:               row.Tags,
+			Variant:            row.Variant,
+			Type:               row.Type,
+			Metadata:           metadata.Decode(row.Metadata),
+			Version:            row.Version,
+		})
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return variants, nil
+}
+
+func (s *ItemsService) ListByParents(list ListByParents) (items []*Item, err error) {
+	params := database.ListItemsByParentsParams{
+		AccountID: list.AccountId,
+		Ids:       list.RequestParams.Ids,
+	}
+
+	rows, err := s.Db.ListItemsByParents(context.Background(), params)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return items, nil
+		}
+		return
+	}
+
+	for _, row := range rows {
+		items = append(items, &Item{
+			ID:                 &row.ID,
+			CreatedAt:          &row.CreatedAt,
+			UpdatedAt:          &row.UpdatedAt,
+			Active:             row.Active,
+			Description:        str.NullString(row.Description),
+			Group:              api.Expandable{ID: row.Group},
+			Identifiers:        api.Expandable{ID: row.Identifiers},
+			Inventory:          api.Expandable{ID: row.Inventory},
+			Name:               row.Name,
+			OptionValues:       decodeOptionValues(row.OptionValues),
+			Options:            decodeOptions(row.Options),
+			Parent:             api.Expandable{ID: row.Parent},
+			PriceAmount:        ints.NullInt32(row.PriceAmount),
+			PriceCurrency:      currency.NullCurrency(row.PriceCurrency),
+			PurchaseUnit:       api.Expandable{ID: row.PurchaseUnit},
+			PurchaseUnitFactor: ints.NullInt32(row.PurchaseUnitFactor),
+			SalesUnit:          api.Expandable{ID: row.SalesUnit},
+			SalesUnitFactor:    ints.NullInt32(row.SalesUnitFactor),
+			Serialized:         row.Serialized,
+			StockingUnit:       api.Expandable{ID: row.StockingUnit},
+			Tags:               row.Tags,
+			Variant:            row.Variant,
+			Type:               row.Type,
+			Metadata:           metadata.Decode(row.Metadata),
+			Version:            row.Version,
+		})
+	}
+
+	return
+}
+
+func (s *ItemsService) ListVariants(list ListVariants) (items []*Item, hasMore bool, err error) {
+	_, err = s.Get(Get{
+		AccountId:     list.AccountId,
+		ItemId:        list.ItemId,
+		RequestParams: RetrieveItemParams{},
+		OmitBase:      true,
+	})
+	if err != nil {
+		return items, hasMore, err
+	}
+
+	parentId := list.ItemId.String()
+	return s.List(List{
+		AccountId: list.AccountId,
+		RequestParams: ListItemsParams{
+			PaginationParams: list.RequestParams.PaginationParams,
+			Parent:           &parentId,
+		},
+	})
+}
+
+// checkVariant makes sure a new variant of parentId picks one allowed value
+// for each of the parent's options, in a combination no other variant of
+// the parent has.
+func (s *ItemsService) checkVariant(accountId, parentId uuid.UUID, optionValues map[string]string) error {
+	parent, err := s.Db.GetItem(context.Background(), database.GetItemParams{
+		ID:        parentId,
+		AccountID: accountId,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return api.NotFoundMessage(parentId, "item")
+		}
+		return err
+	}
+
+	options := decodeOptions(parent.Options)
+	if parent.Parent.Valid || len(options) == 0 {
+		return noOptionsMessage(parentId)
+	}
+	if err := checkOptionValues(options, optionValues); err != nil {
+		return err
+	}
+
+	siblings, err := s.ListByParents(ListByParents{
+		AccountId: accountId,
+		RequestParams: ListItemsByParentsParams{
+			Ids: []uuid.UUID{parentId},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	key := optionValuesKey(options, optionValues)
+	for _, sibling := range siblings {
+		if optionValuesKey(options, sibling.OptionValues) == key {
+			return &api.AppError{
+				Message: fmt.Sprintf("Item '%v' already has a variant with these option values: '%v'.", parentId, *sibling.ID),
+				Status:  http.StatusBadRequest,
+				Type:    api.InvalidRequestError,
+			}
+		}
+	}
+
+	return nil
+}
+
+func checkOptionValues(options []ItemOption, optionValues map[string]string) error {
+	for _, option := range options {
+		value, ok := optionValues[option.Name]
+		if !ok {
+			return &api.AppError{
+				Message: fmt.Sprintf("Option '%s' needs a value.", option.Name),
+				Status:  http.StatusBadRequest,
+				Type:    api.InvalidRequestError,
+			}
+		}
+		if !slices.Contains(option.Values, value) {
+			return &api.AppError{
+				Message: fmt.Sprintf("'%s' isn't one of the values of option '%s'.", value, option.Name),
+				Status:  http.StatusBadRequest,
+				Type:    api.InvalidRequestError,
+			}
+		}
+	}
+
+	if len(optionValues) != len(options) {
+		return &api.AppError{
+			Message: "Option values can only be given for the options of the parent item.",
+			Status:  http.StatusBadRequest,
+			Type:    api.InvalidRequestError,
+		}
+	}
+
+	return nil
+}
+
+// variantMatrix returns every combination of the options' values, varying
+// the last option fastest.
+func variantMatrix(options []ItemOption) []map[string]string {
+	matrix := []map[string]string{{}}
+	for _, option := range options {
+		next := make([]map[string]string, 0, len(matrix)*len(option.Values))
+		for _, values := range matrix {
+			for _, value := range option.Values {
+				combination := make(map[string]string, len(values)+1)
+				for name, v := range values {
+					combination[name] = v
+				}
+				combination[option.Name] = value
+				next = append(next, combination)
+			}
+		}
+		matrix = next
+	}
+	return matrix
+}
+
+func optionValuesKey(options []ItemOption, optionValues map[string]string) string {
+	values := make([]string, 0, len(options))
+	for _, option := range options {
+		values = append(values, optionValues[option.Name])
+	}
+	return strings.Join(values, "\x00")
+}
+
+func decodeOptions(raw []byte) (options []ItemOption) {
+	if len(raw) != 0 {
+		json.Unmarshal(raw, &options)
+	}
+	return
+}
+
+func decodeOptionValues(raw []byte) (optionValues map[string]string) {
+	if len(raw) != 0 {
+		json.Unmarshal(raw, &optionValues)
+	}
+	return
+}
+
+func noOptionsMessage(itemId uuid.UUID) error {
+	return &api.AppError{
+		Message: fmt.Sprintf("Item '%v' has no options to make variants of.", itemId),
+		Status:  http.StatusBadRequest,
+		Type:    api.InvalidRequestError,
+	}
+}
@@ -0,0 +1,20 @@
+package locations
+
+import (
+	"time"
+
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/d-darac/inventory-assets/str"
+	"github.com/google/uuid"
+)
+
+type Location struct {
+	ID          *uuid.UUID            `json:"id,omitempty"`
+	CreatedAt   *time.Time            `json:"created_at,omitempty"`
+	UpdatedAt   *time.Time            `json:"updated_at,omitempty"`
+	Active      bool                  `json:"active"`
+	Address     str.NullString        `json:"address"`
+	Description str.NullString        `json:"description"`
+	Name        string                `json:"name"`
+	Type        database.LocationType `json:"type"`
+}
@@ -0,0 +1,174 @@
+package locations
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+	"github.com/joho/godotenv"
+	_ "github.com/lib/pq"
+)
+
+func TestUnitNewListLocationsParams(t *testing.T) {
+	lp := NewListLocationsParams()
+	if lp.PaginationParams == nil {
+		t.Fatal("PaginationParams is nil")
+	}
+	if lp.PaginationParams.Limit == nil {
+		t.Fatal("Limit is nil")
+	}
+	if *lp.PaginationParams.Limit != 10 {
+		t.Fatalf("expected limit 10, got %d", *lp.PaginationParams.Limit)
+	}
+}
+
+func TestUnitMapCreateLocationParams(t *testing.T) {
+	acc := uuid.New()
+	address := "1 Main Street"
+	cp := CreateLocationParams{
+		Address: &address,
+		Name:    "Main Warehouse",
+		Type:    database.LocationTypeWarehouse,
+	}
+
+	dbp := MapCreateLocationParams(Create{AccountId: acc, RequestParams: cp})
+	if dbp.AccountID != acc {
+		t.Fatalf("expected account id %v, got %v", acc, dbp.AccountID)
+	}
+	if dbp.Name != cp.Name {
+		t.Fatalf("expected name %s, got %s", cp.Name, dbp.Name)
+	}
+	if dbp.Type != cp.Type {
+		t.Fatalf("expected type %s, got %s", cp.Type, dbp.Type)
+	}
+	if (!dbp.Address.Valid) || dbp.Address.String != address {
+		t.Fatalf("expected address %s, got %s", address, dbp.Address.String)
+	}
+	if dbp.Description.Valid {
+		t.Fatalf("expected no description, got %s", dbp.Description.String)
+	}
+}
+
+func TestUnitMapListLocationsParams(t *testing.T) {
+	acc := uuid.New()
+	lp := NewListLocationsParams()
+	active := false
+	locationType := database.LocationTypeStore
+	lp.Active = &active
+	lp.Type = &locationType
+
+	dbp := MapListLocationsParams(List{AccountId: acc, RequestParams: lp})
+	if dbp.AccountID != acc {
+		t.Fatalf("expected account id %v, got %v", acc, dbp.AccountID)
+	}
+	if (!dbp.Active.Valid) || dbp.Active.Bool != active {
+		t.Fatalf("expected active %v, got %v", active, dbp.Active)
+	}
+	if (!dbp.Type.Valid) || dbp.Type.LocationType != locationType {
+		t.Fatalf("expected type %s, got %v", locationType, dbp.Type)
+	}
+	if dbp.Name.Valid {
+		t.Fatalf("expected no name, got %s", dbp.Name.String)
+	}
+}
+
+func TestUnitMapUpdateLocationParams(t *testing.T) {
+	id := uuid.New()
+	acc := uuid.New()
+	active := false
+	up := UpdateLocationParams{
+		Active: &active,
+	}
+
+	dbp := MapUpdateLocationParams(Update{AccountId: acc, LocationId: id, RequestParams: up})
+	if dbp.ID != id {
+		t.Fatalf("expected id %v, got %v", id, dbp.ID)
+	}
+	if dbp.AccountID != acc {
+		t.Fatalf("expected account id %v, got %v", acc, dbp.AccountID)
+	}
+	if (!dbp.Active.Valid) || dbp.Active.Bool != active {
+		t.Fatalf("expected active %v, got %v", active, dbp.Active)
+	}
+	if dbp.Name.Valid || dbp.Type.Valid {
+		t.Fatalf("expected name and type to be left alone, got %v and %v", dbp.Name, dbp.Type)
+	}
+}
+
+func TestIntegrationCreateUpdateDelete(t *testing.T) {
+	godotenv.Load("../../.env")
+	dbUrl := os.Getenv("DB_URL")
+
+	db, err := sql.Open("postgres", dbUrl)
+	if err != nil {
+		t.Fatalf("couldn't open database: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.Ping(); err != nil {
+		t.Fatalf("couldn't connect to database: %v", err)
+	}
+
+	q := database.New(db)
+
+	acc, err := q.CreateAccount(context.Background(), database.CreateAccountParams{
+		Country: database.CountryIE,
+		Nickname: sql.NullString{
+			String: "Test Account",
+			Valid:  true,
+		},
+	})
+	if err != nil {
+		t.Fatalf("couldn't create test account: %v", err)
+	}
+	defer db.Exec("DELETE FROM accounts WHERE id = $1;", acc.ID)
+
+	s := NewLocationsService(q)
+
+	location, err := s.Create(Create{AccountId: acc.ID, RequestParams: CreateLocationParams{
+		Name: "Main Warehouse",
+		Type: database.LocationTypeWarehouse,
+	}})
+	if err != nil {
+		t.Fatalf("couldn't create test location: %v", err)
+	}
+	if !location.Active {
+		t.Fatal("expected a new location to be active")
+	}
+	if location.Type != database.LocationTypeWarehouse {
+		t.Fatalf("expected type %s, got %s", database.LocationTypeWarehouse, location.Type)
+	}
+
+	active := false
+	store := database.LocationTypeStore
+	location, err = s.Update(Update{AccountId: acc.ID, LocationId: *location.ID, RequestParams: UpdateLocationParams{Active: &active, Type: &store}})
+	if err != nil {
+		t.Fatalf("couldn't update test location: %v", err)
+	}
+	if location.Active || location.Type != store {
+		t.Fatalf("expected an inactive store, got active %v and type %s", location.Active, location.Type)
+	}
+	if location.Name != "Main Warehouse" {
+		t.Fatalf("expected name to be left alone, got %s", location.Name)
+	}
+
+	lp := NewListLocationsParams()
+	lp.Active = &active
+	locations, _, err := s.List(List{AccountId: acc.ID, RequestParams: lp})
+	if err != nil {
+		t.Fatalf("error listing locations: %v", err)
+	}
+	if len(locations) != 1 || *locations[0].ID != *location.ID {
+		t.Fatalf("expected only the inactive location, got %v", locations)
+	}
+
+	if err := s.Delete(Delete{AccountId: acc.ID, LocationId: *location.ID}); err != nil {
+		t.Fatalf("error deleting location: %v", err)
+	}
+	if _, err := s.Get(Get{AccountId: acc.ID, LocationId: *location.ID}); err == nil {
+		t.Fatal("expected the deleted location to be gone")
+	}
+}
@@ -0,0 +1,61 @@
+package locations
+
+import (
+	"time"
+
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+func MapCreateLocationParams(create Create) database.CreateLocationParams {
+	t := time.Now()
+	clp := database.CreateLocationParams{
+		ID:          uuid.New(),
+		CreatedAt:   t,
+		UpdatedAt:   t,
+		AccountID:   create.AccountId,
+		Address:     api.NullString(create.RequestParams.Address),
+		Description: api.NullString(create.RequestParams.Description),
+		Name:        create.RequestParams.Name,
+		Type:        create.RequestParams.Type,
+	}
+	return clp
+}
+
+func MapListLocationsParams(list List) database.ListLocationsParams {
+	llp := database.ListLocationsParams{
+		AccountID: list.AccountId,
+		Active:    api.NullBool(list.RequestParams.Active),
+		Name:      api.NullString(list.RequestParams.Name),
+	}
+	if list.RequestParams.Type != nil {
+		llp.Type = database.NullLocationType{
+			LocationType: *list.RequestParams.Type,
+			Valid:        true,
+		}
+	}
+	database.MapTimeRange(list.RequestParams.CreatedAt, &llp.CreatedAtGt, &llp.CreatedAtGte, &llp.CreatedAtLt, &llp.CreatedAtLte)
+	database.MapTimeRange(list.RequestParams.UpdatedAt, &llp.UpdatedAtGt, &llp.UpdatedAtGte, &llp.UpdatedAtLt, &llp.UpdatedAtLte)
+	database.MapPaginationParams(*list.RequestParams.PaginationParams, &llp)
+	return llp
+}
+
+func MapUpdateLocationParams(update Update) database.UpdateLocationParams {
+	ulp := database.UpdateLocationParams{
+		UpdatedAt:   time.Now(),
+		AccountID:   update.AccountId,
+		ID:          update.LocationId,
+		Active:      api.NullBool(update.RequestParams.Active),
+		Address:     api.NullString(update.RequestParams.Address),
+		Description: api.NullString(update.RequestParams.Description),
+		Name:        api.NullString(update.RequestParams.Name),
+	}
+	if update.RequestParams.Type != nil {
+		ulp.Type = database.NullLocationType{
+			LocationType: *update.RequestParams.Type,
+			Valid:        true,
+		}
+	}
+	return ulp
+}
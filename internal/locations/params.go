@@ -0,0 +1,46 @@
+package locations
+
+import (
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+type CreateLocationParams struct {
+	Address     *string               `json:"address" validate:"omitnil"`
+	Description *string               `json:"description" validate:"omitnil"`
+	Name        string                `json:"name" validate:"required"`
+	Type        database.LocationType `json:"type" validate:"required,oneof=warehouse store"`
+}
+
+type ListLocationsByIdsParams struct {
+	Ids []uuid.UUID
+}
+
+type ListLocationsParams struct {
+	*database.PaginationParams
+	Active    *bool                  `json:"active" validate:"omitnil"`
+	CreatedAt *database.TimeRange    `json:"created_at" validate:"omitnil"`
+	Name      *string                `json:"name" validate:"omitnil"`
+	Type      *database.LocationType `json:"type" validate:"omitnil,oneof=warehouse store"`
+	UpdatedAt *database.TimeRange    `json:"updated_at" validate:"omitnil"`
+}
+
+type RetrieveLocationParams struct {
+}
+
+type UpdateLocationParams struct {
+	Active      *bool                  `json:"active" validate:"omitnil"`
+	Address     *string                `json:"address" validate:"omitnil"`
+	Description *string                `json:"description" validate:"omitnil"`
+	Name        *string                `json:"name" validate:"omitnil"`
+	Type        *database.LocationType `json:"type" validate:"omitnil,oneof=warehouse store"`
+}
+
+func NewListLocationsParams() ListLocationsParams {
+	limit := int32(10)
+	return ListLocationsParams{
+		PaginationParams: &database.PaginationParams{
+			Limit: &limit,
+		},
+	}
+}
@@ -0,0 +1,249 @@
+package locations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/d-darac/inventory-assets/str"
+	"github.com/google/uuid"
+)
+
+type LocationsService struct {
+	Db *database.Queries
+}
+
+type Create struct {
+	AccountId     uuid.UUID
+	RequestParams CreateLocationParams
+}
+
+type Delete struct {
+	AccountId  uuid.UUID
+	LocationId uuid.UUID
+}
+
+type Get struct {
+	AccountId     uuid.UUID
+	LocationId    uuid.UUID
+	RequestParams RetrieveLocationParams
+	OmitBase      bool
+}
+
+type ListByIds struct {
+	AccountId     uuid.UUID
+	RequestParams ListLocationsByIdsParams
+}
+
+type List struct {
+	AccountId     uuid.UUID
+	RequestParams ListLocationsParams
+}
+
+type Update struct {
+	AccountId     uuid.UUID
+	LocationId    uuid.UUID
+	RequestParams UpdateLocationParams
+}
+
+func NewLocationsService(db *database.Queries) *LocationsService {
+	return &LocationsService{
+		Db: db,
+	}
+}
+
+func (s *LocationsService) Create(create Create) (*Location, error) {
+	dbParams := MapCreateLocationParams(create)
+	row, err := s.Db.CreateLocation(context.Background(), dbParams)
+	if err != nil {
+		return nil, err
+	}
+
+	location := &Location{
+		ID:          &row.ID,
+		CreatedAt:   &row.CreatedAt,
+		UpdatedAt:   &row.UpdatedAt,
+		Active:      row.Active,
+		Address:     str.NullString(row.Address),
+		Description: str.NullString(row.Description),
+		Name:        row.Name,
+		Type:        row.Type,
+	}
+
+	return location, nil
+}
+
+func (s *LocationsService) Delete(delete Delete) error {
+	_, err := s.Get(Get{
+		AccountId:     delete.AccountId,
+		LocationId:    delete.LocationId,
+		RequestParams: RetrieveLocationParams{},
+		OmitBase:      true,
+	})
+	if err != nil {
+		return err
+	}
+	return s.Db.DeleteLocation(context.Background(), database.DeleteLocationParams{
+		ID:        delete.LocationId,
+		AccountID: delete.AccountId,
+	})
+}
+
+func (s *LocationsService) Get(get Get) (*Location, error) {
+	row, err := s.Db.GetLocation(context.Background(), database.GetLocationParams{
+		ID:        get.LocationId,
+		AccountID: get.AccountId,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, api.NotFoundMessage(get.LocationId, "location")
+		}
+		return nil, err
+	}
+
+	location := &Location{
+		Active:      row.Active,
+		Address:     str.NullString(row.Address),
+		Description: str.NullString(row.Description),
+		Name:        row.Name,
+		Type:        row.Type,
+	}
+
+	if !get.OmitBase {
+		location.ID = &row.ID
+		location.CreatedAt = &row.CreatedAt
+		location.UpdatedAt = &row.UpdatedAt
+	}
+
+	return location, nil
+}
+
+func (s *LocationsService) ListByIds(list ListByIds) (locations []*Location, err error) {
+	params := database.ListLocationsByIdsParams{
+		AccountID: list.AccountId,
+		Ids:       list.RequestParams.Ids,
+	}
+
+	rows, err := s.Db.ListLocationsByIds(context.Background(), params)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return locations, nil
+		}
+		return
+	}
+
+	for _, row := range rows {
+		locations = append(locations, &Location{
+			ID:          &row.ID,
+			CreatedAt:   &row.CreatedAt,
+			UpdatedAt:   &row.UpdatedAt,
+			Active:      row.Active,
+			Address:     str.NullString(row.Address),
+			Description: str.NullString(row.Description),
+			Name:        row.Name,
+			Type:        row.Type,
+		})
+	}
+
+	return
+}
+
+func (s *LocationsService) List(list List) (locations []*Location, hasMore bool, err error) {
+	if list.RequestParams.StartingAfter != nil {
+		location, err := s.Get(Get{
+			AccountId:     list.AccountId,
+			LocationId:    *list.RequestParams.StartingAfter,
+			RequestParams: RetrieveLocationParams{},
+			OmitBase:      false,
+		})
+		if err != nil {
+			return locations, hasMore, err
+		}
+		list.RequestParams.StartingAfterDate = location.CreatedAt
+	}
+
+	if list.RequestParams.EndingBefore != nil {
+		location, err := s.Get(Get{
+			AccountId:     list.AccountId,
+			LocationId:    *list.RequestParams.EndingBefore,
+			RequestParams: RetrieveLocationParams{},
+			OmitBase:      false,
+		})
+		if err != nil {
+			return locations, hasMore, err
+		}
+		list.RequestParams.EndingBeforeDate = location.CreatedAt
+	}
+
+	dbParams := MapListLocationsParams(list)
+
+	rows, err := s.Db.ListLocations(context.Background(), dbParams)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return locations, hasMore, nil
+		}
+		return
+	}
+
+	if dbParams.Limit.Valid {
+		hasMore = len(rows) > int(dbParams.Limit.Int32)
+	} else {
+		hasMore = len(rows) > 10
+	}
+
+	if hasMore {
+		if dbParams.EndingBefore.Valid {
+			rows = rows[1:]
+		} else {
+			rows = rows[:len(rows)-1]
+		}
+	}
+
+	for _, row := range rows {
+		locations = append(locations, &Location{
+			ID:          &row.ID,
+			CreatedAt:   &row.CreatedAt,
+			UpdatedAt:   &row.UpdatedAt,
+			Active:      row.Active,
+			Address:     str.NullString(row.Address),
+			Description: str.NullString(row.Description),
+			Name:        row.Name,
+			Type:        row.Type,
+		})
+	}
+
+	return locations, hasMore, err
+}
+
+func (s *LocationsService) Update(update Update) (*Location, error) {
+	_, err := s.Get(Get{
+		AccountId:     update.AccountId,
+		LocationId:    update.LocationId,
+		RequestParams: RetrieveLocationParams{},
+		OmitBase:      true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dbParams := MapUpdateLocationParams(update)
+
+	row, err := s.Db.UpdateLocation(context.Background(), dbParams)
+	if err != nil {
+		return nil, err
+	}
+
+	location := &Location{
+		ID:          &row.ID,
+		CreatedAt:   &row.CreatedAt,
+		UpdatedAt:   &row.UpdatedAt,
+		Active:      row.Active,
+		Address:     str.NullString(row.Address),
+		Description: str.NullString(row.Description),
+		Name:        row.Name,
+		Type:        row.Type,
+	}
+
+	return location, nil
+}
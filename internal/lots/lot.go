@@ -0,0 +1,19 @@
+package lots
+
+import (
+	"time"
+
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/google/uuid"
+)
+
+type Lot struct {
+	ID              *uuid.UUID     `json:"id,omitempty"`
+	CreatedAt       *time.Time     `json:"created_at,omitempty"`
+	UpdatedAt       *time.Time     `json:"updated_at,omitempty"`
+	ExpiryDate      *time.Time     `json:"expiry_date"`
+	Inventory       api.Expandable `json:"inventory"`
+	LotNumber       string         `json:"lot_number"`
+	ManufactureDate *time.Time     `json:"manufacture_date"`
+	Quantity        int32          `json:"quantity"`
+}
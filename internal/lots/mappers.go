@@ -0,0 +1,62 @@
+package lots
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+func MapCreateLotParams(create Create) database.CreateLotParams {
+	t := time.Now()
+	clp := database.CreateLotParams{
+		ID:          uuid.New(),
+		CreatedAt:   t,
+		UpdatedAt:   t,
+		AccountID:   create.AccountId,
+		InventoryID: uuid.MustParse(create.RequestParams.Inventory),
+		LotNumber:   create.RequestParams.LotNumber,
+	}
+	if create.RequestParams.ExpiryDate != nil {
+		clp.ExpiryDate = sql.NullTime{Time: *create.RequestParams.ExpiryDate, Valid: true}
+	}
+	if create.RequestParams.ManufactureDate != nil {
+		clp.ManufactureDate = sql.NullTime{Time: *create.RequestParams.ManufactureDate, Valid: true}
+	}
+	return clp
+}
+
+func MapListLotsParams(list List) database.ListLotsParams {
+	llp := database.ListLotsParams{
+		AccountID:   list.AccountId,
+		InventoryID: api.NullUUID(nil),
+		LotNumber:   api.NullString(list.RequestParams.LotNumber),
+	}
+	if list.RequestParams.Inventory != nil {
+		inventoryId := uuid.MustParse(*list.RequestParams.Inventory)
+		llp.InventoryID = api.NullUUID(&inventoryId)
+	}
+	database.MapTimeRange(list.RequestParams.CreatedAt, &llp.CreatedAtGt, &llp.CreatedAtGte, &llp.CreatedAtLt, &llp.CreatedAtLte)
+	database.MapTimeRange(list.RequestParams.ExpiryDate, &llp.ExpiryDateGt, &llp.ExpiryDateGte, &llp.ExpiryDateLt, &llp.ExpiryDateLte)
+	database.MapTimeRange(list.RequestParams.UpdatedAt, &llp.UpdatedAtGt, &llp.UpdatedAtGte, &llp.UpdatedAtLt, &llp.UpdatedAtLte)
+	database.MapPaginationParams(*list.RequestParams.PaginationParams, &llp)
+	return llp
+}
+
+func MapUpdateLotParams(update Update) database.UpdateLotParams {
+	ulp := database.UpdateLotParams{
+		UpdatedAt: time.Now(),
+		AccountID: update.AccountId,
+		ID:        update.LotId,
+		LotNumber: api.NullString(update.RequestParams.LotNumber),
+	}
+	if update.RequestParams.ExpiryDate != nil {
+		ulp.ExpiryDate = sql.NullTime{Time: *update.RequestParams.ExpiryDate, Valid: true}
+	}
+	if update.RequestParams.ManufactureDate != nil {
+		ulp.ManufactureDate = sql.NullTime{Time: *update.RequestParams.ManufactureDate, Valid: true}
+	}
+	return ulp
+}
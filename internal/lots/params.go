@@ -0,0 +1,54 @@
+package lots
+
+import (
+	"time"
+
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+type CreateLotParams struct {
+	ExpiryDate       *time.Time         `json:"expiry_date" validate:"omitnil"`
+	Inventory        string             `json:"inventory" validate:"required,uuid"`
+	LotNumber        string             `json:"lot_number" validate:"required"`
+	ManufactureDate  *time.Time         `json:"manufacture_date" validate:"omitnil"`
+	Quantity         int32              `json:"quantity" validate:"gte=0"`
+	Unit             *string            `json:"unit" validate:"omitnil,uuid"`
+	UnitCostAmount   *int32             `json:"unit_cost_amount" validate:"omitnil,gte=0"`
+	UnitCostCurrency *database.Currency `json:"unit_cost_currency" validate:"omitnil,currency,required_with=UnitCostAmount"`
+	Expand           []string           `json:"expand" validate:"omitnil,dive,oneof=inventory"`
+}
+
+type ListLotsByIdsParams struct {
+	Ids []uuid.UUID
+}
+
+type ListLotsParams struct {
+	*database.PaginationParams
+	CreatedAt  *database.TimeRange `json:"created_at" validate:"omitnil"`
+	ExpiryDate *database.TimeRange `json:"expiry_date" validate:"omitnil"`
+	Inventory  *string             `json:"inventory" validate:"omitnil,uuid"`
+	LotNumber  *string             `json:"lot_number" validate:"omitnil"`
+	UpdatedAt  *database.TimeRange `json:"updated_at" validate:"omitnil"`
+	Expand     []string            `json:"expand" validate:"omitnil,dive,oneof=inventory"`
+}
+
+type RetrieveLotParams struct {
+	Expand []string `json:"expand" validate:"omitnil,dive,oneof=inventory"`
+}
+
+type UpdateLotParams struct {
+	ExpiryDate      *time.Time `json:"expiry_date" validate:"omitnil"`
+	LotNumber       *string    `json:"lot_number" validate:"omitnil"`
+	ManufactureDate *time.Time `json:"manufacture_date" validate:"omitnil"`
+	Expand          []string   `json:"expand" validate:"omitnil,dive,oneof=inventory"`
+}
+
+func NewListLotsParams() ListLotsParams {
+	limit := int32(10)
+	return ListLotsParams{
+		PaginationParams: &database.PaginationParams{
+			Limit: &limit,
+		},
+	}
+}
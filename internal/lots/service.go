@@ -0,0 +1,331 @@
+package lots
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	stockmovements "github.com/d-darac/inventory-api/internal/stock_movements"
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+type LotsService struct {
+	Conn *sql.DB
+	Db   *database.Queries
+}
+
+type Create struct {
+	AccountId     uuid.UUID
+	RequestParams CreateLotParams
+}
+
+type Delete struct {
+	AccountId uuid.UUID
+	LotId     uuid.UUID
+}
+
+type Get struct {
+	AccountId     uuid.UUID
+	LotId         uuid.UUID
+	RequestParams RetrieveLotParams
+	OmitBase      bool
+}
+
+type ListByIds struct {
+	AccountId     uuid.UUID
+	RequestParams ListLotsByIdsParams
+}
+
+type List struct {
+	AccountId     uuid.UUID
+	RequestParams ListLotsParams
+}
+
+type Update struct {
+	AccountId     uuid.UUID
+	LotId         uuid.UUID
+	RequestParams UpdateLotParams
+}
+
+func NewLotsService(db *database.Queries, conn *sql.DB) *LotsService {
+	return &LotsService{
+		Conn: conn,
+		Db:   db,
+	}
+}
+
+// Create adds a lot to an inventory and receives its quantity into it, so
+// the inventory's in_stock stays the sum of its lots. Stock that's already
+// in an inventory without lots can't be assigned to one, the first lot has
+// to be added while the inventory is empty.
+func (s *LotsService) Create(create Create) (*Lot, error) {
+	tx, err := s.Conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.Db.WithTx(tx)
+
+	dbParams := MapCreateLotParams(create)
+
+	inventory, err := qtx.GetInventoryForUpdate(context.Background(), database.GetInventoryForUpdateParams{
+		ID:        dbParams.InventoryID,
+		AccountID: create.AccountId,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, api.NotFoundMessage(dbParams.InventoryID, "inventory")
+		}
+		return nil, err
+	}
+
+	count, err := qtx.CountInventoryLots(context.Background(), database.CountInventoryLotsParams{
+		AccountID:   create.AccountId,
+		InventoryID: inventory.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 && inventory.InStock != 0 {
+		return nil, &api.AppError{
+			Message: fmt.Sprintf("Inventory '%v' has stock that isn't in a lot, lots can only be added to it once it's empty.", inventory.ID),
+			Status:  http.StatusBadRequest,
+			Type:    api.InvalidRequestError,
+		}
+	}
+
+	row, err := qtx.CreateLot(context.Background(), dbParams)
+	if err != nil {
+		return nil, err
+	}
+
+	if create.RequestParams.Quantity > 0 {
+		lotId := row.ID.String()
+		stockMovement, err := stockmovements.Post(qtx, stockmovements.Create{
+			AccountId: create.AccountId,
+			RequestParams: stockmovements.CreateStockMovementParams{
+				Inventory:        inventory.ID.String(),
+				Lot:              &lotId,
+				Quantity:         create.RequestParams.Quantity,
+				Reference:        &lotId,
+				Type:             database.StockMovementTypeReceipt,
+				Unit:             create.RequestParams.Unit,
+				UnitCostAmount:   create.RequestParams.UnitCostAmount,
+				UnitCostCurrency: create.RequestParams.UnitCostCurrency,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		row.Quantity = stockMovement.Quantity
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	lot := &Lot{
+		ID:              &row.ID,
+		CreatedAt:       &row.CreatedAt,
+		UpdatedAt:       &row.UpdatedAt,
+		ExpiryDate:      nullTime(row.ExpiryDate),
+		Inventory:       api.Expandable{ID: row.Inventory},
+		LotNumber:       row.LotNumber,
+		ManufactureDate: nullTime(row.ManufactureDate),
+		Quantity:        row.Quantity,
+	}
+
+	return lot, nil
+}
+
+func (s *LotsService) Delete(delete Delete) error {
+	lot, err := s.Get(Get{
+		AccountId:     delete.AccountId,
+		LotId:         delete.LotId,
+		RequestParams: RetrieveLotParams{},
+		OmitBase:      true,
+	})
+	if err != nil {
+		return err
+	}
+	if lot.Quantity != 0 {
+		return &api.AppError{
+			Message: fmt.Sprintf("Lot '%v' still holds stock and can't be deleted.", delete.LotId),
+			Status:  http.StatusBadRequest,
+			Type:    api.InvalidRequestError,
+		}
+	}
+	return s.Db.DeleteLot(context.Background(), database.DeleteLotParams{
+		ID:        delete.LotId,
+		AccountID: delete.AccountId,
+	})
+}
+
+func (s *LotsService) Get(get Get) (*Lot, error) {
+	row, err := s.Db.GetLot(context.Background(), database.GetLotParams{
+		ID:        get.LotId,
+		AccountID: get.AccountId,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, api.NotFoundMessage(get.LotId, "lot")
+		}
+		return nil, err
+	}
+
+	lot := &Lot{
+		ExpiryDate:      nullTime(row.ExpiryDate),
+		Inventory:       api.Expandable{ID: row.Inventory},
+		LotNumber:       row.LotNumber,
+		ManufactureDate: nullTime(row.ManufactureDate),
+		Quantity:        row.Quantity,
+	}
+
+	if !get.OmitBase {
+		lot.ID = &row.ID
+		lot.CreatedAt = &row.CreatedAt
+		lot.UpdatedAt = &row.UpdatedAt
+	}
+
+	return lot, nil
+}
+
+func (s *LotsService) ListByIds(list ListByIds) (lots []*Lot, err error) {
+	params := database.ListLotsByIdsParams{
+		AccountID: list.AccountId,
+		Ids:       list.RequestParams.Ids,
+	}
+
+	rows, err := s.Db.ListLotsByIds(context.Background(), params)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return lots, nil
+		}
+		return
+	}
+
+	for _, row := range rows {
+		lots = append(lots, &Lot{
+			ID:              &row.ID,
+			CreatedAt:       &row.CreatedAt,
+			UpdatedAt:       &row.UpdatedAt,
+			ExpiryDate:      nullTime(row.ExpiryDate),
+			Inventory:       api.Expandable{ID: row.Inventory},
+			LotNumber:       row.LotNumber,
+			ManufactureDate: nullTime(row.ManufactureDate),
+			Quantity:        row.Quantity,
+		})
+	}
+
+	return
+}
+
+func (s *LotsService) List(list List) (lots []*Lot, hasMore bool, err error) {
+	if list.RequestParams.StartingAfter != nil {
+		lot, err := s.Get(Get{
+			AccountId:     list.AccountId,
+			LotId:         *list.RequestParams.StartingAfter,
+			RequestParams: RetrieveLotParams{},
+			OmitBase:      false,
+		})
+		if err != nil {
+			return lots, hasMore, err
+		}
+		list.RequestParams.StartingAfterDate = lot.CreatedAt
+	}
+
+	if list.RequestParams.EndingBefore != nil {
+		lot, err := s.Get(Get{
+			AccountId:     list.AccountId,
+			LotId:         *list.RequestParams.EndingBefore,
+			RequestParams: RetrieveLotParams{},
+			OmitBase:      false,
+		})
+		if err != nil {
+			return lots, hasMore, err
+		}
+		list.RequestParams.EndingBeforeDate = lot.CreatedAt
+	}
+
+	dbParams := MapListLotsParams(list)
+
+	rows, err := s.Db.ListLots(context.Background(), dbParams)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return lots, hasMore, nil
+		}
+		return
+	}
+
+	if dbParams.Limit.Valid {
+		hasMore = len(rows) > int(dbParams.Limit.Int32)
+	} else {
+		hasMore = len(rows) > 10
+	}
+
+	if hasMore {
+		if dbParams.EndingBefore.Valid {
+			rows = rows[1:]
+		} else {
+			rows = rows[:len(rows)-1]
+		}
+	}
+
+	for _, row := range rows {
+		lots = append(lots, &Lot{
+			ID:              &row.ID,
+			CreatedAt:       &row.CreatedAt,
+			UpdatedAt:       &row.UpdatedAt,
+			ExpiryDate:      nullTime(row.ExpiryDate),
+			Inventory:       api.Expandable{ID: row.Inventory},
+			LotNumber:       row.LotNumber,
+			ManufactureDate: nullTime(row.ManufactureDate),
+			Quantity:        row.Quantity,
+		})
+	}
+
+	return lots, hasMore, err
+}
+
+func (s *LotsService) Update(update Update) (*Lot, error) {
+	_, err := s.Get(Get{
+		AccountId:     update.AccountId,
+		LotId:         update.LotId,
+		RequestParams: RetrieveLotParams{},
+		OmitBase:      true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dbParams := MapUpdateLotParams(update)
+
+	row, err := s.Db.UpdateLot(context.Background(), dbParams)
+	if err != nil {
+		return nil, err
+	}
+
+	lot := &Lot{
+		ID:              &row.ID,
+		CreatedAt:       &row.CreatedAt,
+		UpdatedAt:       &row.UpdatedAt,
+		ExpiryDate:      nullTime(row.ExpiryDate),
+		Inventory:       api.Expandable{ID: row.Inventory},
+		LotNumber:       row.LotNumber,
+		ManufactureDate: nullTime(row.ManufactureDate),
+		Quantity:        row.Quantity,
+	}
+
+	return lot, nil
+}
+
+func nullTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
@@ -0,0 +1,60 @@
+package metadata
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+
+	"github.com/d-darac/inventory-assets/api"
+)
+
+// MaxKeys is how many keys a resource's metadata can hold. The length of
+// keys and values is limited by the validate tags of the params.
+const MaxKeys = 50
+
+// Decode reads metadata as stored in a jsonb column, always returning a map
+// so that resources without any metadata still show it as an empty object.
+func Decode(raw []byte) map[string]string {
+	metadata := make(map[string]string)
+	if len(raw) != 0 {
+		json.Unmarshal(raw, &metadata)
+	}
+	return metadata
+}
+
+// Encode prepares metadata for a jsonb column or filter. Nil stays nil, so
+// the column or filter is left alone.
+func Encode(metadata map[string]string) []byte {
+	if metadata == nil {
+		return nil
+	}
+	raw, _ := json.Marshal(metadata)
+	return raw
+}
+
+// Merge applies an update to the current metadata. Keys in the update
+// overwrite the current ones, and a key set to an empty string is removed.
+func Merge(current, update map[string]string) (map[string]string, error) {
+	merged := maps.Clone(current)
+	if merged == nil {
+		merged = make(map[string]string, len(update))
+	}
+	for key, value := range update {
+		if value == "" {
+			delete(merged, key)
+			continue
+		}
+		merged[key] = value
+	}
+
+	if len(merged) > MaxKeys {
+		return nil, &api.AppError{
+			Message: fmt.Sprintf("Metadata can hold at most %d keys.", MaxKeys),
+			Status:  http.StatusBadRequest,
+			Type:    api.InvalidRequestError,
+		}
+	}
+
+	return merged, nil
+}
@@ -0,0 +1,37 @@
+package metadata
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestUnitMerge(t *testing.T) {
+	current := map[string]string{"erp_id": "123", "flag": "on"}
+
+	merged, err := Merge(current, map[string]string{"erp_id": "456", "flag": "", "region": "eu"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{"erp_id": "456", "region": "eu"}
+	if !maps.Equal(merged, want) {
+		t.Fatalf("expected %v, got %v", want, merged)
+	}
+	if current["erp_id"] != "123" || current["flag"] != "on" {
+		t.Fatalf("expected the current metadata to be left alone, got %v", current)
+	}
+}
+
+func TestUnitMergeTooManyKeys(t *testing.T) {
+	current := make(map[string]string, MaxKeys)
+	for i := range MaxKeys {
+		current[string(rune('a'+i%26))+string(rune('a'+i/26))] = "x"
+	}
+
+	if _, err := Merge(current, map[string]string{"erp_id": "123"}); err == nil {
+		t.Fatal("expected an error for one key too many")
+	}
+	if _, err := Merge(current, map[string]string{"aa": ""}); err != nil {
+		t.Fatalf("expected removing a key to be allowed, got %v", err)
+	}
+}
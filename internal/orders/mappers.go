@@ -0,0 +1,47 @@
+package orders
+
+import (
+	"time"
+
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+func MapCreateOrderParams(create Create) database.CreateOrderParams {
+	t := time.Now()
+	return database.CreateOrderParams{
+		ID:        uuid.New(),
+		CreatedAt: t,
+		UpdatedAt: t,
+		AccountID: create.AccountId,
+		Reference: api.NullString(create.RequestParams.Reference),
+		Status:    database.OrderStatusDraft,
+	}
+}
+
+func MapListOrdersParams(list List) database.ListOrdersParams {
+	lop := database.ListOrdersParams{
+		AccountID: list.AccountId,
+		Reference: api.NullString(list.RequestParams.Reference),
+	}
+	if list.RequestParams.Status != nil {
+		lop.Status = database.NullOrderStatus{
+			OrderStatus: *list.RequestParams.Status,
+			Valid:       true,
+		}
+	}
+	database.MapTimeRange(list.RequestParams.CreatedAt, &lop.CreatedAtGt, &lop.CreatedAtGte, &lop.CreatedAtLt, &lop.CreatedAtLte)
+	database.MapTimeRange(list.RequestParams.UpdatedAt, &lop.UpdatedAtGt, &lop.UpdatedAtGte, &lop.UpdatedAtLt, &lop.UpdatedAtLte)
+	database.MapPaginationParams(*list.RequestParams.PaginationParams, &lop)
+	return lop
+}
+
+func MapUpdateOrderParams(update Update) database.UpdateOrderParams {
+	return database.UpdateOrderParams{
+		UpdatedAt: time.Now(),
+		AccountID: update.AccountId,
+		ID:        update.OrderId,
+		Reference: api.NullString(update.RequestParams.Reference),
+	}
+}
@@ -0,0 +1,35 @@
+package orders
+
+import (
+	"time"
+
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/d-darac/inventory-assets/str"
+	"github.com/google/uuid"
+)
+
+// Order is a sale of one or more items. Confirming it reserves the stock of
+// its lines, fulfilling it sells the reserved stock and canceling it gives
+// the stock back.
+type Order struct {
+	ID          *uuid.UUID           `json:"id,omitempty"`
+	CreatedAt   *time.Time           `json:"created_at,omitempty"`
+	UpdatedAt   *time.Time           `json:"updated_at,omitempty"`
+	ConfirmedAt *time.Time           `json:"confirmed_at"`
+	FulfilledAt *time.Time           `json:"fulfilled_at"`
+	Lines       []*OrderLine         `json:"lines,omitempty"`
+	Reference   str.NullString       `json:"reference"`
+	Status      database.OrderStatus `json:"status"`
+}
+
+// OrderLine is a quantity of an item sold from one of its inventories.
+// Quantities are in the item's stocking unit. Reservation is the hold placed
+// on the inventory when the order was confirmed.
+type OrderLine struct {
+	ID          uuid.UUID      `json:"id"`
+	Inventory   api.Expandable `json:"inventory"`
+	Item        api.Expandable `json:"item"`
+	Quantity    int32          `json:"quantity"`
+	Reservation api.Expandable `json:"reservation"`
+}
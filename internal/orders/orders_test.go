@@ -0,0 +1,194 @@
+package orders
+
+import (
+	"testing"
+
+	"github.com/d-darac/inventory-api/internal/reservations"
+	"github.com/d-darac/inventory-api/internal/testutil"
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+func TestUnitMapCreateOrderParams(t *testing.T) {
+	acc := uuid.New()
+	reference := "SO-1"
+
+	dbp := MapCreateOrderParams(Create{AccountId: acc, RequestParams: CreateOrderParams{Reference: &reference}})
+	if dbp.AccountID != acc {
+		t.Fatalf("expected account id %v, got %v", acc, dbp.AccountID)
+	}
+	if dbp.Status != database.OrderStatusDraft {
+		t.Fatalf("expected status %v, got %v", database.OrderStatusDraft, dbp.Status)
+	}
+	if (!dbp.Reference.Valid) || dbp.Reference.String != reference {
+		t.Fatalf("expected reference %s, got %s", reference, dbp.Reference.String)
+	}
+}
+
+func TestUnitMapListOrdersParams(t *testing.T) {
+	acc := uuid.New()
+	lp := NewListOrdersParams()
+
+	dbp := MapListOrdersParams(List{AccountId: acc, RequestParams: lp})
+	if dbp.Status.Valid {
+		t.Fatalf("expected no status filter, got %v", dbp.Status.OrderStatus)
+	}
+
+	status := database.OrderStatusConfirmed
+	lp.Status = &status
+	dbp = MapListOrdersParams(List{AccountId: acc, RequestParams: lp})
+	if (!dbp.Status.Valid) || dbp.Status.OrderStatus != status {
+		t.Fatalf("expected status %v, got %v", status, dbp.Status)
+	}
+}
+
+func TestIntegrationConfirmFulfill(t *testing.T) {
+	db, q, accountId := testutil.OpenAccount(t)
+	defer db.Close()
+	defer db.Exec("DELETE FROM accounts WHERE id = $1;", accountId)
+
+	s := NewOrdersService(q, db)
+	first := testutil.CreateInventory(t, q, accountId, testutil.CreateItem(t, q, accountId, database.ItemTypePRODUCT), nil, 10)
+	second := testutil.CreateInventory(t, q, accountId, testutil.CreateItem(t, q, accountId, database.ItemTypePRODUCT), nil, 3)
+
+	order, err := s.Create(Create{AccountId: accountId, RequestParams: CreateOrderParams{Lines: []OrderLineParams{
+		{Inventory: first.String(), Quantity: 4},
+		{Inventory: second.String(), Quantity: 2},
+	}}})
+	if err != nil {
+		t.Fatalf("couldn't create order: %v", err)
+	}
+	testutil.AssertStock(t, q, accountId, first, 10, 0)
+
+	order, err = s.Confirm(Confirm{AccountId: accountId, OrderId: *order.ID})
+	if err != nil {
+		t.Fatalf("couldn't confirm order: %v", err)
+	}
+	if order.Status != database.OrderStatusConfirmed {
+		t.Fatalf("expected status %v, got %v", database.OrderStatusConfirmed, order.Status)
+	}
+	testutil.AssertStock(t, q, accountId, first, 10, 4)
+	testutil.AssertStock(t, q, accountId, second, 3, 2)
+
+	if _, err := s.Confirm(Confirm{AccountId: accountId, OrderId: *order.ID}); err == nil {
+		t.Fatal("expected confirming a confirmed order to fail")
+	}
+
+	order, err = s.Fulfill(Fulfill{AccountId: accountId, OrderId: *order.ID})
+	if err != nil {
+		t.Fatalf("couldn't fulfill order: %v", err)
+	}
+	if order.Status != database.OrderStatusFulfilled {
+		t.Fatalf("expected status %v, got %v", database.OrderStatusFulfilled, order.Status)
+	}
+	testutil.AssertStock(t, q, accountId, first, 6, 0)
+	testutil.AssertStock(t, q, accountId, second, 1, 0)
+
+	if _, err := s.Cancel(Cancel{AccountId: accountId, OrderId: *order.ID}); err == nil {
+		t.Fatal("expected canceling a fulfilled order to fail")
+	}
+}
+
+func TestIntegrationConfirmCancel(t *testing.T) {
+	db, q, accountId := testutil.OpenAccount(t)
+	defer db.Close()
+	defer db.Exec("DELETE FROM accounts WHERE id = $1;", accountId)
+
+	s := NewOrdersService(q, db)
+	first := testutil.CreateInventory(t, q, accountId, testutil.CreateItem(t, q, accountId, database.ItemTypePRODUCT), nil, 10)
+	second := testutil.CreateInventory(t, q, accountId, testutil.CreateItem(t, q, accountId, database.ItemTypePRODUCT), nil, 3)
+
+	order, err := s.Create(Create{AccountId: accountId, RequestParams: CreateOrderParams{Lines: []OrderLineParams{
+		{Inventory: first.String(), Quantity: 4},
+		{Inventory: second.String(), Quantity: 2},
+	}}})
+	if err != nil {
+		t.Fatalf("couldn't create order: %v", err)
+	}
+
+	order, err = s.Confirm(Confirm{AccountId: accountId, OrderId: *order.ID})
+	if err != nil {
+		t.Fatalf("couldn't confirm order: %v", err)
+	}
+	testutil.AssertStock(t, q, accountId, first, 10, 4)
+	testutil.AssertStock(t, q, accountId, second, 3, 2)
+
+	// The holds belong to the order, so they can't be finished on their own.
+	r := reservations.NewReservationsService(q, db)
+	held := order.Lines[0].Reservation.ID.UUID
+	if _, err := r.Release(reservations.Release{AccountId: accountId, ReservationId: held}); err == nil {
+		t.Fatal("expected releasing an order's hold directly to fail")
+	}
+	if _, err := r.Commit(reservations.Commit{AccountId: accountId, ReservationId: held}); err == nil {
+		t.Fatal("expected committing an order's hold directly to fail")
+	}
+	testutil.AssertStock(t, q, accountId, first, 10, 4)
+
+	order, err = s.Cancel(Cancel{AccountId: accountId, OrderId: *order.ID})
+	if err != nil {
+		t.Fatalf("couldn't cancel order: %v", err)
+	}
+	if order.Status != database.OrderStatusCanceled {
+		t.Fatalf("expected status %v, got %v", database.OrderStatusCanceled, order.Status)
+	}
+	testutil.AssertStock(t, q, accountId, first, 10, 0)
+	testutil.AssertStock(t, q, accountId, second, 3, 0)
+
+	if _, err := s.Fulfill(Fulfill{AccountId: accountId, OrderId: *order.ID}); err == nil {
+		t.Fatal("expected fulfilling a canceled order to fail")
+	}
+}
+
+func TestIntegrationCreateSerialized(t *testing.T) {
+	db, q, accountId := testutil.OpenAccount(t)
+	defer db.Close()
+	defer db.Exec("DELETE FROM accounts WHERE id = $1;", accountId)
+
+	s := NewOrdersService(q, db)
+	inventory := testutil.CreateInventory(t, q, accountId, testutil.CreateSerializedItem(t, q, accountId), nil, 0)
+
+	_, err := s.Create(Create{AccountId: accountId, RequestParams: CreateOrderParams{Lines: []OrderLineParams{
+		{Inventory: inventory.String(), Quantity: 1},
+	}}})
+	if _, ok := err.(*api.AppError); !ok {
+		t.Fatalf("expected an invalid request error for a serialized item, got %v", err)
+	}
+}
+
+func TestIntegrationConfirmRollback(t *testing.T) {
+	db, q, accountId := testutil.OpenAccount(t)
+	defer db.Close()
+	defer db.Exec("DELETE FROM accounts WHERE id = $1;", accountId)
+
+	s := NewOrdersService(q, db)
+	first := testutil.CreateInventory(t, q, accountId, testutil.CreateItem(t, q, accountId, database.ItemTypePRODUCT), nil, 10)
+	second := testutil.CreateInventory(t, q, accountId, testutil.CreateItem(t, q, accountId, database.ItemTypePRODUCT), nil, 3)
+
+	order, err := s.Create(Create{AccountId: accountId, RequestParams: CreateOrderParams{Lines: []OrderLineParams{
+		{Inventory: first.String(), Quantity: 4},
+		{Inventory: second.String(), Quantity: 5},
+	}}})
+	if err != nil {
+		t.Fatalf("couldn't create order: %v", err)
+	}
+
+	if _, err := s.Confirm(Confirm{AccountId: accountId, OrderId: *order.ID}); err == nil {
+		t.Fatal("expected confirming more than the available stock to fail")
+	}
+	testutil.AssertStock(t, q, accountId, first, 10, 0)
+	testutil.AssertStock(t, q, accountId, second, 3, 0)
+
+	order, err = s.Get(Get{AccountId: accountId, OrderId: *order.ID})
+	if err != nil {
+		t.Fatalf("error retrieving order: %v", err)
+	}
+	if order.Status != database.OrderStatusDraft {
+		t.Fatalf("expected status %v, got %v", database.OrderStatusDraft, order.Status)
+	}
+	for _, line := range order.Lines {
+		if line.Reservation.ID.Valid {
+			t.Fatalf("expected line '%v' to have no reservation, got '%v'", line.ID, line.Reservation.ID.UUID)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package orders
+
+import (
+	"github.com/d-darac/inventory-assets/database"
+)
+
+type CancelOrderParams struct {
+	Expand []string `json:"expand" validate:"omitnil,dive,oneof=lines.inventory lines.item"`
+}
+
+type ConfirmOrderParams struct {
+	Expand []string `json:"expand" validate:"omitnil,dive,oneof=lines.inventory lines.item"`
+}
+
+type CreateOrderParams struct {
+	Lines     []OrderLineParams `json:"lines" validate:"required,min=1,max=100,dive"`
+	Reference *string           `json:"reference" validate:"omitnil"`
+	Expand    []string          `json:"expand" validate:"omitnil,dive,oneof=lines.inventory lines.item"`
+}
+
+type FulfillOrderParams struct {
+	Expand []string `json:"expand" validate:"omitnil,dive,oneof=lines.inventory lines.item"`
+}
+
+type ListOrdersParams struct {
+	*database.PaginationParams
+	CreatedAt *database.TimeRange   `json:"created_at" validate:"omitnil"`
+	Reference *string               `json:"reference" validate:"omitnil"`
+	Status    *database.OrderStatus `json:"status" validate:"omitnil,oneof=draft confirmed fulfilled canceled"`
+	UpdatedAt *database.TimeRange   `json:"updated_at" validate:"omitnil"`
+}
+
+// OrderLineParams takes quantity in unit when one is given, see
+// units.ToStockingUnit.
+type OrderLineParams struct {
+	Inventory string  `json:"inventory" validate:"required,uuid"`
+	Quantity  int32   `json:"quantity" validate:"required,gt=0"`
+	Unit      *string `json:"unit" validate:"omitnil,uuid"`
+}
+
+type RetrieveOrderParams struct {
+	Expand []string `json:"expand" validate:"omitnil,dive,oneof=lines.inventory lines.item"`
+}
+
+// UpdateOrderParams changes a draft order. Lines, when given, replace all of
+// the order's lines.
+type UpdateOrderParams struct {
+	Lines     []OrderLineParams `json:"lines" validate:"omitnil,min=1,max=100,dive"`
+	Reference *string           `json:"reference" validate:"omitnil"`
+	Expand    []string          `json:"expand" validate:"omitnil,dive,oneof=lines.inventory lines.item"`
+}
+
+func NewListOrdersParams() ListOrdersParams {
+	limit := int32(10)
+	return ListOrdersParams{
+		PaginationParams: &database.PaginationParams{
+			Limit: &limit,
+		},
+	}
+}
@@ -0,0 +1,597 @@
+package orders
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/d-darac/inventory-api/internal/reservations"
+	"github.com/d-darac/inventory-api/internal/units"
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/d-darac/inventory-assets/str"
+	"github.com/google/uuid"
+)
+
+type OrdersService struct {
+	Conn *sql.DB
+	Db   *database.Queries
+}
+
+type Cancel struct {
+	AccountId     uuid.UUID
+	OrderId       uuid.UUID
+	RequestParams CancelOrderParams
+}
+
+type Confirm struct {
+	AccountId     uuid.UUID
+	OrderId       uuid.UUID
+	RequestParams ConfirmOrderParams
+}
+
+type Create struct {
+	AccountId     uuid.UUID
+	RequestParams CreateOrderParams
+}
+
+type Fulfill struct {
+	AccountId     uuid.UUID
+	OrderId       uuid.UUID
+	RequestParams FulfillOrderParams
+}
+
+type Get struct {
+	AccountId     uuid.UUID
+	OrderId       uuid.UUID
+	RequestParams RetrieveOrderParams
+	OmitBase      bool
+}
+
+type List struct {
+	AccountId     uuid.UUID
+	RequestParams ListOrdersParams
+}
+
+type Update struct {
+	AccountId     uuid.UUID
+	OrderId       uuid.UUID
+	RequestParams UpdateOrderParams
+}
+
+func NewOrdersService(db *database.Queries, conn *sql.DB) *OrdersService {
+	return &OrdersService{
+		Conn: conn,
+		Db:   db,
+	}
+}
+
+// Cancel closes an order that hasn't been fulfilled. The stock reserved for
+// a confirmed order is released.
+func (s *OrdersService) Cancel(cancel Cancel) (*Order, error) {
+	tx, err := s.Conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.Db.WithTx(tx)
+
+	current, err := getForUpdate(qtx, cancel.AccountId, cancel.OrderId, database.OrderStatusDraft, database.OrderStatusConfirmed)
+	if err != nil {
+		return nil, err
+	}
+
+	lines, err := listLines(qtx, cancel.AccountId, current.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, line := range lines {
+		if !line.Reservation.ID.Valid {
+			continue
+		}
+		_, err := reservations.ReleaseHold(qtx, reservations.Release{
+			AccountId:     cancel.AccountId,
+			ReservationId: line.Reservation.ID.UUID,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	row, err := qtx.UpdateOrderStatus(context.Background(), database.UpdateOrderStatusParams{
+		UpdatedAt:   time.Now(),
+		AccountID:   cancel.AccountId,
+		ID:          cancel.OrderId,
+		ConfirmedAt: current.ConfirmedAt,
+		FulfilledAt: sql.NullTime{},
+		Status:      database.OrderStatusCanceled,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	order := &Order{
+		ID:          &row.ID,
+		CreatedAt:   &row.CreatedAt,
+		UpdatedAt:   &row.UpdatedAt,
+		ConfirmedAt: nullTime(row.ConfirmedAt),
+		FulfilledAt: nullTime(row.FulfilledAt),
+		Lines:       lines,
+		Reference:   str.NullString(row.Reference),
+		Status:      row.Status,
+	}
+
+	return order, nil
+}
+
+// Confirm reserves the stock of every line of a draft order. Either all of
+// the lines can be reserved or the order stays a draft.
+func (s *OrdersService) Confirm(confirm Confirm) (*Order, error) {
+	tx, err := s.Conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.Db.WithTx(tx)
+
+	current, err := getForUpdate(qtx, confirm.AccountId, confirm.OrderId, database.OrderStatusDraft)
+	if err != nil {
+		return nil, err
+	}
+
+	lines, err := listLines(qtx, confirm.AccountId, current.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	reference := current.ID.String()
+	for _, line := range lines {
+		if !line.Inventory.ID.Valid {
+			return nil, &api.AppError{
+				Message: fmt.Sprintf("The inventory of line '%v' no longer exists.", line.ID),
+				Status:  http.StatusBadRequest,
+				Type:    api.InvalidRequestError,
+			}
+		}
+
+		reservation, err := reservations.Reserve(qtx, reservations.Create{
+			AccountId: confirm.AccountId,
+			RequestParams: reservations.CreateReservationParams{
+				Inventory: line.Inventory.ID.UUID.String(),
+				Quantity:  line.Quantity,
+				Reference: &reference,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		err = qtx.SetOrderLineReservation(context.Background(), database.SetOrderLineReservationParams{
+			ID:            line.ID,
+			ReservationID: api.NullUUID(reservation.ID),
+		})
+		if err != nil {
+			return nil, err
+		}
+		line.Reservation = api.Expandable{ID: api.NullUUID(reservation.ID)}
+	}
+
+	t := time.Now()
+	row, err := qtx.UpdateOrderStatus(context.Background(), database.UpdateOrderStatusParams{
+		UpdatedAt:   t,
+		AccountID:   confirm.AccountId,
+		ID:          confirm.OrderId,
+		ConfirmedAt: sql.NullTime{Time: t, Valid: true},
+		FulfilledAt: sql.NullTime{},
+		Status:      database.OrderStatusConfirmed,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	order := &Order{
+		ID:          &row.ID,
+		CreatedAt:   &row.CreatedAt,
+		UpdatedAt:   &row.UpdatedAt,
+		ConfirmedAt: nullTime(row.ConfirmedAt),
+		FulfilledAt: nullTime(row.FulfilledAt),
+		Lines:       lines,
+		Reference:   str.NullString(row.Reference),
+		Status:      row.Status,
+	}
+
+	return order, nil
+}
+
+func (s *OrdersService) Create(create Create) (*Order, error) {
+	tx, err := s.Conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.Db.WithTx(tx)
+
+	dbParams := MapCreateOrderParams(create)
+
+	row, err := qtx.CreateOrder(context.Background(), dbParams)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := createLines(qtx, create.AccountId, row.ID, create.RequestParams.Lines); err != nil {
+		return nil, err
+	}
+
+	lines, err := listLines(qtx, create.AccountId, row.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	order := &Order{
+		ID:          &row.ID,
+		CreatedAt:   &row.CreatedAt,
+		UpdatedAt:   &row.UpdatedAt,
+		ConfirmedAt: nullTime(row.ConfirmedAt),
+		FulfilledAt: nullTime(row.FulfilledAt),
+		Lines:       lines,
+		Reference:   str.NullString(row.Reference),
+		Status:      row.Status,
+	}
+
+	return order, nil
+}
+
+// Fulfill sells the stock reserved for a confirmed order, taking it out of
+// the lines' inventories.
+func (s *OrdersService) Fulfill(fulfill Fulfill) (*Order, error) {
+	tx, err := s.Conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.Db.WithTx(tx)
+
+	current, err := getForUpdate(qtx, fulfill.AccountId, fulfill.OrderId, database.OrderStatusConfirmed)
+	if err != nil {
+		return nil, err
+	}
+
+	lines, err := listLines(qtx, fulfill.AccountId, current.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, line := range lines {
+		_, err := reservations.CommitHold(qtx, reservations.Commit{
+			AccountId:     fulfill.AccountId,
+			ReservationId: line.Reservation.ID.UUID,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	t := time.Now()
+	row, err := qtx.UpdateOrderStatus(context.Background(), database.UpdateOrderStatusParams{
+		UpdatedAt:   t,
+		AccountID:   fulfill.AccountId,
+		ID:          fulfill.OrderId,
+		ConfirmedAt: current.ConfirmedAt,
+		FulfilledAt: sql.NullTime{Time: t, Valid: true},
+		Status:      database.OrderStatusFulfilled,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	order := &Order{
+		ID:          &row.ID,
+		CreatedAt:   &row.CreatedAt,
+		UpdatedAt:   &row.UpdatedAt,
+		ConfirmedAt: nullTime(row.ConfirmedAt),
+		FulfilledAt: nullTime(row.FulfilledAt),
+		Lines:       lines,
+		Reference:   str.NullString(row.Reference),
+		Status:      row.Status,
+	}
+
+	return order, nil
+}
+
+func (s *OrdersService) Get(get Get) (*Order, error) {
+	row, err := s.Db.GetOrder(context.Background(), database.GetOrderParams{
+		ID:        get.OrderId,
+		AccountID: get.AccountId,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, api.NotFoundMessage(get.OrderId, "order")
+		}
+		return nil, err
+	}
+
+	lines, err := listLines(s.Db, get.AccountId, row.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	order := &Order{
+		ConfirmedAt: nullTime(row.ConfirmedAt),
+		FulfilledAt: nullTime(row.FulfilledAt),
+		Lines:       lines,
+		Reference:   str.NullString(row.Reference),
+		Status:      row.Status,
+	}
+
+	if !get.OmitBase {
+		order.ID = &row.ID
+		order.CreatedAt = &row.CreatedAt
+		order.UpdatedAt = &row.UpdatedAt
+	}
+
+	return order, nil
+}
+
+func (s *OrdersService) List(list List) (orders []*Order, hasMore bool, err error) {
+	if list.RequestParams.StartingAfter != nil {
+		order, err := s.Get(Get{
+			AccountId:     list.AccountId,
+			OrderId:       *list.RequestParams.StartingAfter,
+			RequestParams: RetrieveOrderParams{},
+			OmitBase:      false,
+		})
+		if err != nil {
+			return orders, hasMore, err
+		}
+		list.RequestParams.StartingAfterDate = order.CreatedAt
+	}
+
+	if list.RequestParams.EndingBefore != nil {
+		order, err := s.Get(Get{
+			AccountId:     list.AccountId,
+			OrderId:       *list.RequestParams.EndingBefore,
+			RequestParams: RetrieveOrderParams{},
+			OmitBase:      false,
+		})
+		if err != nil {
+			return orders, hasMore, err
+		}
+		list.RequestParams.EndingBeforeDate = order.CreatedAt
+	}
+
+	dbParams := MapListOrdersParams(list)
+
+	rows, err := s.Db.ListOrders(context.Background(), dbParams)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return orders, hasMore, nil
+		}
+		return
+	}
+
+	if dbParams.Limit.Valid {
+		hasMore = len(rows) > int(dbParams.Limit.Int32)
+	} else {
+		hasMore = len(rows) > 10
+	}
+
+	if hasMore {
+		if dbParams.EndingBefore.Valid {
+			rows = rows[1:]
+		} else {
+			rows = rows[:len(rows)-1]
+		}
+	}
+
+	for _, row := range rows {
+		orders = append(orders, &Order{
+			ID:          &row.ID,
+			CreatedAt:   &row.CreatedAt,
+			UpdatedAt:   &row.UpdatedAt,
+			ConfirmedAt: nullTime(row.ConfirmedAt),
+			FulfilledAt: nullTime(row.FulfilledAt),
+			Reference:   str.NullString(row.Reference),
+			Status:      row.Status,
+		})
+	}
+
+	return orders, hasMore, err
+}
+
+func (s *OrdersService) Update(update Update) (*Order, error) {
+	tx, err := s.Conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.Db.WithTx(tx)
+
+	current, err := getForUpdate(qtx, update.AccountId, update.OrderId, database.OrderStatusDraft)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.RequestParams.Lines != nil {
+		err := qtx.DeleteOrderLines(context.Background(), database.DeleteOrderLinesParams{
+			AccountID: update.AccountId,
+			OrderID:   current.ID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := createLines(qtx, update.AccountId, current.ID, update.RequestParams.Lines); err != nil {
+			return nil, err
+		}
+	}
+
+	dbParams := MapUpdateOrderParams(update)
+
+	row, err := qtx.UpdateOrder(context.Background(), dbParams)
+	if err != nil {
+		return nil, err
+	}
+
+	lines, err := listLines(qtx, update.AccountId, row.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	order := &Order{
+		ID:          &row.ID,
+		CreatedAt:   &row.CreatedAt,
+		UpdatedAt:   &row.UpdatedAt,
+		ConfirmedAt: nullTime(row.ConfirmedAt),
+		FulfilledAt: nullTime(row.FulfilledAt),
+		Lines:       lines,
+		Reference:   str.NullString(row.Reference),
+		Status:      row.Status,
+	}
+
+	return order, nil
+}
+
+// createLines adds lines to an order, each for the item of its inventory.
+// Quantities given in one of the item's units are stored in its stocking
+// unit. Serialized items are left out, since fulfilling a line posts a plain
+// sale and their stock only goes out through serial numbers.
+func createLines(q *database.Queries, accountId, orderId uuid.UUID, lines []OrderLineParams) error {
+	for _, line := range lines {
+		inventoryId := uuid.MustParse(line.Inventory)
+		inventory, err := q.GetInventory(context.Background(), database.GetInventoryParams{
+			ID:        inventoryId,
+			AccountID: accountId,
+		})
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return api.NotFoundMessage(inventoryId, "inventory")
+			}
+			return err
+		}
+		if !inventory.Item.Valid {
+			return &api.AppError{
+				Message: fmt.Sprintf("Inventory '%v' doesn't belong to an item.", inventoryId),
+				Status:  http.StatusBadRequest,
+				Type:    api.InvalidRequestError,
+			}
+		}
+
+		serialized, err := q.IsInventorySerialized(context.Background(), database.IsInventorySerializedParams{
+			ID:        inventoryId,
+			AccountID: accountId,
+		})
+		if err != nil {
+			return err
+		}
+		if serialized {
+			return &api.AppError{
+				Message: fmt.Sprintf("Inventory '%v' is of a serialized item, which is sold through its serial numbers rather than an order.", inventoryId),
+				Status:  http.StatusBadRequest,
+				Type:    api.InvalidRequestError,
+			}
+		}
+
+		quantity := line.Quantity
+		if line.Unit != nil {
+			quantity, err = units.ToStockingUnit(q, accountId, inventory.Item, uuid.MustParse(*line.Unit), line.Quantity)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = q.CreateOrderLine(context.Background(), database.CreateOrderLineParams{
+			ID:          uuid.New(),
+			AccountID:   accountId,
+			OrderID:     orderId,
+			InventoryID: inventoryId,
+			ItemID:      inventory.Item.UUID,
+			Quantity:    quantity,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getForUpdate locks the order and makes sure it's in one of the statuses
+// the next step of its lifecycle starts from.
+func getForUpdate(q *database.Queries, accountId, orderId uuid.UUID, statuses ...database.OrderStatus) (*database.GetOrderForUpdateRow, error) {
+	row, err := q.GetOrderForUpdate(context.Background(), database.GetOrderForUpdateParams{
+		ID:        orderId,
+		AccountID: accountId,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, api.NotFoundMessage(orderId, "order")
+		}
+		return nil, err
+	}
+
+	expected := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		if row.Status == status {
+			return &row, nil
+		}
+		expected = append(expected, string(status))
+	}
+
+	return nil, &api.AppError{
+		Message: fmt.Sprintf("Order '%v' is %s, expected it to be %s.", orderId, row.Status, strings.Join(expected, " or ")),
+		Status:  http.StatusBadRequest,
+		Type:    api.InvalidRequestError,
+	}
+}
+
+func listLines(q *database.Queries, accountId, orderId uuid.UUID) ([]*OrderLine, error) {
+	rows, err := q.ListOrderLines(context.Background(), database.ListOrderLinesParams{
+		AccountID: accountId,
+		OrderID:   orderId,
+	})
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	lines := make([]*OrderLine, 0, len(rows))
+	for _, row := range rows {
+		lines = append(lines, &OrderLine{
+			ID:          row.ID,
+			Inventory:   api.Expandable{ID: row.Inventory},
+			Item:        api.Expandable{ID: row.Item},
+			Quantity:    row.Quantity,
+			Reservation: api.Expandable{ID: row.Reservation},
+		})
+	}
+
+	return lines, nil
+}
+
+func nullTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
@@ -0,0 +1,106 @@
+package pricelists
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+func MapCreatePriceListParams(create Create) database.CreatePriceListParams {
+	t := time.Now()
+	cplp := database.CreatePriceListParams{
+		ID:        uuid.New(),
+		CreatedAt: t,
+		UpdatedAt: t,
+		AccountID: create.AccountId,
+		Currency:  create.RequestParams.Currency,
+		Name:      create.RequestParams.Name,
+	}
+	if create.RequestParams.EndsAt != nil {
+		cplp.EndsAt = sql.NullTime{Time: *create.RequestParams.EndsAt, Valid: true}
+	}
+	if create.RequestParams.StartsAt != nil {
+		cplp.StartsAt = sql.NullTime{Time: *create.RequestParams.StartsAt, Valid: true}
+	}
+	return cplp
+}
+
+func MapCreatePriceParams(create CreatePrice) database.CreatePriceParams {
+	t := time.Now()
+	cpp := database.CreatePriceParams{
+		ID:          uuid.New(),
+		CreatedAt:   t,
+		UpdatedAt:   t,
+		AccountID:   create.AccountId,
+		ItemID:      uuid.MustParse(create.RequestParams.Item),
+		MinQuantity: 1,
+		PriceListID: create.PriceListId,
+		UnitAmount:  create.RequestParams.UnitAmount,
+	}
+	if create.RequestParams.MinQuantity != nil {
+		cpp.MinQuantity = *create.RequestParams.MinQuantity
+	}
+	return cpp
+}
+
+func MapListPriceListsParams(list List) database.ListPriceListsParams {
+	lplp := database.ListPriceListsParams{
+		AccountID: list.AccountId,
+		Currency:  api.NullCurrency(list.RequestParams.Currency),
+		Name:      api.NullString(list.RequestParams.Name),
+	}
+	if list.RequestParams.ActiveAt != nil {
+		lplp.ActiveAt = sql.NullTime{Time: *list.RequestParams.ActiveAt, Valid: true}
+	}
+	database.MapTimeRange(list.RequestParams.CreatedAt, &lplp.CreatedAtGt, &lplp.CreatedAtGte, &lplp.CreatedAtLt, &lplp.CreatedAtLte)
+	database.MapTimeRange(list.RequestParams.UpdatedAt, &lplp.UpdatedAtGt, &lplp.UpdatedAtGte, &lplp.UpdatedAtLt, &lplp.UpdatedAtLte)
+	database.MapPaginationParams(*list.RequestParams.PaginationParams, &lplp)
+	return lplp
+}
+
+func MapListPricesParams(list ListPrices) database.ListPricesParams {
+	lpp := database.ListPricesParams{
+		AccountID:   list.AccountId,
+		ItemID:      api.NullUUID(nil),
+		PriceListID: list.PriceListId,
+	}
+	if list.RequestParams.Item != nil {
+		itemId := uuid.MustParse(*list.RequestParams.Item)
+		lpp.ItemID = api.NullUUID(&itemId)
+	}
+	database.MapTimeRange(list.RequestParams.CreatedAt, &lpp.CreatedAtGt, &lpp.CreatedAtGte, &lpp.CreatedAtLt, &lpp.CreatedAtLte)
+	database.MapTimeRange(list.RequestParams.UpdatedAt, &lpp.UpdatedAtGt, &lpp.UpdatedAtGte, &lpp.UpdatedAtLt, &lpp.UpdatedAtLte)
+	database.MapPaginationParams(*list.RequestParams.PaginationParams, &lpp)
+	return lpp
+}
+
+func MapUpdatePriceListParams(update Update) database.UpdatePriceListParams {
+	uplp := database.UpdatePriceListParams{
+		UpdatedAt: time.Now(),
+		AccountID: update.AccountId,
+		ID:        update.PriceListId,
+		Name:      api.NullString(update.RequestParams.Name),
+	}
+	if update.RequestParams.EndsAt != nil {
+		uplp.EndsAt = sql.NullTime{Time: *update.RequestParams.EndsAt, Valid: true}
+	}
+	if update.RequestParams.StartsAt != nil {
+		uplp.StartsAt = sql.NullTime{Time: *update.RequestParams.StartsAt, Valid: true}
+	}
+	return uplp
+}
+
+func MapUpdatePriceParams(update UpdatePrice) database.UpdatePriceParams {
+	upp := database.UpdatePriceParams{
+		UpdatedAt:   time.Now(),
+		AccountID:   update.AccountId,
+		ID:          update.PriceId,
+		MinQuantity: api.NullInt32(update.RequestParams.MinQuantity),
+		PriceListID: update.PriceListId,
+		UnitAmount:  api.NullInt32(update.RequestParams.UnitAmount),
+	}
+	return upp
+}
@@ -0,0 +1,93 @@
+package pricelists
+
+import (
+	"time"
+
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+type CreatePriceListParams struct {
+	Currency database.Currency `json:"currency" validate:"required,currency"`
+	EndsAt   *time.Time        `json:"ends_at" validate:"omitnil"`
+	Name     string            `json:"name" validate:"required"`
+	StartsAt *time.Time        `json:"starts_at" validate:"omitnil"`
+}
+
+type CreatePriceParams struct {
+	Item        string   `json:"item" validate:"required,uuid"`
+	MinQuantity *int32   `json:"min_quantity" validate:"omitnil,gt=0"`
+	UnitAmount  int32    `json:"unit_amount" validate:"gte=0"`
+	Expand      []string `json:"expand" validate:"omitnil,dive,oneof=item price_list"`
+}
+
+type ListPriceListsByIdsParams struct {
+	Ids []uuid.UUID
+}
+
+type ListPriceListsParams struct {
+	*database.PaginationParams
+	ActiveAt  *time.Time          `json:"active_at" validate:"omitnil"`
+	CreatedAt *database.TimeRange `json:"created_at" validate:"omitnil"`
+	Currency  *database.Currency  `json:"currency" validate:"omitnil,currency"`
+	Name      *string             `json:"name" validate:"omitnil"`
+	UpdatedAt *database.TimeRange `json:"updated_at" validate:"omitnil"`
+}
+
+type ListPricesByItemsParams struct {
+	Ids []uuid.UUID
+}
+
+type ListPricesParams struct {
+	*database.PaginationParams
+	CreatedAt *database.TimeRange `json:"created_at" validate:"omitnil"`
+	Item      *string             `json:"item" validate:"omitnil,uuid"`
+	UpdatedAt *database.TimeRange `json:"updated_at" validate:"omitnil"`
+	Expand    []string            `json:"expand" validate:"omitnil,dive,oneof=item price_list"`
+}
+
+type QuoteParams struct {
+	Date     *time.Time `json:"date" validate:"omitnil"`
+	Item     string     `json:"item" validate:"required,uuid"`
+	Quantity int32      `json:"quantity" validate:"required,gt=0"`
+	Expand   []string   `json:"expand" validate:"omitnil,dive,oneof=item price_list"`
+}
+
+type RetrievePriceListParams struct {
+}
+
+type RetrievePriceParams struct {
+	Expand []string `json:"expand" validate:"omitnil,dive,oneof=item price_list"`
+}
+
+// UpdatePriceListParams leaves out currency, as the prices already in the
+// list are amounts of it.
+type UpdatePriceListParams struct {
+	EndsAt   *time.Time `json:"ends_at" validate:"omitnil"`
+	Name     *string    `json:"name" validate:"omitnil"`
+	StartsAt *time.Time `json:"starts_at" validate:"omitnil"`
+}
+
+type UpdatePriceParams struct {
+	MinQuantity *int32   `json:"min_quantity" validate:"omitnil,gt=0"`
+	UnitAmount  *int32   `json:"unit_amount" validate:"omitnil,gte=0"`
+	Expand      []string `json:"expand" validate:"omitnil,dive,oneof=item price_list"`
+}
+
+func NewListPriceListsParams() ListPriceListsParams {
+	limit := int32(10)
+	return ListPriceListsParams{
+		PaginationParams: &database.PaginationParams{
+			Limit: &limit,
+		},
+	}
+}
+
+func NewListPricesParams() ListPricesParams {
+	limit := int32(10)
+	return ListPricesParams{
+		PaginationParams: &database.PaginationParams{
+			Limit: &limit,
+		},
+	}
+}
@@ -0,0 +1,45 @@
+package pricelists
+
+import (
+	"time"
+
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+// PriceList is a named set of item prices in a single currency, optionally
+// only in effect between StartsAt and EndsAt.
+type PriceList struct {
+	ID        *uuid.UUID        `json:"id,omitempty"`
+	CreatedAt *time.Time        `json:"created_at,omitempty"`
+	UpdatedAt *time.Time        `json:"updated_at,omitempty"`
+	Currency  database.Currency `json:"currency"`
+	EndsAt    *time.Time        `json:"ends_at"`
+	Name      string            `json:"name"`
+	StartsAt  *time.Time        `json:"starts_at"`
+}
+
+// Price is the unit price of an item in a price list from MinQuantity up.
+// An item can have several, one per quantity break.
+type Price struct {
+	ID          *uuid.UUID     `json:"id,omitempty"`
+	CreatedAt   *time.Time     `json:"created_at,omitempty"`
+	UpdatedAt   *time.Time     `json:"updated_at,omitempty"`
+	Item        api.Expandable `json:"item"`
+	MinQuantity int32          `json:"min_quantity"`
+	PriceList   api.Expandable `json:"price_list"`
+	UnitAmount  int32          `json:"unit_amount"`
+}
+
+// Quote is the price in effect for a quantity of an item in a price list.
+type Quote struct {
+	Currency    database.Currency `json:"currency"`
+	Date        time.Time         `json:"date"`
+	Item        api.Expandable    `json:"item"`
+	MinQuantity int32             `json:"min_quantity"`
+	PriceList   api.Expandable    `json:"price_list"`
+	Quantity    int32             `json:"quantity"`
+	TotalAmount int64             `json:"total_amount"`
+	UnitAmount  int32             `json:"unit_amount"`
+}
@@ -0,0 +1,61 @@
+package pricelists
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUnitEffectivePrice(t *testing.T) {
+	prices := []*Price{
+		{MinQuantity: 10, UnitAmount: 900},
+		{MinQuantity: 1, UnitAmount: 1000},
+		{MinQuantity: 100, UnitAmount: 750},
+	}
+
+	tests := []struct {
+		name     string
+		prices   []*Price
+		quantity int32
+		want     int32
+		wantNil  bool
+	}{
+		{name: "single unit", prices: prices, quantity: 1, want: 1000},
+		{name: "just below a break", prices: prices, quantity: 9, want: 1000},
+		{name: "on a break", prices: prices, quantity: 10, want: 900},
+		{name: "above the highest break", prices: prices, quantity: 500, want: 750},
+		{name: "below every break", prices: prices[:1], quantity: 5, wantNil: true},
+		{name: "no prices", quantity: 1, wantNil: true},
+	}
+
+	for _, tt := range tests {
+		got := effectivePrice(tt.prices, tt.quantity)
+		if tt.wantNil {
+			if got != nil {
+				t.Fatalf("%s: expected no price, got %d", tt.name, got.UnitAmount)
+			}
+			continue
+		}
+		if got == nil || got.UnitAmount != tt.want {
+			t.Fatalf("%s: expected %d, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestUnitInEffect(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	priceList := &PriceList{StartsAt: &start, EndsAt: &end}
+
+	if inEffect(priceList, start.Add(-time.Second)) {
+		t.Fatal("expected the price list not to be in effect before it starts")
+	}
+	if !inEffect(priceList, start) {
+		t.Fatal("expected the price list to be in effect when it starts")
+	}
+	if inEffect(priceList, end) {
+		t.Fatal("expected the price list not to be in effect when it ends")
+	}
+	if !inEffect(&PriceList{}, end) {
+		t.Fatal("expected an unscheduled price list to always be in effect")
+	}
+}
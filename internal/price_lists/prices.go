@@ -0,0 +1,315 @@
+package pricelists
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+type CreatePrice struct {
+	AccountId     uuid.UUID
+	PriceListId   uuid.UUID
+	RequestParams CreatePriceParams
+}
+
+type DeletePrice struct {
+	AccountId   uuid.UUID
+	PriceListId uuid.UUID
+	PriceId     uuid.UUID
+}
+
+type GetPrice struct {
+	AccountId     uuid.UUID
+	PriceListId   uuid.UUID
+	PriceId       uuid.UUID
+	RequestParams RetrievePriceParams
+	OmitBase      bool
+}
+
+type ListPricesByItems struct {
+	AccountId     uuid.UUID
+	RequestParams ListPricesByItemsParams
+}
+
+type ListPrices struct {
+	AccountId     uuid.UUID
+	PriceListId   uuid.UUID
+	RequestParams ListPricesParams
+}
+
+type UpdatePrice struct {
+	AccountId     uuid.UUID
+	PriceListId   uuid.UUID
+	PriceId       uuid.UUID
+	RequestParams UpdatePriceParams
+}
+
+// CreatePrice adds a quantity break for an item to a price list. Each
+// min_quantity can only be priced once per item and list.
+func (s *PriceListsService) CreatePrice(create CreatePrice) (*Price, error) {
+	_, err := s.Get(Get{
+		AccountId:     create.AccountId,
+		PriceListId:   create.PriceListId,
+		RequestParams: RetrievePriceListParams{},
+		OmitBase:      true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dbParams := MapCreatePriceParams(create)
+
+	_, err = s.Db.GetItem(context.Background(), database.GetItemParams{
+		ID:        dbParams.ItemID,
+		AccountID: create.AccountId,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, api.NotFoundMessage(dbParams.ItemID, "item")
+		}
+		return nil, err
+	}
+
+	if err := s.checkMinQuantity(create.AccountId, create.PriceListId, dbParams.ItemID, dbParams.MinQuantity, uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	row, err := s.Db.CreatePrice(context.Background(), dbParams)
+	if err != nil {
+		return nil, err
+	}
+
+	price := &Price{
+		ID:          &row.ID,
+		CreatedAt:   &row.CreatedAt,
+		UpdatedAt:   &row.UpdatedAt,
+		Item:        api.Expandable{ID: row.Item},
+		MinQuantity: row.MinQuantity,
+		PriceList:   api.Expandable{ID: row.PriceList},
+		UnitAmount:  row.UnitAmount,
+	}
+
+	return price, nil
+}
+
+func (s *PriceListsService) DeletePrice(delete DeletePrice) error {
+	_, err := s.GetPrice(GetPrice{
+		AccountId:     delete.AccountId,
+		PriceListId:   delete.PriceListId,
+		PriceId:       delete.PriceId,
+		RequestParams: RetrievePriceParams{},
+		OmitBase:      true,
+	})
+	if err != nil {
+		return err
+	}
+	return s.Db.DeletePrice(context.Background(), database.DeletePriceParams{
+		ID:          delete.PriceId,
+		AccountID:   delete.AccountId,
+		PriceListID: delete.PriceListId,
+	})
+}
+
+func (s *PriceListsService) GetPrice(get GetPrice) (*Price, error) {
+	row, err := s.Db.GetPrice(context.Background(), database.GetPriceParams{
+		ID:          get.PriceId,
+		AccountID:   get.AccountId,
+		PriceListID: get.PriceListId,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, api.NotFoundMessage(get.PriceId, "price")
+		}
+		return nil, err
+	}
+
+	price := &Price{
+		Item:        api.Expandable{ID: row.Item},
+		MinQuantity: row.MinQuantity,
+		PriceList:   api.Expandable{ID: row.PriceList},
+		UnitAmount:  row.UnitAmount,
+	}
+
+	if !get.OmitBase {
+		price.ID = &row.ID
+		price.CreatedAt = &row.CreatedAt
+		price.UpdatedAt = &row.UpdatedAt
+	}
+
+	return price, nil
+}
+
+func (s *PriceListsService) ListPricesByItems(list ListPricesByItems) (prices []*Price, err error) {
+	params := database.ListPricesByItemsParams{
+		AccountID: list.AccountId,
+		Ids:       list.RequestParams.Ids,
+	}
+
+	rows, err := s.Db.ListPricesByItems(context.Background(), params)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return prices, nil
+		}
+		return
+	}
+
+	for _, row := range rows {
+		prices = append(prices, &Price{
+			ID:          &row.ID,
+			CreatedAt:   &row.CreatedAt,
+			UpdatedAt:   &row.UpdatedAt,
+			Item:        api.Expandable{ID: row.Item},
+			MinQuantity: row.MinQuantity,
+			PriceList:   api.Expandable{ID: row.PriceList},
+			UnitAmount:  row.UnitAmount,
+		})
+	}
+
+	return
+}
+
+func (s *PriceListsService) ListPrices(list ListPrices) (prices []*Price, hasMore bool, err error) {
+	_, err = s.Get(Get{
+		AccountId:     list.AccountId,
+		PriceListId:   list.PriceListId,
+		RequestParams: RetrievePriceListParams{},
+		OmitBase:      true,
+	})
+	if err != nil {
+		return prices, hasMore, err
+	}
+
+	if list.RequestParams.StartingAfter != nil {
+		price, err := s.GetPrice(GetPrice{
+			AccountId:     list.AccountId,
+			PriceListId:   list.PriceListId,
+			PriceId:       *list.RequestParams.StartingAfter,
+			RequestParams: RetrievePriceParams{},
+			OmitBase:      false,
+		})
+		if err != nil {
+			return prices, hasMore, err
+		}
+		list.RequestParams.StartingAfterDate = price.CreatedAt
+	}
+
+	if list.RequestParams.EndingBefore != nil {
+		price, err := s.GetPrice(GetPrice{
+			AccountId:     list.AccountId,
+			PriceListId:   list.PriceListId,
+			PriceId:       *list.RequestParams.EndingBefore,
+			RequestParams: RetrievePriceParams{},
+			OmitBase:      false,
+		})
+		if err != nil {
+			return prices, hasMore, err
+		}
+		list.RequestParams.EndingBeforeDate = price.CreatedAt
+	}
+
+	dbParams := MapListPricesParams(list)
+
+	rows, err := s.Db.ListPrices(context.Background(), dbParams)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return prices, hasMore, nil
+		}
+		return
+	}
+
+	if dbParams.Limit.Valid {
+		hasMore = len(rows) > int(dbParams.Limit.Int32)
+	} else {
+		hasMore = len(rows) > 10
+	}
+
+	if hasMore {
+		if dbParams.EndingBefore.Valid {
+			rows = rows[1:]
+		} else {
+			rows = rows[:len(rows)-1]
+		}
+	}
+
+	for _, row := range rows {
+		prices = append(prices, &Price{
+			ID:          &row.ID,
+			CreatedAt:   &row.CreatedAt,
+			UpdatedAt:   &row.UpdatedAt,
+			Item:        api.Expandable{ID: row.Item},
+			MinQuantity: row.MinQuantity,
+			PriceList:   api.Expandable{ID: row.PriceList},
+			UnitAmount:  row.UnitAmount,
+		})
+	}
+
+	return prices, hasMore, err
+}
+
+func (s *PriceListsService) UpdatePrice(update UpdatePrice) (*Price, error) {
+	current, err := s.GetPrice(GetPrice{
+		AccountId:     update.AccountId,
+		PriceListId:   update.PriceListId,
+		PriceId:       update.PriceId,
+		RequestParams: RetrievePriceParams{},
+		OmitBase:      true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if minQuantity := update.RequestParams.MinQuantity; minQuantity != nil && *minQuantity != current.MinQuantity {
+		if err := s.checkMinQuantity(update.AccountId, update.PriceListId, current.Item.ID.UUID, *minQuantity, update.PriceId); err != nil {
+			return nil, err
+		}
+	}
+
+	dbParams := MapUpdatePriceParams(update)
+
+	row, err := s.Db.UpdatePrice(context.Background(), dbParams)
+	if err != nil {
+		return nil, err
+	}
+
+	price := &Price{
+		ID:          &row.ID,
+		CreatedAt:   &row.CreatedAt,
+		UpdatedAt:   &row.UpdatedAt,
+		Item:        api.Expandable{ID: row.Item},
+		MinQuantity: row.MinQuantity,
+		PriceList:   api.Expandable{ID: row.PriceList},
+		UnitAmount:  row.UnitAmount,
+	}
+
+	return price, nil
+}
+
+// checkMinQuantity errors when another price than priceId of the item in
+// the list already starts at minQuantity.
+func (s *PriceListsService) checkMinQuantity(accountId, priceListId, itemId uuid.UUID, minQuantity int32, priceId uuid.UUID) error {
+	rows, err := s.Db.ListItemPrices(context.Background(), database.ListItemPricesParams{
+		AccountID:   accountId,
+		ItemID:      itemId,
+		PriceListID: priceListId,
+	})
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	for _, row := range rows {
+		if row.MinQuantity == minQuantity && row.ID != priceId {
+			return &api.AppError{
+				Message: fmt.Sprintf("Item '%v' already has a price from quantity %d in price list '%v': '%v'.", itemId, minQuantity, priceListId, row.ID),
+				Status:  http.StatusBadRequest,
+				Type:    api.InvalidRequestError,
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package pricelists
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+type GetQuote struct {
+	AccountId     uuid.UUID
+	PriceListId   uuid.UUID
+	RequestParams QuoteParams
+}
+
+// Quote resolves the unit price of a quantity of an item in a price list on
+// a date, which defaults to now. The price is that of the item's highest
+// quantity break the quantity reaches.
+func (s *PriceListsService) Quote(get GetQuote) (*Quote, error) {
+	priceList, err := s.Get(Get{
+		AccountId:     get.AccountId,
+		PriceListId:   get.PriceListId,
+		RequestParams: RetrievePriceListParams{},
+		OmitBase:      true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	date := time.Now()
+	if get.RequestParams.Date != nil {
+		date = *get.RequestParams.Date
+	}
+	if !inEffect(priceList, date) {
+		return nil, &api.AppError{
+			Message: fmt.Sprintf("Price list '%v' isn't in effect on %s.", get.PriceListId, date.Format(time.RFC3339)),
+			Status:  http.StatusBadRequest,
+			Type:    api.InvalidRequestError,
+		}
+	}
+
+	itemId := uuid.MustParse(get.RequestParams.Item)
+
+	rows, err := s.Db.ListItemPrices(context.Background(), database.ListItemPricesParams{
+		AccountID:   get.AccountId,
+		ItemID:      itemId,
+		PriceListID: get.PriceListId,
+	})
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	prices := make([]*Price, 0, len(rows))
+	for _, row := range rows {
+		prices = append(prices, &Price{
+			Item:        api.Expandable{ID: row.Item},
+			MinQuantity: row.MinQuantity,
+			PriceList:   api.Expandable{ID: row.PriceList},
+			UnitAmount:  row.UnitAmount,
+		})
+	}
+
+	price := effectivePrice(prices, get.RequestParams.Quantity)
+	if price == nil {
+		return nil, &api.AppError{
+			Message: fmt.Sprintf("Item '%v' has no price for quantity %d in price list '%v'.", itemId, get.RequestParams.Quantity, get.PriceListId),
+			Status:  http.StatusBadRequest,
+			Type:    api.InvalidRequestError,
+		}
+	}
+
+	quote := &Quote{
+		Currency:    priceList.Currency,
+		Date:        date,
+		Item:        price.Item,
+		MinQuantity: price.MinQuantity,
+		PriceList:   price.PriceList,
+		Quantity:    get.RequestParams.Quantity,
+		TotalAmount: int64(price.UnitAmount) * int64(get.RequestParams.Quantity),
+		UnitAmount:  price.UnitAmount,
+	}
+
+	return quote, nil
+}
+
+// effectivePrice returns the price with the highest min_quantity that
+// quantity reaches, or nil when it's below all of them.
+func effectivePrice(prices []*Price, quantity int32) *Price {
+	var effective *Price
+	for _, price := range prices {
+		if price.MinQuantity <= quantity && (effective == nil || price.MinQuantity > effective.MinQuantity) {
+			effective = price
+		}
+	}
+	return effective
+}
+
+// inEffect reports whether date falls in the price list's schedule, which
+// includes its start and excludes its end.
+func inEffect(priceList *PriceList, date time.Time) bool {
+	if priceList.StartsAt != nil && date.Before(*priceList.StartsAt) {
+		return false
+	}
+	if priceList.EndsAt != nil && !date.Before(*priceList.EndsAt) {
+		return false
+	}
+	return true
+}
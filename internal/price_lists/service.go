@@ -0,0 +1,278 @@
+package pricelists
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+type PriceListsService struct {
+	Db *database.Queries
+}
+
+type Create struct {
+	AccountId     uuid.UUID
+	RequestParams CreatePriceListParams
+}
+
+type Delete struct {
+	AccountId   uuid.UUID
+	PriceListId uuid.UUID
+}
+
+type Get struct {
+	AccountId     uuid.UUID
+	PriceListId   uuid.UUID
+	RequestParams RetrievePriceListParams
+	OmitBase      bool
+}
+
+type ListByIds struct {
+	AccountId     uuid.UUID
+	RequestParams ListPriceListsByIdsParams
+}
+
+type List struct {
+	AccountId     uuid.UUID
+	RequestParams ListPriceListsParams
+}
+
+type Update struct {
+	AccountId     uuid.UUID
+	PriceListId   uuid.UUID
+	RequestParams UpdatePriceListParams
+}
+
+func NewPriceListsService(db *database.Queries) *PriceListsService {
+	return &PriceListsService{
+		Db: db,
+	}
+}
+
+func (s *PriceListsService) Create(create Create) (*PriceList, error) {
+	if err := checkSchedule(create.RequestParams.StartsAt, create.RequestParams.EndsAt); err != nil {
+		return nil, err
+	}
+
+	dbParams := MapCreatePriceListParams(create)
+	row, err := s.Db.CreatePriceList(context.Background(), dbParams)
+	if err != nil {
+		return nil, err
+	}
+
+	priceList := &PriceList{
+		ID:        &row.ID,
+		CreatedAt: &row.CreatedAt,
+		UpdatedAt: &row.UpdatedAt,
+		Currency:  row.Currency,
+		EndsAt:    nullTime(row.EndsAt),
+		Name:      row.Name,
+		StartsAt:  nullTime(row.StartsAt),
+	}
+
+	return priceList, nil
+}
+
+func (s *PriceListsService) Delete(delete Delete) error {
+	_, err := s.Get(Get{
+		AccountId:     delete.AccountId,
+		PriceListId:   delete.PriceListId,
+		RequestParams: RetrievePriceListParams{},
+		OmitBase:      true,
+	})
+	if err != nil {
+		return err
+	}
+	return s.Db.DeletePriceList(context.Background(), database.DeletePriceListParams{
+		ID:        delete.PriceListId,
+		AccountID: delete.AccountId,
+	})
+}
+
+func (s *PriceListsService) Get(get Get) (*PriceList, error) {
+	row, err := s.Db.GetPriceList(context.Background(), database.GetPriceListParams{
+		ID:        get.PriceListId,
+		AccountID: get.AccountId,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, api.NotFoundMessage(get.PriceListId, "price_list")
+		}
+		return nil, err
+	}
+
+	priceList := &PriceList{
+		Currency: row.Currency,
+		EndsAt:   nullTime(row.EndsAt),
+		Name:     row.Name,
+		StartsAt: nullTime(row.StartsAt),
+	}
+
+	if !get.OmitBase {
+		priceList.ID = &row.ID
+		priceList.CreatedAt = &row.CreatedAt
+		priceList.UpdatedAt = &row.UpdatedAt
+	}
+
+	return priceList, nil
+}
+
+func (s *PriceListsService) ListByIds(list ListByIds) (priceLists []*PriceList, err error) {
+	params := database.ListPriceListsByIdsParams{
+		AccountID: list.AccountId,
+		Ids:       list.RequestParams.Ids,
+	}
+
+	rows, err := s.Db.ListPriceListsByIds(context.Background(), params)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return priceLists, nil
+		}
+		return
+	}
+
+	for _, row := range rows {
+		priceLists = append(priceLists, &PriceList{
+			ID:        &row.ID,
+			CreatedAt: &row.CreatedAt,
+			UpdatedAt: &row.UpdatedAt,
+			Currency:  row.Currency,
+			EndsAt:    nullTime(row.EndsAt),
+			Name:      row.Name,
+			StartsAt:  nullTime(row.StartsAt),
+		})
+	}
+
+	return
+}
+
+func (s *PriceListsService) List(list List) (priceLists []*PriceList, hasMore bool, err error) {
+	if list.RequestParams.StartingAfter != nil {
+		priceList, err := s.Get(Get{
+			AccountId:     list.AccountId,
+			PriceListId:   *list.RequestParams.StartingAfter,
+			RequestParams: RetrievePriceListParams{},
+			OmitBase:      false,
+		})
+		if err != nil {
+			return priceLists, hasMore, err
+		}
+		list.RequestParams.StartingAfterDate = priceList.CreatedAt
+	}
+
+	if list.RequestParams.EndingBefore != nil {
+		priceList, err := s.Get(Get{
+			AccountId:     list.AccountId,
+			PriceListId:   *list.RequestParams.EndingBefore,
+			RequestParams: RetrievePriceListParams{},
+			OmitBase:      false,
+		})
+		if err != nil {
+			return priceLists, hasMore, err
+		}
+		list.RequestParams.EndingBeforeDate = priceList.CreatedAt
+	}
+
+	dbParams := MapListPriceListsParams(list)
+
+	rows, err := s.Db.ListPriceLists(context.Background(), dbParams)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return priceLists, hasMore, nil
+		}
+		return
+	}
+
+	if dbParams.Limit.Valid {
+		hasMore = len(rows) > int(dbParams.Limit.Int32)
+	} else {
+		hasMore = len(rows) > 10
+	}
+
+	if hasMore {
+		if dbParams.EndingBefore.Valid {
+			rows = rows[1:]
+		} else {
+			rows = rows[:len(rows)-1]
+		}
+	}
+
+	for _, row := range rows {
+		priceLists = append(priceLists, &PriceList{
+			ID:        &row.ID,
+			CreatedAt: &row.CreatedAt,
+			UpdatedAt: &row.UpdatedAt,
+			Currency:  row.Currency,
+			EndsAt:    nullTime(row.EndsAt),
+			Name:      row.Name,
+			StartsAt:  nullTime(row.StartsAt),
+		})
+	}
+
+	return priceLists, hasMore, err
+}
+
+func (s *PriceListsService) Update(update Update) (*PriceList, error) {
+	current, err := s.Get(Get{
+		AccountId:     update.AccountId,
+		PriceListId:   update.PriceListId,
+		RequestParams: RetrievePriceListParams{},
+		OmitBase:      true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	startsAt, endsAt := current.StartsAt, current.EndsAt
+	if update.RequestParams.StartsAt != nil {
+		startsAt = update.RequestParams.StartsAt
+	}
+	if update.RequestParams.EndsAt != nil {
+		endsAt = update.RequestParams.EndsAt
+	}
+	if err := checkSchedule(startsAt, endsAt); err != nil {
+		return nil, err
+	}
+
+	dbParams := MapUpdatePriceListParams(update)
+
+	row, err := s.Db.UpdatePriceList(context.Background(), dbParams)
+	if err != nil {
+		return nil, err
+	}
+
+	priceList := &PriceList{
+		ID:        &row.ID,
+		CreatedAt: &row.CreatedAt,
+		UpdatedAt: &row.UpdatedAt,
+		Currency:  row.Currency,
+		EndsAt:    nullTime(row.EndsAt),
+		Name:      row.Name,
+		StartsAt:  nullTime(row.StartsAt),
+	}
+
+	return priceList, nil
+}
+
+func checkSchedule(startsAt, endsAt *time.Time) error {
+	if startsAt != nil && endsAt != nil && !endsAt.After(*startsAt) {
+		return &api.AppError{
+			Message: "A price list has to end after it starts.",
+			Status:  http.StatusBadRequest,
+			Type:    api.InvalidRequestError,
+		}
+	}
+	return nil
+}
+
+func nullTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
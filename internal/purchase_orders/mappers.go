@@ -0,0 +1,62 @@
+package purchaseorders
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+func MapCreatePurchaseOrderParams(create Create, currency database.Currency) database.CreatePurchaseOrderParams {
+	t := time.Now()
+	cpop := database.CreatePurchaseOrderParams{
+		ID:         uuid.New(),
+		CreatedAt:  t,
+		UpdatedAt:  t,
+		AccountID:  create.AccountId,
+		Currency:   currency,
+		Reference:  api.NullString(create.RequestParams.Reference),
+		Status:     database.PurchaseOrderStatusDraft,
+		SupplierID: uuid.MustParse(create.RequestParams.Supplier),
+	}
+	if create.RequestParams.ExpectedAt != nil {
+		cpop.ExpectedAt = sql.NullTime{Time: *create.RequestParams.ExpectedAt, Valid: true}
+	}
+	return cpop
+}
+
+func MapListPurchaseOrdersParams(list List) database.ListPurchaseOrdersParams {
+	lpop := database.ListPurchaseOrdersParams{
+		AccountID:  list.AccountId,
+		SupplierID: api.NullUUID(nil),
+	}
+	if list.RequestParams.Status != nil {
+		lpop.Status = database.NullPurchaseOrderStatus{
+			PurchaseOrderStatus: *list.RequestParams.Status,
+			Valid:               true,
+		}
+	}
+	if list.RequestParams.Supplier != nil {
+		supplierId := uuid.MustParse(*list.RequestParams.Supplier)
+		lpop.SupplierID = api.NullUUID(&supplierId)
+	}
+	database.MapTimeRange(list.RequestParams.CreatedAt, &lpop.CreatedAtGt, &lpop.CreatedAtGte, &lpop.CreatedAtLt, &lpop.CreatedAtLte)
+	database.MapTimeRange(list.RequestParams.UpdatedAt, &lpop.UpdatedAtGt, &lpop.UpdatedAtGte, &lpop.UpdatedAtLt, &lpop.UpdatedAtLte)
+	database.MapPaginationParams(*list.RequestParams.PaginationParams, &lpop)
+	return lpop
+}
+
+func MapUpdatePurchaseOrderParams(update Update) database.UpdatePurchaseOrderParams {
+	upop := database.UpdatePurchaseOrderParams{
+		UpdatedAt: time.Now(),
+		AccountID: update.AccountId,
+		ID:        update.PurchaseOrderId,
+		Reference: api.NullString(update.RequestParams.Reference),
+	}
+	if update.RequestParams.ExpectedAt != nil {
+		upop.ExpectedAt = sql.NullTime{Time: *update.RequestParams.ExpectedAt, Valid: true}
+	}
+	return upop
+}
@@ -0,0 +1,80 @@
+package purchaseorders
+
+import (
+	"time"
+
+	"github.com/d-darac/inventory-assets/database"
+)
+
+type CancelPurchaseOrderParams struct {
+	Expand []string `json:"expand" validate:"omitnil,dive,oneof=supplier"`
+}
+
+type CreatePurchaseOrderParams struct {
+	ExpectedAt *time.Time                `json:"expected_at" validate:"omitnil"`
+	Lines      []PurchaseOrderLineParams `json:"lines" validate:"required,min=1,max=100,dive"`
+	Reference  *string                   `json:"reference" validate:"omitnil"`
+	Supplier   string                    `json:"supplier" validate:"required,uuid"`
+	Expand     []string                  `json:"expand" validate:"omitnil,dive,oneof=supplier"`
+}
+
+type ListPurchaseOrdersParams struct {
+	*database.PaginationParams
+	CreatedAt *database.TimeRange           `json:"created_at" validate:"omitnil"`
+	Status    *database.PurchaseOrderStatus `json:"status" validate:"omitnil,oneof=draft sent partially_received received canceled"`
+	Supplier  *string                       `json:"supplier" validate:"omitnil,uuid"`
+	UpdatedAt *database.TimeRange           `json:"updated_at" validate:"omitnil"`
+	Expand    []string                      `json:"expand" validate:"omitnil,dive,oneof=supplier"`
+}
+
+// PurchaseOrderLineParams takes quantity in unit, usually the item's
+// purchase unit, when one is given, see units.ToStockingUnit. The line is
+// stored in the stocking unit, and unit_cost_amount is the cost of one
+// stocking unit.
+type PurchaseOrderLineParams struct {
+	Inventory      string  `json:"inventory" validate:"required,uuid"`
+	Quantity       int32   `json:"quantity" validate:"required,gt=0"`
+	Unit           *string `json:"unit" validate:"omitnil,uuid"`
+	UnitCostAmount int32   `json:"unit_cost_amount" validate:"gte=0"`
+}
+
+// ReceiptLineParams is a quantity received against one of the order's lines,
+// optionally into a lot of the line's inventory, and in one of the item's
+// units the same way as on the line.
+type ReceiptLineParams struct {
+	Line     string  `json:"line" validate:"required,uuid"`
+	Lot      *string `json:"lot" validate:"omitnil,uuid"`
+	Quantity int32   `json:"quantity" validate:"required,gt=0"`
+	Unit     *string `json:"unit" validate:"omitnil,uuid"`
+}
+
+type ReceivePurchaseOrderParams struct {
+	Lines  []ReceiptLineParams `json:"lines" validate:"required,min=1,unique=Line,dive"`
+	Expand []string            `json:"expand" validate:"omitnil,dive,oneof=supplier"`
+}
+
+type RetrievePurchaseOrderParams struct {
+	Expand []string `json:"expand" validate:"omitnil,dive,oneof=supplier"`
+}
+
+type SendPurchaseOrderParams struct {
+	Expand []string `json:"expand" validate:"omitnil,dive,oneof=supplier"`
+}
+
+// UpdatePurchaseOrderParams changes a draft order. Lines, when given, replace
+// all of the order's lines.
+type UpdatePurchaseOrderParams struct {
+	ExpectedAt *time.Time                `json:"expected_at" validate:"omitnil"`
+	Lines      []PurchaseOrderLineParams `json:"lines" validate:"omitnil,min=1,max=100,dive"`
+	Reference  *string                   `json:"reference" validate:"omitnil"`
+	Expand     []string                  `json:"expand" validate:"omitnil,dive,oneof=supplier"`
+}
+
+func NewListPurchaseOrdersParams() ListPurchaseOrdersParams {
+	limit := int32(10)
+	return ListPurchaseOrdersParams{
+		PaginationParams: &database.PaginationParams{
+			Limit: &limit,
+		},
+	}
+}
@@ -0,0 +1,37 @@
+package purchaseorders
+
+import (
+	"time"
+
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/d-darac/inventory-assets/str"
+	"github.com/google/uuid"
+)
+
+// PurchaseOrder is an order placed with a supplier. It's kept in the
+// supplier's currency, which the unit costs of its lines are in.
+type PurchaseOrder struct {
+	ID         *uuid.UUID                   `json:"id,omitempty"`
+	CreatedAt  *time.Time                   `json:"created_at,omitempty"`
+	UpdatedAt  *time.Time                   `json:"updated_at,omitempty"`
+	Currency   database.Currency            `json:"currency"`
+	ExpectedAt *time.Time                   `json:"expected_at"`
+	Lines      []*PurchaseOrderLine         `json:"lines,omitempty"`
+	ReceivedAt *time.Time                   `json:"received_at"`
+	Reference  str.NullString               `json:"reference"`
+	SentAt     *time.Time                   `json:"sent_at"`
+	Status     database.PurchaseOrderStatus `json:"status"`
+	Supplier   api.Expandable               `json:"supplier"`
+}
+
+// PurchaseOrderLine is a quantity of an item ordered into one of its
+// inventories. Quantities are in the item's stocking unit.
+type PurchaseOrderLine struct {
+	ID               uuid.UUID      `json:"id"`
+	Inventory        api.Expandable `json:"inventory"`
+	Item             api.Expandable `json:"item"`
+	Quantity         int32          `json:"quantity"`
+	ReceivedQuantity int32          `json:"received_quantity"`
+	UnitCostAmount   int32          `json:"unit_cost_amount"`
+}
@@ -0,0 +1,37 @@
+package purchaseorders
+
+import (
+	"testing"
+
+	"github.com/d-darac/inventory-assets/database"
+)
+
+func TestUnitReceiptStatus(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []*PurchaseOrderLine
+		want  database.PurchaseOrderStatus
+	}{
+		{
+			name:  "all received",
+			lines: []*PurchaseOrderLine{{Quantity: 5, ReceivedQuantity: 5}, {Quantity: 2, ReceivedQuantity: 2}},
+			want:  database.PurchaseOrderStatusReceived,
+		},
+		{
+			name:  "one line short",
+			lines: []*PurchaseOrderLine{{Quantity: 5, ReceivedQuantity: 5}, {Quantity: 2, ReceivedQuantity: 1}},
+			want:  database.PurchaseOrderStatusPartiallyReceived,
+		},
+		{
+			name:  "line not received yet",
+			lines: []*PurchaseOrderLine{{Quantity: 5, ReceivedQuantity: 3}, {Quantity: 2}},
+			want:  database.PurchaseOrderStatusPartiallyReceived,
+		},
+	}
+
+	for _, tt := range tests {
+		if got := receiptStatus(tt.lines); got != tt.want {
+			t.Fatalf("%s: expected %s, got %s", tt.name, tt.want, got)
+		}
+	}
+}
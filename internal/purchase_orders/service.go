@@ -0,0 +1,715 @@
+package purchaseorders
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	stockmovements "github.com/d-darac/inventory-api/internal/stock_movements"
+	"github.com/d-darac/inventory-api/internal/units"
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/d-darac/inventory-assets/str"
+	"github.com/google/uuid"
+)
+
+type PurchaseOrdersService struct {
+	Conn *sql.DB
+	Db   *database.Queries
+}
+
+type Cancel struct {
+	AccountId       uuid.UUID
+	PurchaseOrderId uuid.UUID
+	RequestParams   CancelPurchaseOrderParams
+}
+
+type Create struct {
+	AccountId     uuid.UUID
+	RequestParams CreatePurchaseOrderParams
+}
+
+type Get struct {
+	AccountId       uuid.UUID
+	PurchaseOrderId uuid.UUID
+	RequestParams   RetrievePurchaseOrderParams
+	OmitBase        bool
+}
+
+type List struct {
+	AccountId     uuid.UUID
+	RequestParams ListPurchaseOrdersParams
+}
+
+type Receive struct {
+	AccountId       uuid.UUID
+	PurchaseOrderId uuid.UUID
+	RequestParams   ReceivePurchaseOrderParams
+}
+
+type Send struct {
+	AccountId       uuid.UUID
+	PurchaseOrderId uuid.UUID
+	RequestParams   SendPurchaseOrderParams
+}
+
+type Update struct {
+	AccountId       uuid.UUID
+	PurchaseOrderId uuid.UUID
+	RequestParams   UpdatePurchaseOrderParams
+}
+
+func NewPurchaseOrdersService(db *database.Queries, conn *sql.DB) *PurchaseOrdersService {
+	return &PurchaseOrdersService{
+		Conn: conn,
+		Db:   db,
+	}
+}
+
+// Cancel closes an order that hasn't been fully received. Whatever was still
+// outstanding on a sent order is taken off its inventories' orderable.
+func (s *PurchaseOrdersService) Cancel(cancel Cancel) (*PurchaseOrder, error) {
+	tx, err := s.Conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.Db.WithTx(tx)
+
+	current, err := getForUpdate(qtx, cancel.AccountId, cancel.PurchaseOrderId,
+		database.PurchaseOrderStatusDraft,
+		database.PurchaseOrderStatusSent,
+		database.PurchaseOrderStatusPartiallyReceived,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	lines, err := listLines(qtx, cancel.AccountId, current.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if current.Status != database.PurchaseOrderStatusDraft {
+		for _, line := range lines {
+			outstanding := line.Quantity - line.ReceivedQuantity
+			if !line.Inventory.ID.Valid || outstanding == 0 {
+				continue
+			}
+			_, err := qtx.AdjustInventoryOrderable(context.Background(), database.AdjustInventoryOrderableParams{
+				UpdatedAt: time.Now(),
+				AccountID: cancel.AccountId,
+				ID:        line.Inventory.ID.UUID,
+				Quantity:  -outstanding,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	row, err := qtx.UpdatePurchaseOrderStatus(context.Background(), database.UpdatePurchaseOrderStatusParams{
+		UpdatedAt:  time.Now(),
+		AccountID:  cancel.AccountId,
+		ID:         cancel.PurchaseOrderId,
+		ReceivedAt: sql.NullTime{},
+		SentAt:     current.SentAt,
+		Status:     database.PurchaseOrderStatusCanceled,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	purchaseOrder := &PurchaseOrder{
+		ID:         &row.ID,
+		CreatedAt:  &row.CreatedAt,
+		UpdatedAt:  &row.UpdatedAt,
+		Currency:   row.Currency,
+		ExpectedAt: nullTime(row.ExpectedAt),
+		Lines:      lines,
+		ReceivedAt: nullTime(row.ReceivedAt),
+		Reference:  str.NullString(row.Reference),
+		SentAt:     nullTime(row.SentAt),
+		Status:     row.Status,
+		Supplier:   api.Expandable{ID: row.Supplier},
+	}
+
+	return purchaseOrder, nil
+}
+
+// Create opens a draft order in the currency of its supplier.
+func (s *PurchaseOrdersService) Create(create Create) (*PurchaseOrder, error) {
+	tx, err := s.Conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.Db.WithTx(tx)
+
+	supplierId := uuid.MustParse(create.RequestParams.Supplier)
+	supplier, err := qtx.GetSupplier(context.Background(), database.GetSupplierParams{
+		ID:        supplierId,
+		AccountID: create.AccountId,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, api.NotFoundMessage(supplierId, "supplier")
+		}
+		return nil, err
+	}
+
+	dbParams := MapCreatePurchaseOrderParams(create, supplier.Currency)
+
+	row, err := qtx.CreatePurchaseOrder(context.Background(), dbParams)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := createLines(qtx, create.AccountId, row.ID, create.RequestParams.Lines); err != nil {
+		return nil, err
+	}
+
+	lines, err := listLines(qtx, create.AccountId, row.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	purchaseOrder := &PurchaseOrder{
+		ID:         &row.ID,
+		CreatedAt:  &row.CreatedAt,
+		UpdatedAt:  &row.UpdatedAt,
+		Currency:   row.Currency,
+		ExpectedAt: nullTime(row.ExpectedAt),
+		Lines:      lines,
+		ReceivedAt: nullTime(row.ReceivedAt),
+		Reference:  str.NullString(row.Reference),
+		SentAt:     nullTime(row.SentAt),
+		Status:     row.Status,
+		Supplier:   api.Expandable{ID: row.Supplier},
+	}
+
+	return purchaseOrder, nil
+}
+
+func (s *PurchaseOrdersService) Get(get Get) (*PurchaseOrder, error) {
+	row, err := s.Db.GetPurchaseOrder(context.Background(), database.GetPurchaseOrderParams{
+		ID:        get.PurchaseOrderId,
+		AccountID: get.AccountId,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, api.NotFoundMessage(get.PurchaseOrderId, "purchase order")
+		}
+		return nil, err
+	}
+
+	lines, err := listLines(s.Db, get.AccountId, row.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	purchaseOrder := &PurchaseOrder{
+		Currency:   row.Currency,
+		ExpectedAt: nullTime(row.ExpectedAt),
+		Lines:      lines,
+		ReceivedAt: nullTime(row.ReceivedAt),
+		Reference:  str.NullString(row.Reference),
+		SentAt:     nullTime(row.SentAt),
+		Status:     row.Status,
+		Supplier:   api.Expandable{ID: row.Supplier},
+	}
+
+	if !get.OmitBase {
+		purchaseOrder.ID = &row.ID
+		purchaseOrder.CreatedAt = &row.CreatedAt
+		purchaseOrder.UpdatedAt = &row.UpdatedAt
+	}
+
+	return purchaseOrder, nil
+}
+
+func (s *PurchaseOrdersService) List(list List) (purchaseOrders []*PurchaseOrder, hasMore bool, err error) {
+	if list.RequestParams.StartingAfter != nil {
+		purchaseOrder, err := s.Get(Get{
+			AccountId:       list.AccountId,
+			PurchaseOrderId: *list.RequestParams.StartingAfter,
+			RequestParams:   RetrievePurchaseOrderParams{},
+			OmitBase:        false,
+		})
+		if err != nil {
+			return purchaseOrders, hasMore, err
+		}
+		list.RequestParams.StartingAfterDate = purchaseOrder.CreatedAt
+	}
+
+	if list.RequestParams.EndingBefore != nil {
+		purchaseOrder, err := s.Get(Get{
+			AccountId:       list.AccountId,
+			PurchaseOrderId: *list.RequestParams.EndingBefore,
+			RequestParams:   RetrievePurchaseOrderParams{},
+			OmitBase:        false,
+		})
+		if err != nil {
+			return purchaseOrders, hasMore, err
+		}
+		list.RequestParams.EndingBeforeDate = purchaseOrder.CreatedAt
+	}
+
+	dbParams := MapListPurchaseOrdersParams(list)
+
+	rows, err := s.Db.ListPurchaseOrders(context.Background(), dbParams)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return purchaseOrders, hasMore, nil
+		}
+		return
+	}
+
+	if dbParams.Limit.Valid {
+		hasMore = len(rows) > int(dbParams.Limit.Int32)
+	} else {
+		hasMore = len(rows) > 10
+	}
+
+	if hasMore {
+		if dbParams.EndingBefore.Valid {
+			rows = rows[1:]
+		} else {
+			rows = rows[:len(rows)-1]
+		}
+	}
+
+	for _, row := range rows {
+		purchaseOrders = append(purchaseOrders, &PurchaseOrder{
+			ID:         &row.ID,
+			CreatedAt:  &row.CreatedAt,
+			UpdatedAt:  &row.UpdatedAt,
+			Currency:   row.Currency,
+			ExpectedAt: nullTime(row.ExpectedAt),
+			ReceivedAt: nullTime(row.ReceivedAt),
+			Reference:  str.NullString(row.Reference),
+			SentAt:     nullTime(row.SentAt),
+			Status:     row.Status,
+			Supplier:   api.Expandable{ID: row.Supplier},
+		})
+	}
+
+	return purchaseOrders, hasMore, err
+}
+
+// Receive books the received quantities into the lines' inventories as
+// receipts at the lines' unit costs, and takes them off the inventories'
+// orderable. The order is received once every line is, and partially
+// received until then.
+func (s *PurchaseOrdersService) Receive(receive Receive) (*PurchaseOrder, error) {
+	tx, err := s.Conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.Db.WithTx(tx)
+
+	current, err := getForUpdate(qtx, receive.AccountId, receive.PurchaseOrderId,
+		database.PurchaseOrderStatusSent,
+		database.PurchaseOrderStatusPartiallyReceived,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	lines, err := listLines(qtx, receive.AccountId, current.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	idLineMap := make(map[uuid.UUID]*PurchaseOrderLine, len(lines))
+	for _, line := range lines {
+		idLineMap[line.ID] = line
+	}
+
+	reference := current.ID.String()
+	for _, receipt := range receive.RequestParams.Lines {
+		lineId := uuid.MustParse(receipt.Line)
+		line, ok := idLineMap[lineId]
+		if !ok {
+			return nil, &api.AppError{
+				Message: fmt.Sprintf("Line '%v' isn't part of purchase order '%v'.", lineId, current.ID),
+				Status:  http.StatusBadRequest,
+				Type:    api.InvalidRequestError,
+			}
+		}
+		if !line.Inventory.ID.Valid {
+			return nil, &api.AppError{
+				Message: fmt.Sprintf("The inventory of line '%v' no longer exists.", lineId),
+				Status:  http.StatusBadRequest,
+				Type:    api.InvalidRequestError,
+			}
+		}
+
+		quantity := receipt.Quantity
+		if receipt.Unit != nil {
+			quantity, err = units.ToStockingUnit(qtx, receive.AccountId, line.Item.ID, uuid.MustParse(*receipt.Unit), receipt.Quantity)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if line.ReceivedQuantity+quantity > line.Quantity {
+			return nil, &api.AppError{
+				Message: fmt.Sprintf("Can't receive more than the %d units outstanding on line '%v'.", line.Quantity-line.ReceivedQuantity, lineId),
+				Status:  http.StatusBadRequest,
+				Type:    api.InvalidRequestError,
+			}
+		}
+
+		unitCostAmount := line.UnitCostAmount
+		_, err = stockmovements.Post(qtx, stockmovements.Create{
+			AccountId: receive.AccountId,
+			RequestParams: stockmovements.CreateStockMovementParams{
+				Inventory:        line.Inventory.ID.UUID.String(),
+				Lot:              receipt.Lot,
+				Quantity:         quantity,
+				Reference:        &reference,
+				Type:             database.StockMovementTypeReceipt,
+				UnitCostAmount:   &unitCostAmount,
+				UnitCostCurrency: &current.Currency,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = qtx.AdjustInventoryOrderable(context.Background(), database.AdjustInventoryOrderableParams{
+			UpdatedAt: time.Now(),
+			AccountID: receive.AccountId,
+			ID:        line.Inventory.ID.UUID,
+			Quantity:  -quantity,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = qtx.ReceivePurchaseOrderLine(context.Background(), database.ReceivePurchaseOrderLineParams{
+			ID:       lineId,
+			Quantity: quantity,
+		})
+		if err != nil {
+			return nil, err
+		}
+		line.ReceivedQuantity += receipt.Quantity
+	}
+
+	t := time.Now()
+	status := receiptStatus(lines)
+	receivedAt := sql.NullTime{}
+	if status == database.PurchaseOrderStatusReceived {
+		receivedAt = sql.NullTime{Time: t, Valid: true}
+	}
+
+	row, err := qtx.UpdatePurchaseOrderStatus(context.Background(), database.UpdatePurchaseOrderStatusParams{
+		UpdatedAt:  t,
+		AccountID:  receive.AccountId,
+		ID:         receive.PurchaseOrderId,
+		ReceivedAt: receivedAt,
+		SentAt:     current.SentAt,
+		Status:     status,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	purchaseOrder := &PurchaseOrder{
+		ID:         &row.ID,
+		CreatedAt:  &row.CreatedAt,
+		UpdatedAt:  &row.UpdatedAt,
+		Currency:   row.Currency,
+		ExpectedAt: nullTime(row.ExpectedAt),
+		Lines:      lines,
+		ReceivedAt: nullTime(row.ReceivedAt),
+		Reference:  str.NullString(row.Reference),
+		SentAt:     nullTime(row.SentAt),
+		Status:     row.Status,
+		Supplier:   api.Expandable{ID: row.Supplier},
+	}
+
+	return purchaseOrder, nil
+}
+
+// Send marks a draft order as sent to the supplier and puts its quantities
+// on order in the lines' inventories.
+func (s *PurchaseOrdersService) Send(send Send) (*PurchaseOrder, error) {
+	tx, err := s.Conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.Db.WithTx(tx)
+
+	current, err := getForUpdate(qtx, send.AccountId, send.PurchaseOrderId, database.PurchaseOrderStatusDraft)
+	if err != nil {
+		return nil, err
+	}
+
+	lines, err := listLines(qtx, send.AccountId, current.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, line := range lines {
+		if !line.Inventory.ID.Valid {
+			return nil, &api.AppError{
+				Message: fmt.Sprintf("The inventory of line '%v' no longer exists.", line.ID),
+				Status:  http.StatusBadRequest,
+				Type:    api.InvalidRequestError,
+			}
+		}
+		_, err := qtx.AdjustInventoryOrderable(context.Background(), database.AdjustInventoryOrderableParams{
+			UpdatedAt: time.Now(),
+			AccountID: send.AccountId,
+			ID:        line.Inventory.ID.UUID,
+			Quantity:  line.Quantity,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	t := time.Now()
+	row, err := qtx.UpdatePurchaseOrderStatus(context.Background(), database.UpdatePurchaseOrderStatusParams{
+		UpdatedAt:  t,
+		AccountID:  send.AccountId,
+		ID:         send.PurchaseOrderId,
+		ReceivedAt: sql.NullTime{},
+		SentAt:     sql.NullTime{Time: t, Valid: true},
+		Status:     database.PurchaseOrderStatusSent,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	purchaseOrder := &PurchaseOrder{
+		ID:         &row.ID,
+		CreatedAt:  &row.CreatedAt,
+		UpdatedAt:  &row.UpdatedAt,
+		Currency:   row.Currency,
+		ExpectedAt: nullTime(row.ExpectedAt),
+		Lines:      lines,
+		ReceivedAt: nullTime(row.ReceivedAt),
+		Reference:  str.NullString(row.Reference),
+		SentAt:     nullTime(row.SentAt),
+		Status:     row.Status,
+		Supplier:   api.Expandable{ID: row.Supplier},
+	}
+
+	return purchaseOrder, nil
+}
+
+func (s *PurchaseOrdersService) Update(update Update) (*PurchaseOrder, error) {
+	tx, err := s.Conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.Db.WithTx(tx)
+
+	current, err := getForUpdate(qtx, update.AccountId, update.PurchaseOrderId, database.PurchaseOrderStatusDraft)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.RequestParams.Lines != nil {
+		err := qtx.DeletePurchaseOrderLines(context.Background(), database.DeletePurchaseOrderLinesParams{
+			AccountID:       update.AccountId,
+			PurchaseOrderID: current.ID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := createLines(qtx, update.AccountId, current.ID, update.RequestParams.Lines); err != nil {
+			return nil, err
+		}
+	}
+
+	dbParams := MapUpdatePurchaseOrderParams(update)
+
+	row, err := qtx.UpdatePurchaseOrder(context.Background(), dbParams)
+	if err != nil {
+		return nil, err
+	}
+
+	lines, err := listLines(qtx, update.AccountId, row.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	purchaseOrder := &PurchaseOrder{
+		ID:         &row.ID,
+		CreatedAt:  &row.CreatedAt,
+		UpdatedAt:  &row.UpdatedAt,
+		Currency:   row.Currency,
+		ExpectedAt: nullTime(row.ExpectedAt),
+		Lines:      lines,
+		ReceivedAt: nullTime(row.ReceivedAt),
+		Reference:  str.NullString(row.Reference),
+		SentAt:     nullTime(row.SentAt),
+		Status:     row.Status,
+		Supplier:   api.Expandable{ID: row.Supplier},
+	}
+
+	return purchaseOrder, nil
+}
+
+// createLines adds lines to an order, each for the item of its inventory.
+// Serialized items are left out, since receiving a line posts plain receipts
+// and their stock only comes in through serial numbers.
+func createLines(q *database.Queries, accountId, purchaseOrderId uuid.UUID, lines []PurchaseOrderLineParams) error {
+	for _, line := range lines {
+		inventoryId := uuid.MustParse(line.Inventory)
+		inventory, err := q.GetInventory(context.Background(), database.GetInventoryParams{
+			ID:        inventoryId,
+			AccountID: accountId,
+		})
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return api.NotFoundMessage(inventoryId, "inventory")
+			}
+			return err
+		}
+		if !inventory.Item.Valid {
+			return &api.AppError{
+				Message: fmt.Sprintf("Inventory '%v' doesn't belong to an item.", inventoryId),
+				Status:  http.StatusBadRequest,
+				Type:    api.InvalidRequestError,
+			}
+		}
+
+		serialized, err := q.IsInventorySerialized(context.Background(), database.IsInventorySerializedParams{
+			ID:        inventoryId,
+			AccountID: accountId,
+		})
+		if err != nil {
+			return err
+		}
+		if serialized {
+			return &api.AppError{
+				Message: fmt.Sprintf("Inventory '%v' is of a serialized item, which is received through its serial numbers rather than a purchase order.", inventoryId),
+				Status:  http.StatusBadRequest,
+				Type:    api.InvalidRequestError,
+			}
+		}
+
+		quantity := line.Quantity
+		if line.Unit != nil {
+			quantity, err = units.ToStockingUnit(q, accountId, inventory.Item, uuid.MustParse(*line.Unit), line.Quantity)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = q.CreatePurchaseOrderLine(context.Background(), database.CreatePurchaseOrderLineParams{
+			ID:              uuid.New(),
+			AccountID:       accountId,
+			PurchaseOrderID: purchaseOrderId,
+			InventoryID:     inventoryId,
+			ItemID:          inventory.Item.UUID,
+			Quantity:        quantity,
+			UnitCostAmount:  line.UnitCostAmount,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getForUpdate locks the purchase order and makes sure it's in one of the
+// statuses the next step of its lifecycle starts from.
+func getForUpdate(q *database.Queries, accountId, purchaseOrderId uuid.UUID, statuses ...database.PurchaseOrderStatus) (*database.GetPurchaseOrderForUpdateRow, error) {
+	row, err := q.GetPurchaseOrderForUpdate(context.Background(), database.GetPurchaseOrderForUpdateParams{
+		ID:        purchaseOrderId,
+		AccountID: accountId,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, api.NotFoundMessage(purchaseOrderId, "purchase order")
+		}
+		return nil, err
+	}
+
+	expected := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		if row.Status == status {
+			return &row, nil
+		}
+		expected = append(expected, string(status))
+	}
+
+	return nil, &api.AppError{
+		Message: fmt.Sprintf("Purchase order '%v' is %s, expected it to be %s.", purchaseOrderId, row.Status, strings.Join(expected, " or ")),
+		Status:  http.StatusBadRequest,
+		Type:    api.InvalidRequestError,
+	}
+}
+
+func listLines(q *database.Queries, accountId, purchaseOrderId uuid.UUID) ([]*PurchaseOrderLine, error) {
+	rows, err := q.ListPurchaseOrderLines(context.Background(), database.ListPurchaseOrderLinesParams{
+		AccountID:       accountId,
+		PurchaseOrderID: purchaseOrderId,
+	})
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	lines := make([]*PurchaseOrderLine, 0, len(rows))
+	for _, row := range rows {
+		lines = append(lines, &PurchaseOrderLine{
+			ID:               row.ID,
+			Inventory:        api.Expandable{ID: row.Inventory},
+			Item:             api.Expandable{ID: row.Item},
+			Quantity:         row.Quantity,
+			ReceivedQuantity: row.ReceivedQuantity,
+			UnitCostAmount:   row.UnitCostAmount,
+		})
+	}
+
+	return lines, nil
+}
+
+// receiptStatus is the status an order is left in after a receipt.
+func receiptStatus(lines []*PurchaseOrderLine) database.PurchaseOrderStatus {
+	for _, line := range lines {
+		if line.ReceivedQuantity < line.Quantity {
+			return database.PurchaseOrderStatusPartiallyReceived
+		}
+	}
+	return database.PurchaseOrderStatusReceived
+}
+
+func nullTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
@@ -0,0 +1,51 @@
+package reservations
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+func MapCreateReservationParams(create Create) database.CreateReservationParams {
+	t := time.Now()
+	crp := database.CreateReservationParams{
+		ID:          uuid.New(),
+		CreatedAt:   t,
+		UpdatedAt:   t,
+		AccountID:   create.AccountId,
+		InventoryID: uuid.MustParse(create.RequestParams.Inventory),
+		ParentID:    api.NullUUID(nil),
+		Quantity:    create.RequestParams.Quantity,
+		Reference:   api.NullString(create.RequestParams.Reference),
+		Status:      database.ReservationStatusActive,
+	}
+	if create.RequestParams.ExpiresAt != nil {
+		crp.ExpiresAt = sql.NullTime{Time: *create.RequestParams.ExpiresAt, Valid: true}
+	}
+	return crp
+}
+
+func MapListReservationsParams(list List) database.ListReservationsParams {
+	lrp := database.ListReservationsParams{
+		AccountID:   list.AccountId,
+		InventoryID: api.NullUUID(nil),
+		Reference:   api.NullString(list.RequestParams.Reference),
+	}
+	if list.RequestParams.Inventory != nil {
+		inventoryId := uuid.MustParse(*list.RequestParams.Inventory)
+		lrp.InventoryID = api.NullUUID(&inventoryId)
+	}
+	if list.RequestParams.Status != nil {
+		lrp.Status = database.NullReservationStatus{
+			ReservationStatus: *list.RequestParams.Status,
+			Valid:             true,
+		}
+	}
+	database.MapTimeRange(list.RequestParams.CreatedAt, &lrp.CreatedAtGt, &lrp.CreatedAtGte, &lrp.CreatedAtLt, &lrp.CreatedAtLte)
+	database.MapTimeRange(list.RequestParams.UpdatedAt, &lrp.UpdatedAtGt, &lrp.UpdatedAtGte, &lrp.UpdatedAtLt, &lrp.UpdatedAtLte)
+	database.MapPaginationParams(*list.RequestParams.PaginationParams, &lrp)
+	return lrp
+}
@@ -0,0 +1,49 @@
+package reservations
+
+import (
+	"time"
+
+	"github.com/d-darac/inventory-assets/database"
+)
+
+type CommitReservationParams struct {
+	Expand []string `json:"expand" validate:"omitnil,dive,oneof=inventory"`
+}
+
+// CreateReservationParams converts quantity from unit, when given, with
+// units.ToStockingUnit.
+type CreateReservationParams struct {
+	ExpiresAt *time.Time `json:"expires_at" validate:"omitnil,gt"`
+	Inventory string     `json:"inventory" validate:"required,uuid"`
+	Quantity  int32      `json:"quantity" validate:"required,gt=0"`
+	Reference *string    `json:"reference" validate:"omitnil"`
+	Unit      *string    `json:"unit" validate:"omitnil,uuid"`
+	Expand    []string   `json:"expand" validate:"omitnil,dive,oneof=inventory"`
+}
+
+type ListReservationsParams struct {
+	*database.PaginationParams
+	CreatedAt *database.TimeRange         `json:"created_at" validate:"omitnil"`
+	Inventory *string                     `json:"inventory" validate:"omitnil,uuid"`
+	Reference *string                     `json:"reference" validate:"omitnil"`
+	Status    *database.ReservationStatus `json:"status" validate:"omitnil,oneof=active released committed expired"`
+	UpdatedAt *database.TimeRange         `json:"updated_at" validate:"omitnil"`
+	Expand    []string                    `json:"expand" validate:"omitnil,dive,oneof=inventory"`
+}
+
+type ReleaseReservationParams struct {
+	Expand []string `json:"expand" validate:"omitnil,dive,oneof=inventory"`
+}
+
+type RetrieveReservationParams struct {
+	Expand []string `json:"expand" validate:"omitnil,dive,oneof=inventory"`
+}
+
+func NewListReservationsParams() ListReservationsParams {
+	limit := int32(10)
+	return ListReservationsParams{
+		PaginationParams: &database.PaginationParams{
+			Limit: &limit,
+		},
+	}
+}
@@ -0,0 +1,22 @@
+package reservations
+
+import (
+	"time"
+
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/d-darac/inventory-assets/str"
+	"github.com/google/uuid"
+)
+
+type Reservation struct {
+	ID        *uuid.UUID                 `json:"id,omitempty"`
+	CreatedAt *time.Time                 `json:"created_at,omitempty"`
+	UpdatedAt *time.Time                 `json:"updated_at,omitempty"`
+	ExpiresAt *time.Time                 `json:"expires_at"`
+	Inventory api.Expandable             `json:"inventory"`
+	Parent    api.Expandable             `json:"parent"`
+	Quantity  int32                      `json:"quantity"`
+	Reference str.NullString             `json:"reference"`
+	Status    database.ReservationStatus `json:"status"`
+}
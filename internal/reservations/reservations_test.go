@@ -0,0 +1,271 @@
+package reservations
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/d-darac/inventory-api/internal/testutil"
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+func TestUnitCheckActive(t *testing.T) {
+	id := uuid.New()
+	past := sql.NullTime{Time: time.Now().Add(-time.Minute), Valid: true}
+	future := sql.NullTime{Time: time.Now().Add(time.Minute), Valid: true}
+
+	tests := []struct {
+		name      string
+		status    database.ReservationStatus
+		expiresAt sql.NullTime
+		ok        bool
+	}{
+		{name: "active", status: database.ReservationStatusActive, ok: true},
+		{name: "active until later", status: database.ReservationStatusActive, expiresAt: future, ok: true},
+		{name: "active past expiry", status: database.ReservationStatusActive, expiresAt: past},
+		{name: "released", status: database.ReservationStatusReleased},
+		{name: "committed", status: database.ReservationStatusCommitted},
+		{name: "expired", status: database.ReservationStatusExpired},
+	}
+
+	for _, tt := range tests {
+		err := checkActive(id, tt.status, tt.expiresAt)
+		if tt.ok && err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if !tt.ok {
+			if _, ok := err.(*api.AppError); !ok {
+				t.Fatalf("%s: expected an invalid request error, got %v", tt.name, err)
+			}
+		}
+	}
+}
+
+func TestUnitCheckNotComponent(t *testing.T) {
+	id := uuid.New()
+	parent := uuid.New()
+
+	if err := checkNotComponent(id, uuid.NullUUID{}, "committed"); err != nil {
+		t.Fatalf("unexpected error for a reservation without a parent: %v", err)
+	}
+	if err := checkNotComponent(id, uuid.NullUUID{UUID: parent, Valid: true}, "committed"); err == nil {
+		t.Fatal("expected an error for a component hold")
+	}
+}
+
+func TestIntegrationHoldCommitRelease(t *testing.T) {
+	db, q, accountId := testutil.OpenAccount(t)
+	defer db.Close()
+	defer db.Exec("DELETE FROM accounts WHERE id = $1;", accountId)
+
+	s := NewReservationsService(q, db)
+	location := testutil.CreateLocation(t, q, accountId)
+	item := testutil.CreateItem(t, q, accountId, database.ItemTypePRODUCT)
+	inventory := testutil.CreateInventory(t, q, accountId, item, &location, 10)
+
+	held, err := s.Create(Create{AccountId: accountId, RequestParams: CreateReservationParams{Inventory: inventory.String(), Quantity: 4}})
+	if err != nil {
+		t.Fatalf("couldn't hold stock: %v", err)
+	}
+	testutil.AssertStock(t, q, accountId, inventory, 10, 4)
+
+	if _, err := s.Create(Create{AccountId: accountId, RequestParams: CreateReservationParams{Inventory: inventory.String(), Quantity: 7}}); err == nil {
+		t.Fatal("expected holding more than the available stock to fail")
+	}
+	testutil.AssertStock(t, q, accountId, inventory, 10, 4)
+
+	committed, err := s.Commit(Commit{AccountId: accountId, ReservationId: *held.ID})
+	if err != nil {
+		t.Fatalf("couldn't commit reservation: %v", err)
+	}
+	if committed.Status != database.ReservationStatusCommitted {
+		t.Fatalf("expected status %v, got %v", database.ReservationStatusCommitted, committed.Status)
+	}
+	testutil.AssertStock(t, q, accountId, inventory, 6, 0)
+
+	if _, err := s.Commit(Commit{AccountId: accountId, ReservationId: *held.ID}); err == nil {
+		t.Fatal("expected committing a committed reservation to fail")
+	}
+	if _, err := s.Release(Release{AccountId: accountId, ReservationId: *held.ID}); err == nil {
+		t.Fatal("expected releasing a committed reservation to fail")
+	}
+
+	held, err = s.Create(Create{AccountId: accountId, RequestParams: CreateReservationParams{Inventory: inventory.String(), Quantity: 6}})
+	if err != nil {
+		t.Fatalf("couldn't hold stock: %v", err)
+	}
+	testutil.AssertStock(t, q, accountId, inventory, 6, 6)
+
+	released, err := s.Release(Release{AccountId: accountId, ReservationId: *held.ID})
+	if err != nil {
+		t.Fatalf("couldn't release reservation: %v", err)
+	}
+	if released.Status != database.ReservationStatusReleased {
+		t.Fatalf("expected status %v, got %v", database.ReservationStatusReleased, released.Status)
+	}
+	testutil.AssertStock(t, q, accountId, inventory, 6, 0)
+}
+
+func TestIntegrationSerialized(t *testing.T) {
+	db, q, accountId := testutil.OpenAccount(t)
+	defer db.Close()
+	defer db.Exec("DELETE FROM accounts WHERE id = $1;", accountId)
+
+	s := NewReservationsService(q, db)
+	inventory := testutil.CreateInventory(t, q, accountId, testutil.CreateSerializedItem(t, q, accountId), nil, 1)
+
+	_, err := s.Create(Create{AccountId: accountId, RequestParams: CreateReservationParams{Inventory: inventory.String(), Quantity: 1}})
+	if _, ok := err.(*api.AppError); !ok {
+		t.Fatalf("expected an invalid request error for a serialized item, got %v", err)
+	}
+	testutil.AssertStock(t, q, accountId, inventory, 1, 0)
+}
+
+func TestIntegrationExpiry(t *testing.T) {
+	db, q, accountId := testutil.OpenAccount(t)
+	defer db.Close()
+	defer db.Exec("DELETE FROM accounts WHERE id = $1;", accountId)
+
+	s := NewReservationsService(q, db)
+	location := testutil.CreateLocation(t, q, accountId)
+	item := testutil.CreateItem(t, q, accountId, database.ItemTypePRODUCT)
+	inventory := testutil.CreateInventory(t, q, accountId, item, &location, 5)
+
+	expiresAt := time.Now().Add(time.Second)
+	held, err := s.Create(Create{AccountId: accountId, RequestParams: CreateReservationParams{ExpiresAt: &expiresAt, Inventory: inventory.String(), Quantity: 5}})
+	if err != nil {
+		t.Fatalf("couldn't hold stock: %v", err)
+	}
+	testutil.AssertStock(t, q, accountId, inventory, 5, 5)
+
+	time.Sleep(time.Until(expiresAt) + 100*time.Millisecond)
+
+	if _, err := s.Commit(Commit{AccountId: accountId, ReservationId: *held.ID}); err == nil {
+		t.Fatal("expected committing an expired reservation to fail")
+	}
+
+	// The next hold on the inventory releases the expired one first, so
+	// the whole stock is available again.
+	if _, err := s.Create(Create{AccountId: accountId, RequestParams: CreateReservationParams{Inventory: inventory.String(), Quantity: 5}}); err != nil {
+		t.Fatalf("couldn't hold the stock of the expired reservation: %v", err)
+	}
+	testutil.AssertStock(t, q, accountId, inventory, 5, 5)
+
+	expired, err := s.Get(Get{AccountId: accountId, ReservationId: *held.ID})
+	if err != nil {
+		t.Fatalf("error retrieving reservation: %v", err)
+	}
+	if expired.Status != database.ReservationStatusExpired {
+		t.Fatalf("expected status %v, got %v", database.ReservationStatusExpired, expired.Status)
+	}
+}
+
+func TestIntegrationBundle(t *testing.T) {
+	db, q, accountId := testutil.OpenAccount(t)
+	defer db.Close()
+	defer db.Exec("DELETE FROM accounts WHERE id = $1;", accountId)
+
+	s := NewReservationsService(q, db)
+	location := testutil.CreateLocation(t, q, accountId)
+	bundle := testutil.CreateItem(t, q, accountId, database.ItemTypeBUNDLE)
+	bundleInventory := testutil.CreateInventory(t, q, accountId, bundle, &location, 0)
+	component := testutil.CreateItem(t, q, accountId, database.ItemTypePRODUCT)
+	componentInventory := testutil.CreateInventory(t, q, accountId, component, &location, 10)
+
+	_, err := q.CreateComponent(context.Background(), database.CreateComponentParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		AccountID: accountId,
+		BundleID:  bundle,
+		ItemID:    component,
+		Quantity:  2,
+	})
+	if err != nil {
+		t.Fatalf("couldn't create test component: %v", err)
+	}
+
+	if _, err := s.Create(Create{AccountId: accountId, RequestParams: CreateReservationParams{Inventory: bundleInventory.String(), Quantity: 6}}); err == nil {
+		t.Fatal("expected holding more bundles than the components make up to fail")
+	}
+	testutil.AssertStock(t, q, accountId, componentInventory, 10, 0)
+
+	held, err := s.Create(Create{AccountId: accountId, RequestParams: CreateReservationParams{Inventory: bundleInventory.String(), Quantity: 3}})
+	if err != nil {
+		t.Fatalf("couldn't hold bundle: %v", err)
+	}
+	testutil.AssertStock(t, q, accountId, componentInventory, 10, 6)
+
+	children, err := q.ListActiveReservationsByParent(context.Background(), database.ListActiveReservationsByParentParams{
+		AccountID: accountId,
+		ParentID:  *held.ID,
+	})
+	if err != nil {
+		t.Fatalf("error listing component holds: %v", err)
+	}
+	if len(children) != 1 {
+		t.Fatalf("expected 1 component hold, got %d", len(children))
+	}
+	if _, err := s.Commit(Commit{AccountId: accountId, ReservationId: children[0].ID}); err == nil {
+		t.Fatal("expected committing a component hold on its own to fail")
+	}
+	if _, err := s.Release(Release{AccountId: accountId, ReservationId: children[0].ID}); err == nil {
+		t.Fatal("expected releasing a component hold on its own to fail")
+	}
+
+	if _, err := s.Commit(Commit{AccountId: accountId, ReservationId: *held.ID}); err != nil {
+		t.Fatalf("couldn't commit bundle reservation: %v", err)
+	}
+	testutil.AssertStock(t, q, accountId, componentInventory, 4, 0)
+
+	child, err := s.Get(Get{AccountId: accountId, ReservationId: children[0].ID})
+	if err != nil {
+		t.Fatalf("error retrieving component hold: %v", err)
+	}
+	if child.Status != database.ReservationStatusCommitted {
+		t.Fatalf("expected component hold status %v, got %v", database.ReservationStatusCommitted, child.Status)
+	}
+}
+
+func TestIntegrationBundleExpiredComponentHolds(t *testing.T) {
+	db, q, accountId := testutil.OpenAccount(t)
+	defer db.Close()
+	defer db.Exec("DELETE FROM accounts WHERE id = $1;", accountId)
+
+	s := NewReservationsService(q, db)
+	location := testutil.CreateLocation(t, q, accountId)
+	bundle := testutil.CreateItem(t, q, accountId, database.ItemTypeBUNDLE)
+	bundleInventory := testutil.CreateInventory(t, q, accountId, bundle, &location, 0)
+	component := testutil.CreateItem(t, q, accountId, database.ItemTypePRODUCT)
+	componentInventory := testutil.CreateInventory(t, q, accountId, component, &location, 4)
+
+	_, err := q.CreateComponent(context.Background(), database.CreateComponentParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		AccountID: accountId,
+		BundleID:  bundle,
+		ItemID:    component,
+		Quantity:  2,
+	})
+	if err != nil {
+		t.Fatalf("couldn't create test component: %v", err)
+	}
+
+	expiresAt := time.Now().Add(time.Second)
+	if _, err := s.Create(Create{AccountId: accountId, RequestParams: CreateReservationParams{ExpiresAt: &expiresAt, Inventory: componentInventory.String(), Quantity: 4}}); err != nil {
+		t.Fatalf("couldn't hold component stock: %v", err)
+	}
+	testutil.AssertStock(t, q, accountId, componentInventory, 4, 4)
+
+	time.Sleep(time.Until(expiresAt) + 100*time.Millisecond)
+
+	// Holding the bundle releases the expired hold on its component first.
+	if _, err := s.Create(Create{AccountId: accountId, RequestParams: CreateReservationParams{Inventory: bundleInventory.String(), Quantity: 2}}); err != nil {
+		t.Fatalf("couldn't hold the bundle over an expired component hold: %v", err)
+	}
+	testutil.AssertStock(t, q, accountId, componentInventory, 4, 4)
+}
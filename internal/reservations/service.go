@@ -0,0 +1,649 @@
+package reservations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	stockmovements "github.com/d-darac/inventory-api/internal/stock_movements"
+	"github.com/d-darac/inventory-api/internal/units"
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/d-darac/inventory-assets/str"
+	"github.com/google/uuid"
+)
+
+type ReservationsService struct {
+	Conn *sql.DB
+	Db   *database.Queries
+}
+
+type Commit struct {
+	AccountId     uuid.UUID
+	ReservationId uuid.UUID
+	RequestParams CommitReservationParams
+}
+
+type Create struct {
+	AccountId     uuid.UUID
+	RequestParams CreateReservationParams
+}
+
+type Get struct {
+	AccountId     uuid.UUID
+	ReservationId uuid.UUID
+	RequestParams RetrieveReservationParams
+	OmitBase      bool
+}
+
+type List struct {
+	AccountId     uuid.UUID
+	RequestParams ListReservationsParams
+}
+
+type Release struct {
+	AccountId     uuid.UUID
+	ReservationId uuid.UUID
+	RequestParams ReleaseReservationParams
+}
+
+func NewReservationsService(db *database.Queries, conn *sql.DB) *ReservationsService {
+	return &ReservationsService{
+		Conn: conn,
+		Db:   db,
+	}
+}
+
+func (s *ReservationsService) Commit(commit Commit) (*Reservation, error) {
+	tx, err := s.Conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.Db.WithTx(tx)
+
+	if err := checkNotOrdered(qtx, commit.AccountId, commit.ReservationId, "committed"); err != nil {
+		return nil, err
+	}
+
+	reservation, err := CommitHold(qtx, commit)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return reservation, nil
+}
+
+func (s *ReservationsService) Create(create Create) (*Reservation, error) {
+	tx, err := s.Conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	reservation, err := Reserve(s.Db.WithTx(tx), create)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return reservation, nil
+}
+
+func (s *ReservationsService) Get(get Get) (*Reservation, error) {
+	row, err := s.Db.GetReservation(context.Background(), database.GetReservationParams{
+		ID:        get.ReservationId,
+		AccountID: get.AccountId,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, api.NotFoundMessage(get.ReservationId, "reservation")
+		}
+		return nil, err
+	}
+
+	reservation := &Reservation{
+		ExpiresAt: nullTime(row.ExpiresAt),
+		Inventory: api.Expandable{ID: row.Inventory},
+		Parent:    api.Expandable{ID: row.Parent},
+		Quantity:  row.Quantity,
+		Reference: str.NullString(row.Reference),
+		Status:    row.Status,
+	}
+
+	if !get.OmitBase {
+		reservation.ID = &row.ID
+		reservation.CreatedAt = &row.CreatedAt
+		reservation.UpdatedAt = &row.UpdatedAt
+	}
+
+	return reservation, nil
+}
+
+func (s *ReservationsService) List(list List) (reservations []*Reservation, hasMore bool, err error) {
+	if list.RequestParams.StartingAfter != nil {
+		reservation, err := s.Get(Get{
+			AccountId:     list.AccountId,
+			ReservationId: *list.RequestParams.StartingAfter,
+			RequestParams: RetrieveReservationParams{},
+			OmitBase:      false,
+		})
+		if err != nil {
+			return reservations, hasMore, err
+		}
+		list.RequestParams.StartingAfterDate = reservation.CreatedAt
+	}
+
+	if list.RequestParams.EndingBefore != nil {
+		reservation, err := s.Get(Get{
+			AccountId:     list.AccountId,
+			ReservationId: *list.RequestParams.EndingBefore,
+			RequestParams: RetrieveReservationParams{},
+			OmitBase:      false,
+		})
+		if err != nil {
+			return reservations, hasMore, err
+		}
+		list.RequestParams.EndingBeforeDate = reservation.CreatedAt
+	}
+
+	dbParams := MapListReservationsParams(list)
+
+	rows, err := s.Db.ListReservations(context.Background(), dbParams)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return reservations, hasMore, nil
+		}
+		return
+	}
+
+	if dbParams.Limit.Valid {
+		hasMore = len(rows) > int(dbParams.Limit.Int32)
+	} else {
+		hasMore = len(rows) > 10
+	}
+
+	if hasMore {
+		if dbParams.EndingBefore.Valid {
+			rows = rows[1:]
+		} else {
+			rows = rows[:len(rows)-1]
+		}
+	}
+
+	for _, row := range rows {
+		reservations = append(reservations, &Reservation{
+			ID:        &row.ID,
+			CreatedAt: &row.CreatedAt,
+			UpdatedAt: &row.UpdatedAt,
+			ExpiresAt: nullTime(row.ExpiresAt),
+			Inventory: api.Expandable{ID: row.Inventory},
+			Parent:    api.Expandable{ID: row.Parent},
+			Quantity:  row.Quantity,
+			Reference: str.NullString(row.Reference),
+			Status:    row.Status,
+		})
+	}
+
+	return reservations, hasMore, err
+}
+
+func (s *ReservationsService) Release(release Release) (*Reservation, error) {
+	tx, err := s.Conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.Db.WithTx(tx)
+
+	if err := checkNotOrdered(qtx, release.AccountId, release.ReservationId, "released"); err != nil {
+		return nil, err
+	}
+
+	reservation, err := ReleaseHold(qtx, release)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return reservation, nil
+}
+
+// ReleaseExpired releases the holds of every active reservation whose
+// expires_at has passed, across all accounts.
+func (s *ReservationsService) ReleaseExpired() error {
+	tx, err := s.Conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = releaseExpired(s.Db.WithTx(tx), database.ExpireReservationsParams{
+		ExpiresAt:   time.Now(),
+		AccountID:   api.NullUUID(nil),
+		InventoryID: api.NullUUID(nil),
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Reserve holds stock of an inventory for a reservation. The inventory row
+// is locked for the duration of the call, so q should be bound to a
+// transaction that the caller commits.
+func Reserve(q *database.Queries, create Create) (*Reservation, error) {
+	inventoryId := uuid.MustParse(create.RequestParams.Inventory)
+
+	if err := releaseExpired(q, database.ExpireReservationsParams{
+		ExpiresAt:   time.Now(),
+		AccountID:   api.NullUUID(&create.AccountId),
+		InventoryID: api.NullUUID(&inventoryId),
+	}); err != nil {
+		return nil, err
+	}
+
+	inventory, err := q.GetInventoryForUpdate(context.Background(), database.GetInventoryForUpdateParams{
+		ID:        inventoryId,
+		AccountID: create.AccountId,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, api.NotFoundMessage(inventoryId, "inventory")
+		}
+		return nil, err
+	}
+
+	if create.RequestParams.Unit != nil {
+		unitId := uuid.MustParse(*create.RequestParams.Unit)
+		quantity, err := units.ToStockingUnit(q, create.AccountId, inventory.Item, unitId, create.RequestParams.Quantity)
+		if err != nil {
+			return nil, err
+		}
+		create.RequestParams.Quantity = quantity
+	}
+
+	bundle, err := q.IsInventoryBundle(context.Background(), database.IsInventoryBundleParams{
+		ID:        inventoryId,
+		AccountID: create.AccountId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !bundle {
+		if err := checkNotSerialized(q, create.AccountId, inventoryId); err != nil {
+			return nil, err
+		}
+	}
+
+	// A bundle's inventory has no stock of its own to check, the components
+	// reserved below are checked instead.
+	if !bundle && inventory.InStock-inventory.Reserved.Int32 < create.RequestParams.Quantity {
+		return nil, stockmovements.InsufficientStockMessage(inventoryId)
+	}
+
+	dbParams := MapCreateReservationParams(create)
+
+	row, err := q.CreateReservation(context.Background(), dbParams)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = q.AdjustInventoryReserved(context.Background(), database.AdjustInventoryReservedParams{
+		UpdatedAt: row.CreatedAt,
+		AccountID: create.AccountId,
+		ID:        inventoryId,
+		Quantity:  row.Quantity,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if bundle {
+		if err := reserveComponents(q, create.AccountId, row); err != nil {
+			return nil, err
+		}
+	}
+
+	reservation := &Reservation{
+		ID:        &row.ID,
+		CreatedAt: &row.CreatedAt,
+		UpdatedAt: &row.UpdatedAt,
+		ExpiresAt: nullTime(row.ExpiresAt),
+		Inventory: api.Expandable{ID: row.Inventory},
+		Parent:    api.Expandable{ID: row.Parent},
+		Quantity:  row.Quantity,
+		Reference: str.NullString(row.Reference),
+		Status:    row.Status,
+	}
+
+	return reservation, nil
+}
+
+// CommitHold turns an active reservation into a sale of the held stock. Like
+// Reserve, it expects q to be bound to a transaction.
+func CommitHold(q *database.Queries, commit Commit) (*Reservation, error) {
+	row, err := q.GetReservationForUpdate(context.Background(), database.GetReservationForUpdateParams{
+		ID:        commit.ReservationId,
+		AccountID: commit.AccountId,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, api.NotFoundMessage(commit.ReservationId, "reservation")
+		}
+		return nil, err
+	}
+
+	if err := checkActive(row.ID, row.Status, row.ExpiresAt); err != nil {
+		return nil, err
+	}
+	if err := checkNotComponent(row.ID, row.Parent, "committed"); err != nil {
+		return nil, err
+	}
+
+	reservation, err := finish(q, commit.AccountId, row.ID, database.ReservationStatusCommitted)
+	if err != nil {
+		return nil, err
+	}
+
+	// The sale below is posted to the components, so their holds go too.
+	if err := finishComponents(q, commit.AccountId, row.ID, database.ReservationStatusCommitted); err != nil {
+		return nil, err
+	}
+
+	reference := row.ID.String()
+	_, err = stockmovements.Post(q, stockmovements.Create{
+		AccountId: commit.AccountId,
+		RequestParams: stockmovements.CreateStockMovementParams{
+			Inventory: row.Inventory.UUID.String(),
+			Quantity:  -row.Quantity,
+			Reference: &reference,
+			Type:      database.StockMovementTypeSale,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reservation, nil
+}
+
+// ReleaseHold gives the stock held by an active reservation back. Like
+// Reserve, it expects q to be bound to a transaction.
+func ReleaseHold(q *database.Queries, release Release) (*Reservation, error) {
+	row, err := q.GetReservationForUpdate(context.Background(), database.GetReservationForUpdateParams{
+		ID:        release.ReservationId,
+		AccountID: release.AccountId,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, api.NotFoundMessage(release.ReservationId, "reservation")
+		}
+		return nil, err
+	}
+
+	if row.Status != database.ReservationStatusActive {
+		return nil, invalidStatusMessage(row.ID, row.Status, "released")
+	}
+	if err := checkNotComponent(row.ID, row.Parent, "released"); err != nil {
+		return nil, err
+	}
+
+	reservation, err := finish(q, release.AccountId, row.ID, database.ReservationStatusReleased)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := finishComponents(q, release.AccountId, row.ID, database.ReservationStatusReleased); err != nil {
+		return nil, err
+	}
+
+	return reservation, nil
+}
+
+// finish moves an active reservation to its final status and gives its
+// quantity back to the inventory's reserved.
+func finish(q *database.Queries, accountId, reservationId uuid.UUID, status database.ReservationStatus) (*Reservation, error) {
+	row, err := q.UpdateReservationStatus(context.Background(), database.UpdateReservationStatusParams{
+		UpdatedAt: time.Now(),
+		AccountID: accountId,
+		ID:        reservationId,
+		Status:    status,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = q.AdjustInventoryReserved(context.Background(), database.AdjustInventoryReservedParams{
+		UpdatedAt: row.UpdatedAt,
+		AccountID: accountId,
+		ID:        row.Inventory.UUID,
+		Quantity:  -row.Quantity,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reservation := &Reservation{
+		ID:        &row.ID,
+		CreatedAt: &row.CreatedAt,
+		UpdatedAt: &row.UpdatedAt,
+		ExpiresAt: nullTime(row.ExpiresAt),
+		Inventory: api.Expandable{ID: row.Inventory},
+		Parent:    api.Expandable{ID: row.Parent},
+		Quantity:  row.Quantity,
+		Reference: str.NullString(row.Reference),
+		Status:    row.Status,
+	}
+
+	return reservation, nil
+}
+
+// reserveComponents holds the components of a reserved bundle in their
+// inventories at the bundle's location. The holds are reservations of their
+// own with the bundle's reservation as parent, so they expire with it and
+// are committed or released through it. As in Reserve, expired holds on each
+// component inventory are released before its stock is checked.
+func reserveComponents(q *database.Queries, accountId uuid.UUID, parent database.CreateReservationRow) error {
+	components, err := q.ListBundleComponentInventories(context.Background(), database.ListBundleComponentInventoriesParams{
+		AccountID:   accountId,
+		InventoryID: parent.Inventory.UUID,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, component := range components {
+		if !component.Inventory.Valid {
+			return &api.AppError{
+				Message: fmt.Sprintf("Component item '%v' has no inventory at the location of bundle inventory '%v'.", component.Item, parent.Inventory.UUID),
+				Status:  http.StatusBadRequest,
+				Type:    api.InvalidRequestError,
+			}
+		}
+
+		if err := releaseExpired(q, database.ExpireReservationsParams{
+			ExpiresAt:   time.Now(),
+			AccountID:   api.NullUUID(&accountId),
+			InventoryID: component.Inventory,
+		}); err != nil {
+			return err
+		}
+
+		inventory, err := q.GetInventoryForUpdate(context.Background(), database.GetInventoryForUpdateParams{
+			ID:        component.Inventory.UUID,
+			AccountID: accountId,
+		})
+		if err != nil {
+			return err
+		}
+		if err := checkNotSerialized(q, accountId, inventory.ID); err != nil {
+			return err
+		}
+
+		quantity := parent.Quantity * component.Quantity
+		if inventory.InStock-inventory.Reserved.Int32 < quantity {
+			return stockmovements.InsufficientStockMessage(inventory.ID)
+		}
+
+		row, err := q.CreateReservation(context.Background(), database.CreateReservationParams{
+			ID:          uuid.New(),
+			CreatedAt:   parent.CreatedAt,
+			UpdatedAt:   parent.UpdatedAt,
+			AccountID:   accountId,
+			ExpiresAt:   parent.ExpiresAt,
+			InventoryID: inventory.ID,
+			ParentID:    api.NullUUID(&parent.ID),
+			Quantity:    quantity,
+			Reference:   parent.Reference,
+			Status:      database.ReservationStatusActive,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = q.AdjustInventoryReserved(context.Background(), database.AdjustInventoryReservedParams{
+			UpdatedAt: row.CreatedAt,
+			AccountID: accountId,
+			ID:        inventory.ID,
+			Quantity:  row.Quantity,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// finishComponents moves the component holds of a bundle's reservation to
+// the same final status as the reservation itself.
+func finishComponents(q *database.Queries, accountId, parentId uuid.UUID, status database.ReservationStatus) error {
+	rows, err := q.ListActiveReservationsByParent(context.Background(), database.ListActiveReservationsByParentParams{
+		AccountID: accountId,
+		ParentID:  parentId,
+	})
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	for _, row := range rows {
+		if _, err := finish(q, accountId, row.ID, status); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func checkNotComponent(reservationId uuid.UUID, parent uuid.NullUUID, action string) error {
+	if !parent.Valid {
+		return nil
+	}
+	return &api.AppError{
+		Message: fmt.Sprintf("Reservation '%v' holds a component of bundle reservation '%v' and can only be %s through it.", reservationId, parent.UUID, action),
+		Status:  http.StatusBadRequest,
+		Type:    api.InvalidRequestError,
+	}
+}
+
+// checkNotSerialized keeps serialized items out of reservations, since
+// committing one posts a plain sale and their stock only goes out through
+// serial numbers.
+func checkNotSerialized(q *database.Queries, accountId, inventoryId uuid.UUID) error {
+	serialized, err := q.IsInventorySerialized(context.Background(), database.IsInventorySerializedParams{
+		ID:        inventoryId,
+		AccountID: accountId,
+	})
+	if err != nil {
+		return err
+	}
+	if serialized {
+		return &api.AppError{
+			Message: fmt.Sprintf("Inventory '%v' is of a serialized item, which is sold through its serial numbers rather than a reservation.", inventoryId),
+			Status:  http.StatusBadRequest,
+			Type:    api.InvalidRequestError,
+		}
+	}
+	return nil
+}
+
+// checkNotOrdered keeps the holds placed by confirming an order from being
+// finished on their own, which would leave the order unable to be fulfilled
+// or canceled.
+func checkNotOrdered(q *database.Queries, accountId, reservationId uuid.UUID, action string) error {
+	orderId, err := q.GetReservationOrder(context.Background(), database.GetReservationOrderParams{
+		AccountID:     accountId,
+		ReservationID: reservationId,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		return err
+	}
+	return &api.AppError{
+		Message: fmt.Sprintf("Reservation '%v' holds stock for order '%v' and can only be %s through it.", reservationId, orderId, action),
+		Status:  http.StatusBadRequest,
+		Type:    api.InvalidRequestError,
+	}
+}
+
+func releaseExpired(q *database.Queries, params database.ExpireReservationsParams) error {
+	rows, err := q.ExpireReservations(context.Background(), params)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		_, err := q.AdjustInventoryReserved(context.Background(), database.AdjustInventoryReservedParams{
+			UpdatedAt: params.ExpiresAt,
+			AccountID: row.AccountID,
+			ID:        row.Inventory.UUID,
+			Quantity:  -row.Quantity,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func checkActive(reservationId uuid.UUID, status database.ReservationStatus, expiresAt sql.NullTime) error {
+	if status != database.ReservationStatusActive {
+		return invalidStatusMessage(reservationId, status, "committed")
+	}
+	if expiresAt.Valid && time.Now().After(expiresAt.Time) {
+		return invalidStatusMessage(reservationId, database.ReservationStatusExpired, "committed")
+	}
+	return nil
+}
+
+func invalidStatusMessage(reservationId uuid.UUID, status database.ReservationStatus, action string) error {
+	return &api.AppError{
+		Message: fmt.Sprintf("Reservation '%v' is %s and can't be %s.", reservationId, status, action),
+		Status:  http.StatusBadRequest,
+		Type:    api.InvalidRequestError,
+	}
+}
+
+func nullTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
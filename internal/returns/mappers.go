@@ -0,0 +1,76 @@
+package returns
+
+import (
+	"time"
+
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+func MapCreateReturnParams(create Create, itemId, inventoryId uuid.UUID) database.CreateReturnParams {
+	t := time.Now()
+	crp := database.CreateReturnParams{
+		ID:          uuid.New(),
+		CreatedAt:   t,
+		UpdatedAt:   t,
+		AccountID:   create.AccountId,
+		InventoryID: inventoryId,
+		ItemID:      itemId,
+		Note:        api.NullString(create.RequestParams.Note),
+		OrderID:     api.NullUUID(nil),
+		Quantity:    create.RequestParams.Quantity,
+		Reason:      create.RequestParams.Reason,
+		Status:      database.ReturnStatusPending,
+	}
+	if create.RequestParams.Order != nil {
+		orderId := uuid.MustParse(*create.RequestParams.Order)
+		crp.OrderID = api.NullUUID(&orderId)
+	}
+	return crp
+}
+
+func MapListReturnsParams(list List) database.ListReturnsParams {
+	lrp := database.ListReturnsParams{
+		AccountID: list.AccountId,
+		ItemID:    api.NullUUID(nil),
+		OrderID:   api.NullUUID(nil),
+	}
+	if list.RequestParams.Item != nil {
+		itemId := uuid.MustParse(*list.RequestParams.Item)
+		lrp.ItemID = api.NullUUID(&itemId)
+	}
+	if list.RequestParams.Order != nil {
+		orderId := uuid.MustParse(*list.RequestParams.Order)
+		lrp.OrderID = api.NullUUID(&orderId)
+	}
+	if list.RequestParams.Reason != nil {
+		lrp.Reason = database.NullReturnReason{
+			ReturnReason: *list.RequestParams.Reason,
+			Valid:        true,
+		}
+	}
+	if list.RequestParams.Status != nil {
+		lrp.Status = database.NullReturnStatus{
+			ReturnStatus: *list.RequestParams.Status,
+			Valid:        true,
+		}
+	}
+	database.MapTimeRange(list.RequestParams.CreatedAt, &lrp.CreatedAtGt, &lrp.CreatedAtGte, &lrp.CreatedAtLt, &lrp.CreatedAtLte)
+	database.MapTimeRange(list.RequestParams.UpdatedAt, &lrp.UpdatedAtGt, &lrp.UpdatedAtGte, &lrp.UpdatedAtLt, &lrp.UpdatedAtLte)
+	database.MapPaginationParams(*list.RequestParams.PaginationParams, &lrp)
+	return lrp
+}
+
+func MapSummarizeReturnReasonsParams(summarize Summarize) database.SummarizeReturnReasonsParams {
+	srrp := database.SummarizeReturnReasonsParams{
+		AccountID: summarize.AccountId,
+		ItemID:    api.NullUUID(nil),
+	}
+	if summarize.RequestParams.Item != nil {
+		itemId := uuid.MustParse(*summarize.RequestParams.Item)
+		srrp.ItemID = api.NullUUID(&itemId)
+	}
+	database.MapTimeRange(summarize.RequestParams.CreatedAt, &srrp.CreatedAtGt, &srrp.CreatedAtGte, &srrp.CreatedAtLt, &srrp.CreatedAtLte)
+	return srrp
+}
@@ -0,0 +1,61 @@
+package returns
+
+import (
+	"github.com/d-darac/inventory-assets/database"
+)
+
+// CreateReturnParams creates a return of an item, or of one of the items of
+// an order. The inventory defaults to the one the item was sold from.
+type CreateReturnParams struct {
+	Inventory *string               `json:"inventory" validate:"omitnil,uuid"`
+	Item      *string               `json:"item" validate:"omitnil,uuid,required_without=Order"`
+	Note      *string               `json:"note" validate:"omitnil"`
+	Order     *string               `json:"order" validate:"omitnil,uuid"`
+	Quantity  int32                 `json:"quantity" validate:"required,gt=0"`
+	Reason    database.ReturnReason `json:"reason" validate:"required,oneof=damaged defective not_as_described wrong_item unwanted other"`
+	Expand    []string              `json:"expand" validate:"omitnil,dive,oneof=inventory item order"`
+}
+
+type InspectReturnParams struct {
+	InspectionNote *string  `json:"inspection_note" validate:"omitnil"`
+	Expand         []string `json:"expand" validate:"omitnil,dive,oneof=inventory item order"`
+}
+
+type ListReturnsParams struct {
+	*database.PaginationParams
+	CreatedAt *database.TimeRange    `json:"created_at" validate:"omitnil"`
+	Item      *string                `json:"item" validate:"omitnil,uuid"`
+	Order     *string                `json:"order" validate:"omitnil,uuid"`
+	Reason    *database.ReturnReason `json:"reason" validate:"omitnil,oneof=damaged defective not_as_described wrong_item unwanted other"`
+	Status    *database.ReturnStatus `json:"status" validate:"omitnil,oneof=pending inspected restocked scrapped"`
+	UpdatedAt *database.TimeRange    `json:"updated_at" validate:"omitnil"`
+	Expand    []string               `json:"expand" validate:"omitnil,dive,oneof=inventory item"`
+}
+
+type RestockReturnParams struct {
+	Lot    *string  `json:"lot" validate:"omitnil,uuid"`
+	Expand []string `json:"expand" validate:"omitnil,dive,oneof=inventory item order"`
+}
+
+type RetrieveReturnParams struct {
+	Expand []string `json:"expand" validate:"omitnil,dive,oneof=inventory item order"`
+}
+
+type RetrieveReturnsReportParams struct {
+	CreatedAt *database.TimeRange `json:"created_at" validate:"omitnil"`
+	Item      *string             `json:"item" validate:"omitnil,uuid"`
+}
+
+type ScrapReturnParams struct {
+	Lot    *string  `json:"lot" validate:"omitnil,uuid"`
+	Expand []string `json:"expand" validate:"omitnil,dive,oneof=inventory item order"`
+}
+
+func NewListReturnsParams() ListReturnsParams {
+	limit := int32(10)
+	return ListReturnsParams{
+		PaginationParams: &database.PaginationParams{
+			Limit: &limit,
+		},
+	}
+}
@@ -0,0 +1,42 @@
+package returns
+
+import (
+	"time"
+
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/d-darac/inventory-assets/str"
+	"github.com/google/uuid"
+)
+
+// Return is stock sent back by a customer. It's held in quarantine, outside
+// of the inventory's in_stock, until it has been inspected and either
+// restocked into the inventory or scrapped.
+type Return struct {
+	ID             *uuid.UUID            `json:"id,omitempty"`
+	CreatedAt      *time.Time            `json:"created_at,omitempty"`
+	UpdatedAt      *time.Time            `json:"updated_at,omitempty"`
+	InspectedAt    *time.Time            `json:"inspected_at"`
+	InspectionNote str.NullString        `json:"inspection_note"`
+	Inventory      api.Expandable        `json:"inventory"`
+	Item           api.Expandable        `json:"item"`
+	Note           str.NullString        `json:"note"`
+	Order          api.Expandable        `json:"order"`
+	Quantity       int32                 `json:"quantity"`
+	Reason         database.ReturnReason `json:"reason"`
+	ResolvedAt     *time.Time            `json:"resolved_at"`
+	Status         database.ReturnStatus `json:"status"`
+}
+
+// Report counts the returns of each reason and how they were resolved.
+type Report struct {
+	Reasons []*ReasonSummary `json:"reasons"`
+}
+
+type ReasonSummary struct {
+	Quantity  int64                 `json:"quantity"`
+	Reason    database.ReturnReason `json:"reason"`
+	Restocked int64                 `json:"restocked"`
+	Returns   int64                 `json:"returns"`
+	Scrapped  int64                 `json:"scrapped"`
+}
@@ -0,0 +1,57 @@
+package returns
+
+import (
+	"testing"
+
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+func TestUnitMatchLine(t *testing.T) {
+	shirt := uuid.New()
+	mug := uuid.New()
+	shirtId := shirt.String()
+	mugId := mug.String()
+	other := uuid.NewString()
+
+	single := []database.ListOrderLinesRow{
+		{Item: uuid.NullUUID{UUID: shirt, Valid: true}, Quantity: 2},
+		{Item: uuid.NullUUID{UUID: shirt, Valid: true}, Quantity: 3},
+	}
+	mixed := []database.ListOrderLinesRow{
+		{Item: uuid.NullUUID{UUID: shirt, Valid: true}, Quantity: 2},
+		{Item: uuid.NullUUID{UUID: mug, Valid: true}, Quantity: 1},
+	}
+
+	tests := []struct {
+		name     string
+		lines    []database.ListOrderLinesRow
+		item     *string
+		wantItem uuid.UUID
+		wantSold int32
+		wantErr  bool
+	}{
+		{name: "single item without item", lines: single, wantItem: shirt, wantSold: 5},
+		{name: "single item with item", lines: single, item: &shirtId, wantItem: shirt, wantSold: 5},
+		{name: "mixed items without item", lines: mixed, wantErr: true},
+		{name: "mixed items with item", lines: mixed, item: &mugId, wantItem: mug, wantSold: 1},
+		{name: "item not on order", lines: mixed, item: &other, wantErr: true},
+		{name: "no lines", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		line, sold, err := matchLine(tt.lines, tt.item)
+		if tt.wantErr {
+			if err == nil {
+				t.Fatalf("%s: expected an error", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if line.Item.UUID != tt.wantItem || sold != tt.wantSold {
+			t.Fatalf("%s: expected item %v with %d sold, got %v with %d", tt.name, tt.wantItem, tt.wantSold, line.Item.UUID, sold)
+		}
+	}
+}
@@ -0,0 +1,588 @@
+package returns
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	stockmovements "github.com/d-darac/inventory-api/internal/stock_movements"
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/d-darac/inventory-assets/str"
+	"github.com/google/uuid"
+)
+
+type ReturnsService struct {
+	Conn *sql.DB
+	Db   *database.Queries
+}
+
+type Create struct {
+	AccountId     uuid.UUID
+	RequestParams CreateReturnParams
+}
+
+type Get struct {
+	AccountId     uuid.UUID
+	ReturnId      uuid.UUID
+	RequestParams RetrieveReturnParams
+	OmitBase      bool
+}
+
+type Inspect struct {
+	AccountId     uuid.UUID
+	ReturnId      uuid.UUID
+	RequestParams InspectReturnParams
+}
+
+type List struct {
+	AccountId     uuid.UUID
+	RequestParams ListReturnsParams
+}
+
+type Restock struct {
+	AccountId     uuid.UUID
+	ReturnId      uuid.UUID
+	RequestParams RestockReturnParams
+}
+
+type Scrap struct {
+	AccountId     uuid.UUID
+	ReturnId      uuid.UUID
+	RequestParams ScrapReturnParams
+}
+
+type Summarize struct {
+	AccountId     uuid.UUID
+	RequestParams RetrieveReturnsReportParams
+}
+
+func NewReturnsService(db *database.Queries, conn *sql.DB) *ReturnsService {
+	return &ReturnsService{
+		Conn: conn,
+		Db:   db,
+	}
+}
+
+func (s *ReturnsService) Create(create Create) (*Return, error) {
+	itemId, inventoryId, err := s.resolve(create.AccountId, create.RequestParams)
+	if err != nil {
+		return nil, err
+	}
+
+	dbParams := MapCreateReturnParams(create, itemId, inventoryId)
+
+	row, err := s.Db.CreateReturn(context.Background(), dbParams)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := &Return{
+		ID:             &row.ID,
+		CreatedAt:      &row.CreatedAt,
+		UpdatedAt:      &row.UpdatedAt,
+		InspectedAt:    nullTime(row.InspectedAt),
+		InspectionNote: str.NullString(row.InspectionNote),
+		Inventory:      api.Expandable{ID: row.Inventory},
+		Item:           api.Expandable{ID: row.Item},
+		Note:           str.NullString(row.Note),
+		Order:          api.Expandable{ID: row.Order},
+		Quantity:       row.Quantity,
+		Reason:         row.Reason,
+		ResolvedAt:     nullTime(row.ResolvedAt),
+		Status:         row.Status,
+	}
+
+	return ret, nil
+}
+
+func (s *ReturnsService) Get(get Get) (*Return, error) {
+	row, err := s.Db.GetReturn(context.Background(), database.GetReturnParams{
+		ID:        get.ReturnId,
+		AccountID: get.AccountId,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, api.NotFoundMessage(get.ReturnId, "return")
+		}
+		return nil, err
+	}
+
+	ret := &Return{
+		InspectedAt:    nullTime(row.InspectedAt),
+		InspectionNote: str.NullString(row.InspectionNote),
+		Inventory:      api.Expandable{ID: row.Inventory},
+		Item:           api.Expandable{ID: row.Item},
+		Note:           str.NullString(row.Note),
+		Order:          api.Expandable{ID: row.Order},
+		Quantity:       row.Quantity,
+		Reason:         row.Reason,
+		ResolvedAt:     nullTime(row.ResolvedAt),
+		Status:         row.Status,
+	}
+
+	if !get.OmitBase {
+		ret.ID = &row.ID
+		ret.CreatedAt = &row.CreatedAt
+		ret.UpdatedAt = &row.UpdatedAt
+	}
+
+	return ret, nil
+}
+
+// Inspect records the outcome of looking the returned stock over. It stays
+// in quarantine until it's restocked or scrapped.
+func (s *ReturnsService) Inspect(inspect Inspect) (*Return, error) {
+	tx, err := s.Conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.Db.WithTx(tx)
+
+	if _, err := getForUpdate(qtx, inspect.AccountId, inspect.ReturnId, database.ReturnStatusPending); err != nil {
+		return nil, err
+	}
+
+	row, err := qtx.InspectReturn(context.Background(), database.InspectReturnParams{
+		UpdatedAt:      time.Now(),
+		AccountID:      inspect.AccountId,
+		ID:             inspect.ReturnId,
+		InspectionNote: api.NullString(inspect.RequestParams.InspectionNote),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	ret := &Return{
+		ID:             &row.ID,
+		CreatedAt:      &row.CreatedAt,
+		UpdatedAt:      &row.UpdatedAt,
+		InspectedAt:    nullTime(row.InspectedAt),
+		InspectionNote: str.NullString(row.InspectionNote),
+		Inventory:      api.Expandable{ID: row.Inventory},
+		Item:           api.Expandable{ID: row.Item},
+		Note:           str.NullString(row.Note),
+		Order:          api.Expandable{ID: row.Order},
+		Quantity:       row.Quantity,
+		Reason:         row.Reason,
+		ResolvedAt:     nullTime(row.ResolvedAt),
+		Status:         row.Status,
+	}
+
+	return ret, nil
+}
+
+func (s *ReturnsService) List(list List) (rets []*Return, hasMore bool, err error) {
+	if list.RequestParams.StartingAfter != nil {
+		ret, err := s.Get(Get{
+			AccountId:     list.AccountId,
+			ReturnId:      *list.RequestParams.StartingAfter,
+			RequestParams: RetrieveReturnParams{},
+			OmitBase:      false,
+		})
+		if err != nil {
+			return rets, hasMore, err
+		}
+		list.RequestParams.StartingAfterDate = ret.CreatedAt
+	}
+
+	if list.RequestParams.EndingBefore != nil {
+		ret, err := s.Get(Get{
+			AccountId:     list.AccountId,
+			ReturnId:      *list.RequestParams.EndingBefore,
+			RequestParams: RetrieveReturnParams{},
+			OmitBase:      false,
+		})
+		if err != nil {
+			return rets, hasMore, err
+		}
+		list.RequestParams.EndingBeforeDate = ret.CreatedAt
+	}
+
+	dbParams := MapListReturnsParams(list)
+
+	rows, err := s.Db.ListReturns(context.Background(), dbParams)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return rets, hasMore, nil
+		}
+		return
+	}
+
+	if dbParams.Limit.Valid {
+		hasMore = len(rows) > int(dbParams.Limit.Int32)
+	} else {
+		hasMore = len(rows) > 10
+	}
+
+	if hasMore {
+		if dbParams.EndingBefore.Valid {
+			rows = rows[1:]
+		} else {
+			rows = rows[:len(rows)-1]
+		}
+	}
+
+	for _, row := range rows {
+		rets = append(rets, &Return{
+			ID:             &row.ID,
+			CreatedAt:      &row.CreatedAt,
+			UpdatedAt:      &row.UpdatedAt,
+			InspectedAt:    nullTime(row.InspectedAt),
+			InspectionNote: str.NullString(row.InspectionNote),
+			Inventory:      api.Expandable{ID: row.Inventory},
+			Item:           api.Expandable{ID: row.Item},
+			Note:           str.NullString(row.Note),
+			Order:          api.Expandable{ID: row.Order},
+			Quantity:       row.Quantity,
+			Reason:         row.Reason,
+			ResolvedAt:     nullTime(row.ResolvedAt),
+			Status:         row.Status,
+		})
+	}
+
+	return rets, hasMore, err
+}
+
+// Restock puts inspected stock back into the return's inventory.
+func (s *ReturnsService) Restock(restock Restock) (*Return, error) {
+	tx, err := s.Conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.Db.WithTx(tx)
+
+	current, err := getForUpdate(qtx, restock.AccountId, restock.ReturnId, database.ReturnStatusInspected)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := postReturn(qtx, restock.AccountId, current, restock.RequestParams.Lot); err != nil {
+		return nil, err
+	}
+
+	row, err := qtx.ResolveReturn(context.Background(), database.ResolveReturnParams{
+		UpdatedAt: time.Now(),
+		AccountID: restock.AccountId,
+		ID:        restock.ReturnId,
+		Status:    database.ReturnStatusRestocked,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	ret := &Return{
+		ID:             &row.ID,
+		CreatedAt:      &row.CreatedAt,
+		UpdatedAt:      &row.UpdatedAt,
+		InspectedAt:    nullTime(row.InspectedAt),
+		InspectionNote: str.NullString(row.InspectionNote),
+		Inventory:      api.Expandable{ID: row.Inventory},
+		Item:           api.Expandable{ID: row.Item},
+		Note:           str.NullString(row.Note),
+		Order:          api.Expandable{ID: row.Order},
+		Quantity:       row.Quantity,
+		Reason:         row.Reason,
+		ResolvedAt:     nullTime(row.ResolvedAt),
+		Status:         row.Status,
+	}
+
+	return ret, nil
+}
+
+// Scrap writes inspected stock off. The stock is booked back into the
+// inventory and written off again in the same transaction, so the loss shows
+// in the ledger while in_stock is left as it was.
+func (s *ReturnsService) Scrap(scrap Scrap) (*Return, error) {
+	tx, err := s.Conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.Db.WithTx(tx)
+
+	current, err := getForUpdate(qtx, scrap.AccountId, scrap.ReturnId, database.ReturnStatusInspected)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := postReturn(qtx, scrap.AccountId, current, scrap.RequestParams.Lot); err != nil {
+		return nil, err
+	}
+
+	reason := string(current.Reason)
+	reference := current.ID.String()
+	_, err = stockmovements.Post(qtx, stockmovements.Create{
+		AccountId: scrap.AccountId,
+		RequestParams: stockmovements.CreateStockMovementParams{
+			Inventory: current.Inventory.UUID.String(),
+			Lot:       scrap.RequestParams.Lot,
+			Quantity:  -current.Quantity,
+			Reason:    &reason,
+			Reference: &reference,
+			Type:      database.StockMovementTypeWriteOff,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := qtx.ResolveReturn(context.Background(), database.ResolveReturnParams{
+		UpdatedAt: time.Now(),
+		AccountID: scrap.AccountId,
+		ID:        scrap.ReturnId,
+		Status:    database.ReturnStatusScrapped,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	ret := &Return{
+		ID:             &row.ID,
+		CreatedAt:      &row.CreatedAt,
+		UpdatedAt:      &row.UpdatedAt,
+		InspectedAt:    nullTime(row.InspectedAt),
+		InspectionNote: str.NullString(row.InspectionNote),
+		Inventory:      api.Expandable{ID: row.Inventory},
+		Item:           api.Expandable{ID: row.Item},
+		Note:           str.NullString(row.Note),
+		Order:          api.Expandable{ID: row.Order},
+		Quantity:       row.Quantity,
+		Reason:         row.Reason,
+		ResolvedAt:     nullTime(row.ResolvedAt),
+		Status:         row.Status,
+	}
+
+	return ret, nil
+}
+
+// Summarize reports how many returns there were for each reason, how much
+// stock they sent back and how much of it was restocked or scrapped.
+func (s *ReturnsService) Summarize(summarize Summarize) (*Report, error) {
+	rows, err := s.Db.SummarizeReturnReasons(context.Background(), MapSummarizeReturnReasonsParams(summarize))
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	report := &Report{
+		Reasons: make([]*ReasonSummary, 0, len(rows)),
+	}
+	for _, row := range rows {
+		report.Reasons = append(report.Reasons, &ReasonSummary{
+			Quantity:  row.Quantity,
+			Reason:    row.Reason,
+			Restocked: row.Restocked,
+			Returns:   row.Returns,
+			Scrapped:  row.Scrapped,
+		})
+	}
+
+	return report, nil
+}
+
+// resolve works out the item a return is for and the inventory it goes back
+// to. A return of an order must be for an item sold on it, and can't send
+// back more than was sold less what has already been returned.
+func (s *ReturnsService) resolve(accountId uuid.UUID, params CreateReturnParams) (itemId, inventoryId uuid.UUID, err error) {
+	var lineInventory uuid.NullUUID
+
+	if params.Order != nil {
+		orderId := uuid.MustParse(*params.Order)
+		order, err := s.Db.GetOrder(context.Background(), database.GetOrderParams{
+			ID:        orderId,
+			AccountID: accountId,
+		})
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return itemId, inventoryId, api.NotFoundMessage(orderId, "order")
+			}
+			return itemId, inventoryId, err
+		}
+		if order.Status != database.OrderStatusFulfilled {
+			return itemId, inventoryId, &api.AppError{
+				Message: fmt.Sprintf("Order '%v' is %s, only fulfilled orders can be returned.", orderId, order.Status),
+				Status:  http.StatusBadRequest,
+				Type:    api.InvalidRequestError,
+			}
+		}
+
+		lines, err := s.Db.ListOrderLines(context.Background(), database.ListOrderLinesParams{
+			AccountID: accountId,
+			OrderID:   orderId,
+		})
+		if err != nil && err != sql.ErrNoRows {
+			return itemId, inventoryId, err
+		}
+
+		line, sold, err := matchLine(lines, params.Item)
+		if err != nil {
+			return itemId, inventoryId, err
+		}
+		itemId = line.Item.UUID
+		lineInventory = line.Inventory
+
+		returned, err := s.Db.SumOrderItemReturns(context.Background(), database.SumOrderItemReturnsParams{
+			AccountID: accountId,
+			OrderID:   orderId,
+			ItemID:    itemId,
+		})
+		if err != nil {
+			return itemId, inventoryId, err
+		}
+		if returned+int64(params.Quantity) > int64(sold) {
+			return itemId, inventoryId, &api.AppError{
+				Message: fmt.Sprintf("Can't return more than the %d units of item '%v' on order '%v' that haven't been returned yet.", int64(sold)-returned, itemId, orderId),
+				Status:  http.StatusBadRequest,
+				Type:    api.InvalidRequestError,
+			}
+		}
+	} else {
+		itemId = uuid.MustParse(*params.Item)
+	}
+
+	if params.Inventory == nil {
+		if !lineInventory.Valid {
+			return itemId, inventoryId, &api.AppError{
+				Message: "An inventory is required to return an item that isn't sold from one on the order.",
+				Status:  http.StatusBadRequest,
+				Type:    api.InvalidRequestError,
+			}
+		}
+		return itemId, lineInventory.UUID, nil
+	}
+
+	inventoryId = uuid.MustParse(*params.Inventory)
+	inventory, err := s.Db.GetInventory(context.Background(), database.GetInventoryParams{
+		ID:        inventoryId,
+		AccountID: accountId,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return itemId, inventoryId, api.NotFoundMessage(inventoryId, "inventory")
+		}
+		return itemId, inventoryId, err
+	}
+	if inventory.Item.UUID != itemId {
+		return itemId, inventoryId, &api.AppError{
+			Message: fmt.Sprintf("Inventory '%v' doesn't hold item '%v'.", inventoryId, itemId),
+			Status:  http.StatusBadRequest,
+			Type:    api.InvalidRequestError,
+		}
+	}
+
+	return itemId, inventoryId, nil
+}
+
+// matchLine finds the line of an order a return is for, along with the
+// quantity of its item sold over all of the order's lines. Without an item
+// the order must have a single item.
+func matchLine(lines []database.ListOrderLinesRow, item *string) (line *database.ListOrderLinesRow, sold int32, err error) {
+	for i := range lines {
+		if !lines[i].Item.Valid {
+			continue
+		}
+		if item != nil && lines[i].Item.UUID.String() != *item {
+			continue
+		}
+		if line != nil && line.Item.UUID != lines[i].Item.UUID {
+			return nil, 0, &api.AppError{
+				Message: "The order has more than one item, an item is required.",
+				Status:  http.StatusBadRequest,
+				Type:    api.InvalidRequestError,
+			}
+		}
+		if line == nil {
+			line = &lines[i]
+		}
+		sold += lines[i].Quantity
+	}
+
+	if line == nil {
+		message := "The order has no items to return."
+		if item != nil {
+			message = fmt.Sprintf("Item '%s' isn't part of the order.", *item)
+		}
+		return nil, 0, &api.AppError{
+			Message: message,
+			Status:  http.StatusBadRequest,
+			Type:    api.InvalidRequestError,
+		}
+	}
+
+	return line, sold, nil
+}
+
+// getForUpdate locks the return and makes sure it's in the status the next
+// step of its lifecycle starts from.
+func getForUpdate(q *database.Queries, accountId, returnId uuid.UUID, status database.ReturnStatus) (*database.GetReturnForUpdateRow, error) {
+	row, err := q.GetReturnForUpdate(context.Background(), database.GetReturnForUpdateParams{
+		ID:        returnId,
+		AccountID: accountId,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, api.NotFoundMessage(returnId, "return")
+		}
+		return nil, err
+	}
+
+	if row.Status != status {
+		return nil, &api.AppError{
+			Message: fmt.Sprintf("Return '%v' is %s, expected it to be %s.", returnId, row.Status, status),
+			Status:  http.StatusBadRequest,
+			Type:    api.InvalidRequestError,
+		}
+	}
+
+	return &row, nil
+}
+
+// postReturn books the returned stock out of quarantine into the return's
+// inventory.
+func postReturn(q *database.Queries, accountId uuid.UUID, ret *database.GetReturnForUpdateRow, lot *string) error {
+	if !ret.Inventory.Valid {
+		return &api.AppError{
+			Message: fmt.Sprintf("The inventory of return '%v' no longer exists.", ret.ID),
+			Status:  http.StatusBadRequest,
+			Type:    api.InvalidRequestError,
+		}
+	}
+
+	reason := string(ret.Reason)
+	reference := ret.ID.String()
+	_, err := stockmovements.Post(q, stockmovements.Create{
+		AccountId: accountId,
+		RequestParams: stockmovements.CreateStockMovementParams{
+			Inventory: ret.Inventory.UUID.String(),
+			Lot:       lot,
+			Quantity:  ret.Quantity,
+			Reason:    &reason,
+			Reference: &reference,
+			Type:      database.StockMovementTypeReturn,
+		},
+	})
+	return err
+}
+
+func nullTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
@@ -0,0 +1,34 @@
+package serialnumbers
+
+import (
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+func MapListSerialNumbersParams(list List) database.ListSerialNumbersParams {
+	lsnp := database.ListSerialNumbersParams{
+		AccountID:   list.AccountId,
+		InventoryID: api.NullUUID(nil),
+		ItemID:      api.NullUUID(nil),
+		Number:      api.NullString(list.RequestParams.Number),
+	}
+	if list.RequestParams.Inventory != nil {
+		inventoryId := uuid.MustParse(*list.RequestParams.Inventory)
+		lsnp.InventoryID = api.NullUUID(&inventoryId)
+	}
+	if list.RequestParams.Item != nil {
+		itemId := uuid.MustParse(*list.RequestParams.Item)
+		lsnp.ItemID = api.NullUUID(&itemId)
+	}
+	if list.RequestParams.Status != nil {
+		lsnp.Status = database.NullSerialNumberStatus{
+			SerialNumberStatus: *list.RequestParams.Status,
+			Valid:              true,
+		}
+	}
+	database.MapTimeRange(list.RequestParams.CreatedAt, &lsnp.CreatedAtGt, &lsnp.CreatedAtGte, &lsnp.CreatedAtLt, &lsnp.CreatedAtLte)
+	database.MapTimeRange(list.RequestParams.UpdatedAt, &lsnp.UpdatedAtGt, &lsnp.UpdatedAtGte, &lsnp.UpdatedAtLt, &lsnp.UpdatedAtLte)
+	database.MapPaginationParams(*list.RequestParams.PaginationParams, &lsnp)
+	return lsnp
+}
@@ -0,0 +1,50 @@
+package serialnumbers
+
+import (
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+type CreateSerialNumberParams struct {
+	Inventory        string             `json:"inventory" validate:"required,uuid"`
+	Number           string             `json:"number" validate:"required"`
+	Reason           *string            `json:"reason" validate:"omitnil"`
+	UnitCostAmount   *int32             `json:"unit_cost_amount" validate:"required,gte=0"`
+	UnitCostCurrency *database.Currency `json:"unit_cost_currency" validate:"required,currency"`
+	Expand           []string           `json:"expand" validate:"omitnil,dive,oneof=inventory item"`
+}
+
+type ListSerialNumbersByItemsParams struct {
+	Ids []uuid.UUID
+}
+
+type ListSerialNumbersParams struct {
+	*database.PaginationParams
+	CreatedAt *database.TimeRange          `json:"created_at" validate:"omitnil"`
+	Inventory *string                      `json:"inventory" validate:"omitnil,uuid"`
+	Item      *string                      `json:"item" validate:"omitnil,uuid"`
+	Number    *string                      `json:"number" validate:"omitnil"`
+	Status    *database.SerialNumberStatus `json:"status" validate:"omitnil,oneof=in_stock reserved sold returned scrapped"`
+	UpdatedAt *database.TimeRange          `json:"updated_at" validate:"omitnil"`
+	Expand    []string                     `json:"expand" validate:"omitnil,dive,oneof=inventory item"`
+}
+
+type RetrieveSerialNumberParams struct {
+	Expand []string `json:"expand" validate:"omitnil,dive,oneof=inventory item"`
+}
+
+type UpdateSerialNumberParams struct {
+	Inventory *string                      `json:"inventory" validate:"omitnil,uuid"`
+	Reason    *string                      `json:"reason" validate:"omitnil"`
+	Status    *database.SerialNumberStatus `json:"status" validate:"omitnil,oneof=in_stock reserved sold returned scrapped"`
+	Expand    []string                     `json:"expand" validate:"omitnil,dive,oneof=inventory item"`
+}
+
+func NewListSerialNumbersParams() ListSerialNumbersParams {
+	limit := int32(10)
+	return ListSerialNumbersParams{
+		PaginationParams: &database.PaginationParams{
+			Limit: &limit,
+		},
+	}
+}
@@ -0,0 +1,31 @@
+package serialnumbers
+
+import (
+	"time"
+
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/d-darac/inventory-assets/str"
+	"github.com/google/uuid"
+)
+
+type SerialNumber struct {
+	ID        *uuid.UUID                  `json:"id,omitempty"`
+	CreatedAt *time.Time                  `json:"created_at,omitempty"`
+	UpdatedAt *time.Time                  `json:"updated_at,omitempty"`
+	Inventory api.Expandable              `json:"inventory"`
+	Item      api.Expandable              `json:"item"`
+	Movements []*SerialNumberMovement     `json:"movements,omitempty"`
+	Number    string                      `json:"number"`
+	Status    database.SerialNumberStatus `json:"status"`
+}
+
+// SerialNumberMovement is an entry in the history of a serial number, written
+// whenever its status or inventory changes.
+type SerialNumberMovement struct {
+	CreatedAt  time.Time                    `json:"created_at"`
+	FromStatus *database.SerialNumberStatus `json:"from_status"`
+	Inventory  api.Expandable               `json:"inventory"`
+	Reason     str.NullString               `json:"reason"`
+	Status     database.SerialNumberStatus  `json:"status"`
+}
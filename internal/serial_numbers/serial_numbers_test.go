@@ -0,0 +1,32 @@
+package serialnumbers
+
+import (
+	"testing"
+
+	"github.com/d-darac/inventory-assets/database"
+)
+
+func TestUnitCheckTransition(t *testing.T) {
+	tests := []struct {
+		from, to database.SerialNumberStatus
+		ok       bool
+	}{
+		{database.SerialNumberStatusInStock, database.SerialNumberStatusSold, true},
+		{database.SerialNumberStatusInStock, database.SerialNumberStatusReturned, false},
+		{database.SerialNumberStatusReserved, database.SerialNumberStatusInStock, true},
+		{database.SerialNumberStatusSold, database.SerialNumberStatusReturned, true},
+		{database.SerialNumberStatusSold, database.SerialNumberStatusInStock, false},
+		{database.SerialNumberStatusReturned, database.SerialNumberStatusInStock, true},
+		{database.SerialNumberStatusScrapped, database.SerialNumberStatusInStock, false},
+	}
+
+	for _, tt := range tests {
+		err := CheckTransition(tt.from, tt.to)
+		if tt.ok && err != nil {
+			t.Fatalf("expected %s -> %s to be allowed, got %v", tt.from, tt.to, err)
+		}
+		if !tt.ok && err == nil {
+			t.Fatalf("expected %s -> %s to be rejected", tt.from, tt.to)
+		}
+	}
+}
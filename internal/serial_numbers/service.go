@@ -0,0 +1,513 @@
+package serialnumbers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	stockmovements "github.com/d-darac/inventory-api/internal/stock_movements"
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/d-darac/inventory-assets/str"
+	"github.com/google/uuid"
+)
+
+// transitions lists the statuses a serial number can move to from each
+// status. Scrapped is final.
+var transitions = map[database.SerialNumberStatus][]database.SerialNumberStatus{
+	database.SerialNumberStatusInStock:  {database.SerialNumberStatusReserved, database.SerialNumberStatusSold, database.SerialNumberStatusScrapped},
+	database.SerialNumberStatusReserved: {database.SerialNumberStatusInStock, database.SerialNumberStatusSold},
+	database.SerialNumberStatusSold:     {database.SerialNumberStatusReturned},
+	database.SerialNumberStatusReturned: {database.SerialNumberStatusInStock, database.SerialNumberStatusScrapped},
+}
+
+type SerialNumbersService struct {
+	Conn *sql.DB
+	Db   *database.Queries
+}
+
+type Create struct {
+	AccountId     uuid.UUID
+	RequestParams CreateSerialNumberParams
+}
+
+type Get struct {
+	AccountId      uuid.UUID
+	SerialNumberId uuid.UUID
+	RequestParams  RetrieveSerialNumberParams
+	OmitBase       bool
+}
+
+type ListByItems struct {
+	AccountId     uuid.UUID
+	RequestParams ListSerialNumbersByItemsParams
+}
+
+type List struct {
+	AccountId     uuid.UUID
+	RequestParams ListSerialNumbersParams
+}
+
+type Update struct {
+	AccountId      uuid.UUID
+	SerialNumberId uuid.UUID
+	RequestParams  UpdateSerialNumberParams
+}
+
+func NewSerialNumbersService(db *database.Queries, conn *sql.DB) *SerialNumbersService {
+	return &SerialNumbersService{
+		Conn: conn,
+		Db:   db,
+	}
+}
+
+// Create registers a serial number as in stock in an inventory of a
+// serialized item and receives it into that inventory.
+func (s *SerialNumbersService) Create(create Create) (*SerialNumber, error) {
+	tx, err := s.Conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.Db.WithTx(tx)
+
+	inventoryId := uuid.MustParse(create.RequestParams.Inventory)
+	itemId, err := serializedItem(qtx, create.AccountId, inventoryId)
+	if err != nil {
+		return nil, err
+	}
+
+	t := time.Now()
+	row, err := qtx.CreateSerialNumber(context.Background(), database.CreateSerialNumberParams{
+		ID:          uuid.New(),
+		CreatedAt:   t,
+		UpdatedAt:   t,
+		AccountID:   create.AccountId,
+		InventoryID: inventoryId,
+		ItemID:      itemId,
+		Number:      create.RequestParams.Number,
+		Status:      database.SerialNumberStatusInStock,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = post(qtx, create.AccountId, row.ID, stockmovements.CreateStockMovementParams{
+		Inventory:        inventoryId.String(),
+		Quantity:         1,
+		Reason:           create.RequestParams.Reason,
+		Type:             database.StockMovementTypeReceipt,
+		UnitCostAmount:   create.RequestParams.UnitCostAmount,
+		UnitCostCurrency: create.RequestParams.UnitCostCurrency,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = qtx.CreateSerialNumberMovement(context.Background(), database.CreateSerialNumberMovementParams{
+		ID:             uuid.New(),
+		CreatedAt:      t,
+		AccountID:      create.AccountId,
+		SerialNumberID: row.ID,
+		FromStatus:     database.NullSerialNumberStatus{},
+		InventoryID:    inventoryId,
+		Reason:         api.NullString(create.RequestParams.Reason),
+		Status:         row.Status,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	movements, err := listMovements(qtx, create.AccountId, row.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	serialNumber := &SerialNumber{
+		ID:        &row.ID,
+		CreatedAt: &row.CreatedAt,
+		UpdatedAt: &row.UpdatedAt,
+		Inventory: api.Expandable{ID: row.Inventory},
+		Item:      api.Expandable{ID: row.Item},
+		Movements: movements,
+		Number:    row.Number,
+		Status:    row.Status,
+	}
+
+	return serialNumber, nil
+}
+
+func (s *SerialNumbersService) Get(get Get) (*SerialNumber, error) {
+	row, err := s.Db.GetSerialNumber(context.Background(), database.GetSerialNumberParams{
+		ID:        get.SerialNumberId,
+		AccountID: get.AccountId,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, api.NotFoundMessage(get.SerialNumberId, "serial number")
+		}
+		return nil, err
+	}
+
+	movements, err := listMovements(s.Db, get.AccountId, row.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	serialNumber := &SerialNumber{
+		Inventory: api.Expandable{ID: row.Inventory},
+		Item:      api.Expandable{ID: row.Item},
+		Movements: movements,
+		Number:    row.Number,
+		Status:    row.Status,
+	}
+
+	if !get.OmitBase {
+		serialNumber.ID = &row.ID
+		serialNumber.CreatedAt = &row.CreatedAt
+		serialNumber.UpdatedAt = &row.UpdatedAt
+	}
+
+	return serialNumber, nil
+}
+
+// ListByItems returns the serial numbers of any of the given items.
+func (s *SerialNumbersService) ListByItems(list ListByItems) (serialNumbers []*SerialNumber, err error) {
+	params := database.ListSerialNumbersByItemsParams{
+		AccountID: list.AccountId,
+		Ids:       list.RequestParams.Ids,
+	}
+
+	rows, err := s.Db.ListSerialNumbersByItems(context.Background(), params)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return serialNumbers, nil
+		}
+		return
+	}
+
+	for _, row := range rows {
+		serialNumbers = append(serialNumbers, &SerialNumber{
+			ID:        &row.ID,
+			CreatedAt: &row.CreatedAt,
+			UpdatedAt: &row.UpdatedAt,
+			Inventory: api.Expandable{ID: row.Inventory},
+			Item:      api.Expandable{ID: row.Item},
+			Number:    row.Number,
+			Status:    row.Status,
+		})
+	}
+
+	return
+}
+
+func (s *SerialNumbersService) List(list List) (serialNumbers []*SerialNumber, hasMore bool, err error) {
+	if list.RequestParams.StartingAfter != nil {
+		serialNumber, err := s.Get(Get{
+			AccountId:      list.AccountId,
+			SerialNumberId: *list.RequestParams.StartingAfter,
+			RequestParams:  RetrieveSerialNumberParams{},
+			OmitBase:       false,
+		})
+		if err != nil {
+			return serialNumbers, hasMore, err
+		}
+		list.RequestParams.StartingAfterDate = serialNumber.CreatedAt
+	}
+
+	if list.RequestParams.EndingBefore != nil {
+		serialNumber, err := s.Get(Get{
+			AccountId:      list.AccountId,
+			SerialNumberId: *list.RequestParams.EndingBefore,
+			RequestParams:  RetrieveSerialNumberParams{},
+			OmitBase:       false,
+		})
+		if err != nil {
+			return serialNumbers, hasMore, err
+		}
+		list.RequestParams.EndingBeforeDate = serialNumber.CreatedAt
+	}
+
+	dbParams := MapListSerialNumbersParams(list)
+
+	rows, err := s.Db.ListSerialNumbers(context.Background(), dbParams)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return serialNumbers, hasMore, nil
+		}
+		return
+	}
+
+	if dbParams.Limit.Valid {
+		hasMore = len(rows) > int(dbParams.Limit.Int32)
+	} else {
+		hasMore = len(rows) > 10
+	}
+
+	if hasMore {
+		if dbParams.EndingBefore.Valid {
+			rows = rows[1:]
+		} else {
+			rows = rows[:len(rows)-1]
+		}
+	}
+
+	for _, row := range rows {
+		serialNumbers = append(serialNumbers, &SerialNumber{
+			ID:        &row.ID,
+			CreatedAt: &row.CreatedAt,
+			UpdatedAt: &row.UpdatedAt,
+			Inventory: api.Expandable{ID: row.Inventory},
+			Item:      api.Expandable{ID: row.Item},
+			Number:    row.Number,
+			Status:    row.Status,
+		})
+	}
+
+	return serialNumbers, hasMore, err
+}
+
+// Update moves a serial number to another status or inventory. Whenever the
+// serial number enters or leaves in_stock, or moves between inventories
+// while in stock, the matching stock movements are posted so in_stock keeps
+// counting the serial numbers that are in stock.
+func (s *SerialNumbersService) Update(update Update) (*SerialNumber, error) {
+	tx, err := s.Conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.Db.WithTx(tx)
+
+	current, err := qtx.GetSerialNumberForUpdate(context.Background(), database.GetSerialNumberForUpdateParams{
+		ID:        update.SerialNumberId,
+		AccountID: update.AccountId,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, api.NotFoundMessage(update.SerialNumberId, "serial number")
+		}
+		return nil, err
+	}
+
+	status := current.Status
+	if update.RequestParams.Status != nil && *update.RequestParams.Status != current.Status {
+		if err := CheckTransition(current.Status, *update.RequestParams.Status); err != nil {
+			return nil, err
+		}
+		status = *update.RequestParams.Status
+	}
+
+	inventoryId := current.Inventory.UUID
+	if update.RequestParams.Inventory != nil {
+		inventoryId = uuid.MustParse(*update.RequestParams.Inventory)
+	}
+
+	if inventoryId != current.Inventory.UUID {
+		if current.Status != database.SerialNumberStatusInStock || status != database.SerialNumberStatusInStock {
+			return nil, &api.AppError{
+				Message: fmt.Sprintf("Serial number '%v' can only change inventory while it's in stock.", current.ID),
+				Status:  http.StatusBadRequest,
+				Type:    api.InvalidRequestError,
+			}
+		}
+		itemId, err := serializedItem(qtx, update.AccountId, inventoryId)
+		if err != nil {
+			return nil, err
+		}
+		if itemId != current.Item.UUID {
+			return nil, &api.AppError{
+				Message: fmt.Sprintf("Inventory '%v' doesn't hold the item of serial number '%v'.", inventoryId, current.ID),
+				Status:  http.StatusBadRequest,
+				Type:    api.InvalidRequestError,
+			}
+		}
+	}
+
+	reason := update.RequestParams.Reason
+	wasInStock := current.Status == database.SerialNumberStatusInStock
+	isInStock := status == database.SerialNumberStatusInStock
+	switch {
+	case wasInStock && !isInStock:
+		movementType := database.StockMovementTypeAdjustment
+		switch status {
+		case database.SerialNumberStatusSold:
+			movementType = database.StockMovementTypeSale
+		case database.SerialNumberStatusScrapped:
+			movementType = database.StockMovementTypeWriteOff
+		}
+		err = post(qtx, update.AccountId, current.ID, stockmovements.CreateStockMovementParams{
+			Inventory: current.Inventory.UUID.String(),
+			Quantity:  -1,
+			Reason:    reason,
+			Type:      movementType,
+		})
+	case !wasInStock && isInStock:
+		err = post(qtx, update.AccountId, current.ID, stockmovements.CreateStockMovementParams{
+			Inventory: inventoryId.String(),
+			Quantity:  1,
+			Reason:    reason,
+			Type:      database.StockMovementTypeAdjustment,
+		})
+	case wasInStock && inventoryId != current.Inventory.UUID:
+		err = post(qtx, update.AccountId, current.ID, stockmovements.CreateStockMovementParams{
+			Inventory: current.Inventory.UUID.String(),
+			Quantity:  -1,
+			Reason:    reason,
+			Type:      database.StockMovementTypeTransferOut,
+		})
+		if err == nil {
+			err = post(qtx, update.AccountId, current.ID, stockmovements.CreateStockMovementParams{
+				Inventory: inventoryId.String(),
+				Quantity:  1,
+				Reason:    reason,
+				Type:      database.StockMovementTypeTransferIn,
+			})
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	t := time.Now()
+	row, err := qtx.UpdateSerialNumber(context.Background(), database.UpdateSerialNumberParams{
+		UpdatedAt:   t,
+		AccountID:   update.AccountId,
+		ID:          current.ID,
+		InventoryID: inventoryId,
+		Status:      status,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if status != current.Status || inventoryId != current.Inventory.UUID {
+		err = qtx.CreateSerialNumberMovement(context.Background(), database.CreateSerialNumberMovementParams{
+			ID:             uuid.New(),
+			CreatedAt:      t,
+			AccountID:      update.AccountId,
+			SerialNumberID: current.ID,
+			FromStatus: database.NullSerialNumberStatus{
+				SerialNumberStatus: current.Status,
+				Valid:              true,
+			},
+			InventoryID: inventoryId,
+			Reason:      api.NullString(reason),
+			Status:      status,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	movements, err := listMovements(qtx, update.AccountId, row.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	serialNumber := &SerialNumber{
+		ID:        &row.ID,
+		CreatedAt: &row.CreatedAt,
+		UpdatedAt: &row.UpdatedAt,
+		Inventory: api.Expandable{ID: row.Inventory},
+		Item:      api.Expandable{ID: row.Item},
+		Movements: movements,
+		Number:    row.Number,
+		Status:    row.Status,
+	}
+
+	return serialNumber, nil
+}
+
+func CheckTransition(from, to database.SerialNumberStatus) error {
+	if !slices.Contains(transitions[from], to) {
+		return &api.AppError{
+			Message: fmt.Sprintf("A serial number can't go from %s to %s.", from, to),
+			Status:  http.StatusBadRequest,
+			Type:    api.InvalidRequestError,
+		}
+	}
+	return nil
+}
+
+func listMovements(q *database.Queries, accountId, serialNumberId uuid.UUID) ([]*SerialNumberMovement, error) {
+	rows, err := q.ListSerialNumberMovements(context.Background(), database.ListSerialNumberMovementsParams{
+		AccountID:      accountId,
+		SerialNumberID: serialNumberId,
+	})
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	movements := make([]*SerialNumberMovement, 0, len(rows))
+	for _, row := range rows {
+		movement := &SerialNumberMovement{
+			CreatedAt: row.CreatedAt,
+			Inventory: api.Expandable{ID: row.Inventory},
+			Reason:    str.NullString(row.Reason),
+			Status:    row.Status,
+		}
+		if row.FromStatus.Valid {
+			movement.FromStatus = &row.FromStatus.SerialNumberStatus
+		}
+		movements = append(movements, movement)
+	}
+
+	return movements, nil
+}
+
+// post records a single unit movement for a serial number, referenced by its
+// id so its stock history can be listed from the ledger.
+func post(q *database.Queries, accountId, serialNumberId uuid.UUID, params stockmovements.CreateStockMovementParams) error {
+	reference := serialNumberId.String()
+	params.Reference = &reference
+	_, err := stockmovements.Post(q, stockmovements.Create{
+		AccountId:        accountId,
+		RequestParams:    params,
+		FromSerialNumber: true,
+	})
+	return err
+}
+
+// serializedItem returns the item held by an inventory, making sure it's a
+// serialized one.
+func serializedItem(q *database.Queries, accountId, inventoryId uuid.UUID) (uuid.UUID, error) {
+	inventory, err := q.GetInventory(context.Background(), database.GetInventoryParams{
+		ID:        inventoryId,
+		AccountID: accountId,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return uuid.Nil, api.NotFoundMessage(inventoryId, "inventory")
+		}
+		return uuid.Nil, err
+	}
+
+	serialized, err := q.IsInventorySerialized(context.Background(), database.IsInventorySerializedParams{
+		ID:        inventoryId,
+		AccountID: accountId,
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if !inventory.Item.Valid || !serialized {
+		return uuid.Nil, &api.AppError{
+			Message: fmt.Sprintf("Inventory '%v' doesn't hold a serialized item.", inventoryId),
+			Status:  http.StatusBadRequest,
+			Type:    api.InvalidRequestError,
+		}
+	}
+
+	return inventory.Item.UUID, nil
+}
@@ -0,0 +1,54 @@
+package stockcounts
+
+import (
+	"time"
+
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+func MapCreateStockCountParams(create Create) database.CreateStockCountParams {
+	t := time.Now()
+	locationId := uuid.MustParse(create.RequestParams.Location)
+	cscp := database.CreateStockCountParams{
+		ID:         uuid.New(),
+		CreatedAt:  t,
+		UpdatedAt:  t,
+		AccountID:  create.AccountId,
+		GroupID:    api.NullUUID(nil),
+		LocationID: api.NullUUID(&locationId),
+		Status:     database.StockCountStatusOpen,
+	}
+	if create.RequestParams.Group != nil {
+		groupId := uuid.MustParse(*create.RequestParams.Group)
+		cscp.GroupID = api.NullUUID(&groupId)
+	}
+	return cscp
+}
+
+func MapListStockCountsParams(list List) database.ListStockCountsParams {
+	lscp := database.ListStockCountsParams{
+		AccountID:  list.AccountId,
+		GroupID:    api.NullUUID(nil),
+		LocationID: api.NullUUID(nil),
+	}
+	if list.RequestParams.Group != nil {
+		groupId := uuid.MustParse(*list.RequestParams.Group)
+		lscp.GroupID = api.NullUUID(&groupId)
+	}
+	if list.RequestParams.Location != nil {
+		locationId := uuid.MustParse(*list.RequestParams.Location)
+		lscp.LocationID = api.NullUUID(&locationId)
+	}
+	if list.RequestParams.Status != nil {
+		lscp.Status = database.NullStockCountStatus{
+			StockCountStatus: *list.RequestParams.Status,
+			Valid:            true,
+		}
+	}
+	database.MapTimeRange(list.RequestParams.CreatedAt, &lscp.CreatedAtGt, &lscp.CreatedAtGte, &lscp.CreatedAtLt, &lscp.CreatedAtLte)
+	database.MapTimeRange(list.RequestParams.UpdatedAt, &lscp.UpdatedAtGt, &lscp.UpdatedAtGte, &lscp.UpdatedAtLt, &lscp.UpdatedAtLte)
+	database.MapPaginationParams(*list.RequestParams.PaginationParams, &lscp)
+	return lscp
+}
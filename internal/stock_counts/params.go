@@ -0,0 +1,60 @@
+package stockcounts
+
+import (
+	"github.com/d-darac/inventory-assets/database"
+)
+
+type CancelStockCountParams struct {
+	Expand []string `json:"expand" validate:"omitnil,dive,oneof=group location"`
+}
+
+// CreateStockCountParams scopes a count to one location, where each item is
+// counted against its single inventory there.
+type CreateStockCountParams struct {
+	Group    *string  `json:"group" validate:"omitnil,uuid"`
+	Items    []string `json:"items" validate:"omitnil,dive,uuid"`
+	Location string   `json:"location" validate:"required,uuid"`
+	Expand   []string `json:"expand" validate:"omitnil,dive,oneof=group location"`
+}
+
+type FinalizeStockCountParams struct {
+	Expand []string `json:"expand" validate:"omitnil,dive,oneof=group location"`
+}
+
+type ListStockCountsParams struct {
+	*database.PaginationParams
+	CreatedAt *database.TimeRange        `json:"created_at" validate:"omitnil"`
+	Group     *string                    `json:"group" validate:"omitnil,uuid"`
+	Location  *string                    `json:"location" validate:"omitnil,uuid"`
+	Status    *database.StockCountStatus `json:"status" validate:"omitnil,oneof=open finalized canceled"`
+	UpdatedAt *database.TimeRange        `json:"updated_at" validate:"omitnil"`
+	Expand    []string                   `json:"expand" validate:"omitnil,dive,oneof=group location"`
+}
+
+type RetrieveStockCountParams struct {
+	Expand []string `json:"expand" validate:"omitnil,dive,oneof=group location"`
+}
+
+// StockCountEntry is a counted quantity for an item, given either by its ID
+// or by one of its scanned identifiers, and optionally in one of its units
+// as converted by units.ToStockingUnit.
+type StockCountEntry struct {
+	Identifier *string `json:"identifier" validate:"omitnil"`
+	Item       *string `json:"item" validate:"omitnil,uuid"`
+	Quantity   int32   `json:"quantity" validate:"gte=0"`
+	Unit       *string `json:"unit" validate:"omitnil,uuid"`
+}
+
+type SubmitStockCountParams struct {
+	Counts []StockCountEntry `json:"counts" validate:"required,min=1,dive"`
+	Expand []string          `json:"expand" validate:"omitnil,dive,oneof=group location"`
+}
+
+func NewListStockCountsParams() ListStockCountsParams {
+	limit := int32(10)
+	return ListStockCountsParams{
+		PaginationParams: &database.PaginationParams{
+			Limit: &limit,
+		},
+	}
+}
@@ -0,0 +1,651 @@
+package stockcounts
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	stockmovements "github.com/d-darac/inventory-api/internal/stock_movements"
+	"github.com/d-darac/inventory-api/internal/units"
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/d-darac/inventory-assets/ints"
+	"github.com/google/uuid"
+)
+
+type StockCountsService struct {
+	Conn *sql.DB
+	Db   *database.Queries
+}
+
+type Cancel struct {
+	AccountId     uuid.UUID
+	StockCountId  uuid.UUID
+	RequestParams CancelStockCountParams
+}
+
+type Create struct {
+	AccountId     uuid.UUID
+	RequestParams CreateStockCountParams
+}
+
+type Finalize struct {
+	AccountId     uuid.UUID
+	StockCountId  uuid.UUID
+	RequestParams FinalizeStockCountParams
+}
+
+type Get struct {
+	AccountId     uuid.UUID
+	StockCountId  uuid.UUID
+	RequestParams RetrieveStockCountParams
+	OmitBase      bool
+}
+
+type List struct {
+	AccountId     uuid.UUID
+	RequestParams ListStockCountsParams
+}
+
+type Submit struct {
+	AccountId     uuid.UUID
+	StockCountId  uuid.UUID
+	RequestParams SubmitStockCountParams
+}
+
+func NewStockCountsService(db *database.Queries, conn *sql.DB) *StockCountsService {
+	return &StockCountsService{
+		Conn: conn,
+		Db:   db,
+	}
+}
+
+func (s *StockCountsService) Cancel(cancel Cancel) (*StockCount, error) {
+	tx, err := s.Conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.Db.WithTx(tx)
+
+	if _, err := getForUpdate(qtx, cancel.AccountId, cancel.StockCountId); err != nil {
+		return nil, err
+	}
+
+	row, err := qtx.UpdateStockCountStatus(context.Background(), database.UpdateStockCountStatusParams{
+		UpdatedAt:   time.Now(),
+		AccountID:   cancel.AccountId,
+		ID:          cancel.StockCountId,
+		FinalizedAt: sql.NullTime{},
+		Status:      database.StockCountStatusCanceled,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	lines, err := listLines(qtx, cancel.AccountId, row.ID, row.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	stockCount := &StockCount{
+		ID:          &row.ID,
+		CreatedAt:   &row.CreatedAt,
+		UpdatedAt:   &row.UpdatedAt,
+		FinalizedAt: nullTime(row.FinalizedAt),
+		Group:       api.Expandable{ID: row.Group},
+		Lines:       lines,
+		Location:    api.Expandable{ID: row.Location},
+		Status:      row.Status,
+	}
+
+	return stockCount, nil
+}
+
+// Create opens a count session with a line for every inventory of the
+// scoped items at the given location. Counts are submitted per item, so each
+// item may only have one inventory there. Inventories whose stock can't be
+// set by an adjustment are left out of a group's count and rejected when
+// their item is listed.
+func (s *StockCountsService) Create(create Create) (*StockCount, error) {
+	if (create.RequestParams.Group == nil) == (len(create.RequestParams.Items) == 0) {
+		return nil, &api.AppError{
+			Message: "A stock count must be scoped to either a group or a list of items.",
+			Status:  http.StatusBadRequest,
+			Type:    api.InvalidRequestError,
+		}
+	}
+
+	tx, err := s.Conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.Db.WithTx(tx)
+
+	dbParams := MapCreateStockCountParams(create)
+
+	itemIds := make([]uuid.UUID, 0, len(create.RequestParams.Items))
+	for _, item := range create.RequestParams.Items {
+		itemIds = append(itemIds, uuid.MustParse(item))
+	}
+
+	inventories, err := qtx.ListInventoriesForStockCount(context.Background(), database.ListInventoriesForStockCountParams{
+		AccountID:  create.AccountId,
+		GroupID:    dbParams.GroupID,
+		ItemIds:    itemIds,
+		LocationID: dbParams.LocationID,
+	})
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	countable := inventories[:0]
+	for _, inventory := range inventories {
+		if err := checkCountable(qtx, create.AccountId, inventory.ID); err != nil {
+			if _, ok := err.(*api.AppError); ok && create.RequestParams.Group != nil {
+				continue
+			}
+			return nil, err
+		}
+		countable = append(countable, inventory)
+	}
+	inventories = countable
+
+	if len(inventories) == 0 {
+		return nil, &api.AppError{
+			Message: "There are no inventories in the scope of this stock count.",
+			Status:  http.StatusBadRequest,
+			Type:    api.InvalidRequestError,
+		}
+	}
+
+	itemInventoryMap := make(map[uuid.UUID]uuid.UUID, len(inventories))
+	for _, inventory := range inventories {
+		if other, ok := itemInventoryMap[inventory.Item.UUID]; ok {
+			return nil, &api.AppError{
+				Message: fmt.Sprintf("Item '%v' has more than one inventory at location '%v' ('%v' and '%v'), so counts for it can't be told apart.", inventory.Item.UUID, dbParams.LocationID.UUID, other, inventory.ID),
+				Status:  http.StatusBadRequest,
+				Type:    api.InvalidRequestError,
+			}
+		}
+		itemInventoryMap[inventory.Item.UUID] = inventory.ID
+	}
+
+	row, err := qtx.CreateStockCount(context.Background(), dbParams)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, inventory := range inventories {
+		err := qtx.CreateStockCountLine(context.Background(), database.CreateStockCountLineParams{
+			ID:           uuid.New(),
+			AccountID:    create.AccountId,
+			StockCountID: row.ID,
+			InventoryID:  inventory.ID,
+			ItemID:       inventory.Item.UUID,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	lines, err := listLines(qtx, create.AccountId, row.ID, row.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	stockCount := &StockCount{
+		ID:          &row.ID,
+		CreatedAt:   &row.CreatedAt,
+		UpdatedAt:   &row.UpdatedAt,
+		FinalizedAt: nullTime(row.FinalizedAt),
+		Group:       api.Expandable{ID: row.Group},
+		Lines:       lines,
+		Location:    api.Expandable{ID: row.Location},
+		Status:      row.Status,
+	}
+
+	return stockCount, nil
+}
+
+// Finalize posts an adjustment for the variance of every counted line and
+// stores the variances on the lines as the count's report. Lines that were
+// never counted are left as they are.
+func (s *StockCountsService) Finalize(finalize Finalize) (*StockCount, error) {
+	tx, err := s.Conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.Db.WithTx(tx)
+
+	current, err := getForUpdate(qtx, finalize.AccountId, finalize.StockCountId)
+	if err != nil {
+		return nil, err
+	}
+
+	lineRows, err := qtx.ListStockCountLines(context.Background(), database.ListStockCountLinesParams{
+		AccountID:    finalize.AccountId,
+		StockCountID: current.ID,
+	})
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	reason := "stock count"
+	reference := current.ID.String()
+	for _, line := range lineRows {
+		inventory, err := qtx.GetInventoryForUpdate(context.Background(), database.GetInventoryForUpdateParams{
+			ID:        line.Inventory.UUID,
+			AccountID: finalize.AccountId,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		variance := sql.NullInt32{}
+		if line.Counted.Valid {
+			variance = sql.NullInt32{Int32: line.Counted.Int32 - inventory.InStock, Valid: true}
+		}
+
+		if variance.Int32 != 0 {
+			_, err = stockmovements.Post(qtx, stockmovements.Create{
+				AccountId: finalize.AccountId,
+				RequestParams: stockmovements.CreateStockMovementParams{
+					Inventory: inventory.ID.String(),
+					Quantity:  variance.Int32,
+					Reason:    &reason,
+					Reference: &reference,
+					Type:      database.StockMovementTypeAdjustment,
+				},
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		err = qtx.FinalizeStockCountLine(context.Background(), database.FinalizeStockCountLineParams{
+			ID:       line.ID,
+			Expected: inventory.InStock,
+			Variance: variance,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	t := time.Now()
+	row, err := qtx.UpdateStockCountStatus(context.Background(), database.UpdateStockCountStatusParams{
+		UpdatedAt:   t,
+		AccountID:   finalize.AccountId,
+		ID:          finalize.StockCountId,
+		FinalizedAt: sql.NullTime{Time: t, Valid: true},
+		Status:      database.StockCountStatusFinalized,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	lines, err := listLines(qtx, finalize.AccountId, row.ID, row.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	stockCount := &StockCount{
+		ID:          &row.ID,
+		CreatedAt:   &row.CreatedAt,
+		UpdatedAt:   &row.UpdatedAt,
+		FinalizedAt: nullTime(row.FinalizedAt),
+		Group:       api.Expandable{ID: row.Group},
+		Lines:       lines,
+		Location:    api.Expandable{ID: row.Location},
+		Status:      row.Status,
+	}
+
+	return stockCount, nil
+}
+
+func (s *StockCountsService) Get(get Get) (*StockCount, error) {
+	row, err := s.Db.GetStockCount(context.Background(), database.GetStockCountParams{
+		ID:        get.StockCountId,
+		AccountID: get.AccountId,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, api.NotFoundMessage(get.StockCountId, "stock count")
+		}
+		return nil, err
+	}
+
+	lines, err := listLines(s.Db, get.AccountId, row.ID, row.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	stockCount := &StockCount{
+		FinalizedAt: nullTime(row.FinalizedAt),
+		Group:       api.Expandable{ID: row.Group},
+		Lines:       lines,
+		Location:    api.Expandable{ID: row.Location},
+		Status:      row.Status,
+	}
+
+	if !get.OmitBase {
+		stockCount.ID = &row.ID
+		stockCount.CreatedAt = &row.CreatedAt
+		stockCount.UpdatedAt = &row.UpdatedAt
+	}
+
+	return stockCount, nil
+}
+
+func (s *StockCountsService) List(list List) (stockCounts []*StockCount, hasMore bool, err error) {
+	if list.RequestParams.StartingAfter != nil {
+		stockCount, err := s.Get(Get{
+			AccountId:     list.AccountId,
+			StockCountId:  *list.RequestParams.StartingAfter,
+			RequestParams: RetrieveStockCountParams{},
+			OmitBase:      false,
+		})
+		if err != nil {
+			return stockCounts, hasMore, err
+		}
+		list.RequestParams.StartingAfterDate = stockCount.CreatedAt
+	}
+
+	if list.RequestParams.EndingBefore != nil {
+		stockCount, err := s.Get(Get{
+			AccountId:     list.AccountId,
+			StockCountId:  *list.RequestParams.EndingBefore,
+			RequestParams: RetrieveStockCountParams{},
+			OmitBase:      false,
+		})
+		if err != nil {
+			return stockCounts, hasMore, err
+		}
+		list.RequestParams.EndingBeforeDate = stockCount.CreatedAt
+	}
+
+	dbParams := MapListStockCountsParams(list)
+
+	rows, err := s.Db.ListStockCounts(context.Background(), dbParams)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return stockCounts, hasMore, nil
+		}
+		return
+	}
+
+	if dbParams.Limit.Valid {
+		hasMore = len(rows) > int(dbParams.Limit.Int32)
+	} else {
+		hasMore = len(rows) > 10
+	}
+
+	if hasMore {
+		if dbParams.EndingBefore.Valid {
+			rows = rows[1:]
+		} else {
+			rows = rows[:len(rows)-1]
+		}
+	}
+
+	for _, row := range rows {
+		stockCounts = append(stockCounts, &StockCount{
+			ID:          &row.ID,
+			CreatedAt:   &row.CreatedAt,
+			UpdatedAt:   &row.UpdatedAt,
+			FinalizedAt: nullTime(row.FinalizedAt),
+			Group:       api.Expandable{ID: row.Group},
+			Location:    api.Expandable{ID: row.Location},
+			Status:      row.Status,
+		})
+	}
+
+	return stockCounts, hasMore, err
+}
+
+// Submit records counted quantities on the count's lines. A quantity
+// replaces whatever was counted for the item before, so an item can be
+// recounted until the count is finalized.
+func (s *StockCountsService) Submit(submit Submit) (*StockCount, error) {
+	tx, err := s.Conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.Db.WithTx(tx)
+
+	current, err := getForUpdate(qtx, submit.AccountId, submit.StockCountId)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range submit.RequestParams.Counts {
+		itemId, err := resolveItem(qtx, submit.AccountId, entry)
+		if err != nil {
+			return nil, err
+		}
+
+		counted := entry.Quantity
+		if entry.Unit != nil {
+			counted, err = units.ToStockingUnit(qtx, submit.AccountId, api.NullUUID(&itemId), uuid.MustParse(*entry.Unit), entry.Quantity)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		_, err = qtx.SetStockCountLineCounted(context.Background(), database.SetStockCountLineCountedParams{
+			AccountID:    submit.AccountId,
+			StockCountID: current.ID,
+			ItemID:       itemId,
+			Counted:      counted,
+		})
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return nil, &api.AppError{
+					Message: fmt.Sprintf("Item '%v' isn't part of stock count '%v'.", itemId, current.ID),
+					Status:  http.StatusBadRequest,
+					Type:    api.InvalidRequestError,
+				}
+			}
+			return nil, err
+		}
+	}
+
+	row, err := qtx.UpdateStockCountStatus(context.Background(), database.UpdateStockCountStatusParams{
+		UpdatedAt:   time.Now(),
+		AccountID:   submit.AccountId,
+		ID:          submit.StockCountId,
+		FinalizedAt: sql.NullTime{},
+		Status:      database.StockCountStatusOpen,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	lines, err := listLines(qtx, submit.AccountId, row.ID, row.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	stockCount := &StockCount{
+		ID:          &row.ID,
+		CreatedAt:   &row.CreatedAt,
+		UpdatedAt:   &row.UpdatedAt,
+		FinalizedAt: nullTime(row.FinalizedAt),
+		Group:       api.Expandable{ID: row.Group},
+		Lines:       lines,
+		Location:    api.Expandable{ID: row.Location},
+		Status:      row.Status,
+	}
+
+	return stockCount, nil
+}
+
+// checkCountable makes sure the inventory's stock can be set by the plain
+// adjustment Finalize posts. Bundles have no stock of their own, serialized
+// items change through their serial numbers and lots have to be adjusted
+// one by one.
+func checkCountable(q *database.Queries, accountId, inventoryId uuid.UUID) error {
+	bundle, err := q.IsInventoryBundle(context.Background(), database.IsInventoryBundleParams{
+		ID:        inventoryId,
+		AccountID: accountId,
+	})
+	if err != nil {
+		return err
+	}
+	if bundle {
+		return &api.AppError{
+			Message: fmt.Sprintf("Inventory '%v' holds a bundle, its stock is counted through its components.", inventoryId),
+			Status:  http.StatusBadRequest,
+			Type:    api.InvalidRequestError,
+		}
+	}
+
+	serialized, err := q.IsInventorySerialized(context.Background(), database.IsInventorySerializedParams{
+		ID:        inventoryId,
+		AccountID: accountId,
+	})
+	if err != nil {
+		return err
+	}
+	if serialized {
+		return &api.AppError{
+			Message: fmt.Sprintf("Inventory '%v' holds a serialized item, its stock can only be changed through serial numbers.", inventoryId),
+			Status:  http.StatusBadRequest,
+			Type:    api.InvalidRequestError,
+		}
+	}
+
+	lots, err := q.CountInventoryLots(context.Background(), database.CountInventoryLotsParams{
+		AccountID:   accountId,
+		InventoryID: inventoryId,
+	})
+	if err != nil {
+		return err
+	}
+	if lots > 0 {
+		return &api.AppError{
+			Message: fmt.Sprintf("Inventory '%v' tracks lots, its stock has to be adjusted lot by lot.", inventoryId),
+			Status:  http.StatusBadRequest,
+			Type:    api.InvalidRequestError,
+		}
+	}
+
+	return nil
+}
+
+// getForUpdate locks the stock count and makes sure it's still open.
+func getForUpdate(q *database.Queries, accountId, stockCountId uuid.UUID) (*database.GetStockCountForUpdateRow, error) {
+	row, err := q.GetStockCountForUpdate(context.Background(), database.GetStockCountForUpdateParams{
+		ID:        stockCountId,
+		AccountID: accountId,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, api.NotFoundMessage(stockCountId, "stock count")
+		}
+		return nil, err
+	}
+
+	if row.Status != database.StockCountStatusOpen {
+		return nil, &api.AppError{
+			Message: fmt.Sprintf("Stock count '%v' is %s, expected it to be %s.", stockCountId, row.Status, database.StockCountStatusOpen),
+			Status:  http.StatusBadRequest,
+			Type:    api.InvalidRequestError,
+		}
+	}
+
+	return &row, nil
+}
+
+// listLines loads the lines of a stock count. Open counts are measured
+// against the inventories' current in_stock, closed ones keep the values
+// stored when they were finalized.
+func listLines(q *database.Queries, accountId, stockCountId uuid.UUID, status database.StockCountStatus) ([]*StockCountLine, error) {
+	rows, err := q.ListStockCountLines(context.Background(), database.ListStockCountLinesParams{
+		AccountID:    accountId,
+		StockCountID: stockCountId,
+	})
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	lines := make([]*StockCountLine, 0, len(rows))
+	for _, row := range rows {
+		line := &StockCountLine{
+			Counted:   ints.NullInt32(row.Counted),
+			Expected:  row.Expected.Int32,
+			Inventory: api.Expandable{ID: row.Inventory},
+			Item:      api.Expandable{ID: row.Item},
+			Variance:  ints.NullInt32(row.Variance),
+		}
+		if status == database.StockCountStatusOpen {
+			line.Expected = row.InStock
+			if row.Counted.Valid {
+				line.Variance = ints.NullInt32{Int32: row.Counted.Int32 - row.InStock, Valid: true}
+			}
+		}
+		lines = append(lines, line)
+	}
+
+	return lines, nil
+}
+
+func resolveItem(q *database.Queries, accountId uuid.UUID, entry StockCountEntry) (uuid.UUID, error) {
+	if (entry.Item == nil) == (entry.Identifier == nil) {
+		return uuid.Nil, &api.AppError{
+			Message: "Each count must have either an item or an identifier.",
+			Status:  http.StatusBadRequest,
+			Type:    api.InvalidRequestError,
+		}
+	}
+
+	if entry.Item != nil {
+		return uuid.MustParse(*entry.Item), nil
+	}
+
+	row, err := q.GetItemIdentifierByCode(context.Background(), database.GetItemIdentifierByCodeParams{
+		AccountID: accountId,
+		Code:      *entry.Identifier,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return uuid.Nil, &api.AppError{
+				Message: fmt.Sprintf("No item has the identifier '%s'.", *entry.Identifier),
+				Status:  http.StatusBadRequest,
+				Type:    api.InvalidRequestError,
+			}
+		}
+		return uuid.Nil, err
+	}
+
+	return row.Item.UUID, nil
+}
+
+func nullTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
@@ -0,0 +1,32 @@
+package stockcounts
+
+import (
+	"time"
+
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/d-darac/inventory-assets/ints"
+	"github.com/google/uuid"
+)
+
+type StockCount struct {
+	ID          *uuid.UUID                `json:"id,omitempty"`
+	CreatedAt   *time.Time                `json:"created_at,omitempty"`
+	UpdatedAt   *time.Time                `json:"updated_at,omitempty"`
+	FinalizedAt *time.Time                `json:"finalized_at"`
+	Group       api.Expandable            `json:"group"`
+	Lines       []*StockCountLine         `json:"lines,omitempty"`
+	Location    api.Expandable            `json:"location"`
+	Status      database.StockCountStatus `json:"status"`
+}
+
+// StockCountLine is the count of a single inventory in the session. While
+// the count is open, Expected and Variance are measured against the live
+// in_stock; once finalized they're the values the adjustment was posted for.
+type StockCountLine struct {
+	Counted   ints.NullInt32 `json:"counted"`
+	Expected  int32          `json:"expected"`
+	Inventory api.Expandable `json:"inventory"`
+	Item      api.Expandable `json:"item"`
+	Variance  ints.NullInt32 `json:"variance"`
+}
@@ -0,0 +1,327 @@
+package stockcounts
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/d-darac/inventory-api/internal/testutil"
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+	"github.com/joho/godotenv"
+	_ "github.com/lib/pq"
+)
+
+func TestUnitCreateScope(t *testing.T) {
+	s := NewStockCountsService(nil, nil)
+	group := uuid.New().String()
+
+	tests := []struct {
+		name   string
+		params CreateStockCountParams
+	}{
+		{name: "unscoped", params: CreateStockCountParams{}},
+		{name: "group and items", params: CreateStockCountParams{Group: &group, Items: []string{uuid.New().String()}}},
+	}
+
+	for _, tt := range tests {
+		_, err := s.Create(Create{AccountId: uuid.New(), RequestParams: tt.params})
+		if _, ok := err.(*api.AppError); !ok {
+			t.Fatalf("%s: expected an invalid request error, got %v", tt.name, err)
+		}
+	}
+}
+
+func TestUnitResolveItem(t *testing.T) {
+	item := uuid.New()
+	itemString := item.String()
+	identifier := "0123456789012"
+
+	tests := []struct {
+		name  string
+		entry StockCountEntry
+		ok    bool
+	}{
+		{name: "item", entry: StockCountEntry{Item: &itemString}, ok: true},
+		{name: "neither", entry: StockCountEntry{}},
+		{name: "both", entry: StockCountEntry{Identifier: &identifier, Item: &itemString}},
+	}
+
+	for _, tt := range tests {
+		itemId, err := resolveItem(nil, uuid.New(), tt.entry)
+		if tt.ok {
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+			if itemId != item {
+				t.Fatalf("%s: expected item %v, got %v", tt.name, item, itemId)
+			}
+			continue
+		}
+		if _, ok := err.(*api.AppError); !ok {
+			t.Fatalf("%s: expected an invalid request error, got %v", tt.name, err)
+		}
+	}
+}
+
+func TestIntegrationFinalize(t *testing.T) {
+	godotenv.Load("../../.env")
+	dbUrl := os.Getenv("DB_URL")
+
+	db, err := sql.Open("postgres", dbUrl)
+	if err != nil {
+		t.Fatalf("couldn't open database: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.Ping(); err != nil {
+		t.Fatalf("couldn't connect to database: %v", err)
+	}
+
+	q := database.New(db)
+
+	acc, err := q.CreateAccount(context.Background(), database.CreateAccountParams{
+		Country: database.CountryIE,
+		Nickname: sql.NullString{
+			String: "Test Account",
+			Valid:  true,
+		},
+	})
+	if err != nil {
+		t.Fatalf("couldn't create test account: %v", err)
+	}
+	defer db.Exec("DELETE FROM accounts WHERE id = $1;", acc.ID)
+
+	location, err := q.CreateLocation(context.Background(), database.CreateLocationParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		AccountID: acc.ID,
+		Name:      "Test Location",
+		Type:      database.LocationTypeWarehouse,
+	})
+	if err != nil {
+		t.Fatalf("couldn't create test location: %v", err)
+	}
+
+	// The first item is counted above its stock, the second below it and the
+	// third not at all.
+	inStock := []int32{10, 10, 10}
+	counted := []int32{12, 7}
+	items := make([]uuid.UUID, 0, len(inStock))
+	inventories := make([]uuid.UUID, 0, len(inStock))
+	for _, n := range inStock {
+		item, err := q.CreateItem(context.Background(), database.CreateItemParams{
+			ID:        uuid.New(),
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+			AccountID: acc.ID,
+			Name:      "Test Item",
+			Type:      database.ItemTypePRODUCT,
+		})
+		if err != nil {
+			t.Fatalf("couldn't create test item: %v", err)
+		}
+		inventory, err := q.CreateInventory(context.Background(), database.CreateInventoryParams{
+			ID:         uuid.New(),
+			CreatedAt:  time.Now(),
+			UpdatedAt:  time.Now(),
+			AccountID:  acc.ID,
+			InStock:    n,
+			ItemID:     api.NullUUID(&item.ID),
+			LocationID: api.NullUUID(&location.ID),
+		})
+		if err != nil {
+			t.Fatalf("couldn't create test inventory: %v", err)
+		}
+		items = append(items, item.ID)
+		inventories = append(inventories, inventory.ID)
+	}
+
+	s := NewStockCountsService(q, db)
+	locationId := location.ID.String()
+	itemIds := make([]string, 0, len(items))
+	for _, item := range items {
+		itemIds = append(itemIds, item.String())
+	}
+
+	stockCount, err := s.Create(Create{AccountId: acc.ID, RequestParams: CreateStockCountParams{Items: itemIds, Location: locationId}})
+	if err != nil {
+		t.Fatalf("couldn't create stock count: %v", err)
+	}
+	if len(stockCount.Lines) != len(items) {
+		t.Fatalf("expected %d lines, got %d", len(items), len(stockCount.Lines))
+	}
+
+	counts := make([]StockCountEntry, 0, len(counted))
+	for i, n := range counted {
+		counts = append(counts, StockCountEntry{Item: &itemIds[i], Quantity: n})
+	}
+	if _, err := s.Submit(Submit{AccountId: acc.ID, StockCountId: *stockCount.ID, RequestParams: SubmitStockCountParams{Counts: counts}}); err != nil {
+		t.Fatalf("couldn't submit counts: %v", err)
+	}
+
+	stockCount, err = s.Finalize(Finalize{AccountId: acc.ID, StockCountId: *stockCount.ID})
+	if err != nil {
+		t.Fatalf("couldn't finalize stock count: %v", err)
+	}
+	if stockCount.Status != database.StockCountStatusFinalized {
+		t.Fatalf("expected status %v, got %v", database.StockCountStatusFinalized, stockCount.Status)
+	}
+
+	inventoryLineMap := make(map[uuid.UUID]*StockCountLine, len(stockCount.Lines))
+	for _, line := range stockCount.Lines {
+		inventoryLineMap[line.Inventory.ID.UUID] = line
+	}
+
+	for i, inventoryId := range inventories {
+		row, err := q.GetInventory(context.Background(), database.GetInventoryParams{ID: inventoryId, AccountID: acc.ID})
+		if err != nil {
+			t.Fatalf("error retrieving inventory: %v", err)
+		}
+		line := inventoryLineMap[inventoryId]
+		if line == nil {
+			t.Fatalf("expected a line for inventory %v", inventoryId)
+		}
+		if line.Expected != inStock[i] {
+			t.Fatalf("expected line %d to expect %d, got %d", i, inStock[i], line.Expected)
+		}
+
+		if i >= len(counted) {
+			if row.InStock != inStock[i] || line.Variance.Valid {
+				t.Fatalf("expected the uncounted inventory to keep %d without a variance, got %d %v", inStock[i], row.InStock, line.Variance)
+			}
+			continue
+		}
+		if row.InStock != counted[i] {
+			t.Fatalf("expected in_stock %d after the count, got %d", counted[i], row.InStock)
+		}
+		if !line.Variance.Valid || line.Variance.Int32 != counted[i]-inStock[i] {
+			t.Fatalf("expected variance %d, got %v", counted[i]-inStock[i], line.Variance)
+		}
+	}
+
+	if _, err := s.Finalize(Finalize{AccountId: acc.ID, StockCountId: *stockCount.ID}); err == nil {
+		t.Fatal("expected finalizing a finalized stock count to fail")
+	}
+	if _, err := s.Submit(Submit{AccountId: acc.ID, StockCountId: *stockCount.ID, RequestParams: SubmitStockCountParams{Counts: counts}}); err == nil {
+		t.Fatal("expected submitting to a finalized stock count to fail")
+	}
+
+	// A second finalize mustn't post the variances again.
+	for i, n := range counted {
+		row, err := q.GetInventory(context.Background(), database.GetInventoryParams{ID: inventories[i], AccountID: acc.ID})
+		if err != nil {
+			t.Fatalf("error retrieving inventory: %v", err)
+		}
+		if row.InStock != n {
+			t.Fatalf("expected in_stock %d after finalizing twice, got %d", n, row.InStock)
+		}
+	}
+}
+
+func TestIntegrationCreateDuplicateItem(t *testing.T) {
+	db, q, accountId := testutil.OpenAccount(t)
+	defer db.Close()
+	defer db.Exec("DELETE FROM accounts WHERE id = $1;", accountId)
+
+	s := NewStockCountsService(q, db)
+	location := testutil.CreateLocation(t, q, accountId)
+	item := testutil.CreateItem(t, q, accountId, database.ItemTypePRODUCT)
+	testutil.CreateInventory(t, q, accountId, item, &location, 4)
+	testutil.CreateInventory(t, q, accountId, item, &location, 6)
+
+	_, err := s.Create(Create{AccountId: accountId, RequestParams: CreateStockCountParams{Items: []string{item.String()}, Location: location.String()}})
+	if _, ok := err.(*api.AppError); !ok {
+		t.Fatalf("expected an invalid request error for an item with two inventories at the location, got %v", err)
+	}
+}
+
+func TestIntegrationCreateUncountable(t *testing.T) {
+	db, q, accountId := testutil.OpenAccount(t)
+	defer db.Close()
+	defer db.Exec("DELETE FROM accounts WHERE id = $1;", accountId)
+
+	s := NewStockCountsService(q, db)
+	location := testutil.CreateLocation(t, q, accountId)
+
+	group, err := q.CreateGroup(context.Background(), database.CreateGroupParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		AccountID: accountId,
+		Name:      "Test Group",
+	})
+	if err != nil {
+		t.Fatalf("couldn't create test group: %v", err)
+	}
+
+	// Every item is in the group, only the product without lots can be
+	// counted.
+	itemTypes := []database.ItemType{database.ItemTypePRODUCT, database.ItemTypeBUNDLE, database.ItemTypePRODUCT}
+	items := make([]uuid.UUID, 0, len(itemTypes))
+	inventories := make([]uuid.UUID, 0, len(itemTypes))
+	for _, itemType := range itemTypes {
+		item, err := q.CreateItem(context.Background(), database.CreateItemParams{
+			ID:        uuid.New(),
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+			AccountID: accountId,
+			GroupID:   api.NullUUID(&group.ID),
+			Name:      "Test Item",
+			Type:      itemType,
+		})
+		if err != nil {
+			t.Fatalf("couldn't create test item: %v", err)
+		}
+		items = append(items, item.ID)
+		inventories = append(inventories, testutil.CreateInventory(t, q, accountId, item.ID, &location, 0))
+	}
+
+	_, err = q.CreateLot(context.Background(), database.CreateLotParams{
+		ID:          uuid.New(),
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+		AccountID:   accountId,
+		InventoryID: inventories[2],
+		LotNumber:   "L1",
+	})
+	if err != nil {
+		t.Fatalf("couldn't create test lot: %v", err)
+	}
+
+	for _, item := range items[1:] {
+		_, err := s.Create(Create{AccountId: accountId, RequestParams: CreateStockCountParams{Items: []string{item.String()}, Location: location.String()}})
+		if _, ok := err.(*api.AppError); !ok {
+			t.Fatalf("expected an invalid request error for item %v, got %v", item, err)
+		}
+	}
+
+	groupId := group.ID.String()
+	stockCount, err := s.Create(Create{AccountId: accountId, RequestParams: CreateStockCountParams{Group: &groupId, Location: location.String()}})
+	if err != nil {
+		t.Fatalf("couldn't create stock count: %v", err)
+	}
+	if len(stockCount.Lines) != 1 || stockCount.Lines[0].Inventory.ID.UUID != inventories[0] {
+		t.Fatalf("expected a single line for inventory %v, got %d lines", inventories[0], len(stockCount.Lines))
+	}
+}
+
+func TestIntegrationCreateSerialized(t *testing.T) {
+	db, q, accountId := testutil.OpenAccount(t)
+	defer db.Close()
+	defer db.Exec("DELETE FROM accounts WHERE id = $1;", accountId)
+
+	s := NewStockCountsService(q, db)
+	location := testutil.CreateLocation(t, q, accountId)
+	item := testutil.CreateSerializedItem(t, q, accountId)
+	testutil.CreateInventory(t, q, accountId, item, &location, 0)
+
+	_, err := s.Create(Create{AccountId: accountId, RequestParams: CreateStockCountParams{Items: []string{item.String()}, Location: location.String()}})
+	if _, ok := err.(*api.AppError); !ok {
+		t.Fatalf("expected an invalid request error for a serialized item, got %v", err)
+	}
+}
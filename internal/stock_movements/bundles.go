@@ -0,0 +1,90 @@
+package stockmovements
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/currency"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/d-darac/inventory-assets/ints"
+	"github.com/d-darac/inventory-assets/str"
+	"github.com/google/uuid"
+)
+
+// postBundle records a sale of a bundle. A bundle's inventory holds no stock
+// of its own, so its movement leaves in_stock alone and the sale is posted
+// to the inventories of its components at the same location instead, each
+// referencing the bundle's movement.
+func postBundle(q *database.Queries, create Create, inventoryId uuid.UUID) (*StockMovement, error) {
+	if create.RequestParams.Type != database.StockMovementTypeSale {
+		return nil, &api.AppError{
+			Message: fmt.Sprintf("Inventory '%v' holds a bundle, which has no stock of its own; only sales can be posted to it.", inventoryId),
+			Status:  http.StatusBadRequest,
+			Type:    api.InvalidRequestError,
+		}
+	}
+
+	components, err := q.ListBundleComponentInventories(context.Background(), database.ListBundleComponentInventoriesParams{
+		AccountID:   create.AccountId,
+		InventoryID: inventoryId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(components) == 0 {
+		return nil, &api.AppError{
+			Message: fmt.Sprintf("The bundle in inventory '%v' has no components to sell.", inventoryId),
+			Status:  http.StatusBadRequest,
+			Type:    api.InvalidRequestError,
+		}
+	}
+
+	dbParams := MapCreateStockMovementParams(create)
+
+	row, err := q.CreateStockMovement(context.Background(), dbParams)
+	if err != nil {
+		return nil, err
+	}
+
+	reference := row.ID.String()
+	for _, component := range components {
+		if !component.Inventory.Valid {
+			return nil, &api.AppError{
+				Message: fmt.Sprintf("Component item '%v' has no inventory at the location of bundle inventory '%v'.", component.Item, inventoryId),
+				Status:  http.StatusBadRequest,
+				Type:    api.InvalidRequestError,
+			}
+		}
+
+		_, err := Post(q, Create{
+			AccountId: create.AccountId,
+			RequestParams: CreateStockMovementParams{
+				Inventory: component.Inventory.UUID.String(),
+				Quantity:  create.RequestParams.Quantity * component.Quantity,
+				Reason:    create.RequestParams.Reason,
+				Reference: &reference,
+				Type:      database.StockMovementTypeSale,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	stockMovement := &StockMovement{
+		ID:               &row.ID,
+		CreatedAt:        &row.CreatedAt,
+		Inventory:        api.Expandable{ID: row.Inventory},
+		Lot:              api.Expandable{ID: row.Lot},
+		Quantity:         row.Quantity,
+		Reason:           str.NullString(row.Reason),
+		Reference:        str.NullString(row.Reference),
+		Type:             row.Type,
+		UnitCostAmount:   ints.NullInt32(row.UnitCostAmount),
+		UnitCostCurrency: currency.NullCurrency(row.UnitCostCurrency),
+	}
+
+	return stockMovement, nil
+}
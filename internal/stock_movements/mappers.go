@@ -0,0 +1,56 @@
+package stockmovements
+
+import (
+	"time"
+
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+func MapCreateStockMovementParams(create Create) database.CreateStockMovementParams {
+	csmp := database.CreateStockMovementParams{
+		ID:               uuid.New(),
+		CreatedAt:        time.Now(),
+		AccountID:        create.AccountId,
+		InventoryID:      uuid.MustParse(create.RequestParams.Inventory),
+		LotID:            api.NullUUID(nil),
+		Quantity:         create.RequestParams.Quantity,
+		Reason:           api.NullString(create.RequestParams.Reason),
+		Reference:        api.NullString(create.RequestParams.Reference),
+		Type:             create.RequestParams.Type,
+		UnitCostAmount:   api.NullInt32(create.RequestParams.UnitCostAmount),
+		UnitCostCurrency: api.NullCurrency(create.RequestParams.UnitCostCurrency),
+	}
+	if create.RequestParams.Lot != nil {
+		lotId := uuid.MustParse(*create.RequestParams.Lot)
+		csmp.LotID = api.NullUUID(&lotId)
+	}
+	return csmp
+}
+
+func MapListStockMovementsParams(list List) database.ListStockMovementsParams {
+	lsmp := database.ListStockMovementsParams{
+		AccountID:   list.AccountId,
+		InventoryID: api.NullUUID(nil),
+		LotID:       api.NullUUID(nil),
+		Reference:   api.NullString(list.RequestParams.Reference),
+	}
+	if list.RequestParams.Inventory != nil {
+		inventoryId := uuid.MustParse(*list.RequestParams.Inventory)
+		lsmp.InventoryID = api.NullUUID(&inventoryId)
+	}
+	if list.RequestParams.Lot != nil {
+		lotId := uuid.MustParse(*list.RequestParams.Lot)
+		lsmp.LotID = api.NullUUID(&lotId)
+	}
+	if list.RequestParams.Type != nil {
+		lsmp.Type = database.NullStockMovementType{
+			StockMovementType: *list.RequestParams.Type,
+			Valid:             true,
+		}
+	}
+	database.MapTimeRange(list.RequestParams.CreatedAt, &lsmp.CreatedAtGt, &lsmp.CreatedAtGte, &lsmp.CreatedAtLt, &lsmp.CreatedAtLte)
+	database.MapPaginationParams(*list.RequestParams.PaginationParams, &lsmp)
+	return lsmp
+}
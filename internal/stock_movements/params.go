@@ -0,0 +1,43 @@
+package stockmovements
+
+import (
+	"github.com/d-darac/inventory-assets/database"
+)
+
+// CreateStockMovementParams takes quantity in unit when one is given, see
+// units.ToStockingUnit.
+type CreateStockMovementParams struct {
+	Inventory        string                     `json:"inventory" validate:"required,uuid"`
+	Lot              *string                    `json:"lot" validate:"omitnil,uuid"`
+	Quantity         int32                      `json:"quantity" validate:"required,ne=0"`
+	Reason           *string                    `json:"reason" validate:"omitnil"`
+	Reference        *string                    `json:"reference" validate:"omitnil"`
+	Type             database.StockMovementType `json:"type" validate:"required,oneof=receipt sale adjustment write_off"`
+	Unit             *string                    `json:"unit" validate:"omitnil,uuid"`
+	UnitCostAmount   *int32                     `json:"unit_cost_amount" validate:"omitnil,gte=0"`
+	UnitCostCurrency *database.Currency         `json:"unit_cost_currency" validate:"omitnil,currency,required_with=UnitCostAmount"`
+	Expand           []string                   `json:"expand" validate:"omitnil,dive,oneof=inventory lot"`
+}
+
+type ListStockMovementsParams struct {
+	*database.PaginationParams
+	CreatedAt *database.TimeRange         `json:"created_at" validate:"omitnil"`
+	Inventory *string                     `json:"inventory" validate:"omitnil,uuid"`
+	Lot       *string                     `json:"lot" validate:"omitnil,uuid"`
+	Reference *string                     `json:"reference" validate:"omitnil"`
+	Type      *database.StockMovementType `json:"type" validate:"omitnil,oneof=receipt sale adjustment write_off return transfer_in transfer_out"`
+	Expand    []string                    `json:"expand" validate:"omitnil,dive,oneof=inventory lot"`
+}
+
+type RetrieveStockMovementParams struct {
+	Expand []string `json:"expand" validate:"omitnil,dive,oneof=inventory lot"`
+}
+
+func NewListStockMovementsParams() ListStockMovementsParams {
+	limit := int32(10)
+	return ListStockMovementsParams{
+		PaginationParams: &database.PaginationParams{
+			Limit: &limit,
+		},
+	}
+}
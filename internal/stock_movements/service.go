@@ -0,0 +1,492 @@
+package stockmovements
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/d-darac/inventory-api/internal/events"
+	"github.com/d-darac/inventory-api/internal/units"
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/currency"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/d-darac/inventory-assets/ints"
+	"github.com/d-darac/inventory-assets/str"
+	"github.com/google/uuid"
+)
+
+type StockMovementsService struct {
+	Conn *sql.DB
+	Db   *database.Queries
+}
+
+type Create struct {
+	AccountId     uuid.UUID
+	RequestParams CreateStockMovementParams
+	// FromSerialNumber marks movements posted for a serial number, the only
+	// ones allowed on inventories of serialized items.
+	FromSerialNumber bool
+}
+
+type Get struct {
+	AccountId       uuid.UUID
+	StockMovementId uuid.UUID
+	RequestParams   RetrieveStockMovementParams
+	OmitBase        bool
+}
+
+type List struct {
+	AccountId     uuid.UUID
+	RequestParams ListStockMovementsParams
+}
+
+func NewStockMovementsService(db *database.Queries, conn *sql.DB) *StockMovementsService {
+	return &StockMovementsService{
+		Conn: conn,
+		Db:   db,
+	}
+}
+
+func (s *StockMovementsService) Create(create Create) (*StockMovement, error) {
+	tx, err := s.Conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	stockMovement, err := Post(s.Db.WithTx(tx), create)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return stockMovement, nil
+}
+
+func (s *StockMovementsService) Get(get Get) (*StockMovement, error) {
+	row, err := s.Db.GetStockMovement(context.Background(), database.GetStockMovementParams{
+		ID:        get.StockMovementId,
+		AccountID: get.AccountId,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, api.NotFoundMessage(get.StockMovementId, "stock movement")
+		}
+		return nil, err
+	}
+
+	stockMovement := &StockMovement{
+		Inventory:        api.Expandable{ID: row.Inventory},
+		Lot:              api.Expandable{ID: row.Lot},
+		Quantity:         row.Quantity,
+		Reason:           str.NullString(row.Reason),
+		Reference:        str.NullString(row.Reference),
+		Type:             row.Type,
+		UnitCostAmount:   ints.NullInt32(row.UnitCostAmount),
+		UnitCostCurrency: currency.NullCurrency(row.UnitCostCurrency),
+	}
+
+	if !get.OmitBase {
+		stockMovement.ID = &row.ID
+		stockMovement.CreatedAt = &row.CreatedAt
+	}
+
+	return stockMovement, nil
+}
+
+func (s *StockMovementsService) List(list List) (stockMovements []*StockMovement, hasMore bool, err error) {
+	if list.RequestParams.StartingAfter != nil {
+		stockMovement, err := s.Get(Get{
+			AccountId:       list.AccountId,
+			StockMovementId: *list.RequestParams.StartingAfter,
+			RequestParams:   RetrieveStockMovementParams{},
+			OmitBase:        false,
+		})
+		if err != nil {
+			return stockMovements, hasMore, err
+		}
+		list.RequestParams.StartingAfterDate = stockMovement.CreatedAt
+	}
+
+	if list.RequestParams.EndingBefore != nil {
+		stockMovement, err := s.Get(Get{
+			AccountId:       list.AccountId,
+			StockMovementId: *list.RequestParams.EndingBefore,
+			RequestParams:   RetrieveStockMovementParams{},
+			OmitBase:        false,
+		})
+		if err != nil {
+			return stockMovements, hasMore, err
+		}
+		list.RequestParams.EndingBeforeDate = stockMovement.CreatedAt
+	}
+
+	dbParams := MapListStockMovementsParams(list)
+
+	rows, err := s.Db.ListStockMovements(context.Background(), dbParams)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return stockMovements, hasMore, nil
+		}
+		return
+	}
+
+	if dbParams.Limit.Valid {
+		hasMore = len(rows) > int(dbParams.Limit.Int32)
+	} else {
+		hasMore = len(rows) > 10
+	}
+
+	if hasMore {
+		if dbParams.EndingBefore.Valid {
+			rows = rows[1:]
+		} else {
+			rows = rows[:len(rows)-1]
+		}
+	}
+
+	for _, row := range rows {
+		stockMovements = append(stockMovements, &StockMovement{
+			ID:               &row.ID,
+			CreatedAt:        &row.CreatedAt,
+			Inventory:        api.Expandable{ID: row.Inventory},
+			Lot:              api.Expandable{ID: row.Lot},
+			Quantity:         row.Quantity,
+			Reason:           str.NullString(row.Reason),
+			Reference:        str.NullString(row.Reference),
+			Type:             row.Type,
+			UnitCostAmount:   ints.NullInt32(row.UnitCostAmount),
+			UnitCostCurrency: currency.NullCurrency(row.UnitCostCurrency),
+		})
+	}
+
+	return stockMovements, hasMore, err
+}
+
+// Post records a stock movement and applies its quantity to the inventory's
+// in_stock, raising a low-stock event if that takes the inventory to its
+// reorder point. The inventory row is locked for the duration of the call, so q
+// should be bound to a transaction that the caller commits.
+func Post(q *database.Queries, create Create) (*StockMovement, error) {
+	if err := CheckQuantity(create.RequestParams.Type, create.RequestParams.Quantity); err != nil {
+		return nil, err
+	}
+
+	if err := CheckUnitCost(create.RequestParams); err != nil {
+		return nil, err
+	}
+
+	inventoryId := uuid.MustParse(create.RequestParams.Inventory)
+
+	inventory, err := q.GetInventoryForUpdate(context.Background(), database.GetInventoryForUpdateParams{
+		ID:        inventoryId,
+		AccountID: create.AccountId,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, api.NotFoundMessage(inventoryId, "inventory")
+		}
+		return nil, err
+	}
+
+	// A quantity in the item's purchase or sales unit is recorded in its
+	// stocking unit, which is what in_stock and lots are kept in.
+	if create.RequestParams.Unit != nil {
+		unitId := uuid.MustParse(*create.RequestParams.Unit)
+		quantity, err := units.ToStockingUnit(q, create.AccountId, inventory.Item, unitId, create.RequestParams.Quantity)
+		if err != nil {
+			return nil, err
+		}
+		create.RequestParams.Quantity = quantity
+	}
+
+	bundle, err := q.IsInventoryBundle(context.Background(), database.IsInventoryBundleParams{
+		ID:        inventoryId,
+		AccountID: create.AccountId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if bundle {
+		return postBundle(q, create, inventoryId)
+	}
+
+	serialized, err := q.IsInventorySerialized(context.Background(), database.IsInventorySerializedParams{
+		ID:        inventoryId,
+		AccountID: create.AccountId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if serialized && !create.FromSerialNumber {
+		return nil, &api.AppError{
+			Message: fmt.Sprintf("Inventory '%v' holds a serialized item, its stock can only be changed through serial numbers.", inventoryId),
+			Status:  http.StatusBadRequest,
+			Type:    api.InvalidRequestError,
+		}
+	}
+
+	if !CheckAvailable(inventory.InStock, inventory.Reserved.Int32, create.RequestParams.Quantity) {
+		return nil, InsufficientStockMessage(inventoryId)
+	}
+
+	if err := postToLots(q, create.AccountId, inventoryId, create.RequestParams); err != nil {
+		return nil, err
+	}
+
+	dbParams := MapCreateStockMovementParams(create)
+
+	row, err := q.CreateStockMovement(context.Background(), dbParams)
+	if err != nil {
+		return nil, err
+	}
+
+	adjusted, err := q.AdjustInventoryInStock(context.Background(), database.AdjustInventoryInStockParams{
+		UpdatedAt: row.CreatedAt,
+		AccountID: create.AccountId,
+		ID:        inventoryId,
+		Quantity:  row.Quantity,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	wasBelow := events.BelowReorderPoint(inventory.InStock, inventory.Reserved.Int32, ints.NullInt32(inventory.ReorderPoint))
+	err = events.RecordLowStock(q, create.AccountId, wasBelow, events.LowStock{
+		Inventory:       adjusted.ID,
+		InStock:         adjusted.InStock,
+		Item:            adjusted.Item,
+		Location:        adjusted.Location,
+		ReorderPoint:    ints.NullInt32(adjusted.ReorderPoint),
+		ReorderQuantity: ints.NullInt32(adjusted.ReorderQuantity),
+		Reserved:        adjusted.Reserved.Int32,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stockMovement := &StockMovement{
+		ID:               &row.ID,
+		CreatedAt:        &row.CreatedAt,
+		Inventory:        api.Expandable{ID: row.Inventory},
+		Lot:              api.Expandable{ID: row.Lot},
+		Quantity:         row.Quantity,
+		Reason:           str.NullString(row.Reason),
+		Reference:        str.NullString(row.Reference),
+		Type:             row.Type,
+		UnitCostAmount:   ints.NullInt32(row.UnitCostAmount),
+		UnitCostCurrency: currency.NullCurrency(row.UnitCostCurrency),
+	}
+
+	return stockMovement, nil
+}
+
+// CheckQuantity enforces the sign convention of each movement type: receipts,
+// returns and incoming transfers add stock, sales, write-offs and outgoing
+// transfers remove it, adjustments go either way.
+func CheckQuantity(movementType database.StockMovementType, quantity int32) error {
+	switch movementType {
+	case database.StockMovementTypeReceipt, database.StockMovementTypeReturn, database.StockMovementTypeTransferIn:
+		if quantity <= 0 {
+			return invalidQuantityMessage(movementType, "positive")
+		}
+	case database.StockMovementTypeSale, database.StockMovementTypeWriteOff, database.StockMovementTypeTransferOut:
+		if quantity >= 0 {
+			return invalidQuantityMessage(movementType, "negative")
+		}
+	case database.StockMovementTypeAdjustment:
+		if quantity == 0 {
+			return &api.AppError{
+				Message: "Quantity of an adjustment can't be zero.",
+				Status:  http.StatusBadRequest,
+				Type:    api.InvalidRequestError,
+			}
+		}
+	}
+	return nil
+}
+
+// CheckAvailable reports whether an inventory can take a movement of the
+// quantity. Decrements can only use stock that isn't held by a reservation;
+// committing a reservation gives its hold back before the sale is posted, so
+// the sale can use the stock it held.
+func CheckAvailable(inStock, reserved, quantity int32) bool {
+	if quantity >= 0 {
+		return inStock+quantity >= 0
+	}
+	return inStock-reserved+quantity >= 0
+}
+
+// postToLots keeps the lots of an inventory in step with its in_stock. A
+// movement naming a lot goes to that lot, a decrement without one is taken
+// from the lots first-expired-first-out, see planLotConsumption. Inventories
+// without lots are left alone.
+func postToLots(q *database.Queries, accountId, inventoryId uuid.UUID, params CreateStockMovementParams) error {
+	if params.Lot != nil {
+		lotId := uuid.MustParse(*params.Lot)
+		lot, err := q.GetLotForUpdate(context.Background(), database.GetLotForUpdateParams{
+			ID:        lotId,
+			AccountID: accountId,
+		})
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return api.NotFoundMessage(lotId, "lot")
+			}
+			return err
+		}
+		if lot.Inventory.UUID != inventoryId {
+			return &api.AppError{
+				Message: fmt.Sprintf("Lot '%v' doesn't belong to inventory '%v'.", lotId, inventoryId),
+				Status:  http.StatusBadRequest,
+				Type:    api.InvalidRequestError,
+			}
+		}
+		if lot.Quantity+params.Quantity < 0 {
+			return &api.AppError{
+				Message: fmt.Sprintf("Insufficient stock in lot '%v'.", lotId),
+				Status:  http.StatusBadRequest,
+				Type:    api.InvalidRequestError,
+			}
+		}
+		_, err = q.AdjustLotQuantity(context.Background(), database.AdjustLotQuantityParams{
+			UpdatedAt: time.Now(),
+			AccountID: accountId,
+			ID:        lotId,
+			Quantity:  params.Quantity,
+		})
+		return err
+	}
+
+	count, err := q.CountInventoryLots(context.Background(), database.CountInventoryLotsParams{
+		AccountID:   accountId,
+		InventoryID: inventoryId,
+	})
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return nil
+	}
+
+	if params.Quantity > 0 {
+		return &api.AppError{
+			Message: fmt.Sprintf("Inventory '%v' tracks lots, a lot is required to add stock to it.", inventoryId),
+			Status:  http.StatusBadRequest,
+			Type:    api.InvalidRequestError,
+		}
+	}
+
+	lots, err := q.ListLotsForConsumption(context.Background(), database.ListLotsForConsumptionParams{
+		AccountID:   accountId,
+		InventoryID: inventoryId,
+	})
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	// in_stock is the sum of the lots, but expired lots don't count here, so
+	// the check against it in Post doesn't cover this.
+	takes, ok := planLotConsumption(lots, -params.Quantity, time.Now())
+	if !ok {
+		return &api.AppError{
+			Message: fmt.Sprintf("Insufficient unexpired stock in the lots of inventory '%v', expired stock has to be taken out of its lot by naming it.", inventoryId),
+			Status:  http.StatusBadRequest,
+			Type:    api.InvalidRequestError,
+		}
+	}
+
+	for _, take := range takes {
+		_, err := q.AdjustLotQuantity(context.Background(), database.AdjustLotQuantityParams{
+			UpdatedAt: time.Now(),
+			AccountID: accountId,
+			ID:        take.Lot,
+			Quantity:  -take.Quantity,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// lotTake is the part of a decrement taken from one lot.
+type lotTake struct {
+	Lot      uuid.UUID
+	Quantity int32
+}
+
+// planLotConsumption splits a decrement of quantity over the lots, using up
+// the one that expires first before moving on to the next. Lots without an
+// expiry date go last and lots that expired before now are passed over. It
+// reports false when the lots left can't cover the quantity.
+func planLotConsumption(lots []database.ListLotsForConsumptionRow, quantity int32, now time.Time) ([]lotTake, bool) {
+	ordered := slices.Clone(lots)
+	slices.SortStableFunc(ordered, func(a, b database.ListLotsForConsumptionRow) int {
+		switch {
+		case a.ExpiryDate.Valid && b.ExpiryDate.Valid:
+			if c := a.ExpiryDate.Time.Compare(b.ExpiryDate.Time); c != 0 {
+				return c
+			}
+		case a.ExpiryDate.Valid:
+			return -1
+		case b.ExpiryDate.Valid:
+			return 1
+		}
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	takes := make([]lotTake, 0, len(ordered))
+	remaining := quantity
+	for _, lot := range ordered {
+		if remaining == 0 {
+			break
+		}
+		if lot.Quantity <= 0 || (lot.ExpiryDate.Valid && lot.ExpiryDate.Time.Before(now)) {
+			continue
+		}
+		take := min(lot.Quantity, remaining)
+		takes = append(takes, lotTake{Lot: lot.ID, Quantity: take})
+		remaining -= take
+	}
+
+	return takes, remaining == 0
+}
+
+// CheckUnitCost makes sure receipts carry the unit cost that valuation is
+// based on.
+func CheckUnitCost(params CreateStockMovementParams) error {
+	if params.Type != database.StockMovementTypeReceipt {
+		return nil
+	}
+	if params.UnitCostAmount == nil || params.UnitCostCurrency == nil {
+		return &api.AppError{
+			Message: "A receipt must have a unit_cost_amount and a unit_cost_currency.",
+			Status:  http.StatusBadRequest,
+			Type:    api.InvalidRequestError,
+		}
+	}
+	return nil
+}
+
+func InsufficientStockMessage(inventoryId uuid.UUID) error {
+	return &api.AppError{
+		Message: fmt.Sprintf("Insufficient stock in inventory '%v'.", inventoryId),
+		Status:  http.StatusBadRequest,
+		Type:    api.InvalidRequestError,
+	}
+}
+
+func invalidQuantityMessage(movementType database.StockMovementType, sign string) error {
+	return &api.AppError{
+		Message: fmt.Sprintf("Quantity of a '%s' movement must be %s.", movementType, sign),
+		Status:  http.StatusBadRequest,
+		Type:    api.InvalidRequestError,
+	}
+}
@@ -0,0 +1,25 @@
+package stockmovements
+
+import (
+	"time"
+
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/currency"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/d-darac/inventory-assets/ints"
+	"github.com/d-darac/inventory-assets/str"
+	"github.com/google/uuid"
+)
+
+type StockMovement struct {
+	ID               *uuid.UUID                 `json:"id,omitempty"`
+	CreatedAt        *time.Time                 `json:"created_at,omitempty"`
+	Inventory        api.Expandable             `json:"inventory"`
+	Lot              api.Expandable             `json:"lot"`
+	Quantity         int32                      `json:"quantity"`
+	Reason           str.NullString             `json:"reason"`
+	Reference        str.NullString             `json:"reference"`
+	Type             database.StockMovementType `json:"type"`
+	UnitCostAmount   ints.NullInt32             `json:"unit_cost_amount"`
+	UnitCostCurrency currency.NullCurrency      `json:"unit_cost_currency"`
+}
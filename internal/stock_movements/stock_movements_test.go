@@ -0,0 +1,218 @@
+package stockmovements
+
+import (
+	"database/sql"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+func TestUnitNewListStockMovementsParams(t *testing.T) {
+	lp := NewListStockMovementsParams()
+	if lp.PaginationParams == nil {
+		t.Fatal("PaginationParams is nil")
+	}
+	if lp.PaginationParams.Limit == nil {
+		t.Fatal("Limit is nil")
+	}
+	if *lp.PaginationParams.Limit != 10 {
+		t.Fatalf("expected limit 10, got %d", *lp.PaginationParams.Limit)
+	}
+}
+
+func TestUnitMapCreateStockMovementParams(t *testing.T) {
+	acc := uuid.New()
+	inventory := uuid.New()
+	reason := "damaged in transit"
+
+	cp := CreateStockMovementParams{
+		Inventory: inventory.String(),
+		Quantity:  -3,
+		Reason:    &reason,
+		Type:      database.StockMovementTypeWriteOff,
+	}
+
+	dbp := MapCreateStockMovementParams(Create{AccountId: acc, RequestParams: cp})
+	if dbp.AccountID != acc {
+		t.Fatalf("expected account id %v, got %v", acc, dbp.AccountID)
+	}
+	if dbp.InventoryID != inventory {
+		t.Fatalf("expected inventory id %v, got %v", inventory, dbp.InventoryID)
+	}
+	if dbp.Quantity != -3 {
+		t.Fatalf("expected quantity %d, got %d", -3, dbp.Quantity)
+	}
+	if (!dbp.Reason.Valid) || dbp.Reason.String != reason {
+		t.Fatalf("expected reason %s, got %s", reason, dbp.Reason.String)
+	}
+	if dbp.Reference.Valid {
+		t.Fatalf("expected null reference, got %s", dbp.Reference.String)
+	}
+	if dbp.LotID.Valid {
+		t.Fatalf("expected null lot id, got %v", dbp.LotID.UUID)
+	}
+}
+
+func TestUnitMapListStockMovementsParams(t *testing.T) {
+	acc := uuid.New()
+	inventory := uuid.New().String()
+	movementType := database.StockMovementTypeSale
+	lp := NewListStockMovementsParams()
+	lp.Inventory = &inventory
+	lp.Type = &movementType
+
+	dbp := MapListStockMovementsParams(List{AccountId: acc, RequestParams: lp})
+	if dbp.AccountID != acc {
+		t.Fatalf("expected account id %v, got %v", acc, dbp.AccountID)
+	}
+	if (!dbp.InventoryID.Valid) || dbp.InventoryID.UUID.String() != inventory {
+		t.Fatalf("expected inventory id %s, got %v", inventory, dbp.InventoryID.UUID)
+	}
+	if (!dbp.Type.Valid) || dbp.Type.StockMovementType != movementType {
+		t.Fatalf("expected type %s, got %s", movementType, dbp.Type.StockMovementType)
+	}
+}
+
+func TestUnitCheckQuantity(t *testing.T) {
+	cases := []struct {
+		movementType database.StockMovementType
+		quantity     int32
+		ok           bool
+	}{
+		{database.StockMovementTypeReceipt, 5, true},
+		{database.StockMovementTypeReceipt, -5, false},
+		{database.StockMovementTypeSale, -1, true},
+		{database.StockMovementTypeSale, 1, false},
+		{database.StockMovementTypeWriteOff, -2, true},
+		{database.StockMovementTypeWriteOff, 2, false},
+		{database.StockMovementTypeAdjustment, 7, true},
+		{database.StockMovementTypeAdjustment, -7, true},
+		{database.StockMovementTypeAdjustment, 0, false},
+		{database.StockMovementTypeReturn, 3, true},
+		{database.StockMovementTypeReturn, -3, false},
+		{database.StockMovementTypeTransferIn, 4, true},
+		{database.StockMovementTypeTransferIn, -4, false},
+		{database.StockMovementTypeTransferOut, -4, true},
+		{database.StockMovementTypeTransferOut, 4, false},
+	}
+
+	for _, c := range cases {
+		err := CheckQuantity(c.movementType, c.quantity)
+		if c.ok && err != nil {
+			t.Fatalf("expected %s of %d to be valid, got %v", c.movementType, c.quantity, err)
+		}
+		if !c.ok && err == nil {
+			t.Fatalf("expected %s of %d to be invalid", c.movementType, c.quantity)
+		}
+	}
+}
+
+func TestUnitCheckAvailable(t *testing.T) {
+	cases := []struct {
+		inStock  int32
+		reserved int32
+		quantity int32
+		ok       bool
+	}{
+		{10, 0, -10, true},
+		{10, 0, -11, false},
+		{10, 4, -6, true},
+		{10, 4, -7, false},
+		{10, 10, -1, false},
+		{0, 0, 5, true},
+		{3, 5, 2, true},
+	}
+
+	for _, c := range cases {
+		ok := CheckAvailable(c.inStock, c.reserved, c.quantity)
+		if ok != c.ok {
+			t.Fatalf("expected %d with %d in stock and %d reserved to be %v, got %v", c.quantity, c.inStock, c.reserved, c.ok, ok)
+		}
+	}
+}
+
+func TestUnitPlanLotConsumption(t *testing.T) {
+	now := time.Now()
+	expiring := func(days int) sql.NullTime {
+		return sql.NullTime{Time: now.AddDate(0, 0, days), Valid: true}
+	}
+	lot := func(id uuid.UUID, quantity int32, expiryDate sql.NullTime, createdDaysAgo int) database.ListLotsForConsumptionRow {
+		return database.ListLotsForConsumptionRow{
+			ID:         id,
+			CreatedAt:  now.AddDate(0, 0, -createdDaysAgo),
+			ExpiryDate: expiryDate,
+			Quantity:   quantity,
+		}
+	}
+	a, b := uuid.New(), uuid.New()
+
+	cases := []struct {
+		name     string
+		lots     []database.ListLotsForConsumptionRow
+		quantity int32
+		takes    []lotTake
+		ok       bool
+	}{
+		{
+			name:     "first expired first",
+			lots:     []database.ListLotsForConsumptionRow{lot(a, 5, expiring(30), 1), lot(b, 5, expiring(10), 1)},
+			quantity: 3,
+			takes:    []lotTake{{b, 3}},
+			ok:       true,
+		},
+		{
+			name:     "spills into the next lot",
+			lots:     []database.ListLotsForConsumptionRow{lot(a, 5, expiring(30), 1), lot(b, 5, expiring(10), 1)},
+			quantity: 7,
+			takes:    []lotTake{{b, 5}, {a, 2}},
+			ok:       true,
+		},
+		{
+			name:     "no expiry date goes last",
+			lots:     []database.ListLotsForConsumptionRow{lot(a, 5, sql.NullTime{}, 9), lot(b, 5, expiring(300), 1)},
+			quantity: 6,
+			takes:    []lotTake{{b, 5}, {a, 1}},
+			ok:       true,
+		},
+		{
+			name:     "same expiry oldest first",
+			lots:     []database.ListLotsForConsumptionRow{lot(a, 5, expiring(10), 1), lot(b, 5, expiring(10), 5)},
+			quantity: 2,
+			takes:    []lotTake{{b, 2}},
+			ok:       true,
+		},
+		{
+			name:     "expired lots passed over",
+			lots:     []database.ListLotsForConsumptionRow{lot(a, 5, expiring(-1), 9), lot(b, 5, expiring(10), 1)},
+			quantity: 4,
+			takes:    []lotTake{{b, 4}},
+			ok:       true,
+		},
+		{
+			name:     "expired stock doesn't cover",
+			lots:     []database.ListLotsForConsumptionRow{lot(a, 5, expiring(-1), 9), lot(b, 5, expiring(10), 1)},
+			quantity: 6,
+			ok:       false,
+		},
+		{
+			name:     "empty lots skipped",
+			lots:     []database.ListLotsForConsumptionRow{lot(a, 0, expiring(1), 1), lot(b, 2, expiring(2), 1)},
+			quantity: 2,
+			takes:    []lotTake{{b, 2}},
+			ok:       true,
+		},
+	}
+
+	for _, c := range cases {
+		takes, ok := planLotConsumption(c.lots, c.quantity, now)
+		if ok != c.ok {
+			t.Fatalf("%s: expected ok %v, got %v", c.name, c.ok, ok)
+		}
+		if ok && !slices.Equal(takes, c.takes) {
+			t.Fatalf("%s: expected takes %v, got %v", c.name, c.takes, takes)
+		}
+	}
+}
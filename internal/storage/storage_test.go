@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestUnitLocalStorage(t *testing.T) {
+	s := NewLocalStorage(t.TempDir())
+
+	n, err := s.Put("account/file", strings.NewReader("spec sheet"))
+	if err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	if n != 10 {
+		t.Fatalf("expected 10 bytes written, got %d", n)
+	}
+
+	rc, err := s.Open("account/file")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	contents, _ := io.ReadAll(rc)
+	rc.Close()
+	if string(contents) != "spec sheet" {
+		t.Fatalf("expected 'spec sheet', got %q", contents)
+	}
+
+	if err := s.Delete("account/file"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := s.Open("account/file"); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected the file to be gone, got %v", err)
+	}
+	if err := s.Delete("account/file"); err != nil {
+		t.Fatalf("deleting a missing key: %v", err)
+	}
+}
+
+func TestUnitLocalStorageInvalidKey(t *testing.T) {
+	s := NewLocalStorage(t.TempDir())
+
+	for _, key := range []string{"", "/etc/passwd", "../outside", "account/../../outside"} {
+		if _, err := s.Put(key, strings.NewReader("x")); !errors.Is(err, ErrInvalidKey) {
+			t.Fatalf("key %q: expected ErrInvalidKey, got %v", key, err)
+		}
+	}
+}
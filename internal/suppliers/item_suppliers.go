@@ -0,0 +1,303 @@
+package suppliers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/d-darac/inventory-assets/str"
+	"github.com/google/uuid"
+)
+
+type CreateItemSupplier struct {
+	AccountId     uuid.UUID
+	ItemId        uuid.UUID
+	RequestParams CreateItemSupplierParams
+}
+
+type DeleteItemSupplier struct {
+	AccountId  uuid.UUID
+	ItemId     uuid.UUID
+	SupplierId uuid.UUID
+}
+
+type GetItemSupplier struct {
+	AccountId     uuid.UUID
+	ItemId        uuid.UUID
+	SupplierId    uuid.UUID
+	RequestParams RetrieveItemSupplierParams
+	OmitBase      bool
+}
+
+type ListItemSuppliersByItems struct {
+	AccountId     uuid.UUID
+	RequestParams ListItemSuppliersByItemsParams
+}
+
+type ListItemSuppliers struct {
+	AccountId     uuid.UUID
+	ItemId        uuid.UUID
+	RequestParams ListItemSuppliersParams
+}
+
+type UpdateItemSupplier struct {
+	AccountId     uuid.UUID
+	ItemId        uuid.UUID
+	SupplierId    uuid.UUID
+	RequestParams UpdateItemSupplierParams
+}
+
+func (s *SuppliersService) CreateItemSupplier(create CreateItemSupplier) (*ItemSupplier, error) {
+	if err := s.checkItem(create.AccountId, create.ItemId); err != nil {
+		return nil, err
+	}
+
+	dbParams := MapCreateItemSupplierParams(create)
+
+	_, err := s.Get(Get{
+		AccountId:     create.AccountId,
+		SupplierId:    dbParams.SupplierID,
+		RequestParams: RetrieveSupplierParams{},
+		OmitBase:      true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.Db.GetItemSupplier(context.Background(), database.GetItemSupplierParams{
+		AccountID:  create.AccountId,
+		ItemID:     create.ItemId,
+		SupplierID: dbParams.SupplierID,
+	})
+	if err == nil {
+		return nil, &api.AppError{
+			Message: fmt.Sprintf("Item '%v' is already linked to supplier '%v'.", create.ItemId, dbParams.SupplierID),
+			Status:  http.StatusBadRequest,
+			Type:    api.InvalidRequestError,
+		}
+	}
+	if err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	row, err := s.Db.CreateItemSupplier(context.Background(), dbParams)
+	if err != nil {
+		return nil, err
+	}
+
+	itemSupplier := &ItemSupplier{
+		ID:               &row.ID,
+		CreatedAt:        &row.CreatedAt,
+		UpdatedAt:        &row.UpdatedAt,
+		CostAmount:       row.CostAmount,
+		Item:             api.Expandable{ID: row.Item},
+		MinOrderQuantity: row.MinOrderQuantity,
+		Supplier:         api.Expandable{ID: row.Supplier},
+		SupplierSku:      str.NullString(row.SupplierSku),
+	}
+
+	return itemSupplier, nil
+}
+
+func (s *SuppliersService) DeleteItemSupplier(delete DeleteItemSupplier) error {
+	_, err := s.GetItemSupplier(GetItemSupplier{
+		AccountId:     delete.AccountId,
+		ItemId:        delete.ItemId,
+		SupplierId:    delete.SupplierId,
+		RequestParams: RetrieveItemSupplierParams{},
+		OmitBase:      true,
+	})
+	if err != nil {
+		return err
+	}
+	return s.Db.DeleteItemSupplier(context.Background(), database.DeleteItemSupplierParams{
+		AccountID:  delete.AccountId,
+		ItemID:     delete.ItemId,
+		SupplierID: delete.SupplierId,
+	})
+}
+
+func (s *SuppliersService) GetItemSupplier(get GetItemSupplier) (*ItemSupplier, error) {
+	row, err := s.Db.GetItemSupplier(context.Background(), database.GetItemSupplierParams{
+		AccountID:  get.AccountId,
+		ItemID:     get.ItemId,
+		SupplierID: get.SupplierId,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, api.NotFoundMessage(get.SupplierId, "item supplier")
+		}
+		return nil, err
+	}
+
+	itemSupplier := &ItemSupplier{
+		CostAmount:       row.CostAmount,
+		Item:             api.Expandable{ID: row.Item},
+		MinOrderQuantity: row.MinOrderQuantity,
+		Supplier:         api.Expandable{ID: row.Supplier},
+		SupplierSku:      str.NullString(row.SupplierSku),
+	}
+
+	if !get.OmitBase {
+		itemSupplier.ID = &row.ID
+		itemSupplier.CreatedAt = &row.CreatedAt
+		itemSupplier.UpdatedAt = &row.UpdatedAt
+	}
+
+	return itemSupplier, nil
+}
+
+func (s *SuppliersService) ListItemSuppliersByItems(list ListItemSuppliersByItems) (itemSuppliers []*ItemSupplier, err error) {
+	params := database.ListItemSuppliersByItemsParams{
+		AccountID: list.AccountId,
+		Ids:       list.RequestParams.Ids,
+	}
+
+	rows, err := s.Db.ListItemSuppliersByItems(context.Background(), params)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return itemSuppliers, nil
+		}
+		return
+	}
+
+	for _, row := range rows {
+		itemSuppliers = append(itemSuppliers, &ItemSupplier{
+			ID:               &row.ID,
+			CreatedAt:        &row.CreatedAt,
+			UpdatedAt:        &row.UpdatedAt,
+			CostAmount:       row.CostAmount,
+			Item:             api.Expandable{ID: row.Item},
+			MinOrderQuantity: row.MinOrderQuantity,
+			Supplier:         api.Expandable{ID: row.Supplier},
+			SupplierSku:      str.NullString(row.SupplierSku),
+		})
+	}
+
+	return
+}
+
+// ListItemSuppliers lists the suppliers of an item. Its starting_after and
+// ending_before are supplier IDs, like the paths of the links.
+func (s *SuppliersService) ListItemSuppliers(list ListItemSuppliers) (itemSuppliers []*ItemSupplier, hasMore bool, err error) {
+	if err := s.checkItem(list.AccountId, list.ItemId); err != nil {
+		return itemSuppliers, hasMore, err
+	}
+
+	if list.RequestParams.StartingAfter != nil {
+		itemSupplier, err := s.GetItemSupplier(GetItemSupplier{
+			AccountId:     list.AccountId,
+			ItemId:        list.ItemId,
+			SupplierId:    *list.RequestParams.StartingAfter,
+			RequestParams: RetrieveItemSupplierParams{},
+			OmitBase:      false,
+		})
+		if err != nil {
+			return itemSuppliers, hasMore, err
+		}
+		list.RequestParams.StartingAfterDate = itemSupplier.CreatedAt
+	}
+
+	if list.RequestParams.EndingBefore != nil {
+		itemSupplier, err := s.GetItemSupplier(GetItemSupplier{
+			AccountId:     list.AccountId,
+			ItemId:        list.ItemId,
+			SupplierId:    *list.RequestParams.EndingBefore,
+			RequestParams: RetrieveItemSupplierParams{},
+			OmitBase:      false,
+		})
+		if err != nil {
+			return itemSuppliers, hasMore, err
+		}
+		list.RequestParams.EndingBeforeDate = itemSupplier.CreatedAt
+	}
+
+	dbParams := MapListItemSuppliersParams(list)
+
+	rows, err := s.Db.ListItemSuppliers(context.Background(), dbParams)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return itemSuppliers, hasMore, nil
+		}
+		return
+	}
+
+	if dbParams.Limit.Valid {
+		hasMore = len(rows) > int(dbParams.Limit.Int32)
+	} else {
+		hasMore = len(rows) > 10
+	}
+
+	if hasMore {
+		if dbParams.EndingBefore.Valid {
+			rows = rows[1:]
+		} else {
+			rows = rows[:len(rows)-1]
+		}
+	}
+
+	for _, row := range rows {
+		itemSuppliers = append(itemSuppliers, &ItemSupplier{
+			ID:               &row.ID,
+			CreatedAt:        &row.CreatedAt,
+			UpdatedAt:        &row.UpdatedAt,
+			CostAmount:       row.CostAmount,
+			Item:             api.Expandable{ID: row.Item},
+			MinOrderQuantity: row.MinOrderQuantity,
+			Supplier:         api.Expandable{ID: row.Supplier},
+			SupplierSku:      str.NullString(row.SupplierSku),
+		})
+	}
+
+	return itemSuppliers, hasMore, err
+}
+
+func (s *SuppliersService) UpdateItemSupplier(update UpdateItemSupplier) (*ItemSupplier, error) {
+	_, err := s.GetItemSupplier(GetItemSupplier{
+		AccountId:     update.AccountId,
+		ItemId:        update.ItemId,
+		SupplierId:    update.SupplierId,
+		RequestParams: RetrieveItemSupplierParams{},
+		OmitBase:      true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dbParams := MapUpdateItemSupplierParams(update)
+
+	row, err := s.Db.UpdateItemSupplier(context.Background(), dbParams)
+	if err != nil {
+		return nil, err
+	}
+
+	itemSupplier := &ItemSupplier{
+		ID:               &row.ID,
+		CreatedAt:        &row.CreatedAt,
+		UpdatedAt:        &row.UpdatedAt,
+		CostAmount:       row.CostAmount,
+		Item:             api.Expandable{ID: row.Item},
+		MinOrderQuantity: row.MinOrderQuantity,
+		Supplier:         api.Expandable{ID: row.Supplier},
+		SupplierSku:      str.NullString(row.SupplierSku),
+	}
+
+	return itemSupplier, nil
+}
+
+func (s *SuppliersService) checkItem(accountId, itemId uuid.UUID) error {
+	_, err := s.Db.GetItem(context.Background(), database.GetItemParams{
+		ID:        itemId,
+		AccountID: accountId,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return api.NotFoundMessage(itemId, "item")
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package suppliers
+
+import (
+	"time"
+
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+func MapCreateSupplierParams(create Create) database.CreateSupplierParams {
+	t := time.Now()
+	csp := database.CreateSupplierParams{
+		ID:           uuid.New(),
+		CreatedAt:    t,
+		UpdatedAt:    t,
+		AccountID:    create.AccountId,
+		ContactEmail: api.NullString(create.RequestParams.ContactEmail),
+		ContactName:  api.NullString(create.RequestParams.ContactName),
+		ContactPhone: api.NullString(create.RequestParams.ContactPhone),
+		Currency:     create.RequestParams.Currency,
+		Name:         create.RequestParams.Name,
+	}
+	if create.RequestParams.LeadTimeDays != nil {
+		csp.LeadTimeDays = *create.RequestParams.LeadTimeDays
+	}
+	return csp
+}
+
+func MapCreateItemSupplierParams(create CreateItemSupplier) database.CreateItemSupplierParams {
+	t := time.Now()
+	cisp := database.CreateItemSupplierParams{
+		ID:               uuid.New(),
+		CreatedAt:        t,
+		UpdatedAt:        t,
+		AccountID:        create.AccountId,
+		CostAmount:       create.RequestParams.CostAmount,
+		ItemID:           create.ItemId,
+		MinOrderQuantity: 1,
+		SupplierID:       uuid.MustParse(create.RequestParams.Supplier),
+		SupplierSku:      api.NullString(create.RequestParams.SupplierSku),
+	}
+	if create.RequestParams.MinOrderQuantity != nil {
+		cisp.MinOrderQuantity = *create.RequestParams.MinOrderQuantity
+	}
+	return cisp
+}
+
+func MapListItemSuppliersParams(list ListItemSuppliers) database.ListItemSuppliersParams {
+	lisp := database.ListItemSuppliersParams{
+		AccountID: list.AccountId,
+		ItemID:    list.ItemId,
+	}
+	database.MapPaginationParams(*list.RequestParams.PaginationParams, &lisp)
+	return lisp
+}
+
+func MapListSuppliersParams(list List) database.ListSuppliersParams {
+	lsp := database.ListSuppliersParams{
+		AccountID: list.AccountId,
+		Currency:  api.NullCurrency(list.RequestParams.Currency),
+		Name:      api.NullString(list.RequestParams.Name),
+	}
+	database.MapTimeRange(list.RequestParams.CreatedAt, &lsp.CreatedAtGt, &lsp.CreatedAtGte, &lsp.CreatedAtLt, &lsp.CreatedAtLte)
+	database.MapTimeRange(list.RequestParams.UpdatedAt, &lsp.UpdatedAtGt, &lsp.UpdatedAtGte, &lsp.UpdatedAtLt, &lsp.UpdatedAtLte)
+	database.MapPaginationParams(*list.RequestParams.PaginationParams, &lsp)
+	return lsp
+}
+
+func MapUpdateItemSupplierParams(update UpdateItemSupplier) database.UpdateItemSupplierParams {
+	uisp := database.UpdateItemSupplierParams{
+		UpdatedAt:        time.Now(),
+		AccountID:        update.AccountId,
+		ItemID:           update.ItemId,
+		SupplierID:       update.SupplierId,
+		CostAmount:       api.NullInt32(update.RequestParams.CostAmount),
+		MinOrderQuantity: api.NullInt32(update.RequestParams.MinOrderQuantity),
+		SupplierSku:      api.NullString(update.RequestParams.SupplierSku),
+	}
+	return uisp
+}
+
+func MapUpdateSupplierParams(update Update) database.UpdateSupplierParams {
+	usp := database.UpdateSupplierParams{
+		UpdatedAt:    time.Now(),
+		AccountID:    update.AccountId,
+		ID:           update.SupplierId,
+		ContactEmail: api.NullString(update.RequestParams.ContactEmail),
+		ContactName:  api.NullString(update.RequestParams.ContactName),
+		ContactPhone: api.NullString(update.RequestParams.ContactPhone),
+		LeadTimeDays: api.NullInt32(update.RequestParams.LeadTimeDays),
+		Name:         api.NullString(update.RequestParams.Name),
+	}
+	return usp
+}
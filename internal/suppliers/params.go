@@ -0,0 +1,84 @@
+package suppliers
+
+import (
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+type CreateSupplierParams struct {
+	ContactEmail *string           `json:"contact_email" validate:"omitnil,email"`
+	ContactName  *string           `json:"contact_name" validate:"omitnil"`
+	ContactPhone *string           `json:"contact_phone" validate:"omitnil"`
+	Currency     database.Currency `json:"currency" validate:"required,currency"`
+	LeadTimeDays *int32            `json:"lead_time_days" validate:"omitnil,gte=0"`
+	Name         string            `json:"name" validate:"required"`
+}
+
+type CreateItemSupplierParams struct {
+	CostAmount       int32    `json:"cost_amount" validate:"gte=0"`
+	MinOrderQuantity *int32   `json:"min_order_quantity" validate:"omitnil,gt=0"`
+	Supplier         string   `json:"supplier" validate:"required,uuid"`
+	SupplierSku      *string  `json:"supplier_sku" validate:"omitnil"`
+	Expand           []string `json:"expand" validate:"omitnil,dive,oneof=item supplier"`
+}
+
+type ListItemSuppliersByItemsParams struct {
+	Ids []uuid.UUID
+}
+
+type ListItemSuppliersParams struct {
+	*database.PaginationParams
+	Expand []string `json:"expand" validate:"omitnil,dive,oneof=item supplier"`
+}
+
+type ListSuppliersByIdsParams struct {
+	Ids []uuid.UUID
+}
+
+type ListSuppliersParams struct {
+	*database.PaginationParams
+	CreatedAt *database.TimeRange `json:"created_at" validate:"omitnil"`
+	Currency  *database.Currency  `json:"currency" validate:"omitnil,currency"`
+	Name      *string             `json:"name" validate:"omitnil"`
+	UpdatedAt *database.TimeRange `json:"updated_at" validate:"omitnil"`
+}
+
+type RetrieveItemSupplierParams struct {
+	Expand []string `json:"expand" validate:"omitnil,dive,oneof=item supplier"`
+}
+
+type RetrieveSupplierParams struct {
+}
+
+type UpdateItemSupplierParams struct {
+	CostAmount       *int32   `json:"cost_amount" validate:"omitnil,gte=0"`
+	MinOrderQuantity *int32   `json:"min_order_quantity" validate:"omitnil,gt=0"`
+	SupplierSku      *string  `json:"supplier_sku" validate:"omitnil"`
+	Expand           []string `json:"expand" validate:"omitnil,dive,oneof=item supplier"`
+}
+
+type UpdateSupplierParams struct {
+	ContactEmail *string `json:"contact_email" validate:"omitnil,email"`
+	ContactName  *string `json:"contact_name" validate:"omitnil"`
+	ContactPhone *string `json:"contact_phone" validate:"omitnil"`
+	LeadTimeDays *int32  `json:"lead_time_days" validate:"omitnil,gte=0"`
+	Name         *string `json:"name" validate:"omitnil"`
+}
+
+func NewListItemSuppliersParams() ListItemSuppliersParams {
+	limit := int32(10)
+	return ListItemSuppliersParams{
+		PaginationParams: &database.PaginationParams{
+			Limit: &limit,
+		},
+	}
+}
+
+func NewListSuppliersParams() ListSuppliersParams {
+	limit := int32(10)
+	return ListSuppliersParams{
+		PaginationParams: &database.PaginationParams{
+			Limit: &limit,
+		},
+	}
+}
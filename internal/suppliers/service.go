@@ -0,0 +1,257 @@
+package suppliers
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/d-darac/inventory-assets/str"
+	"github.com/google/uuid"
+)
+
+type SuppliersService struct {
+	Db *database.Queries
+}
+
+type Create struct {
+	AccountId     uuid.UUID
+	RequestParams CreateSupplierParams
+}
+
+type Delete struct {
+	AccountId  uuid.UUID
+	SupplierId uuid.UUID
+}
+
+type Get struct {
+	AccountId     uuid.UUID
+	SupplierId    uuid.UUID
+	RequestParams RetrieveSupplierParams
+	OmitBase      bool
+}
+
+type ListByIds struct {
+	AccountId     uuid.UUID
+	RequestParams ListSuppliersByIdsParams
+}
+
+type List struct {
+	AccountId     uuid.UUID
+	RequestParams ListSuppliersParams
+}
+
+type Update struct {
+	AccountId     uuid.UUID
+	SupplierId    uuid.UUID
+	RequestParams UpdateSupplierParams
+}
+
+func NewSuppliersService(db *database.Queries) *SuppliersService {
+	return &SuppliersService{
+		Db: db,
+	}
+}
+
+func (s *SuppliersService) Create(create Create) (*Supplier, error) {
+	dbParams := MapCreateSupplierParams(create)
+	row, err := s.Db.CreateSupplier(context.Background(), dbParams)
+	if err != nil {
+		return nil, err
+	}
+
+	supplier := &Supplier{
+		ID:           &row.ID,
+		CreatedAt:    &row.CreatedAt,
+		UpdatedAt:    &row.UpdatedAt,
+		ContactEmail: str.NullString(row.ContactEmail),
+		ContactName:  str.NullString(row.ContactName),
+		ContactPhone: str.NullString(row.ContactPhone),
+		Currency:     row.Currency,
+		LeadTimeDays: row.LeadTimeDays,
+		Name:         row.Name,
+	}
+
+	return supplier, nil
+}
+
+// Delete removes a supplier along with its links to items.
+func (s *SuppliersService) Delete(delete Delete) error {
+	_, err := s.Get(Get{
+		AccountId:     delete.AccountId,
+		SupplierId:    delete.SupplierId,
+		RequestParams: RetrieveSupplierParams{},
+		OmitBase:      true,
+	})
+	if err != nil {
+		return err
+	}
+	return s.Db.DeleteSupplier(context.Background(), database.DeleteSupplierParams{
+		ID:        delete.SupplierId,
+		AccountID: delete.AccountId,
+	})
+}
+
+func (s *SuppliersService) Get(get Get) (*Supplier, error) {
+	row, err := s.Db.GetSupplier(context.Background(), database.GetSupplierParams{
+		ID:        get.SupplierId,
+		AccountID: get.AccountId,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, api.NotFoundMessage(get.SupplierId, "supplier")
+		}
+		return nil, err
+	}
+
+	supplier := &Supplier{
+		ContactEmail: str.NullString(row.ContactEmail),
+		ContactName:  str.NullString(row.ContactName),
+		ContactPhone: str.NullString(row.ContactPhone),
+		Currency:     row.Currency,
+		LeadTimeDays: row.LeadTimeDays,
+		Name:         row.Name,
+	}
+
+	if !get.OmitBase {
+		supplier.ID = &row.ID
+		supplier.CreatedAt = &row.CreatedAt
+		supplier.UpdatedAt = &row.UpdatedAt
+	}
+
+	return supplier, nil
+}
+
+func (s *SuppliersService) ListByIds(list ListByIds) (suppliers []*Supplier, err error) {
+	params := database.ListSuppliersByIdsParams{
+		AccountID: list.AccountId,
+		Ids:       list.RequestParams.Ids,
+	}
+
+	rows, err := s.Db.ListSuppliersByIds(context.Background(), params)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return suppliers, nil
+		}
+		return
+	}
+
+	for _, row := range rows {
+		suppliers = append(suppliers, &Supplier{
+			ID:           &row.ID,
+			CreatedAt:    &row.CreatedAt,
+			UpdatedAt:    &row.UpdatedAt,
+			ContactEmail: str.NullString(row.ContactEmail),
+			ContactName:  str.NullString(row.ContactName),
+			ContactPhone: str.NullString(row.ContactPhone),
+			Currency:     row.Currency,
+			LeadTimeDays: row.LeadTimeDays,
+			Name:         row.Name,
+		})
+	}
+
+	return
+}
+
+func (s *SuppliersService) List(list List) (suppliers []*Supplier, hasMore bool, err error) {
+	if list.RequestParams.StartingAfter != nil {
+		supplier, err := s.Get(Get{
+			AccountId:     list.AccountId,
+			SupplierId:    *list.RequestParams.StartingAfter,
+			RequestParams: RetrieveSupplierParams{},
+			OmitBase:      false,
+		})
+		if err != nil {
+			return suppliers, hasMore, err
+		}
+		list.RequestParams.StartingAfterDate = supplier.CreatedAt
+	}
+
+	if list.RequestParams.EndingBefore != nil {
+		supplier, err := s.Get(Get{
+			AccountId:     list.AccountId,
+			SupplierId:    *list.RequestParams.EndingBefore,
+			RequestParams: RetrieveSupplierParams{},
+			OmitBase:      false,
+		})
+		if err != nil {
+			return suppliers, hasMore, err
+		}
+		list.RequestParams.EndingBeforeDate = supplier.CreatedAt
+	}
+
+	dbParams := MapListSuppliersParams(list)
+
+	rows, err := s.Db.ListSuppliers(context.Background(), dbParams)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return suppliers, hasMore, nil
+		}
+		return
+	}
+
+	if dbParams.Limit.Valid {
+		hasMore = len(rows) > int(dbParams.Limit.Int32)
+	} else {
+		hasMore = len(rows) > 10
+	}
+
+	if hasMore {
+		if dbParams.EndingBefore.Valid {
+			rows = rows[1:]
+		} else {
+			rows = rows[:len(rows)-1]
+		}
+	}
+
+	for _, row := range rows {
+		suppliers = append(suppliers, &Supplier{
+			ID:           &row.ID,
+			CreatedAt:    &row.CreatedAt,
+			UpdatedAt:    &row.UpdatedAt,
+			ContactEmail: str.NullString(row.ContactEmail),
+			ContactName:  str.NullString(row.ContactName),
+			ContactPhone: str.NullString(row.ContactPhone),
+			Currency:     row.Currency,
+			LeadTimeDays: row.LeadTimeDays,
+			Name:         row.Name,
+		})
+	}
+
+	return suppliers, hasMore, err
+}
+
+// Update can't change a supplier's currency, since the costs of its items
+// are kept in it.
+func (s *SuppliersService) Update(update Update) (*Supplier, error) {
+	_, err := s.Get(Get{
+		AccountId:     update.AccountId,
+		SupplierId:    update.SupplierId,
+		RequestParams: RetrieveSupplierParams{},
+		OmitBase:      true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dbParams := MapUpdateSupplierParams(update)
+
+	row, err := s.Db.UpdateSupplier(context.Background(), dbParams)
+	if err != nil {
+		return nil, err
+	}
+
+	supplier := &Supplier{
+		ID:           &row.ID,
+		CreatedAt:    &row.CreatedAt,
+		UpdatedAt:    &row.UpdatedAt,
+		ContactEmail: str.NullString(row.ContactEmail),
+		ContactName:  str.NullString(row.ContactName),
+		ContactPhone: str.NullString(row.ContactPhone),
+		Currency:     row.Currency,
+		LeadTimeDays: row.LeadTimeDays,
+		Name:         row.Name,
+	}
+
+	return supplier, nil
+}
@@ -0,0 +1,38 @@
+package suppliers
+
+import (
+	"time"
+
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/d-darac/inventory-assets/str"
+	"github.com/google/uuid"
+)
+
+// Supplier is someone items are bought from. LeadTimeDays is how long their
+// deliveries usually take.
+type Supplier struct {
+	ID           *uuid.UUID        `json:"id,omitempty"`
+	CreatedAt    *time.Time        `json:"created_at,omitempty"`
+	UpdatedAt    *time.Time        `json:"updated_at,omitempty"`
+	ContactEmail str.NullString    `json:"contact_email"`
+	ContactName  str.NullString    `json:"contact_name"`
+	ContactPhone str.NullString    `json:"contact_phone"`
+	Currency     database.Currency `json:"currency"`
+	LeadTimeDays int32             `json:"lead_time_days"`
+	Name         string            `json:"name"`
+}
+
+// ItemSupplier links an item to a supplier of it, with what buying it from
+// them takes. CostAmount is in the supplier's currency. An item has at most
+// one link to each supplier, so links are addressed by the supplier's ID.
+type ItemSupplier struct {
+	ID               *uuid.UUID     `json:"id,omitempty"`
+	CreatedAt        *time.Time     `json:"created_at,omitempty"`
+	UpdatedAt        *time.Time     `json:"updated_at,omitempty"`
+	CostAmount       int32          `json:"cost_amount"`
+	Item             api.Expandable `json:"item"`
+	MinOrderQuantity int32          `json:"min_order_quantity"`
+	Supplier         api.Expandable `json:"supplier"`
+	SupplierSku      str.NullString `json:"supplier_sku"`
+}
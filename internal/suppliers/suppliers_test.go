@@ -0,0 +1,200 @@
+package suppliers
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+	"github.com/joho/godotenv"
+	_ "github.com/lib/pq"
+)
+
+func TestUnitNewListSuppliersParams(t *testing.T) {
+	lp := NewListSuppliersParams()
+	if lp.PaginationParams == nil {
+		t.Fatal("PaginationParams is nil")
+	}
+	if lp.PaginationParams.Limit == nil {
+		t.Fatal("Limit is nil")
+	}
+	if *lp.PaginationParams.Limit != 10 {
+		t.Fatalf("expected limit 10, got %d", *lp.PaginationParams.Limit)
+	}
+}
+
+func TestUnitMapCreateSupplierParams(t *testing.T) {
+	acc := uuid.New()
+	email := "orders@example.com"
+	leadTimeDays := int32(14)
+	cp := CreateSupplierParams{
+		ContactEmail: &email,
+		Currency:     database.CurrencyEUR,
+		LeadTimeDays: &leadTimeDays,
+		Name:         "Acme",
+	}
+
+	dbp := MapCreateSupplierParams(Create{AccountId: acc, RequestParams: cp})
+	if dbp.AccountID != acc {
+		t.Fatalf("expected account id %v, got %v", acc, dbp.AccountID)
+	}
+	if dbp.Name != cp.Name || dbp.Currency != cp.Currency {
+		t.Fatalf("expected %s in %s, got %s in %s", cp.Name, cp.Currency, dbp.Name, dbp.Currency)
+	}
+	if (!dbp.ContactEmail.Valid) || dbp.ContactEmail.String != email {
+		t.Fatalf("expected contact email %s, got %s", email, dbp.ContactEmail.String)
+	}
+	if dbp.LeadTimeDays != leadTimeDays {
+		t.Fatalf("expected lead time %d, got %d", leadTimeDays, dbp.LeadTimeDays)
+	}
+
+	cp.LeadTimeDays = nil
+	dbp = MapCreateSupplierParams(Create{AccountId: acc, RequestParams: cp})
+	if dbp.LeadTimeDays != 0 {
+		t.Fatalf("expected lead time 0 by default, got %d", dbp.LeadTimeDays)
+	}
+}
+
+func TestUnitMapCreateItemSupplierParams(t *testing.T) {
+	acc := uuid.New()
+	item := uuid.New()
+	supplier := uuid.New()
+	cp := CreateItemSupplierParams{
+		CostAmount: 250,
+		Supplier:   supplier.String(),
+	}
+
+	dbp := MapCreateItemSupplierParams(CreateItemSupplier{AccountId: acc, ItemId: item, RequestParams: cp})
+	if dbp.ItemID != item || dbp.SupplierID != supplier {
+		t.Fatalf("expected item %v and supplier %v, got %v and %v", item, supplier, dbp.ItemID, dbp.SupplierID)
+	}
+	if dbp.CostAmount != cp.CostAmount {
+		t.Fatalf("expected cost %d, got %d", cp.CostAmount, dbp.CostAmount)
+	}
+	if dbp.MinOrderQuantity != 1 {
+		t.Fatalf("expected a minimum order quantity of 1 by default, got %d", dbp.MinOrderQuantity)
+	}
+
+	minOrderQuantity := int32(12)
+	cp.MinOrderQuantity = &minOrderQuantity
+	dbp = MapCreateItemSupplierParams(CreateItemSupplier{AccountId: acc, ItemId: item, RequestParams: cp})
+	if dbp.MinOrderQuantity != minOrderQuantity {
+		t.Fatalf("expected a minimum order quantity of %d, got %d", minOrderQuantity, dbp.MinOrderQuantity)
+	}
+}
+
+func TestUnitMapListSuppliersParams(t *testing.T) {
+	acc := uuid.New()
+	lp := NewListSuppliersParams()
+	currency := database.CurrencyEUR
+	lp.Currency = &currency
+
+	dbp := MapListSuppliersParams(List{AccountId: acc, RequestParams: lp})
+	if dbp.AccountID != acc {
+		t.Fatalf("expected account id %v, got %v", acc, dbp.AccountID)
+	}
+	if (!dbp.Currency.Valid) || dbp.Currency.Currency != currency {
+		t.Fatalf("expected currency %s, got %v", currency, dbp.Currency)
+	}
+	if dbp.Name.Valid {
+		t.Fatalf("expected no name, got %s", dbp.Name.String)
+	}
+}
+
+func TestIntegrationItemSuppliers(t *testing.T) {
+	godotenv.Load("../../.env")
+	dbUrl := os.Getenv("DB_URL")
+
+	db, err := sql.Open("postgres", dbUrl)
+	if err != nil {
+		t.Fatalf("couldn't open database: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.Ping(); err != nil {
+		t.Fatalf("couldn't connect to database: %v", err)
+	}
+
+	q := database.New(db)
+
+	acc, err := q.CreateAccount(context.Background(), database.CreateAccountParams{
+		Country: database.CountryIE,
+		Nickname: sql.NullString{
+			String: "Test Account",
+			Valid:  true,
+		},
+	})
+	if err != nil {
+		t.Fatalf("couldn't create test account: %v", err)
+	}
+	defer db.Exec("DELETE FROM accounts WHERE id = $1;", acc.ID)
+
+	item, err := q.CreateItem(context.Background(), database.CreateItemParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		AccountID: acc.ID,
+		Name:      "Test Item",
+		Type:      database.ItemTypePRODUCT,
+	})
+	if err != nil {
+		t.Fatalf("couldn't create test item: %v", err)
+	}
+
+	s := NewSuppliersService(q)
+
+	supplier, err := s.Create(Create{AccountId: acc.ID, RequestParams: CreateSupplierParams{
+		Currency: database.CurrencyEUR,
+		Name:     "Acme",
+	}})
+	if err != nil {
+		t.Fatalf("couldn't create test supplier: %v", err)
+	}
+
+	cp := CreateItemSupplierParams{CostAmount: 250, Supplier: supplier.ID.String()}
+	itemSupplier, err := s.CreateItemSupplier(CreateItemSupplier{AccountId: acc.ID, ItemId: item.ID, RequestParams: cp})
+	if err != nil {
+		t.Fatalf("couldn't link item to supplier: %v", err)
+	}
+	if itemSupplier.CostAmount != 250 || itemSupplier.MinOrderQuantity != 1 {
+		t.Fatalf("expected cost 250 and minimum order 1, got %d and %d", itemSupplier.CostAmount, itemSupplier.MinOrderQuantity)
+	}
+
+	if _, err := s.CreateItemSupplier(CreateItemSupplier{AccountId: acc.ID, ItemId: item.ID, RequestParams: cp}); err == nil {
+		t.Fatal("expected linking the item to the same supplier twice to fail")
+	}
+	if _, err := s.CreateItemSupplier(CreateItemSupplier{AccountId: acc.ID, ItemId: uuid.New(), RequestParams: cp}); err == nil {
+		t.Fatal("expected linking a missing item to fail")
+	}
+	missing := CreateItemSupplierParams{CostAmount: 250, Supplier: uuid.New().String()}
+	if _, err := s.CreateItemSupplier(CreateItemSupplier{AccountId: acc.ID, ItemId: item.ID, RequestParams: missing}); err == nil {
+		t.Fatal("expected linking a missing supplier to fail")
+	}
+
+	cost := int32(300)
+	itemSupplier, err = s.UpdateItemSupplier(UpdateItemSupplier{AccountId: acc.ID, ItemId: item.ID, SupplierId: *supplier.ID, RequestParams: UpdateItemSupplierParams{CostAmount: &cost}})
+	if err != nil {
+		t.Fatalf("couldn't update item supplier: %v", err)
+	}
+	if itemSupplier.CostAmount != cost {
+		t.Fatalf("expected cost %d, got %d", cost, itemSupplier.CostAmount)
+	}
+
+	itemSuppliers, hasMore, err := s.ListItemSuppliers(ListItemSuppliers{AccountId: acc.ID, ItemId: item.ID, RequestParams: NewListItemSuppliersParams()})
+	if err != nil {
+		t.Fatalf("error listing item suppliers: %v", err)
+	}
+	if hasMore || len(itemSuppliers) != 1 {
+		t.Fatalf("expected 1 item supplier, got %d and hasMore %v", len(itemSuppliers), hasMore)
+	}
+
+	if err := s.DeleteItemSupplier(DeleteItemSupplier{AccountId: acc.ID, ItemId: item.ID, SupplierId: *supplier.ID}); err != nil {
+		t.Fatalf("error unlinking item supplier: %v", err)
+	}
+	if _, err := s.GetItemSupplier(GetItemSupplier{AccountId: acc.ID, ItemId: item.ID, SupplierId: *supplier.ID}); err == nil {
+		t.Fatal("expected the unlinked item supplier to be gone")
+	}
+}
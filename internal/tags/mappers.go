@@ -0,0 +1,17 @@
+package tags
+
+import (
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+)
+
+// MapListTagsParams asks for one tag more than the limit, which tells List
+// whether there are more.
+func MapListTagsParams(list List) database.ListTagsParams {
+	ltp := database.ListTagsParams{
+		AccountID: list.AccountId,
+		Prefix:    api.NullString(list.RequestParams.Prefix),
+		Limit:     *list.RequestParams.Limit + 1,
+	}
+	return ltp
+}
@@ -0,0 +1,13 @@
+package tags
+
+type ListTagsParams struct {
+	Limit  *int32  `json:"limit" validate:"required,min=1,max=100"`
+	Prefix *string `json:"prefix" validate:"omitnil,max=40"`
+}
+
+func NewListTagsParams() ListTagsParams {
+	limit := int32(10)
+	return ListTagsParams{
+		Limit: &limit,
+	}
+}
@@ -0,0 +1,53 @@
+package tags
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+type TagsService struct {
+	Db *database.Queries
+}
+
+type List struct {
+	AccountId     uuid.UUID
+	RequestParams ListTagsParams
+}
+
+func NewTagsService(db *database.Queries) *TagsService {
+	return &TagsService{
+		Db: db,
+	}
+}
+
+// List returns the tags of the account's items, most used first. Tags have
+// no IDs to page by, so only the first page is available; narrowing it down
+// is what prefix is for.
+func (s *TagsService) List(list List) (tags []*Tag, hasMore bool, err error) {
+	dbParams := MapListTagsParams(list)
+
+	rows, err := s.Db.ListTags(context.Background(), dbParams)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return tags, hasMore, nil
+		}
+		return
+	}
+
+	hasMore = len(rows) > int(*list.RequestParams.Limit)
+	if hasMore {
+		rows = rows[:len(rows)-1]
+	}
+
+	for _, row := range rows {
+		tags = append(tags, &Tag{
+			Name:  row.Name,
+			Count: row.Count,
+		})
+	}
+
+	return tags, hasMore, err
+}
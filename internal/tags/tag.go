@@ -0,0 +1,7 @@
+package tags
+
+// Tag is a label used on items, with the number of items that have it.
+type Tag struct {
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
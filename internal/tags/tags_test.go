@@ -0,0 +1,131 @@
+package tags
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+	"github.com/joho/godotenv"
+	_ "github.com/lib/pq"
+)
+
+func TestUnitNewListTagsParams(t *testing.T) {
+	lp := NewListTagsParams()
+	if lp.Limit == nil {
+		t.Fatal("Limit is nil")
+	}
+	if *lp.Limit != 10 {
+		t.Fatalf("expected limit 10, got %d", *lp.Limit)
+	}
+}
+
+func TestUnitMapListTagsParams(t *testing.T) {
+	acc := uuid.New()
+	prefix := "sum"
+	lp := NewListTagsParams()
+	lp.Prefix = &prefix
+
+	dbp := MapListTagsParams(List{AccountId: acc, RequestParams: lp})
+	if dbp.AccountID != acc {
+		t.Fatalf("expected account id %v, got %v", acc, dbp.AccountID)
+	}
+	if (!dbp.Prefix.Valid) || dbp.Prefix.String != prefix {
+		t.Fatalf("expected prefix %s, got %s", prefix, dbp.Prefix.String)
+	}
+	if dbp.Limit != 11 {
+		t.Fatalf("expected limit 11 to tell whether there are more, got %d", dbp.Limit)
+	}
+}
+
+func TestIntegrationList(t *testing.T) {
+	godotenv.Load("../../.env")
+	dbUrl := os.Getenv("DB_URL")
+
+	db, err := sql.Open("postgres", dbUrl)
+	if err != nil {
+		t.Fatalf("couldn't open database: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.Ping(); err != nil {
+		t.Fatalf("couldn't connect to database: %v", err)
+	}
+
+	q := database.New(db)
+
+	acc, err := q.CreateAccount(context.Background(), database.CreateAccountParams{
+		Country: database.CountryIE,
+		Nickname: sql.NullString{
+			String: "Test Account",
+			Valid:  true,
+		},
+	})
+	if err != nil {
+		t.Fatalf("couldn't create test account: %v", err)
+	}
+	defer db.Exec("DELETE FROM accounts WHERE id = $1;", acc.ID)
+
+	itemTags := [][]string{
+		{"summer", "sale"},
+		{"summer", "outdoor"},
+		{"summer"},
+		{"sale"},
+	}
+	for _, tags := range itemTags {
+		_, err := q.CreateItem(context.Background(), database.CreateItemParams{
+			ID:        uuid.New(),
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+			AccountID: acc.ID,
+			Name:      "Test Item",
+			Type:      database.ItemTypePRODUCT,
+			Tags:      tags,
+		})
+		if err != nil {
+			t.Fatalf("couldn't create test item: %v", err)
+		}
+	}
+
+	s := NewTagsService(q)
+
+	tags, hasMore, err := s.List(List{AccountId: acc.ID, RequestParams: NewListTagsParams()})
+	if err != nil {
+		t.Fatalf("error listing tags: %v", err)
+	}
+	if hasMore {
+		t.Fatalf("expected hasMore %v, got %v", false, hasMore)
+	}
+	expected := []Tag{{"summer", 3}, {"sale", 2}, {"outdoor", 1}}
+	if len(tags) != len(expected) {
+		t.Fatalf("expected %d tags, got %d", len(expected), len(tags))
+	}
+	for i, tag := range tags {
+		if *tag != expected[i] {
+			t.Fatalf("expected tag %v at %d, got %v", expected[i], i, *tag)
+		}
+	}
+
+	limit := int32(1)
+	tags, hasMore, err = s.List(List{AccountId: acc.ID, RequestParams: ListTagsParams{Limit: &limit}})
+	if err != nil {
+		t.Fatalf("error listing tags: %v", err)
+	}
+	if !hasMore || len(tags) != 1 || tags[0].Name != "summer" {
+		t.Fatalf("expected only summer with more to come, got %v and hasMore %v", tags, hasMore)
+	}
+
+	prefix := "sa"
+	lp := NewListTagsParams()
+	lp.Prefix = &prefix
+	tags, _, err = s.List(List{AccountId: acc.ID, RequestParams: lp})
+	if err != nil {
+		t.Fatalf("error listing tags: %v", err)
+	}
+	if len(tags) != 1 || tags[0].Name != "sale" {
+		t.Fatalf("expected only sale for prefix %s, got %v", prefix, tags)
+	}
+}
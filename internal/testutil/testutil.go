@@ -0,0 +1,123 @@
+package testutil
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+	"github.com/joho/godotenv"
+	_ "github.com/lib/pq"
+)
+
+// OpenAccount connects to the test database and creates an account for the
+// test. Callers close the connection and delete the account when done.
+func OpenAccount(t *testing.T) (*sql.DB, *database.Queries, uuid.UUID) {
+	t.Helper()
+	godotenv.Load("../../.env")
+	dbUrl := os.Getenv("DB_URL")
+
+	db, err := sql.Open("postgres", dbUrl)
+	if err != nil {
+		t.Fatalf("couldn't open database: %v", err)
+	}
+
+	if err := db.Ping(); err != nil {
+		t.Fatalf("couldn't connect to database: %v", err)
+	}
+
+	q := database.New(db)
+
+	acc, err := q.CreateAccount(context.Background(), database.CreateAccountParams{
+		Country: database.CountryIE,
+		Nickname: sql.NullString{
+			String: "Test Account",
+			Valid:  true,
+		},
+	})
+	if err != nil {
+		t.Fatalf("couldn't create test account: %v", err)
+	}
+
+	return db, q, acc.ID
+}
+
+func CreateLocation(t *testing.T, q *database.Queries, accountId uuid.UUID) uuid.UUID {
+	t.Helper()
+	row, err := q.CreateLocation(context.Background(), database.CreateLocationParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		AccountID: accountId,
+		Name:      "Test Location",
+		Type:      database.LocationTypeWarehouse,
+	})
+	if err != nil {
+		t.Fatalf("couldn't create test location: %v", err)
+	}
+	return row.ID
+}
+
+func CreateItem(t *testing.T, q *database.Queries, accountId uuid.UUID, itemType database.ItemType) uuid.UUID {
+	t.Helper()
+	return createItem(t, q, accountId, itemType, false)
+}
+
+func CreateSerializedItem(t *testing.T, q *database.Queries, accountId uuid.UUID) uuid.UUID {
+	t.Helper()
+	return createItem(t, q, accountId, database.ItemTypePRODUCT, true)
+}
+
+// CreateInventory creates an inventory of the item holding inStock, at the
+// location when one is given.
+func CreateInventory(t *testing.T, q *database.Queries, accountId, itemId uuid.UUID, locationId *uuid.UUID, inStock int32) uuid.UUID {
+	t.Helper()
+	inventory, err := q.CreateInventory(context.Background(), database.CreateInventoryParams{
+		ID:         uuid.New(),
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+		AccountID:  accountId,
+		InStock:    inStock,
+		ItemID:     api.NullUUID(&itemId),
+		LocationID: api.NullUUID(locationId),
+	})
+	if err != nil {
+		t.Fatalf("couldn't create test inventory: %v", err)
+	}
+	return inventory.ID
+}
+
+func AssertStock(t *testing.T, q *database.Queries, accountId, inventoryId uuid.UUID, inStock, reserved int32) {
+	t.Helper()
+	row, err := q.GetInventory(context.Background(), database.GetInventoryParams{ID: inventoryId, AccountID: accountId})
+	if err != nil {
+		t.Fatalf("error retrieving inventory: %v", err)
+	}
+	if row.InStock != inStock {
+		t.Fatalf("expected in_stock %d, got %d", inStock, row.InStock)
+	}
+	if row.Reserved.Int32 != reserved {
+		t.Fatalf("expected reserved %d, got %d", reserved, row.Reserved.Int32)
+	}
+}
+
+func createItem(t *testing.T, q *database.Queries, accountId uuid.UUID, itemType database.ItemType, serialized bool) uuid.UUID {
+	t.Helper()
+	item, err := q.CreateItem(context.Background(), database.CreateItemParams{
+		ID:         uuid.New(),
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+		AccountID:  accountId,
+		Name:       "Test Item",
+		Serialized: serialized,
+		Type:       itemType,
+	})
+	if err != nil {
+		t.Fatalf("couldn't create test item: %v", err)
+	}
+	return item.ID
+}
@@ -0,0 +1,61 @@
+package transfers
+
+import (
+	"time"
+
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+func MapCreateTransferParams(create Create) database.CreateTransferParams {
+	t := time.Now()
+	ctp := database.CreateTransferParams{
+		ID:                     uuid.New(),
+		CreatedAt:              t,
+		UpdatedAt:              t,
+		AccountID:              create.AccountId,
+		DestinationInventoryID: uuid.MustParse(create.RequestParams.DestinationInventory),
+		Quantity:               create.RequestParams.Quantity,
+		Reference:              api.NullString(create.RequestParams.Reference),
+		SourceInventoryID:      uuid.MustParse(create.RequestParams.SourceInventory),
+		Status:                 database.TransferStatusDraft,
+	}
+	return ctp
+}
+
+func MapListTransfersParams(list List) database.ListTransfersParams {
+	ltp := database.ListTransfersParams{
+		AccountID:              list.AccountId,
+		DestinationInventoryID: api.NullUUID(nil),
+		SourceInventoryID:      api.NullUUID(nil),
+	}
+	if list.RequestParams.DestinationInventory != nil {
+		inventoryId := uuid.MustParse(*list.RequestParams.DestinationInventory)
+		ltp.DestinationInventoryID = api.NullUUID(&inventoryId)
+	}
+	if list.RequestParams.SourceInventory != nil {
+		inventoryId := uuid.MustParse(*list.RequestParams.SourceInventory)
+		ltp.SourceInventoryID = api.NullUUID(&inventoryId)
+	}
+	if list.RequestParams.Status != nil {
+		ltp.Status = database.NullTransferStatus{
+			TransferStatus: *list.RequestParams.Status,
+			Valid:          true,
+		}
+	}
+	database.MapTimeRange(list.RequestParams.CreatedAt, &ltp.CreatedAtGt, &ltp.CreatedAtGte, &ltp.CreatedAtLt, &ltp.CreatedAtLte)
+	database.MapTimeRange(list.RequestParams.UpdatedAt, &ltp.UpdatedAtGt, &ltp.UpdatedAtGte, &ltp.UpdatedAtLt, &ltp.UpdatedAtLte)
+	database.MapPaginationParams(*list.RequestParams.PaginationParams, &ltp)
+	return ltp
+}
+
+func MapUpdateTransferParams(update Update) database.UpdateTransferParams {
+	return database.UpdateTransferParams{
+		UpdatedAt: time.Now(),
+		AccountID: update.AccountId,
+		ID:        update.TransferId,
+		Quantity:  api.NullInt32(update.RequestParams.Quantity),
+		Reference: api.NullString(update.RequestParams.Reference),
+	}
+}
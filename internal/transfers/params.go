@@ -0,0 +1,65 @@
+package transfers
+
+import (
+	"github.com/d-darac/inventory-assets/database"
+)
+
+type CancelTransferParams struct {
+	Expand []string `json:"expand" validate:"omitnil,dive,oneof=destination_inventory source_inventory"`
+}
+
+// CreateTransferParams takes quantity in unit when one is given, converted
+// against the source inventory's item by units.ToStockingUnit.
+type CreateTransferParams struct {
+	DestinationInventory string   `json:"destination_inventory" validate:"required,uuid,nefield=SourceInventory"`
+	Quantity             int32    `json:"quantity" validate:"required,gt=0"`
+	Reference            *string  `json:"reference" validate:"omitnil"`
+	SourceInventory      string   `json:"source_inventory" validate:"required,uuid"`
+	Unit                 *string  `json:"unit" validate:"omitnil,uuid"`
+	Expand               []string `json:"expand" validate:"omitnil,dive,oneof=destination_inventory source_inventory"`
+}
+
+type DispatchTransferParams struct {
+	Expand []string `json:"expand" validate:"omitnil,dive,oneof=destination_inventory source_inventory"`
+}
+
+type ListTransfersParams struct {
+	*database.PaginationParams
+	CreatedAt            *database.TimeRange      `json:"created_at" validate:"omitnil"`
+	DestinationInventory *string                  `json:"destination_inventory" validate:"omitnil,uuid"`
+	SourceInventory      *string                  `json:"source_inventory" validate:"omitnil,uuid"`
+	Status               *database.TransferStatus `json:"status" validate:"omitnil,oneof=draft in_transit received canceled"`
+	UpdatedAt            *database.TimeRange      `json:"updated_at" validate:"omitnil"`
+	Expand               []string                 `json:"expand" validate:"omitnil,dive,oneof=destination_inventory source_inventory"`
+}
+
+type ReceiveTransferParams struct {
+	Complete        *bool    `json:"complete" validate:"omitnil"`
+	DiscrepancyNote *string  `json:"discrepancy_note" validate:"omitnil"`
+	Lot             *string  `json:"lot" validate:"omitnil,uuid"`
+	Quantity        int32    `json:"quantity" validate:"required,gt=0"`
+	Unit            *string  `json:"unit" validate:"omitnil,uuid"`
+	Expand          []string `json:"expand" validate:"omitnil,dive,oneof=destination_inventory source_inventory"`
+}
+
+type RetrieveTransferParams struct {
+	Expand []string `json:"expand" validate:"omitnil,dive,oneof=destination_inventory source_inventory"`
+}
+
+// UpdateTransferParams takes unit only together with the quantity it
+// applies to.
+type UpdateTransferParams struct {
+	Quantity  *int32   `json:"quantity" validate:"omitnil,gt=0"`
+	Reference *string  `json:"reference" validate:"omitnil"`
+	Unit      *string  `json:"unit" validate:"omitnil,uuid"`
+	Expand    []string `json:"expand" validate:"omitnil,dive,oneof=destination_inventory source_inventory"`
+}
+
+func NewListTransfersParams() ListTransfersParams {
+	limit := int32(10)
+	return ListTransfersParams{
+		PaginationParams: &database.PaginationParams{
+			Limit: &limit,
+		},
+	}
+}
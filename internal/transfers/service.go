@@ -0,0 +1,594 @@
+package transfers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	stockmovements "github.com/d-darac/inventory-api/internal/stock_movements"
+	"github.com/d-darac/inventory-api/internal/units"
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/d-darac/inventory-assets/str"
+	"github.com/google/uuid"
+)
+
+type TransfersService struct {
+	Conn *sql.DB
+	Db   *database.Queries
+}
+
+type Cancel struct {
+	AccountId     uuid.UUID
+	TransferId    uuid.UUID
+	RequestParams CancelTransferParams
+}
+
+type Create struct {
+	AccountId     uuid.UUID
+	RequestParams CreateTransferParams
+}
+
+type Dispatch struct {
+	AccountId     uuid.UUID
+	TransferId    uuid.UUID
+	RequestParams DispatchTransferParams
+}
+
+type Get struct {
+	AccountId     uuid.UUID
+	TransferId    uuid.UUID
+	RequestParams RetrieveTransferParams
+	OmitBase      bool
+}
+
+type List struct {
+	AccountId     uuid.UUID
+	RequestParams ListTransfersParams
+}
+
+type Receive struct {
+	AccountId     uuid.UUID
+	TransferId    uuid.UUID
+	RequestParams ReceiveTransferParams
+}
+
+type Update struct {
+	AccountId     uuid.UUID
+	TransferId    uuid.UUID
+	RequestParams UpdateTransferParams
+}
+
+func NewTransfersService(db *database.Queries, conn *sql.DB) *TransfersService {
+	return &TransfersService{
+		Conn: conn,
+		Db:   db,
+	}
+}
+
+func (s *TransfersService) Cancel(cancel Cancel) (*Transfer, error) {
+	tx, err := s.Conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.Db.WithTx(tx)
+
+	if _, err := getForUpdate(qtx, cancel.AccountId, cancel.TransferId, database.TransferStatusDraft); err != nil {
+		return nil, err
+	}
+
+	row, err := qtx.CancelTransfer(context.Background(), database.CancelTransferParams{
+		UpdatedAt: time.Now(),
+		AccountID: cancel.AccountId,
+		ID:        cancel.TransferId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	transfer := &Transfer{
+		ID:                   &row.ID,
+		CreatedAt:            &row.CreatedAt,
+		UpdatedAt:            &row.UpdatedAt,
+		DestinationInventory: api.Expandable{ID: row.DestinationInventory},
+		DiscrepancyNote:      str.NullString(row.DiscrepancyNote),
+		DispatchedAt:         nullTime(row.DispatchedAt),
+		Quantity:             row.Quantity,
+		ReceivedAt:           nullTime(row.ReceivedAt),
+		ReceivedQuantity:     row.ReceivedQuantity,
+		Reference:            str.NullString(row.Reference),
+		SourceInventory:      api.Expandable{ID: row.SourceInventory},
+		Status:               row.Status,
+	}
+
+	return transfer, nil
+}
+
+func (s *TransfersService) Create(create Create) (*Transfer, error) {
+	sourceId := uuid.MustParse(create.RequestParams.SourceInventory)
+	destinationId := uuid.MustParse(create.RequestParams.DestinationInventory)
+	if sourceId == destinationId {
+		return nil, &api.AppError{
+			Message: "A transfer's source and destination inventories have to be different.",
+			Status:  http.StatusBadRequest,
+			Type:    api.InvalidRequestError,
+		}
+	}
+
+	source, err := getInventory(s.Db, create.AccountId, sourceId)
+	if err != nil {
+		return nil, err
+	}
+	destination, err := getInventory(s.Db, create.AccountId, destinationId)
+	if err != nil {
+		return nil, err
+	}
+	if source.Item != destination.Item {
+		return nil, &api.AppError{
+			Message: fmt.Sprintf("Inventories '%v' and '%v' hold different items, stock can only be transferred between inventories of the same item.", sourceId, destinationId),
+			Status:  http.StatusBadRequest,
+			Type:    api.InvalidRequestError,
+		}
+	}
+	if err := checkTransferable(s.Db, create.AccountId, sourceId); err != nil {
+		return nil, err
+	}
+
+	if create.RequestParams.Unit != nil {
+		quantity, err := units.ToStockingUnit(s.Db, create.AccountId, source.Item, uuid.MustParse(*create.RequestParams.Unit), create.RequestParams.Quantity)
+		if err != nil {
+			return nil, err
+		}
+		create.RequestParams.Quantity = quantity
+	}
+
+	dbParams := MapCreateTransferParams(create)
+	row, err := s.Db.CreateTransfer(context.Background(), dbParams)
+	if err != nil {
+		return nil, err
+	}
+
+	transfer := &Transfer{
+		ID:                   &row.ID,
+		CreatedAt:            &row.CreatedAt,
+		UpdatedAt:            &row.UpdatedAt,
+		DestinationInventory: api.Expandable{ID: row.DestinationInventory},
+		DiscrepancyNote:      str.NullString(row.DiscrepancyNote),
+		DispatchedAt:         nullTime(row.DispatchedAt),
+		Quantity:             row.Quantity,
+		ReceivedAt:           nullTime(row.ReceivedAt),
+		ReceivedQuantity:     row.ReceivedQuantity,
+		Reference:            str.NullString(row.Reference),
+		SourceInventory:      api.Expandable{ID: row.SourceInventory},
+		Status:               row.Status,
+	}
+
+	return transfer, nil
+}
+
+// Dispatch takes the transfer's quantity out of the source inventory and
+// marks the transfer as in transit.
+func (s *TransfersService) Dispatch(dispatch Dispatch) (*Transfer, error) {
+	tx, err := s.Conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.Db.WithTx(tx)
+
+	current, err := getForUpdate(qtx, dispatch.AccountId, dispatch.TransferId, database.TransferStatusDraft)
+	if err != nil {
+		return nil, err
+	}
+
+	reference := current.ID.String()
+	_, err = stockmovements.Post(qtx, stockmovements.Create{
+		AccountId: dispatch.AccountId,
+		RequestParams: stockmovements.CreateStockMovementParams{
+			Inventory: current.SourceInventory.UUID.String(),
+			Quantity:  -current.Quantity,
+			Reference: &reference,
+			Type:      database.StockMovementTypeTransferOut,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := qtx.DispatchTransfer(context.Background(), database.DispatchTransferParams{
+		UpdatedAt: time.Now(),
+		AccountID: dispatch.AccountId,
+		ID:        dispatch.TransferId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	transfer := &Transfer{
+		ID:                   &row.ID,
+		CreatedAt:            &row.CreatedAt,
+		UpdatedAt:            &row.UpdatedAt,
+		DestinationInventory: api.Expandable{ID: row.DestinationInventory},
+		DiscrepancyNote:      str.NullString(row.DiscrepancyNote),
+		DispatchedAt:         nullTime(row.DispatchedAt),
+		Quantity:             row.Quantity,
+		ReceivedAt:           nullTime(row.ReceivedAt),
+		ReceivedQuantity:     row.ReceivedQuantity,
+		Reference:            str.NullString(row.Reference),
+		SourceInventory:      api.Expandable{ID: row.SourceInventory},
+		Status:               row.Status,
+	}
+
+	return transfer, nil
+}
+
+func (s *TransfersService) Get(get Get) (*Transfer, error) {
+	row, err := s.Db.GetTransfer(context.Background(), database.GetTransferParams{
+		ID:        get.TransferId,
+		AccountID: get.AccountId,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, api.NotFoundMessage(get.TransferId, "transfer")
+		}
+		return nil, err
+	}
+
+	transfer := &Transfer{
+		DestinationInventory: api.Expandable{ID: row.DestinationInventory},
+		DiscrepancyNote:      str.NullString(row.DiscrepancyNote),
+		DispatchedAt:         nullTime(row.DispatchedAt),
+		Quantity:             row.Quantity,
+		ReceivedAt:           nullTime(row.ReceivedAt),
+		ReceivedQuantity:     row.ReceivedQuantity,
+		Reference:            str.NullString(row.Reference),
+		SourceInventory:      api.Expandable{ID: row.SourceInventory},
+		Status:               row.Status,
+	}
+
+	if !get.OmitBase {
+		transfer.ID = &row.ID
+		transfer.CreatedAt = &row.CreatedAt
+		transfer.UpdatedAt = &row.UpdatedAt
+	}
+
+	return transfer, nil
+}
+
+func (s *TransfersService) List(list List) (transfers []*Transfer, hasMore bool, err error) {
+	if list.RequestParams.StartingAfter != nil {
+		transfer, err := s.Get(Get{
+			AccountId:     list.AccountId,
+			TransferId:    *list.RequestParams.StartingAfter,
+			RequestParams: RetrieveTransferParams{},
+			OmitBase:      false,
+		})
+		if err != nil {
+			return transfers, hasMore, err
+		}
+		list.RequestParams.StartingAfterDate = transfer.CreatedAt
+	}
+
+	if list.RequestParams.EndingBefore != nil {
+		transfer, err := s.Get(Get{
+			AccountId:     list.AccountId,
+			TransferId:    *list.RequestParams.EndingBefore,
+			RequestParams: RetrieveTransferParams{},
+			OmitBase:      false,
+		})
+		if err != nil {
+			return transfers, hasMore, err
+		}
+		list.RequestParams.EndingBeforeDate = transfer.CreatedAt
+	}
+
+	dbParams := MapListTransfersParams(list)
+
+	rows, err := s.Db.ListTransfers(context.Background(), dbParams)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return transfers, hasMore, nil
+		}
+		return
+	}
+
+	if dbParams.Limit.Valid {
+		hasMore = len(rows) > int(dbParams.Limit.Int32)
+	} else {
+		hasMore = len(rows) > 10
+	}
+
+	if hasMore {
+		if dbParams.EndingBefore.Valid {
+			rows = rows[1:]
+		} else {
+			rows = rows[:len(rows)-1]
+		}
+	}
+
+	for _, row := range rows {
+		transfers = append(transfers, &Transfer{
+			ID:                   &row.ID,
+			CreatedAt:            &row.CreatedAt,
+			UpdatedAt:            &row.UpdatedAt,
+			DestinationInventory: api.Expandable{ID: row.DestinationInventory},
+			DiscrepancyNote:      str.NullString(row.DiscrepancyNote),
+			DispatchedAt:         nullTime(row.DispatchedAt),
+			Quantity:             row.Quantity,
+			ReceivedAt:           nullTime(row.ReceivedAt),
+			ReceivedQuantity:     row.ReceivedQuantity,
+			Reference:            str.NullString(row.Reference),
+			SourceInventory:      api.Expandable{ID: row.SourceInventory},
+			Status:               row.Status,
+		})
+	}
+
+	return transfers, hasMore, err
+}
+
+// Receive books a (possibly partial) receipt into the destination inventory.
+// The transfer is marked as received once everything has arrived, or earlier
+// when the receipt is flagged as complete, in which case the shortfall has to
+// be explained with a discrepancy note.
+func (s *TransfersService) Receive(receive Receive) (*Transfer, error) {
+	tx, err := s.Conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.Db.WithTx(tx)
+
+	current, err := getForUpdate(qtx, receive.AccountId, receive.TransferId, database.TransferStatusInTransit)
+	if err != nil {
+		return nil, err
+	}
+
+	quantity, err := toStockingUnit(qtx, receive.AccountId, current.DestinationInventory.UUID, receive.RequestParams.Unit, receive.RequestParams.Quantity)
+	if err != nil {
+		return nil, err
+	}
+	receive.RequestParams.Quantity = quantity
+
+	received := current.ReceivedQuantity + receive.RequestParams.Quantity
+	if received > current.Quantity {
+		return nil, &api.AppError{
+			Message: fmt.Sprintf("Can't receive more than the %d units outstanding on transfer '%v'.", current.Quantity-current.ReceivedQuantity, current.ID),
+			Status:  http.StatusBadRequest,
+			Type:    api.InvalidRequestError,
+		}
+	}
+
+	complete := received == current.Quantity
+	if receive.RequestParams.Complete != nil && *receive.RequestParams.Complete {
+		if !complete && receive.RequestParams.DiscrepancyNote == nil {
+			return nil, &api.AppError{
+				Message: "A discrepancy note is required to complete a transfer with a short receipt.",
+				Status:  http.StatusBadRequest,
+				Type:    api.InvalidRequestError,
+			}
+		}
+		complete = true
+	}
+
+	reference := current.ID.String()
+	_, err = stockmovements.Post(qtx, stockmovements.Create{
+		AccountId: receive.AccountId,
+		RequestParams: stockmovements.CreateStockMovementParams{
+			Inventory: current.DestinationInventory.UUID.String(),
+			Lot:       receive.RequestParams.Lot,
+			Quantity:  receive.RequestParams.Quantity,
+			Reason:    receive.RequestParams.DiscrepancyNote,
+			Reference: &reference,
+			Type:      database.StockMovementTypeTransferIn,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	status := database.TransferStatusInTransit
+	if complete {
+		status = database.TransferStatusReceived
+	}
+
+	row, err := qtx.ReceiveTransfer(context.Background(), database.ReceiveTransferParams{
+		UpdatedAt:       time.Now(),
+		AccountID:       receive.AccountId,
+		ID:              receive.TransferId,
+		DiscrepancyNote: api.NullString(receive.RequestParams.DiscrepancyNote),
+		Quantity:        receive.RequestParams.Quantity,
+		Status:          status,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	transfer := &Transfer{
+		ID:                   &row.ID,
+		CreatedAt:            &row.CreatedAt,
+		UpdatedAt:            &row.UpdatedAt,
+		DestinationInventory: api.Expandable{ID: row.DestinationInventory},
+		DiscrepancyNote:      str.NullString(row.DiscrepancyNote),
+		DispatchedAt:         nullTime(row.DispatchedAt),
+		Quantity:             row.Quantity,
+		ReceivedAt:           nullTime(row.ReceivedAt),
+		ReceivedQuantity:     row.ReceivedQuantity,
+		Reference:            str.NullString(row.Reference),
+		SourceInventory:      api.Expandable{ID: row.SourceInventory},
+		Status:               row.Status,
+	}
+
+	return transfer, nil
+}
+
+func (s *TransfersService) Update(update Update) (*Transfer, error) {
+	if update.RequestParams.Unit != nil && update.RequestParams.Quantity == nil {
+		return nil, &api.AppError{
+			Message: "A unit can only be given together with the quantity it's the unit of.",
+			Status:  http.StatusBadRequest,
+			Type:    api.InvalidRequestError,
+		}
+	}
+
+	tx, err := s.Conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.Db.WithTx(tx)
+
+	current, err := getForUpdate(qtx, update.AccountId, update.TransferId, database.TransferStatusDraft)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.RequestParams.Quantity != nil {
+		quantity, err := toStockingUnit(qtx, update.AccountId, current.SourceInventory.UUID, update.RequestParams.Unit, *update.RequestParams.Quantity)
+		if err != nil {
+			return nil, err
+		}
+		update.RequestParams.Quantity = &quantity
+	}
+
+	dbParams := MapUpdateTransferParams(update)
+
+	row, err := qtx.UpdateTransfer(context.Background(), dbParams)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	transfer := &Transfer{
+		ID:                   &row.ID,
+		CreatedAt:            &row.CreatedAt,
+		UpdatedAt:            &row.UpdatedAt,
+		DestinationInventory: api.Expandable{ID: row.DestinationInventory},
+		DiscrepancyNote:      str.NullString(row.DiscrepancyNote),
+		DispatchedAt:         nullTime(row.DispatchedAt),
+		Quantity:             row.Quantity,
+		ReceivedAt:           nullTime(row.ReceivedAt),
+		ReceivedQuantity:     row.ReceivedQuantity,
+		Reference:            str.NullString(row.Reference),
+		SourceInventory:      api.Expandable{ID: row.SourceInventory},
+		Status:               row.Status,
+	}
+
+	return transfer, nil
+}
+
+// checkTransferable makes sure the inventory's stock can be moved by the
+// plain transfer movements Dispatch and Receive post. Both inventories hold
+// the same item, so checking one of them is enough.
+func checkTransferable(q *database.Queries, accountId, inventoryId uuid.UUID) error {
+	bundle, err := q.IsInventoryBundle(context.Background(), database.IsInventoryBundleParams{
+		ID:        inventoryId,
+		AccountID: accountId,
+	})
+	if err != nil {
+		return err
+	}
+	if bundle {
+		return &api.AppError{
+			Message: fmt.Sprintf("Inventory '%v' holds a bundle, which has no stock of its own to transfer.", inventoryId),
+			Status:  http.StatusBadRequest,
+			Type:    api.InvalidRequestError,
+		}
+	}
+
+	serialized, err := q.IsInventorySerialized(context.Background(), database.IsInventorySerializedParams{
+		ID:        inventoryId,
+		AccountID: accountId,
+	})
+	if err != nil {
+		return err
+	}
+	if serialized {
+		return &api.AppError{
+			Message: fmt.Sprintf("Inventory '%v' is of a serialized item, which is moved through its serial numbers rather than a transfer.", inventoryId),
+			Status:  http.StatusBadRequest,
+			Type:    api.InvalidRequestError,
+		}
+	}
+
+	return nil
+}
+
+// getForUpdate locks the transfer and makes sure it's in the status the
+// next step of its lifecycle starts from.
+func getForUpdate(q *database.Queries, accountId, transferId uuid.UUID, status database.TransferStatus) (*database.GetTransferForUpdateRow, error) {
+	row, err := q.GetTransferForUpdate(context.Background(), database.GetTransferForUpdateParams{
+		ID:        transferId,
+		AccountID: accountId,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, api.NotFoundMessage(transferId, "transfer")
+		}
+		return nil, err
+	}
+
+	if row.Status != status {
+		return nil, &api.AppError{
+			Message: fmt.Sprintf("Transfer '%v' is %s, expected it to be %s.", transferId, row.Status, status),
+			Status:  http.StatusBadRequest,
+			Type:    api.InvalidRequestError,
+		}
+	}
+
+	return &row, nil
+}
+
+// toStockingUnit converts a quantity given in one of the units of the
+// inventory's item into its stocking unit. Without a unit the quantity is
+// already in the stocking unit.
+func toStockingUnit(q *database.Queries, accountId, inventoryId uuid.UUID, unit *string, quantity int32) (int32, error) {
+	if unit == nil {
+		return quantity, nil
+	}
+
+	inventory, err := getInventory(q, accountId, inventoryId)
+	if err != nil {
+		return 0, err
+	}
+
+	return units.ToStockingUnit(q, accountId, inventory.Item, uuid.MustParse(*unit), quantity)
+}
+
+func getInventory(q *database.Queries, accountId, inventoryId uuid.UUID) (*database.GetInventoryRow, error) {
+	row, err := q.GetInventory(context.Background(), database.GetInventoryParams{
+		ID:        inventoryId,
+		AccountID: accountId,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, api.NotFoundMessage(inventoryId, "inventory")
+		}
+		return nil, err
+	}
+	return &row, nil
+}
+
+func nullTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
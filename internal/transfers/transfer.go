@@ -0,0 +1,25 @@
+package transfers
+
+import (
+	"time"
+
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/d-darac/inventory-assets/str"
+	"github.com/google/uuid"
+)
+
+type Transfer struct {
+	ID                   *uuid.UUID              `json:"id,omitempty"`
+	CreatedAt            *time.Time              `json:"created_at,omitempty"`
+	UpdatedAt            *time.Time              `json:"updated_at,omitempty"`
+	DestinationInventory api.Expandable          `json:"destination_inventory"`
+	DiscrepancyNote      str.NullString          `json:"discrepancy_note"`
+	DispatchedAt         *time.Time              `json:"dispatched_at"`
+	Quantity             int32                   `json:"quantity"`
+	ReceivedAt           *time.Time              `json:"received_at"`
+	ReceivedQuantity     int32                   `json:"received_quantity"`
+	Reference            str.NullString          `json:"reference"`
+	SourceInventory      api.Expandable          `json:"source_inventory"`
+	Status               database.TransferStatus `json:"status"`
+}
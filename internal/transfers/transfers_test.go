@@ -0,0 +1,203 @@
+package transfers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/d-darac/inventory-api/internal/testutil"
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+func TestUnitCreateSameInventory(t *testing.T) {
+	s := NewTransfersService(nil, nil)
+	id := uuid.New()
+
+	tests := []struct {
+		name        string
+		source      string
+		destination string
+	}{
+		{name: "same", source: id.String(), destination: id.String()},
+		{name: "different case", source: id.String(), destination: strings.ToUpper(id.String())},
+	}
+
+	for _, tt := range tests {
+		_, err := s.Create(Create{AccountId: uuid.New(), RequestParams: CreateTransferParams{
+			DestinationInventory: tt.destination,
+			Quantity:             1,
+			SourceInventory:      tt.source,
+		}})
+		if _, ok := err.(*api.AppError); !ok {
+			t.Fatalf("%s: expected an invalid request error, got %v", tt.name, err)
+		}
+	}
+}
+
+func TestUnitUpdateUnitWithoutQuantity(t *testing.T) {
+	s := NewTransfersService(nil, nil)
+	unit := uuid.New().String()
+
+	_, err := s.Update(Update{AccountId: uuid.New(), TransferId: uuid.New(), RequestParams: UpdateTransferParams{Unit: &unit}})
+	if _, ok := err.(*api.AppError); !ok {
+		t.Fatalf("expected an invalid request error, got %v", err)
+	}
+}
+
+func TestIntegrationDispatchReceive(t *testing.T) {
+	db, q, accountId := testutil.OpenAccount(t)
+	defer db.Close()
+	defer db.Exec("DELETE FROM accounts WHERE id = $1;", accountId)
+
+	s := NewTransfersService(q, db)
+	item := testutil.CreateItem(t, q, accountId, database.ItemTypePRODUCT)
+	source := testutil.CreateInventory(t, q, accountId, item, nil, 10)
+	destination := testutil.CreateInventory(t, q, accountId, item, nil, 0)
+
+	transfer, err := s.Create(Create{AccountId: accountId, RequestParams: CreateTransferParams{
+		DestinationInventory: destination.String(),
+		Quantity:             6,
+		SourceInventory:      source.String(),
+	}})
+	if err != nil {
+		t.Fatalf("couldn't create transfer: %v", err)
+	}
+	testutil.AssertStock(t, q, accountId, source, 10, 0)
+
+	if _, err := s.Receive(Receive{AccountId: accountId, TransferId: *transfer.ID, RequestParams: ReceiveTransferParams{Quantity: 1}}); err == nil {
+		t.Fatal("expected receiving a draft transfer to fail")
+	}
+
+	transfer, err = s.Dispatch(Dispatch{AccountId: accountId, TransferId: *transfer.ID})
+	if err != nil {
+		t.Fatalf("couldn't dispatch transfer: %v", err)
+	}
+	if transfer.Status != database.TransferStatusInTransit {
+		t.Fatalf("expected status %v, got %v", database.TransferStatusInTransit, transfer.Status)
+	}
+	testutil.AssertStock(t, q, accountId, source, 4, 0)
+	testutil.AssertStock(t, q, accountId, destination, 0, 0)
+
+	if _, err := s.Receive(Receive{AccountId: accountId, TransferId: *transfer.ID, RequestParams: ReceiveTransferParams{Quantity: 7}}); err == nil {
+		t.Fatal("expected receiving more than was dispatched to fail")
+	}
+
+	transfer, err = s.Receive(Receive{AccountId: accountId, TransferId: *transfer.ID, RequestParams: ReceiveTransferParams{Quantity: 4}})
+	if err != nil {
+		t.Fatalf("couldn't receive transfer: %v", err)
+	}
+	if transfer.Status != database.TransferStatusInTransit || transfer.ReceivedQuantity != 4 {
+		t.Fatalf("expected 4 received in transit, got %d %v", transfer.ReceivedQuantity, transfer.Status)
+	}
+	testutil.AssertStock(t, q, accountId, destination, 4, 0)
+
+	transfer, err = s.Receive(Receive{AccountId: accountId, TransferId: *transfer.ID, RequestParams: ReceiveTransferParams{Quantity: 2}})
+	if err != nil {
+		t.Fatalf("couldn't receive transfer: %v", err)
+	}
+	if transfer.Status != database.TransferStatusReceived {
+		t.Fatalf("expected status %v, got %v", database.TransferStatusReceived, transfer.Status)
+	}
+	testutil.AssertStock(t, q, accountId, source, 4, 0)
+	testutil.AssertStock(t, q, accountId, destination, 6, 0)
+
+	// The movements out of the source and into the destination both
+	// reference the transfer and add up to the same quantity.
+	rows, err := db.Query("SELECT type, SUM(quantity) FROM stock_movements WHERE account_id = $1 AND reference = $2 GROUP BY type;", accountId, transfer.ID.String())
+	if err != nil {
+		t.Fatalf("error summing stock movements: %v", err)
+	}
+	defer rows.Close()
+
+	typeQuantityMap := make(map[database.StockMovementType]int32)
+	for rows.Next() {
+		var movementType database.StockMovementType
+		var quantity int32
+		if err := rows.Scan(&movementType, &quantity); err != nil {
+			t.Fatalf("error scanning stock movements: %v", err)
+		}
+		typeQuantityMap[movementType] = quantity
+	}
+	if len(typeQuantityMap) != 2 || typeQuantityMap[database.StockMovementTypeTransferOut] != -6 || typeQuantityMap[database.StockMovementTypeTransferIn] != 6 {
+		t.Fatalf("expected -6 transferred out and 6 in, got %v", typeQuantityMap)
+	}
+}
+
+func TestIntegrationShortReceipt(t *testing.T) {
+	db, q, accountId := testutil.OpenAccount(t)
+	defer db.Close()
+	defer db.Exec("DELETE FROM accounts WHERE id = $1;", accountId)
+
+	s := NewTransfersService(q, db)
+	item := testutil.CreateItem(t, q, accountId, database.ItemTypePRODUCT)
+	source := testutil.CreateInventory(t, q, accountId, item, nil, 5)
+	destination := testutil.CreateInventory(t, q, accountId, item, nil, 0)
+
+	transfer, err := s.Create(Create{AccountId: accountId, RequestParams: CreateTransferParams{
+		DestinationInventory: destination.String(),
+		Quantity:             5,
+		SourceInventory:      source.String(),
+	}})
+	if err != nil {
+		t.Fatalf("couldn't create transfer: %v", err)
+	}
+	if _, err := s.Dispatch(Dispatch{AccountId: accountId, TransferId: *transfer.ID}); err != nil {
+		t.Fatalf("couldn't dispatch transfer: %v", err)
+	}
+
+	complete := true
+	if _, err := s.Receive(Receive{AccountId: accountId, TransferId: *transfer.ID, RequestParams: ReceiveTransferParams{Complete: &complete, Quantity: 3}}); err == nil {
+		t.Fatal("expected completing a short receipt without a discrepancy note to fail")
+	}
+	testutil.AssertStock(t, q, accountId, destination, 0, 0)
+
+	note := "2 damaged in transit"
+	transfer, err = s.Receive(Receive{AccountId: accountId, TransferId: *transfer.ID, RequestParams: ReceiveTransferParams{Complete: &complete, DiscrepancyNote: &note, Quantity: 3}})
+	if err != nil {
+		t.Fatalf("couldn't receive transfer: %v", err)
+	}
+	if transfer.Status != database.TransferStatusReceived || transfer.ReceivedQuantity != 3 {
+		t.Fatalf("expected 3 received and status %v, got %d %v", database.TransferStatusReceived, transfer.ReceivedQuantity, transfer.Status)
+	}
+	testutil.AssertStock(t, q, accountId, destination, 3, 0)
+}
+
+func TestIntegrationCreateDifferentItems(t *testing.T) {
+	db, q, accountId := testutil.OpenAccount(t)
+	defer db.Close()
+	defer db.Exec("DELETE FROM accounts WHERE id = $1;", accountId)
+
+	s := NewTransfersService(q, db)
+	source := testutil.CreateInventory(t, q, accountId, testutil.CreateItem(t, q, accountId, database.ItemTypePRODUCT), nil, 5)
+	destination := testutil.CreateInventory(t, q, accountId, testutil.CreateItem(t, q, accountId, database.ItemTypePRODUCT), nil, 0)
+
+	_, err := s.Create(Create{AccountId: accountId, RequestParams: CreateTransferParams{
+		DestinationInventory: destination.String(),
+		Quantity:             5,
+		SourceInventory:      source.String(),
+	}})
+	if _, ok := err.(*api.AppError); !ok {
+		t.Fatalf("expected an invalid request error for inventories of different items, got %v", err)
+	}
+}
+
+func TestIntegrationCreateSerialized(t *testing.T) {
+	db, q, accountId := testutil.OpenAccount(t)
+	defer db.Close()
+	defer db.Exec("DELETE FROM accounts WHERE id = $1;", accountId)
+
+	s := NewTransfersService(q, db)
+	item := testutil.CreateSerializedItem(t, q, accountId)
+	source := testutil.CreateInventory(t, q, accountId, item, nil, 1)
+	destination := testutil.CreateInventory(t, q, accountId, item, nil, 0)
+
+	_, err := s.Create(Create{AccountId: accountId, RequestParams: CreateTransferParams{
+		DestinationInventory: destination.String(),
+		Quantity:             1,
+		SourceInventory:      source.String(),
+	}})
+	if _, ok := err.(*api.AppError); !ok {
+		t.Fatalf("expected an invalid request error for a serialized item, got %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package units
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"net/http"
+
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+// Convert turns a quantity given in a unit with decimalPlaces into the
+// stocking unit, where factor is how many stocking quantities make up one
+// whole given unit. It errors when the result isn't a whole stocking
+// quantity or doesn't fit in one.
+func Convert(quantity, factor, decimalPlaces int32) (int32, error) {
+	scale := int64(math.Pow10(int(decimalPlaces)))
+	product := int64(quantity) * int64(factor)
+
+	if product%scale != 0 {
+		return 0, &api.AppError{
+			Message: fmt.Sprintf("Quantity %d doesn't convert to a whole quantity of the stocking unit.", quantity),
+			Status:  http.StatusBadRequest,
+			Type:    api.InvalidRequestError,
+		}
+	}
+
+	converted := product / scale
+	if converted > math.MaxInt32 || converted < math.MinInt32 {
+		return 0, &api.AppError{
+			Message: fmt.Sprintf("Quantity %d is too large once converted to the stocking unit.", quantity),
+			Status:  http.StatusBadRequest,
+			Type:    api.InvalidRequestError,
+		}
+	}
+
+	return int32(converted), nil
+}
+
+// ToStockingUnit converts a quantity given in one of an item's units, i.e.
+// its stocking, purchase or sales unit, into its stocking unit. Quantities
+// are integers throughout the API, so one given in a unit counts that unit's
+// smallest fraction as set by its decimal_places: with 3 decimal places,
+// 1500 in a kilogram unit is 1.5 kg.
+func ToStockingUnit(q *database.Queries, accountId uuid.UUID, itemId uuid.NullUUID, unitId uuid.UUID, quantity int32) (int32, error) {
+	if !itemId.Valid {
+		return 0, noUnitsMessage()
+	}
+
+	row, err := q.GetItemUnits(context.Background(), database.GetItemUnitsParams{
+		ID:        itemId.UUID,
+		AccountID: accountId,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, api.NotFoundMessage(itemId.UUID, "item")
+		}
+		return 0, err
+	}
+
+	if !row.StockingUnit.Valid {
+		return 0, noUnitsMessage()
+	}
+
+	switch {
+	case row.StockingUnit.UUID == unitId:
+		return quantity, nil
+	case row.PurchaseUnit.Valid && row.PurchaseUnit.UUID == unitId:
+		return Convert(quantity, row.PurchaseUnitFactor.Int32, row.PurchaseUnitDecimalPlaces.Int32)
+	case row.SalesUnit.Valid && row.SalesUnit.UUID == unitId:
+		return Convert(quantity, row.SalesUnitFactor.Int32, row.SalesUnitDecimalPlaces.Int32)
+	}
+
+	return 0, &api.AppError{
+		Message: fmt.Sprintf("Unit '%v' isn't the stocking, purchase or sales unit of item '%v'.", unitId, itemId.UUID),
+		Status:  http.StatusBadRequest,
+		Type:    api.InvalidRequestError,
+	}
+}
+
+func noUnitsMessage() error {
+	return &api.AppError{
+		Message: "A unit can only be given for an item that has a stocking unit.",
+		Status:  http.StatusBadRequest,
+		Type:    api.InvalidRequestError,
+	}
+}
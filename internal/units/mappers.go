@@ -0,0 +1,48 @@
+package units
+
+import (
+	"time"
+
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+func MapCreateUnitParams(create Create) database.CreateUnitParams {
+	t := time.Now()
+	cup := database.CreateUnitParams{
+		ID:        uuid.New(),
+		CreatedAt: t,
+		UpdatedAt: t,
+		AccountID: create.AccountId,
+		Code:      create.RequestParams.Code,
+		Name:      create.RequestParams.Name,
+	}
+	if create.RequestParams.DecimalPlaces != nil {
+		cup.DecimalPlaces = *create.RequestParams.DecimalPlaces
+	}
+	return cup
+}
+
+func MapListUnitsParams(list List) database.ListUnitsParams {
+	lup := database.ListUnitsParams{
+		AccountID: list.AccountId,
+		Code:      api.NullString(list.RequestParams.Code),
+		Name:      api.NullString(list.RequestParams.Name),
+	}
+	database.MapTimeRange(list.RequestParams.CreatedAt, &lup.CreatedAtGt, &lup.CreatedAtGte, &lup.CreatedAtLt, &lup.CreatedAtLte)
+	database.MapTimeRange(list.RequestParams.UpdatedAt, &lup.UpdatedAtGt, &lup.UpdatedAtGte, &lup.UpdatedAtLt, &lup.UpdatedAtLte)
+	database.MapPaginationParams(*list.RequestParams.PaginationParams, &lup)
+	return lup
+}
+
+func MapUpdateUnitParams(update Update) database.UpdateUnitParams {
+	uup := database.UpdateUnitParams{
+		UpdatedAt: time.Now(),
+		AccountID: update.AccountId,
+		ID:        update.UnitId,
+		Code:      api.NullString(update.RequestParams.Code),
+		Name:      api.NullString(update.RequestParams.Name),
+	}
+	return uup
+}
@@ -0,0 +1,43 @@
+package units
+
+import (
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+type CreateUnitParams struct {
+	Code          string `json:"code" validate:"required"`
+	DecimalPlaces *int32 `json:"decimal_places" validate:"omitnil,gte=0,lte=6"`
+	Name          string `json:"name" validate:"required"`
+}
+
+type ListUnitsByIdsParams struct {
+	Ids []uuid.UUID
+}
+
+type ListUnitsParams struct {
+	*database.PaginationParams
+	Code      *string             `json:"code" validate:"omitnil"`
+	CreatedAt *database.TimeRange `json:"created_at" validate:"omitnil"`
+	Name      *string             `json:"name" validate:"omitnil"`
+	UpdatedAt *database.TimeRange `json:"updated_at" validate:"omitnil"`
+}
+
+type RetrieveUnitParams struct {
+}
+
+// UpdateUnitParams leaves out decimal_places, as changing it would change
+// the meaning of every quantity already recorded in the unit.
+type UpdateUnitParams struct {
+	Code *string `json:"code" validate:"omitnil"`
+	Name *string `json:"name" validate:"omitnil"`
+}
+
+func NewListUnitsParams() ListUnitsParams {
+	limit := int32(10)
+	return ListUnitsParams{
+		PaginationParams: &database.PaginationParams{
+			Limit: &limit,
+		},
+	}
+}
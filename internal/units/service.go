@@ -0,0 +1,256 @@
+package units
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+type UnitsService struct {
+	Db *database.Queries
+}
+
+type Create struct {
+	AccountId     uuid.UUID
+	RequestParams CreateUnitParams
+}
+
+type Delete struct {
+	AccountId uuid.UUID
+	UnitId    uuid.UUID
+}
+
+type Get struct {
+	AccountId     uuid.UUID
+	UnitId        uuid.UUID
+	RequestParams RetrieveUnitParams
+	OmitBase      bool
+}
+
+type ListByIds struct {
+	AccountId     uuid.UUID
+	RequestParams ListUnitsByIdsParams
+}
+
+type List struct {
+	AccountId     uuid.UUID
+	RequestParams ListUnitsParams
+}
+
+type Update struct {
+	AccountId     uuid.UUID
+	UnitId        uuid.UUID
+	RequestParams UpdateUnitParams
+}
+
+func NewUnitsService(db *database.Queries) *UnitsService {
+	return &UnitsService{
+		Db: db,
+	}
+}
+
+func (s *UnitsService) Create(create Create) (*Unit, error) {
+	dbParams := MapCreateUnitParams(create)
+	row, err := s.Db.CreateUnit(context.Background(), dbParams)
+	if err != nil {
+		return nil, err
+	}
+
+	unit := &Unit{
+		ID:            &row.ID,
+		CreatedAt:     &row.CreatedAt,
+		UpdatedAt:     &row.UpdatedAt,
+		Code:          row.Code,
+		DecimalPlaces: row.DecimalPlaces,
+		Name:          row.Name,
+	}
+
+	return unit, nil
+}
+
+func (s *UnitsService) Delete(delete Delete) error {
+	_, err := s.Get(Get{
+		AccountId:     delete.AccountId,
+		UnitId:        delete.UnitId,
+		RequestParams: RetrieveUnitParams{},
+		OmitBase:      true,
+	})
+	if err != nil {
+		return err
+	}
+
+	inUse, err := s.Db.IsUnitInUse(context.Background(), database.IsUnitInUseParams{
+		ID:        delete.UnitId,
+		AccountID: delete.AccountId,
+	})
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return &api.AppError{
+			Message: fmt.Sprintf("Unit '%v' is used by items and can't be deleted.", delete.UnitId),
+			Status:  http.StatusBadRequest,
+			Type:    api.InvalidRequestError,
+		}
+	}
+
+	return s.Db.DeleteUnit(context.Background(), database.DeleteUnitParams{
+		ID:        delete.UnitId,
+		AccountID: delete.AccountId,
+	})
+}
+
+func (s *UnitsService) Get(get Get) (*Unit, error) {
+	row, err := s.Db.GetUnit(context.Background(), database.GetUnitParams{
+		ID:        get.UnitId,
+		AccountID: get.AccountId,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, api.NotFoundMessage(get.UnitId, "unit")
+		}
+		return nil, err
+	}
+
+	unit := &Unit{
+		Code:          row.Code,
+		DecimalPlaces: row.DecimalPlaces,
+		Name:          row.Name,
+	}
+
+	if !get.OmitBase {
+		unit.ID = &row.ID
+		unit.CreatedAt = &row.CreatedAt
+		unit.UpdatedAt = &row.UpdatedAt
+	}
+
+	return unit, nil
+}
+
+func (s *UnitsService) ListByIds(list ListByIds) (units []*Unit, err error) {
+	params := database.ListUnitsByIdsParams{
+		AccountID: list.AccountId,
+		Ids:       list.RequestParams.Ids,
+	}
+
+	rows, err := s.Db.ListUnitsByIds(context.Background(), params)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return units, nil
+		}
+		return
+	}
+
+	for _, row := range rows {
+		units = append(units, &Unit{
+			ID:            &row.ID,
+			CreatedAt:     &row.CreatedAt,
+			UpdatedAt:     &row.UpdatedAt,
+			Code:          row.Code,
+			DecimalPlaces: row.DecimalPlaces,
+			Name:          row.Name,
+		})
+	}
+
+	return
+}
+
+func (s *UnitsService) List(list List) (units []*Unit, hasMore bool, err error) {
+	if list.RequestParams.StartingAfter != nil {
+		unit, err := s.Get(Get{
+			AccountId:     list.AccountId,
+			UnitId:        *list.RequestParams.StartingAfter,
+			RequestParams: RetrieveUnitParams{},
+			OmitBase:      false,
+		})
+		if err != nil {
+			return units, hasMore, err
+		}
+		list.RequestParams.StartingAfterDate = unit.CreatedAt
+	}
+
+	if list.RequestParams.EndingBefore != nil {
+		unit, err := s.Get(Get{
+			AccountId:     list.AccountId,
+			UnitId:        *list.RequestParams.EndingBefore,
+			RequestParams: RetrieveUnitParams{},
+			OmitBase:      false,
+		})
+		if err != nil {
+			return units, hasMore, err
+		}
+		list.RequestParams.EndingBeforeDate = unit.CreatedAt
+	}
+
+	dbParams := MapListUnitsParams(list)
+
+	rows, err := s.Db.ListUnits(context.Background(), dbParams)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return units, hasMore, nil
+		}
+		return
+	}
+
+	if dbParams.Limit.Valid {
+		hasMore = len(rows) > int(dbParams.Limit.Int32)
+	} else {
+		hasMore = len(rows) > 10
+	}
+
+	if hasMore {
+		if dbParams.EndingBefore.Valid {
+			rows = rows[1:]
+		} else {
+			rows = rows[:len(rows)-1]
+		}
+	}
+
+	for _, row := range rows {
+		units = append(units, &Unit{
+			ID:            &row.ID,
+			CreatedAt:     &row.CreatedAt,
+			UpdatedAt:     &row.UpdatedAt,
+			Code:          row.Code,
+			DecimalPlaces: row.DecimalPlaces,
+			Name:          row.Name,
+		})
+	}
+
+	return units, hasMore, err
+}
+
+func (s *UnitsService) Update(update Update) (*Unit, error) {
+	_, err := s.Get(Get{
+		AccountId:     update.AccountId,
+		UnitId:        update.UnitId,
+		RequestParams: RetrieveUnitParams{},
+		OmitBase:      true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dbParams := MapUpdateUnitParams(update)
+
+	row, err := s.Db.UpdateUnit(context.Background(), dbParams)
+	if err != nil {
+		return nil, err
+	}
+
+	unit := &Unit{
+		ID:            &row.ID,
+		CreatedAt:     &row.CreatedAt,
+		UpdatedAt:     &row.UpdatedAt,
+		Code:          row.Code,
+		DecimalPlaces: row.DecimalPlaces,
+		Name:          row.Name,
+	}
+
+	return unit, nil
+}
@@ -0,0 +1,19 @@
+package units
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Unit is a unit of measure. Quantities in a unit are whole numbers of its
+// smallest fraction, e.g. grams for a kilogram unit with 3 decimal places,
+// the same way amounts are whole numbers of a currency's minor unit.
+type Unit struct {
+	ID            *uuid.UUID `json:"id,omitempty"`
+	CreatedAt     *time.Time `json:"created_at,omitempty"`
+	UpdatedAt     *time.Time `json:"updated_at,omitempty"`
+	Code          string     `json:"code"`
+	DecimalPlaces int32      `json:"decimal_places"`
+	Name          string     `json:"name"`
+}
@@ -0,0 +1,37 @@
+package units
+
+import "testing"
+
+func TestUnitConvert(t *testing.T) {
+	tests := []struct {
+		name          string
+		quantity      int32
+		factor        int32
+		decimalPlaces int32
+		want          int32
+		wantErr       bool
+	}{
+		{name: "cases of 24", quantity: 3, factor: 24, want: 72},
+		{name: "negative", quantity: -2, factor: 24, want: -48},
+		{name: "fractional unit", quantity: 1500, factor: 1000, decimalPlaces: 3, want: 1500},
+		{name: "pounds to grams not whole", quantity: 251, factor: 454, decimalPlaces: 2, wantErr: true},
+		{name: "pounds to grams exact", quantity: 200, factor: 454, decimalPlaces: 2, want: 908},
+		{name: "overflow", quantity: 1 << 30, factor: 24, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := Convert(tt.quantity, tt.factor, tt.decimalPlaces)
+		if tt.wantErr {
+			if err == nil {
+				t.Fatalf("%s: expected an error, got %d", tt.name, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got != tt.want {
+			t.Fatalf("%s: expected %d, got %d", tt.name, tt.want, got)
+		}
+	}
+}
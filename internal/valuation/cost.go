@@ -0,0 +1,87 @@
+package valuation
+
+import (
+	"errors"
+
+	"github.com/d-darac/inventory-assets/database"
+)
+
+var errMixedCurrencies = errors.New("receipts in more than one currency")
+
+// Movement is a stock movement of a single item as seen by the valuation.
+type Movement struct {
+	Quantity         int32
+	Type             database.StockMovementType
+	UnitCostAmount   int32
+	UnitCostCurrency database.NullCurrency
+}
+
+type layer struct {
+	quantity int64
+	cost     int64
+}
+
+// Value replays the movements of an item, oldest first, and returns the
+// quantity left and its value. Movements that add stock without a cost of
+// their own are valued at the last known cost (FIFO) or at the running
+// average (weighted average). Transfers only move stock between inventories
+// of the same item, so they don't change its value and are skipped.
+func Value(method Method, movements []Movement) (quantity, value int64, cur database.NullCurrency, err error) {
+	var lastCost int64
+	var layers []layer
+
+	for _, m := range movements {
+		if m.Type == database.StockMovementTypeTransferIn || m.Type == database.StockMovementTypeTransferOut {
+			continue
+		}
+
+		if m.UnitCostCurrency.Valid {
+			if cur.Valid && cur.Currency != m.UnitCostCurrency.Currency {
+				return 0, 0, cur, errMixedCurrencies
+			}
+			cur = m.UnitCostCurrency
+		}
+
+		q := int64(m.Quantity)
+		if q > 0 {
+			cost := lastCost
+			if m.UnitCostCurrency.Valid {
+				cost = int64(m.UnitCostAmount)
+				lastCost = cost
+			} else if method == MethodWeightedAverage && quantity > 0 {
+				cost = value / quantity
+			}
+			if method == MethodFIFO {
+				layers = append(layers, layer{quantity: q, cost: cost})
+			}
+			quantity += q
+			value += q * cost
+			continue
+		}
+
+		// Stock held before it was tracked in the ledger has no movements,
+		// so a decrement can exceed what the replay has seen; only what's
+		// there is taken.
+		out := min(-q, quantity)
+		if out <= 0 {
+			continue
+		}
+		switch method {
+		case MethodFIFO:
+			for remaining := out; remaining > 0 && len(layers) > 0; {
+				take := min(remaining, layers[0].quantity)
+				layers[0].quantity -= take
+				value -= take * layers[0].cost
+				remaining -= take
+				if layers[0].quantity == 0 {
+					layers = layers[1:]
+				}
+			}
+		case MethodWeightedAverage:
+			value -= value * out / quantity
+		}
+		quantity -= out
+	}
+
+	return quantity, value, cur, nil
+}
@@ -0,0 +1,13 @@
+package valuation
+
+import (
+	"time"
+)
+
+type RetrieveValuationParams struct {
+	AsOf   *time.Time `json:"as_of" validate:"omitnil"`
+	Group  *string    `json:"group" validate:"omitnil,uuid"`
+	Item   *string    `json:"item" validate:"omitnil,uuid"`
+	Method *Method    `json:"method" validate:"omitnil,oneof=fifo weighted_average"`
+	Expand []string   `json:"expand" validate:"omitnil,dive,oneof=group item"`
+}
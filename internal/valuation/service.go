@@ -0,0 +1,139 @@
+package valuation
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/currency"
+	"github.com/d-darac/inventory-assets/database"
+	"github.com/google/uuid"
+)
+
+type ValuationService struct {
+	Db *database.Queries
+}
+
+type Get struct {
+	AccountId     uuid.UUID
+	RequestParams RetrieveValuationParams
+}
+
+func NewValuationService(db *database.Queries) *ValuationService {
+	return &ValuationService{
+		Db: db,
+	}
+}
+
+// Get values the stock of every item as of the given date by replaying the
+// stock movement ledger up to it, then sums the item values per group and
+// for the whole account, separately for each currency.
+func (s *ValuationService) Get(get Get) (*Valuation, error) {
+	asOf := time.Now()
+	if get.RequestParams.AsOf != nil {
+		asOf = *get.RequestParams.AsOf
+	}
+	method := MethodFIFO
+	if get.RequestParams.Method != nil {
+		method = *get.RequestParams.Method
+	}
+
+	params := database.ListValuationMovementsParams{
+		AccountID: get.AccountId,
+		AsOf:      asOf,
+		GroupID:   api.NullUUID(nil),
+		ItemID:    api.NullUUID(nil),
+	}
+	if get.RequestParams.Group != nil {
+		groupId := uuid.MustParse(*get.RequestParams.Group)
+		params.GroupID = api.NullUUID(&groupId)
+	}
+	if get.RequestParams.Item != nil {
+		itemId := uuid.MustParse(*get.RequestParams.Item)
+		params.ItemID = api.NullUUID(&itemId)
+	}
+
+	rows, err := s.Db.ListValuationMovements(context.Background(), params)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	itemsIds := make([]uuid.UUID, 0)
+	itemGroups := make(map[uuid.UUID]uuid.NullUUID, 0)
+	itemMovements := make(map[uuid.UUID][]Movement, 0)
+	for _, row := range rows {
+		if _, ok := itemMovements[row.Item]; !ok {
+			itemsIds = append(itemsIds, row.Item)
+			itemGroups[row.Item] = row.Group
+		}
+		itemMovements[row.Item] = append(itemMovements[row.Item], Movement{
+			Quantity:         row.Quantity,
+			Type:             row.Type,
+			UnitCostAmount:   row.UnitCostAmount.Int32,
+			UnitCostCurrency: row.UnitCostCurrency,
+		})
+	}
+
+	valuation := &Valuation{
+		AsOf:   asOf,
+		Groups: make([]*GroupValuation, 0),
+		Items:  make([]*ItemValuation, 0, len(itemsIds)),
+		Method: method,
+		Totals: make([]*Total, 0),
+	}
+
+	type groupKey struct {
+		group    uuid.NullUUID
+		currency database.NullCurrency
+	}
+	groups := make(map[groupKey]*GroupValuation, 0)
+	totals := make(map[database.NullCurrency]*Total, 0)
+
+	for _, itemId := range itemsIds {
+		quantity, value, cur, err := Value(method, itemMovements[itemId])
+		if err != nil {
+			return nil, &api.AppError{
+				Message: fmt.Sprintf("Item '%v' has receipts in more than one currency and can't be valued.", itemId),
+				Status:  http.StatusBadRequest,
+				Type:    api.InvalidRequestError,
+			}
+		}
+
+		valuation.Items = append(valuation.Items, &ItemValuation{
+			Currency: currency.NullCurrency(cur),
+			Group:    api.Expandable{ID: itemGroups[itemId]},
+			Item:     api.Expandable{ID: uuid.NullUUID{UUID: itemId, Valid: true}},
+			Quantity: quantity,
+			Value:    value,
+		})
+
+		if itemGroups[itemId].Valid {
+			key := groupKey{group: itemGroups[itemId], currency: cur}
+			group, ok := groups[key]
+			if !ok {
+				group = &GroupValuation{
+					Currency: currency.NullCurrency(cur),
+					Group:    api.Expandable{ID: itemGroups[itemId]},
+				}
+				groups[key] = group
+				valuation.Groups = append(valuation.Groups, group)
+			}
+			group.Quantity += quantity
+			group.Value += value
+		}
+
+		total, ok := totals[cur]
+		if !ok {
+			total = &Total{Currency: currency.NullCurrency(cur)}
+			totals[cur] = total
+			valuation.Totals = append(valuation.Totals, total)
+		}
+		total.Quantity += quantity
+		total.Value += value
+	}
+
+	return valuation, nil
+}
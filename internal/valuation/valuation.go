@@ -0,0 +1,45 @@
+package valuation
+
+import (
+	"time"
+
+	"github.com/d-darac/inventory-assets/api"
+	"github.com/d-darac/inventory-assets/currency"
+)
+
+type Method string
+
+const (
+	MethodFIFO            Method = "fifo"
+	MethodWeightedAverage Method = "weighted_average"
+)
+
+type Valuation struct {
+	AsOf   time.Time         `json:"as_of"`
+	Groups []*GroupValuation `json:"groups"`
+	Items  []*ItemValuation  `json:"items"`
+	Method Method            `json:"method"`
+	Totals []*Total          `json:"totals"`
+}
+
+type GroupValuation struct {
+	Currency currency.NullCurrency `json:"currency"`
+	Group    api.Expandable        `json:"group"`
+	Quantity int64                 `json:"quantity"`
+	Value    int64                 `json:"value"`
+}
+
+type ItemValuation struct {
+	Currency currency.NullCurrency `json:"currency"`
+	Group    api.Expandable        `json:"group"`
+	Item     api.Expandable        `json:"item"`
+	Quantity int64                 `json:"quantity"`
+	Value    int64                 `json:"value"`
+}
+
+// Total is the value of the whole account in one currency.
+type Total struct {
+	Currency currency.NullCurrency `json:"currency"`
+	Quantity int64                 `json:"quantity"`
+	Value    int64                 `json:"value"`
+}
@@ -0,0 +1,80 @@
+package valuation
+
+import (
+	"testing"
+
+	"github.com/d-darac/inventory-assets/database"
+)
+
+var eur = database.NullCurrency{Currency: database.CurrencyEUR, Valid: true}
+
+func receipt(quantity, cost int32) Movement {
+	return Movement{
+		Quantity:         quantity,
+		Type:             database.StockMovementTypeReceipt,
+		UnitCostAmount:   cost,
+		UnitCostCurrency: eur,
+	}
+}
+
+func sale(quantity int32) Movement {
+	return Movement{Quantity: -quantity, Type: database.StockMovementTypeSale}
+}
+
+func TestUnitValueFIFO(t *testing.T) {
+	movements := []Movement{receipt(10, 100), receipt(10, 200), sale(15)}
+
+	quantity, value, cur, err := Value(MethodFIFO, movements)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if quantity != 5 {
+		t.Fatalf("expected quantity 5, got %d", quantity)
+	}
+	if value != 1000 {
+		t.Fatalf("expected value 1000, got %d", value)
+	}
+	if cur != eur {
+		t.Fatalf("expected currency %v, got %v", eur, cur)
+	}
+}
+
+func TestUnitValueWeightedAverage(t *testing.T) {
+	movements := []Movement{receipt(10, 100), receipt(10, 200), sale(15)}
+
+	quantity, value, _, err := Value(MethodWeightedAverage, movements)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if quantity != 5 {
+		t.Fatalf("expected quantity 5, got %d", quantity)
+	}
+	if value != 750 {
+		t.Fatalf("expected value 750, got %d", value)
+	}
+}
+
+func TestUnitValueSkipsTransfers(t *testing.T) {
+	movements := []Movement{
+		receipt(10, 100),
+		{Quantity: -4, Type: database.StockMovementTypeTransferOut},
+		{Quantity: 4, Type: database.StockMovementTypeTransferIn},
+	}
+
+	quantity, value, _, err := Value(MethodFIFO, movements)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if quantity != 10 || value != 1000 {
+		t.Fatalf("expected 10 units worth 1000, got %d worth %d", quantity, value)
+	}
+}
+
+func TestUnitValueMixedCurrencies(t *testing.T) {
+	usd := receipt(1, 100)
+	usd.UnitCostCurrency = database.NullCurrency{Currency: "USD", Valid: true}
+
+	if _, _, _, err := Value(MethodFIFO, []Movement{receipt(1, 100), usd}); err == nil {
+		t.Fatal("expected an error for receipts in two currencies")
+	}
+}
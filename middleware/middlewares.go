@@ -19,11 +19,21 @@ import (
 type ctxKey string
 
 var AuthAccountID ctxKey = "middleware.auth.accountID"
+var AuthApiKeyID ctxKey = "middleware.auth.apiKeyID"
+
+// streamingRoutes are the routes whose bodies are streamed instead of
+// buffered: file uploads, which are capped at MaxUploadSize rather than
+// MaxReqSize, and file downloads.
+var streamingRoutes = map[string][]string{
+	`^\/v1\/files$`:                  {"POST"},
+	`^\/v1\/files\/[^\/]+\/content$`: {"GET"},
+}
 
 type Middleware struct {
-	MaxReqSize int
-	Db         *database.Queries
-	Auth       struct {
+	MaxReqSize    int
+	MaxUploadSize int
+	Db            *database.Queries
+	Auth          struct {
 		MasterKey string
 		Iv        string
 	}
@@ -47,7 +57,12 @@ func (mw *Middleware) CreateStack(xs ...MiddlewareFuncs) MiddlewareFuncs {
 	}
 }
 
+// Write buffers the response, unless the route streams it, in which case
+// buf is nil and the response goes straight out.
 func (w *wrappedWriter) Write(p []byte) (int, error) {
+	if w.buf == nil {
+		return w.ResponseWriter.Write(p)
+	}
 	return w.buf.Write(p)
 }
 
@@ -60,6 +75,16 @@ func (mw *Middleware) LoggerMw(next http.HandlerFunc) http.HandlerFunc {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
 
+		if isStreamingRoute(r) {
+			wrapped := &wrappedWriter{
+				ResponseWriter: w,
+				statusCode:     http.StatusOK,
+			}
+			next.ServeHTTP(wrapped, r)
+			log.Println(wrapped.statusCode, r.Method, r.URL.Path, time.Since(start))
+			return
+		}
+
 		body, err := io.ReadAll(r.Body)
 		if err != nil {
 			api.ResError(w, api.ApiErrorMessage())
@@ -91,6 +116,11 @@ func (mw *Middleware) LoggerMw(next http.HandlerFunc) http.HandlerFunc {
 
 func (mw *Middleware) CheckReqBodyLengthMw(next http.HandlerFunc) http.HandlerFunc {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isStreamingRoute(r) {
+			r.Body = http.MaxBytesReader(w, r.Body, int64(mw.MaxUploadSize))
+			next.ServeHTTP(w, r)
+			return
+		}
 		r.Body = http.MaxBytesReader(w, r.Body, int64(mw.MaxReqSize))
 		body, err := io.ReadAll(r.Body)
 		if err != nil {
@@ -122,14 +152,67 @@ func (mw *Middleware) RecoveryMw(next http.HandlerFunc) http.HandlerFunc {
 func (mw *Middleware) CheckRouteAndMethodMw(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		pathsMethods := map[string][]string{
-			`^\/v1\/groups$`:                   {"GET", "POST"},
-			`^\/v1\/groups\/[^\/]+$`:           {"DELETE", "GET", "PATCH"},
-			`^\/v1\/inventories$`:              {"GET", "POST"},
-			`^\/v1\/inventories\/[^\/]+$`:      {"DELETE", "GET", "PATCH"},
-			`^\/v1\/items$`:                    {"GET", "POST"},
-			`^\/v1\/items\/[^\/]+$`:            {"DELETE", "GET", "PATCH"},
-			`^\/v1\/item_identifiers$`:         {"GET", "POST"},
-			`^\/v1\/item_identifiers\/[^\/]+$`: {"DELETE", "GET", "PATCH"},
+			`^\/v1\/files$`:                                          {"GET", "POST"},
+			`^\/v1\/files\/[^\/]+$`:                                  {"DELETE", "GET", "PATCH"},
+			`^\/v1\/files\/[^\/]+\/content$`:                         {"GET"},
+			`^\/v1\/groups$`:                                         {"GET", "POST"},
+			`^\/v1\/groups\/[^\/]+$`:                                 {"DELETE", "GET", "PATCH"},
+			`^\/v1\/groups\/[^\/]+\/(ancestors|tree)$`:               {"GET"},
+			`^\/v1\/inventories$`:                                    {"GET", "POST"},
+			`^\/v1\/inventories\/[^\/]+$`:                            {"DELETE", "GET", "PATCH"},
+			`^\/v1\/inventories\/[^\/]+\/history$`:                   {"GET"},
+			`^\/v1\/items$`:                                          {"GET", "POST"},
+			`^\/v1\/items\/[^\/]+$`:                                  {"DELETE", "GET", "PATCH"},
+			`^\/v1\/items\/[^\/]+\/components$`:                      {"GET", "POST"},
+			`^\/v1\/items\/tags\/(add|remove)$`:                      {"POST"},
+			`^\/v1\/items\/[^\/]+\/price_history$`:                   {"GET"},
+			`^\/v1\/items\/[^\/]+\/variants$`:                        {"GET"},
+			`^\/v1\/items\/[^\/]+\/variants\/generate$`:              {"POST"},
+			`^\/v1\/items\/[^\/]+\/components\/[^\/]+$`:              {"DELETE", "GET", "PATCH"},
+			`^\/v1\/items\/[^\/]+\/suppliers$`:                       {"GET", "POST"},
+			`^\/v1\/items\/[^\/]+\/suppliers\/[^\/]+$`:               {"DELETE", "GET", "PATCH"},
+			`^\/v1\/item_identifiers$`:                               {"GET", "POST"},
+			`^\/v1\/item_identifiers\/[^\/]+$`:                       {"DELETE", "GET", "PATCH"},
+			`^\/v1\/locations$`:                                      {"GET", "POST"},
+			`^\/v1\/locations\/[^\/]+$`:                              {"DELETE", "GET", "PATCH"},
+			`^\/v1\/price_lists$`:                                    {"GET", "POST"},
+			`^\/v1\/price_lists\/[^\/]+$`:                            {"DELETE", "GET", "PATCH"},
+			`^\/v1\/price_lists\/[^\/]+\/quote$`:                     {"GET"},
+			`^\/v1\/price_lists\/[^\/]+\/prices$`:                    {"GET", "POST"},
+			`^\/v1\/price_lists\/[^\/]+\/prices\/[^\/]+$`:            {"DELETE", "GET", "PATCH"},
+			`^\/v1\/suppliers$`:                                      {"GET", "POST"},
+			`^\/v1\/suppliers\/[^\/]+$`:                              {"DELETE", "GET", "PATCH"},
+			`^\/v1\/tags$`:                                           {"GET"},
+			`^\/v1\/units$`:                                          {"GET", "POST"},
+			`^\/v1\/units\/[^\/]+$`:                                  {"DELETE", "GET", "PATCH"},
+			`^\/v1\/stock_movements$`:                                {"GET", "POST"},
+			`^\/v1\/stock_movements\/[^\/]+$`:                        {"GET"},
+			`^\/v1\/lots$`:                                           {"GET", "POST"},
+			`^\/v1\/lots\/[^\/]+$`:                                   {"DELETE", "GET", "PATCH"},
+			`^\/v1\/serial_numbers$`:                                 {"GET", "POST"},
+			`^\/v1\/serial_numbers\/[^\/]+$`:                         {"GET", "PATCH"},
+			`^\/v1\/reservations$`:                                   {"GET", "POST"},
+			`^\/v1\/reservations\/[^\/]+$`:                           {"GET"},
+			`^\/v1\/reservations\/[^\/]+\/(commit|release)$`:         {"POST"},
+			`^\/v1\/orders$`:                                         {"GET", "POST"},
+			`^\/v1\/orders\/[^\/]+$`:                                 {"GET", "PATCH"},
+			`^\/v1\/orders\/[^\/]+\/(cancel|confirm|fulfill)$`:       {"POST"},
+			`^\/v1\/purchase_orders$`:                                {"GET", "POST"},
+			`^\/v1\/purchase_orders\/[^\/]+$`:                        {"GET", "PATCH"},
+			`^\/v1\/purchase_orders\/[^\/]+\/(cancel|receive|send)$`: {"POST"},
+			`^\/v1\/returns$`:                                        {"GET", "POST"},
+			`^\/v1\/returns\/[^\/]+$`:                                {"GET"},
+			`^\/v1\/returns\/[^\/]+\/(inspect|restock|scrap)$`:       {"POST"},
+			`^\/v1\/transfers$`:                                      {"GET", "POST"},
+			`^\/v1\/transfers\/[^\/]+$`:                              {"GET", "PATCH"},
+			`^\/v1\/transfers\/[^\/]+\/(cancel|dispatch|receive)$`:   {"POST"},
+			`^\/v1\/stock_counts$`:                                   {"GET", "POST"},
+			`^\/v1\/stock_counts\/[^\/]+$`:                           {"GET"},
+			`^\/v1\/stock_counts\/[^\/]+\/(cancel|counts|finalize)$`: {"POST"},
+			`^\/v1\/reports\/return_reasons$`:                        {"GET"},
+			`^\/v1\/reports\/valuation$`:                             {"GET"},
+			`^\/v1\/events$`:                                         {"GET"},
+			`^\/v1\/events\/[^\/]+$`:                                 {"GET"},
 		}
 		if err := validateRoute(r.Method, r.URL.Path, pathsMethods); err != nil {
 			api.ResError(w, err)
@@ -173,12 +256,22 @@ func (mw *Middleware) ApiKeyAuthMw(next http.HandlerFunc) http.HandlerFunc {
 		}
 
 		ctx := context.WithValue(r.Context(), AuthAccountID, apiKey.Account)
+		ctx = context.WithValue(ctx, AuthApiKeyID, apiKey.ID)
 		req := r.WithContext(ctx)
 
 		next.ServeHTTP(w, req)
 	}
 }
 
+func isStreamingRoute(r *http.Request) bool {
+	for pattern, methods := range streamingRoutes {
+		if slices.Contains(methods, r.Method) && regexp.MustCompile(pattern).MatchString(r.URL.Path) {
+			return true
+		}
+	}
+	return false
+}
+
 func validateRoute(reqMethod, reqPath string, pathsMethods map[string][]string) error {
 	methods := []string{}
 	for kPath, vMethods := range pathsMethods {
@@ -1,39 +1,182 @@
 package router
 
 import (
+	"database/sql"
 	"net/http"
 
 	"github.com/d-darac/inventory-api/handlers"
+	"github.com/d-darac/inventory-api/internal/storage"
 	"github.com/d-darac/inventory-assets/api"
 )
 
-func LoadRoutes(mux *http.ServeMux, cfg *api.ApiConfig) {
+func LoadRoutes(mux *http.ServeMux, cfg *api.ApiConfig, conn *sql.DB, store storage.Storage) {
 	// TODO: Implement routes
-	groupsHandler := handlers.NewGroupsHandler(cfg.Db)
+	groupsHandler := handlers.NewGroupsHandler(cfg.Db, conn)
 	mux.HandleFunc("POST /groups", groupsHandler.Create)
 	mux.HandleFunc("DELETE /groups/{id}", groupsHandler.Delete)
 	mux.HandleFunc("GET /groups", groupsHandler.List)
 	mux.HandleFunc("GET /groups/{id}", groupsHandler.Retrieve)
 	mux.HandleFunc("PATCH /groups/{id}", groupsHandler.Update)
+	mux.HandleFunc("GET /groups/{id}/ancestors", groupsHandler.Ancestors)
+	mux.HandleFunc("GET /groups/{id}/tree", groupsHandler.Tree)
 
-	itemsHandler := handlers.NewItemsHandler(cfg.Db)
+	itemsHandler := handlers.NewItemsHandler(cfg.Db, conn, store)
 	mux.HandleFunc("POST /items", itemsHandler.Create)
 	mux.HandleFunc("DELETE /items/{id}", itemsHandler.Delete)
 	mux.HandleFunc("GET /items", itemsHandler.List)
 	mux.HandleFunc("GET /items/{id}", itemsHandler.Retrieve)
 	mux.HandleFunc("PATCH /items/{id}", itemsHandler.Update)
+	mux.HandleFunc("POST /items/tags/add", itemsHandler.AddTags)
+	mux.HandleFunc("POST /items/tags/remove", itemsHandler.RemoveTags)
+	mux.HandleFunc("GET /items/{id}/price_history", itemsHandler.PriceHistory)
+	mux.HandleFunc("GET /items/{id}/variants", itemsHandler.Variants)
+	mux.HandleFunc("POST /items/{id}/variants/generate", itemsHandler.GenerateVariants)
 
-	inventoriesHandler := handlers.NewInventoriesHandler(cfg.Db)
+	componentsHandler := handlers.NewComponentsHandler(cfg.Db, conn)
+	mux.HandleFunc("POST /items/{id}/components", componentsHandler.Create)
+	mux.HandleFunc("DELETE /items/{id}/components/{component_id}", componentsHandler.Delete)
+	mux.HandleFunc("GET /items/{id}/components", componentsHandler.List)
+	mux.HandleFunc("GET /items/{id}/components/{component_id}", componentsHandler.Retrieve)
+	mux.HandleFunc("PATCH /items/{id}/components/{component_id}", componentsHandler.Update)
+
+	filesHandler := handlers.NewFilesHandler(cfg.Db, conn, store)
+	mux.HandleFunc("POST /files", filesHandler.Create)
+	mux.HandleFunc("DELETE /files/{id}", filesHandler.Delete)
+	mux.HandleFunc("GET /files", filesHandler.List)
+	mux.HandleFunc("GET /files/{id}", filesHandler.Retrieve)
+	mux.HandleFunc("PATCH /files/{id}", filesHandler.Update)
+	mux.HandleFunc("GET /files/{id}/content", filesHandler.Content)
+
+	tagsHandler := handlers.NewTagsHandler(cfg.Db)
+	mux.HandleFunc("GET /tags", tagsHandler.List)
+
+	suppliersHandler := handlers.NewSuppliersHandler(cfg.Db, conn)
+	mux.HandleFunc("POST /suppliers", suppliersHandler.Create)
+	mux.HandleFunc("DELETE /suppliers/{id}", suppliersHandler.Delete)
+	mux.HandleFunc("GET /suppliers", suppliersHandler.List)
+	mux.HandleFunc("GET /suppliers/{id}", suppliersHandler.Retrieve)
+	mux.HandleFunc("PATCH /suppliers/{id}", suppliersHandler.Update)
+	mux.HandleFunc("POST /items/{id}/suppliers", suppliersHandler.CreateItemSupplier)
+	mux.HandleFunc("DELETE /items/{id}/suppliers/{supplier_id}", suppliersHandler.DeleteItemSupplier)
+	mux.HandleFunc("GET /items/{id}/suppliers", suppliersHandler.ListItemSuppliers)
+	mux.HandleFunc("GET /items/{id}/suppliers/{supplier_id}", suppliersHandler.RetrieveItemSupplier)
+	mux.HandleFunc("PATCH /items/{id}/suppliers/{supplier_id}", suppliersHandler.UpdateItemSupplier)
+
+	inventoriesHandler := handlers.NewInventoriesHandler(cfg.Db, conn)
 	mux.HandleFunc("POST /inventories", inventoriesHandler.Create)
 	mux.HandleFunc("DELETE /inventories/{id}", inventoriesHandler.Delete)
 	mux.HandleFunc("GET /inventories", inventoriesHandler.List)
 	mux.HandleFunc("GET /inventories/{id}", inventoriesHandler.Retrieve)
+	mux.HandleFunc("GET /inventories/{id}/history", inventoriesHandler.History)
 	mux.HandleFunc("PATCH /inventories/{id}", inventoriesHandler.Update)
 
-	itemIdentifiersHandler := handlers.NewItemIdentifiersHandler(cfg.Db)
+	itemIdentifiersHandler := handlers.NewItemIdentifiersHandler(cfg.Db, conn)
 	mux.HandleFunc("POST /item_identifiers", itemIdentifiersHandler.Create)
 	mux.HandleFunc("DELETE /item_identifiers/{id}", itemIdentifiersHandler.Delete)
 	mux.HandleFunc("GET /item_identifiers", itemIdentifiersHandler.List)
 	mux.HandleFunc("GET /item_identifiers/{id}", itemIdentifiersHandler.Retrieve)
 	mux.HandleFunc("PATCH /item_identifiers/{id}", itemIdentifiersHandler.Update)
+
+	locationsHandler := handlers.NewLocationsHandler(cfg.Db)
+	mux.HandleFunc("POST /locations", locationsHandler.Create)
+	mux.HandleFunc("DELETE /locations/{id}", locationsHandler.Delete)
+	mux.HandleFunc("GET /locations", locationsHandler.List)
+	mux.HandleFunc("GET /locations/{id}", locationsHandler.Retrieve)
+	mux.HandleFunc("PATCH /locations/{id}", locationsHandler.Update)
+
+	priceListsHandler := handlers.NewPriceListsHandler(cfg.Db, conn)
+	mux.HandleFunc("POST /price_lists", priceListsHandler.Create)
+	mux.HandleFunc("DELETE /price_lists/{id}", priceListsHandler.Delete)
+	mux.HandleFunc("GET /price_lists", priceListsHandler.List)
+	mux.HandleFunc("GET /price_lists/{id}", priceListsHandler.Retrieve)
+	mux.HandleFunc("PATCH /price_lists/{id}", priceListsHandler.Update)
+	mux.HandleFunc("GET /price_lists/{id}/quote", priceListsHandler.Quote)
+	mux.HandleFunc("POST /price_lists/{id}/prices", priceListsHandler.CreatePrice)
+	mux.HandleFunc("DELETE /price_lists/{id}/prices/{price_id}", priceListsHandler.DeletePrice)
+	mux.HandleFunc("GET /price_lists/{id}/prices", priceListsHandler.ListPrices)
+	mux.HandleFunc("GET /price_lists/{id}/prices/{price_id}", priceListsHandler.RetrievePrice)
+	mux.HandleFunc("PATCH /price_lists/{id}/prices/{price_id}", priceListsHandler.UpdatePrice)
+
+	unitsHandler := handlers.NewUnitsHandler(cfg.Db)
+	mux.HandleFunc("POST /units", unitsHandler.Create)
+	mux.HandleFunc("DELETE /units/{id}", unitsHandler.Delete)
+	mux.HandleFunc("GET /units", unitsHandler.List)
+	mux.HandleFunc("GET /units/{id}", unitsHandler.Retrieve)
+	mux.HandleFunc("PATCH /units/{id}", unitsHandler.Update)
+
+	stockMovementsHandler := handlers.NewStockMovementsHandler(cfg.Db, conn)
+	mux.HandleFunc("POST /stock_movements", stockMovementsHandler.Create)
+	mux.HandleFunc("GET /stock_movements", stockMovementsHandler.List)
+	mux.HandleFunc("GET /stock_movements/{id}", stockMovementsHandler.Retrieve)
+
+	lotsHandler := handlers.NewLotsHandler(cfg.Db, conn)
+	mux.HandleFunc("POST /lots", lotsHandler.Create)
+	mux.HandleFunc("DELETE /lots/{id}", lotsHandler.Delete)
+	mux.HandleFunc("GET /lots", lotsHandler.List)
+	mux.HandleFunc("GET /lots/{id}", lotsHandler.Retrieve)
+	mux.HandleFunc("PATCH /lots/{id}", lotsHandler.Update)
+
+	serialNumbersHandler := handlers.NewSerialNumbersHandler(cfg.Db, conn)
+	mux.HandleFunc("POST /serial_numbers", serialNumbersHandler.Create)
+	mux.HandleFunc("GET /serial_numbers", serialNumbersHandler.List)
+	mux.HandleFunc("GET /serial_numbers/{id}", serialNumbersHandler.Retrieve)
+	mux.HandleFunc("PATCH /serial_numbers/{id}", serialNumbersHandler.Update)
+
+	reservationsHandler := handlers.NewReservationsHandler(cfg.Db, conn)
+	mux.HandleFunc("POST /reservations", reservationsHandler.Create)
+	mux.HandleFunc("GET /reservations", reservationsHandler.List)
+	mux.HandleFunc("GET /reservations/{id}", reservationsHandler.Retrieve)
+	mux.HandleFunc("POST /reservations/{id}/commit", reservationsHandler.Commit)
+	mux.HandleFunc("POST /reservations/{id}/release", reservationsHandler.Release)
+
+	transfersHandler := handlers.NewTransfersHandler(cfg.Db, conn)
+	mux.HandleFunc("POST /transfers", transfersHandler.Create)
+	mux.HandleFunc("GET /transfers", transfersHandler.List)
+	mux.HandleFunc("GET /transfers/{id}", transfersHandler.Retrieve)
+	mux.HandleFunc("PATCH /transfers/{id}", transfersHandler.Update)
+	mux.HandleFunc("POST /transfers/{id}/cancel", transfersHandler.Cancel)
+	mux.HandleFunc("POST /transfers/{id}/dispatch", transfersHandler.Dispatch)
+	mux.HandleFunc("POST /transfers/{id}/receive", transfersHandler.Receive)
+
+	ordersHandler := handlers.NewOrdersHandler(cfg.Db, conn)
+	mux.HandleFunc("POST /orders", ordersHandler.Create)
+	mux.HandleFunc("GET /orders", ordersHandler.List)
+	mux.HandleFunc("GET /orders/{id}", ordersHandler.Retrieve)
+	mux.HandleFunc("PATCH /orders/{id}", ordersHandler.Update)
+	mux.HandleFunc("POST /orders/{id}/cancel", ordersHandler.Cancel)
+	mux.HandleFunc("POST /orders/{id}/confirm", ordersHandler.Confirm)
+	mux.HandleFunc("POST /orders/{id}/fulfill", ordersHandler.Fulfill)
+
+	purchaseOrdersHandler := handlers.NewPurchaseOrdersHandler(cfg.Db, conn)
+	mux.HandleFunc("POST /purchase_orders", purchaseOrdersHandler.Create)
+	mux.HandleFunc("GET /purchase_orders", purchaseOrdersHandler.List)
+	mux.HandleFunc("GET /purchase_orders/{id}", purchaseOrdersHandler.Retrieve)
+	mux.HandleFunc("PATCH /purchase_orders/{id}", purchaseOrdersHandler.Update)
+	mux.HandleFunc("POST /purchase_orders/{id}/cancel", purchaseOrdersHandler.Cancel)
+	mux.HandleFunc("POST /purchase_orders/{id}/receive", purchaseOrdersHandler.Receive)
+	mux.HandleFunc("POST /purchase_orders/{id}/send", purchaseOrdersHandler.Send)
+
+	returnsHandler := handlers.NewReturnsHandler(cfg.Db, conn)
+	mux.HandleFunc("POST /returns", returnsHandler.Create)
+	mux.HandleFunc("GET /returns", returnsHandler.List)
+	mux.HandleFunc("GET /returns/{id}", returnsHandler.Retrieve)
+	mux.HandleFunc("POST /returns/{id}/inspect", returnsHandler.Inspect)
+	mux.HandleFunc("POST /returns/{id}/restock", returnsHandler.Restock)
+	mux.HandleFunc("POST /returns/{id}/scrap", returnsHandler.Scrap)
+
+	stockCountsHandler := handlers.NewStockCountsHandler(cfg.Db, conn)
+	mux.HandleFunc("POST /stock_counts", stockCountsHandler.Create)
+	mux.HandleFunc("GET /stock_counts", stockCountsHandler.List)
+	mux.HandleFunc("GET /stock_counts/{id}", stockCountsHandler.Retrieve)
+	mux.HandleFunc("POST /stock_counts/{id}/cancel", stockCountsHandler.Cancel)
+	mux.HandleFunc("POST /stock_counts/{id}/counts", stockCountsHandler.Submit)
+	mux.HandleFunc("POST /stock_counts/{id}/finalize", stockCountsHandler.Finalize)
+
+	reportsHandler := handlers.NewReportsHandler(cfg.Db, conn)
+	mux.HandleFunc("GET /reports/return_reasons", reportsHandler.ReturnReasons)
+	mux.HandleFunc("GET /reports/valuation", reportsHandler.Valuation)
+
+	eventsHandler := handlers.NewEventsHandler(cfg.Db)
+	mux.HandleFunc("GET /events", eventsHandler.List)
+	mux.HandleFunc("GET /events/{id}", eventsHandler.Retrieve)
 }